@@ -139,7 +139,7 @@ export default function Page() {
           },
         );
         if (response.status === 200) {
-          setComments(response.data.data);
+          setComments(response.data.data.items);
         } else {
           console.error("Failed to fetch comments:", response.statusText);
         }
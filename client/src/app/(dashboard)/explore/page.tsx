@@ -11,7 +11,8 @@ import { DecodeUserFromToken } from "~/utils/utils";
 export default function Page() {
   const scrollRef = useRef<HTMLDivElement>(null);
   const [limit] = useState(10);
-  const [offset, setOffset] = useState(0);
+  const [cursor, setCursor] = useState("");
+  const [nextCursor, setNextCursor] = useState("");
   const [hasMore, setHasMore] = useState(true);
   const [posts, setPosts] = useState<PostInterface[]>([]);
   const [loading, setLoading] = useState(true);
@@ -54,15 +55,14 @@ export default function Page() {
       }
       setLoading(true);
       const fetchAllPosts = async () => {
-        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&offset=${offset}`;
+        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&cursor=${encodeURIComponent(cursor)}`;
         try {
           const response = await axios.get<PostResponse>(apiUrl);
           if (response.status === 200) {
-            const newPosts = response.data.data;
-            setPosts((prev) => mergePostsUnique(prev, newPosts));
-            if (newPosts.length < limit || newPosts.length === 0) {
-              setHasMore(false);
-            }
+            const page = response.data.data;
+            setPosts((prev) => mergePostsUnique(prev, page.items));
+            setNextCursor(page.next_cursor);
+            setHasMore(page.has_more);
           } else {
             console.error("Failed to fetch all posts:", response.statusText);
           }
@@ -95,28 +95,27 @@ export default function Page() {
         hasMore
       ) {
         setLoading(true);
-        setOffset((prev) => prev + limit);
+        setCursor(nextCursor);
       }
     };
 
     container.addEventListener("scroll", handleScroll);
     return () => container.removeEventListener("scroll", handleScroll);
-  }, [loading, hasMore]); // eslint-disable-line react-hooks/exhaustive-deps
+  }, [loading, hasMore, nextCursor]); // eslint-disable-line react-hooks/exhaustive-deps
 
   useEffect(() => {
-    if (offset === 0) return;
+    if (cursor === "") return;
 
     const fetchMorePosts = async () => {
       setLoading(true);
       try {
-        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&offset=${offset}`;
+        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&cursor=${encodeURIComponent(cursor)}`;
         const response = await axios.get<PostResponse>(apiUrl);
         if (response.status === 200) {
-          const newPosts = response.data.data;
-          setPosts((prev) => mergePostsUnique(prev, newPosts));
-          if (newPosts.length < limit || newPosts.length === 0) {
-            setHasMore(false);
-          }
+          const page = response.data.data;
+          setPosts((prev) => mergePostsUnique(prev, page.items));
+          setNextCursor(page.next_cursor);
+          setHasMore(page.has_more);
         }
       } catch (error) {
         console.error(error);
@@ -131,7 +130,7 @@ export default function Page() {
       setLoading(false);
       setHasMore(false);
     });
-  }, [offset, isLoggedIn, user.id]); // eslint-disable-line react-hooks/exhaustive-deps
+  }, [cursor, isLoggedIn, user.id]); // eslint-disable-line react-hooks/exhaustive-deps
 
   function renderPosts() {
     if (loading && posts.length === 0)
@@ -148,7 +147,7 @@ export default function Page() {
         <div className="mx-auto mt-8 flex max-w-4xl flex-col gap-4">
           {renderPosts()}
         </div>
-        {loading && offset !== 0 && (
+        {loading && cursor !== "" && (
           <p className="text-center text-black">Loading more posts...</p>
         )}
       </section>
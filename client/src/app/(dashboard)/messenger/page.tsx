@@ -81,7 +81,7 @@ export default function Page() {
             },
           );
           if (response.status === 200) {
-            const data = response.data.data;
+            const data = response.data.data.items;
             setFriends(data);
           }
         } catch (error) {
@@ -100,7 +100,7 @@ export default function Page() {
     if (!token || !user.id) return;
     if (!ws.current || ws.current.readyState === WebSocket.CLOSED) {
       ws.current = new WebSocket(
-        `${process.env.NEXT_PUBLIC_WEB_SOCKET_URL}/api/chat/ws?id=${user.id}`,
+        `${process.env.NEXT_PUBLIC_WEB_SOCKET_URL}/api/chat/ws?token=${encodeURIComponent(token)}`,
       );
       ws.current.onopen = () => {
         console.log("WebSocket connection established");
@@ -12,7 +12,8 @@ import { DecodeUserFromToken } from "~/utils/utils";
 export default function HomePage() {
   const scrollRef = useRef<HTMLDivElement>(null);
   const [limit] = useState(10);
-  const [offset, setOffset] = useState(0);
+  const [cursor, setCursor] = useState("");
+  const [nextCursor, setNextCursor] = useState("");
   const [hasMore, setHasMore] = useState(true);
   const [posts, setPosts] = useState<PostInterface[]>([]);
   const [loading, setLoading] = useState(true);
@@ -55,15 +56,14 @@ export default function HomePage() {
       }
       setLoading(true);
       const fetchAllPosts = async () => {
-        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&offset=${offset}`;
+        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&cursor=${encodeURIComponent(cursor)}`;
         try {
           const response = await axios.get<PostResponse>(apiUrl);
           if (response.status === 200) {
-            const newPosts = response.data.data;
-            setPosts((prev) => mergePostsUnique(prev, newPosts));
-            if (newPosts.length < limit || newPosts.length === 0) {
-              setHasMore(false);
-            }
+            const page = response.data.data;
+            setPosts((prev) => mergePostsUnique(prev, page.items));
+            setNextCursor(page.next_cursor);
+            setHasMore(page.has_more);
           } else {
             console.error("Failed to fetch all posts:", response.statusText);
           }
@@ -78,9 +78,9 @@ export default function HomePage() {
       const fetchFriendPosts = async (
         userID: number,
         limit: number,
-        offset: number,
+        cursor: string,
       ) => {
-        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/friend-posts/${userID}?limit=${limit}&offset=${offset}`;
+        const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/friend-posts/${userID}?limit=${limit}&cursor=${encodeURIComponent(cursor)}`;
         try {
           const response = await axios.get<PostResponse>(apiUrl, {
             headers: {
@@ -89,11 +89,10 @@ export default function HomePage() {
             },
           });
           if (response.status === 200) {
-            const friendPosts = response.data.data;
-            setPosts((prev) => mergePostsUnique(prev, friendPosts));
-            if (friendPosts.length < limit || friendPosts.length === 0) {
-              setHasMore(false);
-            }
+            const page = response.data.data;
+            setPosts((prev) => mergePostsUnique(prev, page.items));
+            setNextCursor(page.next_cursor);
+            setHasMore(page.has_more);
           } else {
             console.error("Failed to fetch friend posts:", response.statusText);
             await fetchAllPosts(); // fallback
@@ -107,7 +106,7 @@ export default function HomePage() {
       };
 
       if (isLoggedIn && user.id > 0) {
-        await fetchFriendPosts(user.id, limit, offset);
+        await fetchFriendPosts(user.id, limit, cursor);
       } else {
         await fetchAllPosts();
       }
@@ -131,22 +130,22 @@ export default function HomePage() {
         hasMore
       ) {
         setLoading(true);
-        setOffset((prev) => prev + limit);
+        setCursor(nextCursor);
       }
     };
 
     container.addEventListener("scroll", handleScroll);
     return () => container.removeEventListener("scroll", handleScroll);
-  }, [loading, hasMore]); // eslint-disable-line react-hooks/exhaustive-deps
+  }, [loading, hasMore, nextCursor]); // eslint-disable-line react-hooks/exhaustive-deps
 
   useEffect(() => {
-    if (offset === 0) return;
+    if (cursor === "") return;
 
     const fetchMorePosts = async () => {
       setLoading(true);
       try {
         if (isLoggedIn && user.id > 0) {
-          const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/friend-posts/${user.id}?limit=${limit}&offset=${offset}`;
+          const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/friend-posts/${user.id}?limit=${limit}&cursor=${encodeURIComponent(cursor)}`;
           const response = await axios.get<PostResponse>(apiUrl, {
             headers: {
               Authorization: `Bearer ${Cookies.get("token")}`,
@@ -154,21 +153,19 @@ export default function HomePage() {
             },
           });
           if (response.status === 200) {
-            const friendPosts = response.data.data;
-            setPosts((prev) => mergePostsUnique(prev, friendPosts));
-            if (friendPosts.length < limit || friendPosts.length === 0) {
-              setHasMore(false);
-            }
+            const page = response.data.data;
+            setPosts((prev) => mergePostsUnique(prev, page.items));
+            setNextCursor(page.next_cursor);
+            setHasMore(page.has_more);
           }
         } else {
-          const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&offset=${offset}`;
+          const apiUrl = `${process.env.NEXT_PUBLIC_API_URL}/api/post/all?limit=${limit}&cursor=${encodeURIComponent(cursor)}`;
           const response = await axios.get<PostResponse>(apiUrl);
           if (response.status === 200) {
-            const newPosts = response.data.data;
-            setPosts((prev) => [...prev, ...newPosts]);
-            if (newPosts.length < limit || newPosts.length === 0) {
-              setHasMore(false);
-            }
+            const page = response.data.data;
+            setPosts((prev) => mergePostsUnique(prev, page.items));
+            setNextCursor(page.next_cursor);
+            setHasMore(page.has_more);
           }
         }
       } catch (error) {
@@ -184,7 +181,7 @@ export default function HomePage() {
       setLoading(false);
       setHasMore(false);
     });
-  }, [offset, isLoggedIn, user.id]); // eslint-disable-line react-hooks/exhaustive-deps
+  }, [cursor, isLoggedIn, user.id]); // eslint-disable-line react-hooks/exhaustive-deps
 
   function renderPosts() {
     if (loading && posts.length === 0)
@@ -207,7 +204,7 @@ export default function HomePage() {
         <div className="mx-auto mt-8 flex max-w-4xl flex-col gap-4">
           {renderPosts()}
         </div>
-        {loading && offset !== 0 && (
+        {loading && cursor !== "" && (
           <p className="text-center text-black">Loading more posts...</p>
         )}
       </section>
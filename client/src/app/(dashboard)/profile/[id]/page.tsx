@@ -48,6 +48,7 @@ export default function Page() {
     fullname: loggedInUser.fullname,
     email: loggedInUser.email,
     password: "",
+    currentPassword: "",
   });
 
   const handleInputChange = (e: React.ChangeEvent<HTMLInputElement>) => {
@@ -105,6 +106,7 @@ export default function Page() {
           fullname: editUser.fullname,
           email: editUser.email,
           password: editUser.password,
+          current_password: editUser.currentPassword,
           profile: "",
         },
         {
@@ -128,6 +130,7 @@ export default function Page() {
           fullname: data.fullname,
           email: data.email,
           password: "",
+          currentPassword: "",
         });
         // Update user token as well:
         await reLogin(editUser.username, editUser.password);
@@ -147,6 +150,7 @@ export default function Page() {
       fullname: loggedInUser.fullname,
       email: loggedInUser.email,
       password: "",
+      currentPassword: "",
     });
     setIsEditing(false);
   };
@@ -168,6 +172,7 @@ export default function Page() {
           fullname: user.user.fullname,
           email: user.user.email,
           password: "",
+          currentPassword: "",
         });
         setIsLoggedIn(true);
       }
@@ -212,7 +217,7 @@ export default function Page() {
           },
         );
         if (response.status === 200) {
-          const data = response.data.data;
+          const data = response.data.data.items;
           setPosts(data);
         }
       } catch (error) {
@@ -232,7 +237,7 @@ export default function Page() {
           },
         );
         if (response.status === 200) {
-          const data = response.data.data;
+          const data = response.data.data.items;
           setFriends(data);
         }
       } catch (error) {
@@ -351,6 +356,14 @@ export default function Page() {
                       placeholder="Password"
                       value={editUser.password}
                       onChange={handleInputChange}
+                      className="mb-2 rounded bg-white px-2 py-1 text-sm"
+                    />
+                    <input
+                      type="password"
+                      name="currentPassword"
+                      placeholder="Current password (needed to change password)"
+                      value={editUser.currentPassword}
+                      onChange={handleInputChange}
                       className="mb-4 rounded bg-white px-2 py-1 text-sm"
                     />
                     <div className="flex gap-2">
@@ -101,7 +101,7 @@ export default function Page() {
           },
         );
         if (response.status === 200) {
-          const data = response.data.data;
+          const data = response.data.data.items;
           setPosts(data);
         }
       } catch (error) {
@@ -121,7 +121,7 @@ export default function Page() {
           },
         );
         if (response.status === 200) {
-          const data = response.data.data;
+          const data = response.data.data.items;
           setFriends(data);
         }
       } catch (error) {
@@ -130,18 +130,19 @@ export default function Page() {
       }
     };
 
-    const fetchUserFriendRequests = async (userID: string) => {
+    const fetchUserFriendRequests = async () => {
       try {
         const response = await axios.get<FriendResponse>(
-          `${process.env.NEXT_PUBLIC_API_URL}/api/friend/requests/${userID}`,
+          `${process.env.NEXT_PUBLIC_API_URL}/api/friend/outgoing`,
           {
             headers: {
+              Authorization: `Bearer ${Cookies.get("token")}`,
               "Content-Type": "application/json",
             },
           },
         );
         if (response.status === 200) {
-          const data = response.data.data;
+          const data = response.data.data.items;
           setFriendRequests(data);
         }
       } catch (error) {
@@ -150,18 +151,19 @@ export default function Page() {
       }
     };
 
-    const fetchMyFriendRequests = async (userID: string) => {
+    const fetchMyFriendRequests = async () => {
       try {
         const response = await axios.get<FriendResponse>(
-          `${process.env.NEXT_PUBLIC_API_URL}/api/friend/requests/${userID}`,
+          `${process.env.NEXT_PUBLIC_API_URL}/api/friend/incoming`,
           {
             headers: {
+              Authorization: `Bearer ${Cookies.get("token")}`,
               "Content-Type": "application/json",
             },
           },
         );
         if (response.status === 200) {
-          const data = response.data.data;
+          const data = response.data.data.items;
           setLoggedInUserFriendRequests(data);
         }
       } catch (error) {
@@ -178,11 +180,11 @@ export default function Page() {
     fetchUserFriends(userID as string).catch((error) => {
       console.error("Failed to fetch user friends:", error);
     });
-    fetchUserFriendRequests(userID as string).catch((error) => {
-      console.error("Failed to fetch user friend requests:", error);
-    });
     if (isLoggedIn) {
-      fetchMyFriendRequests(loggedInUser.id.toString()).catch((error) => {
+      fetchUserFriendRequests().catch((error) => {
+        console.error("Failed to fetch user friend requests:", error);
+      });
+      fetchMyFriendRequests().catch((error) => {
         console.error("Failed to fetch logged-in user friend requests:", error);
       });
     }
@@ -358,7 +360,7 @@ export default function Page() {
                     f.frienduserid === user.id) ||
                   (f.userid === user.id && f.frienduserid === loggedInUser.id),
               ) &&
-              (friendRequests.some((f) => f.userid === loggedInUser.id) ? (
+              (friendRequests.some((f) => f.frienduserid === user.id) ? (
                 <button
                   disabled
                   className="absolute right-4 bottom-4 items-center justify-center rounded-lg bg-yellow-400 px-6 py-3 font-bold text-white shadow-xl"
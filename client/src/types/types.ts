@@ -3,9 +3,16 @@ export interface User {
   username: string;
   fullname: string;
   email: string;
+  avatar?: Avatar | null; // null = belum upload, pakai gambar default
   createdAt: string;
 }
 
+export interface Avatar {
+  small: string;
+  medium: string;
+  large: string;
+}
+
 export interface LoginResponse {
   code: number;
   data: string; // JWT token
@@ -23,9 +30,15 @@ export interface MoodPredictionResponse {
   prediction: string;
 }
 
+export interface Page<T> {
+  items: T[];
+  next_cursor: string;
+  has_more: boolean;
+}
+
 export interface PostResponse {
   code: number;
-  data: PostInterface[];
+  data: Page<PostInterface>;
   message: string;
 }
 
@@ -50,7 +63,7 @@ export interface PostInterface {
 
 export interface CommentResponse {
   code: number;
-  data: CommentInterface[];
+  data: Page<CommentInterface>;
   message: string;
 }
 
@@ -75,7 +88,7 @@ export interface CommentInterface {
 
 export interface FriendResponse {
   code: number;
-  data: FriendInterface[];
+  data: Page<FriendInterface>;
   message: string;
 }
 
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"mood-bridge-v2/server/infrastructure/cache"
+	"mood-bridge-v2/server/infrastructure/db"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/service"
+	"os"
+	"time"
+)
+
+// runCreateAdmin menjadikan user yang sudah register sebagai admin pertama:
+//
+//	./main create-admin -username alice
+//
+// Perintah ini ditolak kalau sudah ada admin, admin berikutnya ditambahkan lewat /api/admin.
+func runCreateAdmin(args []string) {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := flags.String("username", "", "username of an existing account to promote to admin")
+	flags.Parse(args)
+	if *username == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	database := db.NewDbConnection()
+	defer database.Close()
+	db.Migrate(database, "up")
+
+	rdb := cache.NewRedisClient()
+	defer rdb.Close()
+
+	userRepository := repository.NewUserRepository()
+	roleRepository := repository.NewRoleRepository()
+	accessService := service.NewAccessService(database, userRepository, roleRepository, rdb)
+	notificationService := service.NewNotificationService(database, repository.NewNotificationRepository())
+	auditService := service.NewAuditService(database, repository.NewAuditRepository())
+	adminService := service.NewAdminService(database, userRepository, roleRepository, repository.NewModerationRepository(), repository.NewMFARepository(), accessService, notificationService, auditService)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := adminService.BootstrapAdmin(ctx, *username); err != nil {
+		log.Fatalf("create-admin: %v", err)
+	}
+	fmt.Printf("%s is now an admin\n", *username)
+}
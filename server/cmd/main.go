@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"mood-bridge-v2/server/infrastructure/cache"
 	"mood-bridge-v2/server/infrastructure/db"
+	"mood-bridge-v2/server/infrastructure/storage"
 	"mood-bridge-v2/server/internal/api"
+	"os"
 )
 
 func main() {
+	// Subcommand CLI, misalnya "./main create-admin -username alice" untuk membuat admin pertama
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		runCreateAdmin(os.Args[2:])
+		return
+	}
+
 	// Pertama-tama kita akan inisialisasi koneksi ke database PostgreSQL
 	database := db.NewDbConnection()
 	defer database.Close()
@@ -18,7 +27,15 @@ func main() {
 	rdb := cache.NewRedisClient()
 	defer rdb.Close()
 
+	// Storage untuk gambar (disk lokal atau S3, tergantung STORAGE_DRIVER)
+	mediaStorage := storage.NewStorage()
+
+	// Jalankan background job (misalnya hitung ulang trending tag)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	api.StartBackgroundJobs(ctx, database, rdb, mediaStorage)
+
 	// Terakhir kita jalankan server-nya
-	router := api.SetupRoutes(database, rdb)
+	router := api.SetupRoutes(database, rdb, mediaStorage)
 	router.Run(":8080")
 }
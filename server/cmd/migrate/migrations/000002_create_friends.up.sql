@@ -2,6 +2,6 @@ CREATE TABLE IF NOT EXISTS friends (
 	FriendID SERIAL PRIMARY KEY,
 	UserID INTEGER REFERENCES users(UserID) ON DELETE CASCADE,
     FriendUserID INTEGER REFERENCES users(UserID) ON DELETE CASCADE,
-	FriendStatus BOOLEAN DEFAULT 'PENDING',
+	FriendStatus BOOLEAN DEFAULT FALSE,
 	CreatedAt TIMESTAMP DEFAULT NOW()
 );
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ai_context_settings;
\ No newline at end of file
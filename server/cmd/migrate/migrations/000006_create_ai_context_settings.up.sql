@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS ai_context_settings (
+	UserID INTEGER PRIMARY KEY REFERENCES users(UserID) ON DELETE CASCADE,
+	Enabled BOOLEAN NOT NULL DEFAULT FALSE,
+	IncludeMoodTrend BOOLEAN NOT NULL DEFAULT TRUE,
+	IncludePosts BOOLEAN NOT NULL DEFAULT TRUE,
+	UpdatedAt TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS journal_keys (
+	UserID INTEGER PRIMARY KEY REFERENCES users(UserID) ON DELETE CASCADE,
+	WrappedKey BYTEA NOT NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+	EntryID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	Content BYTEA NOT NULL,
+	EmotionTags BYTEA,
+	SelfMood SMALLINT CHECK (SelfMood BETWEEN 1 AND 5),
+	PredictedMood VARCHAR(50),
+	EntryDate DATE NOT NULL DEFAULT CURRENT_DATE,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	UpdatedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_user_date ON journal_entries (UserID, EntryDate DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_userid_createdat;
+DROP INDEX IF EXISTS idx_posts_visibility_createdat;
+ALTER TABLE posts DROP COLUMN IF EXISTS IsAnonymous;
+ALTER TABLE posts DROP COLUMN IF EXISTS Visibility;
@@ -0,0 +1,5 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS Visibility VARCHAR(20) NOT NULL DEFAULT 'public' CHECK (Visibility IN ('public', 'friends', 'private'));
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS IsAnonymous BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_posts_visibility_createdat ON posts (Visibility, CreatedAt DESC);
+CREATE INDEX IF NOT EXISTS idx_posts_userid_createdat ON posts (UserID, CreatedAt DESC);
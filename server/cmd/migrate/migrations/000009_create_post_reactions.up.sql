@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS post_reactions (
+	PostID INTEGER NOT NULL REFERENCES posts(PostID) ON DELETE CASCADE,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	ReactionType VARCHAR(30) NOT NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (PostID, UserID, ReactionType)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_reactions_post_type ON post_reactions (PostID, ReactionType, CreatedAt DESC);
+
+CREATE TABLE IF NOT EXISTS post_reaction_counts (
+	PostID INTEGER NOT NULL REFERENCES posts(PostID) ON DELETE CASCADE,
+	ReactionType VARCHAR(30) NOT NULL,
+	Count INTEGER NOT NULL DEFAULT 0 CHECK (Count >= 0),
+	PRIMARY KEY (PostID, ReactionType)
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_comments_parent;
+DROP INDEX IF EXISTS idx_comments_post_toplevel;
+ALTER TABLE comments DROP COLUMN IF EXISTS DeletedAt;
+ALTER TABLE comments DROP COLUMN IF EXISTS IsDeleted;
+ALTER TABLE comments DROP COLUMN IF EXISTS ReplyCount;
+ALTER TABLE comments DROP COLUMN IF EXISTS Depth;
+ALTER TABLE comments DROP COLUMN IF EXISTS ParentCommentID;
@@ -0,0 +1,8 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS ParentCommentID INTEGER REFERENCES comments(CommentID) ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS Depth SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS ReplyCount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS IsDeleted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS DeletedAt TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_comments_post_toplevel ON comments (PostID, CreatedAt, CommentID) WHERE ParentCommentID IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments (ParentCommentID, CreatedAt, CommentID);
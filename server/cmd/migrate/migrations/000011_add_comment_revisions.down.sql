@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS comment_revisions;
+ALTER TABLE users DROP COLUMN IF EXISTS IsModerator;
+DROP INDEX IF EXISTS idx_comments_needs_review;
+ALTER TABLE comments DROP COLUMN IF EXISTS NeedsReview;
+ALTER TABLE comments DROP COLUMN IF EXISTS Mood;
+ALTER TABLE comments DROP COLUMN IF EXISTS EditedAt;
@@ -0,0 +1,18 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS EditedAt TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS Mood VARCHAR(50);
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS NeedsReview BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_comments_needs_review ON comments (CreatedAt DESC) WHERE NeedsReview = TRUE;
+
+-- Moderator sementara ditandai langsung di tabel users, merekalah yang melihat antrian komentar needsreview
+ALTER TABLE users ADD COLUMN IF NOT EXISTS IsModerator BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+	RevisionID SERIAL PRIMARY KEY,
+	CommentID INTEGER NOT NULL REFERENCES comments(CommentID) ON DELETE CASCADE,
+	Content TEXT NOT NULL,
+	Mood VARCHAR(50),
+	EditedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment ON comment_revisions (CommentID, EditedAt DESC);
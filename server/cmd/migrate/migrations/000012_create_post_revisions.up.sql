@@ -0,0 +1,13 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS DeclaredMood VARCHAR(50);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS EditedAt TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+	RevisionID SERIAL PRIMARY KEY,
+	PostID INTEGER NOT NULL REFERENCES posts(PostID) ON DELETE CASCADE,
+	Content TEXT NOT NULL,
+	Mood VARCHAR(50),
+	DeclaredMood VARCHAR(50),
+	CreatedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post ON post_revisions (PostID, CreatedAt DESC);
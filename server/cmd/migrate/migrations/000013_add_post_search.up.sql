@@ -0,0 +1,15 @@
+-- Konfigurasi "indonesian" baru ada di Postgres versi yang lebih baru, jadi kalau belum ada pakai salinan "simple" (tanpa stemming)
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'indonesian') THEN
+		CREATE TEXT SEARCH CONFIGURATION public.indonesian (COPY = pg_catalog.simple);
+	END IF;
+END
+$$;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS SearchVector tsvector GENERATED ALWAYS AS (
+	to_tsvector('english'::regconfig, coalesce(Content, '')) || to_tsvector('indonesian'::regconfig, coalesce(Content, ''))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_posts_search_vector ON posts USING GIN (SearchVector);
+CREATE INDEX IF NOT EXISTS idx_posts_mood ON posts (Mood);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tag_resources;
+DROP TABLE IF EXISTS trending_tags;
+DROP TABLE IF EXISTS tag_follows;
+DROP TABLE IF EXISTS post_tags;
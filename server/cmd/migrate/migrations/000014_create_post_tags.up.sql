@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS post_tags (
+	PostID INTEGER NOT NULL REFERENCES posts(PostID) ON DELETE CASCADE,
+	Tag VARCHAR(50) NOT NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (PostID, Tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags (Tag, CreatedAt DESC);
+
+CREATE TABLE IF NOT EXISTS tag_follows (
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	Tag VARCHAR(50) NOT NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (UserID, Tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tag_follows_tag ON tag_follows (Tag);
+
+-- Diisi ulang secara berkala oleh background job, bukan dihitung saat request
+CREATE TABLE IF NOT EXISTS trending_tags (
+	Tag VARCHAR(50) PRIMARY KEY,
+	PostCount INTEGER NOT NULL DEFAULT 0,
+	ComputedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tag_resources (
+	ResourceID SERIAL PRIMARY KEY,
+	Tag VARCHAR(50) NOT NULL,
+	Title VARCHAR(200) NOT NULL,
+	URL TEXT NOT NULL,
+	Description TEXT,
+	UNIQUE (Tag, URL)
+);
+
+INSERT INTO tag_resources (Tag, Title, URL, Description) VALUES
+	('anxiety', 'WHO: Anxiety disorders', 'https://www.who.int/news-room/fact-sheets/detail/anxiety-disorders', 'Gejala, penyebab, dan pilihan penanganan gangguan kecemasan.'),
+	('anxiety', 'NIMH: Anxiety Disorders', 'https://www.nimh.nih.gov/health/topics/anxiety-disorders', 'Overview of anxiety disorders, treatments, and ways to find help.'),
+	('depression', 'WHO: Depressive disorder', 'https://www.who.int/news-room/fact-sheets/detail/depression', 'Gejala depresi dan kapan harus mencari bantuan profesional.'),
+	('depression', 'NIMH: Depression', 'https://www.nimh.nih.gov/health/topics/depression', 'Signs, symptoms, and treatment options for depression.'),
+	('stress', 'WHO: Stress', 'https://www.who.int/news-room/questions-and-answers/item/stress', 'Apa itu stres dan cara sederhana untuk mengelolanya.'),
+	('suicide', 'WHO: Suicide', 'https://www.who.int/news-room/fact-sheets/detail/suicide', 'Kalau kamu dalam bahaya, segera hubungi 112 atau layanan darurat terdekat.'),
+	('mentalhealth', 'WHO: Mental health', 'https://www.who.int/news-room/fact-sheets/detail/mental-health-strengthening-our-response', 'Pengantar tentang kesehatan mental dan cara menjaganya.')
+ON CONFLICT (Tag, URL) DO NOTHING;
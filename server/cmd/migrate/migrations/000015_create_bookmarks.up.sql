@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+	CollectionID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	Name VARCHAR(100) NOT NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	UpdatedAt TIMESTAMP DEFAULT NOW(),
+	UNIQUE (UserID, Name)
+);
+
+-- Bookmark ikut terhapus kalau postingannya dihapus (ON DELETE CASCADE)
+CREATE TABLE IF NOT EXISTS bookmarks (
+	CollectionID INTEGER NOT NULL REFERENCES bookmark_collections(CollectionID) ON DELETE CASCADE,
+	PostID INTEGER NOT NULL REFERENCES posts(PostID) ON DELETE CASCADE,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (CollectionID, PostID)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_collection_createdat ON bookmarks (CollectionID, CreatedAt DESC, PostID DESC);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_postid ON bookmarks (PostID);
@@ -0,0 +1,19 @@
+-- PostID masih NULL selama gambar baru di-upload dan belum dipasang ke postingan.
+-- Yang terlalu lama tidak dipakai dibersihkan oleh background job (termasuk file-nya di storage).
+CREATE TABLE IF NOT EXISTS post_media (
+	MediaID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	PostID INTEGER REFERENCES posts(PostID) ON DELETE CASCADE,
+	Position INTEGER NOT NULL DEFAULT 0,
+	StorageKey TEXT NOT NULL,
+	ThumbnailKey TEXT NOT NULL,
+	ContentType VARCHAR(50) NOT NULL,
+	Width INTEGER NOT NULL,
+	Height INTEGER NOT NULL,
+	Blurhash VARCHAR(64) NOT NULL,
+	SizeBytes INTEGER NOT NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_media_postid ON post_media (PostID, Position);
+CREATE INDEX IF NOT EXISTS idx_post_media_pending ON post_media (CreatedAt) WHERE PostID IS NULL;
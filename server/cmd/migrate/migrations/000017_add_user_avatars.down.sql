@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ProfileUrl VARCHAR(255);
+ALTER TABLE users DROP COLUMN IF EXISTS AvatarKey;
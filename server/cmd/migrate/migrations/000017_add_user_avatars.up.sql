@@ -0,0 +1,4 @@
+-- Avatar sekarang di-upload lewat server dan disimpan di media storage, URL eksternal sembarang tidak diterima lagi.
+-- AvatarKey adalah prefix key di storage, setiap ukuran disimpan sebagai <AvatarKey>_<ukuran>.jpg.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS AvatarKey VARCHAR(255);
+ALTER TABLE users DROP COLUMN IF EXISTS ProfileUrl;
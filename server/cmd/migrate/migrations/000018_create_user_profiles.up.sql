@@ -0,0 +1,20 @@
+-- Profil tambahan user, barisnya baru dibuat waktu user pertama kali mengubah profilnya.
+-- Default visibility di sini harus sama dengan entity.DefaultUserProfile.
+CREATE TABLE IF NOT EXISTS user_profiles (
+	UserID INTEGER PRIMARY KEY REFERENCES users(UserID) ON DELETE CASCADE,
+	Bio VARCHAR(300) NOT NULL DEFAULT '',
+	Pronouns VARCHAR(30) NOT NULL DEFAULT '',
+	Location VARCHAR(100) NOT NULL DEFAULT '',
+	Interests TEXT[] NOT NULL DEFAULT '{}',
+	SupportSeeking TEXT[] NOT NULL DEFAULT '{}',
+	SupportOffering TEXT[] NOT NULL DEFAULT '{}',
+	Language VARCHAR(10) NOT NULL DEFAULT '',
+	BioVisibility VARCHAR(10) NOT NULL DEFAULT 'public',
+	PronounsVisibility VARCHAR(10) NOT NULL DEFAULT 'public',
+	LocationVisibility VARCHAR(10) NOT NULL DEFAULT 'friends',
+	InterestsVisibility VARCHAR(10) NOT NULL DEFAULT 'public',
+	SupportSeekingVisibility VARCHAR(10) NOT NULL DEFAULT 'friends',
+	SupportOfferingVisibility VARCHAR(10) NOT NULL DEFAULT 'public',
+	LanguageVisibility VARCHAR(10) NOT NULL DEFAULT 'public',
+	UpdatedAt TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,19 @@
+-- Blokir berlaku dua arah (kedua user tidak bisa saling lihat/berinteraksi),
+-- mute cuma menyembunyikan konten user yang di-mute dari feed/list milik user yang me-mute.
+CREATE TABLE IF NOT EXISTS user_blocks (
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	BlockedUserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (UserID, BlockedUserID),
+	CHECK (UserID <> BlockedUserID)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blockeduserid ON user_blocks (BlockedUserID);
+
+CREATE TABLE IF NOT EXISTS user_mutes (
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	MutedUserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (UserID, MutedUserID),
+	CHECK (UserID <> MutedUserID)
+);
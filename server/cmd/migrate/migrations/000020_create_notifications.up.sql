@@ -0,0 +1,13 @@
+-- Notifikasi di dalam aplikasi (misalnya laporan yang sudah ditangani moderator).
+-- ReferenceID menunjuk ke data yang terkait tergantung Type-nya, misalnya ReportID.
+CREATE TABLE IF NOT EXISTS notifications (
+	NotificationID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	Type VARCHAR(50) NOT NULL,
+	Message TEXT NOT NULL,
+	ReferenceID INTEGER,
+	ReadAt TIMESTAMP,
+	CreatedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_createdat ON notifications (UserID, CreatedAt DESC, NotificationID DESC);
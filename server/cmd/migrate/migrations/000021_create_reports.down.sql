@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS moderation_actions;
+DROP TABLE IF EXISTS reports;
+ALTER TABLE comments DROP COLUMN IF EXISTS HiddenAt;
+ALTER TABLE posts DROP COLUMN IF EXISTS HiddenAt;
+ALTER TABLE users DROP COLUMN IF EXISTS SuspendedUntil;
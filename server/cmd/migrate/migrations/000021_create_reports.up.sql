@@ -0,0 +1,44 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS SuspendedUntil TIMESTAMP;
+
+-- Konten yang disembunyikan (otomatis karena banyak dilaporkan, atau oleh moderator) cuma bisa dilihat pemiliknya
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS HiddenAt TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS HiddenAt TIMESTAMP;
+
+-- TargetID tidak pakai foreign key karena bisa menunjuk ke posts, comments, messages, atau users (tergantung TargetType),
+-- dan laporannya harus tetap ada walaupun kontennya sudah dihapus. TargetOwnerID dicatat waktu laporan dibuat.
+CREATE TABLE IF NOT EXISTS reports (
+	ReportID SERIAL PRIMARY KEY,
+	ReporterID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	TargetType VARCHAR(20) NOT NULL CHECK (TargetType IN ('post', 'comment', 'message', 'user')),
+	TargetID INTEGER NOT NULL,
+	TargetOwnerID INTEGER REFERENCES users(UserID) ON DELETE SET NULL,
+	Reason VARCHAR(30) NOT NULL,
+	Details TEXT NOT NULL DEFAULT '',
+	Status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (Status IN ('open', 'claimed', 'resolved')),
+	ClaimedBy INTEGER REFERENCES users(UserID) ON DELETE SET NULL,
+	ClaimedAt TIMESTAMP,
+	Resolution VARCHAR(20),
+	ResolutionNote TEXT,
+	ResolvedBy INTEGER REFERENCES users(UserID) ON DELETE SET NULL,
+	ResolvedAt TIMESTAMP,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	UNIQUE (ReporterID, TargetType, TargetID)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_status_createdat ON reports (Status, CreatedAt, ReportID);
+CREATE INDEX IF NOT EXISTS idx_reports_target ON reports (TargetType, TargetID) WHERE Status <> 'resolved';
+
+-- Catatan semua tindakan moderator, hanya ditambah (tidak pernah di-update/dihapus).
+-- ModeratorID NULL berarti tindakan otomatis oleh sistem.
+CREATE TABLE IF NOT EXISTS moderation_actions (
+	ActionID SERIAL PRIMARY KEY,
+	ModeratorID INTEGER REFERENCES users(UserID) ON DELETE SET NULL,
+	ReportID INTEGER REFERENCES reports(ReportID) ON DELETE SET NULL,
+	Action VARCHAR(20) NOT NULL,
+	TargetType VARCHAR(20) NOT NULL,
+	TargetID INTEGER NOT NULL,
+	Note TEXT NOT NULL DEFAULT '',
+	CreatedAt TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_createdat ON moderation_actions (CreatedAt DESC, ActionID DESC);
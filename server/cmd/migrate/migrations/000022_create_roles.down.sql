@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS IsModerator BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Admin juga bisa memoderasi, jadi dua-duanya dikembalikan sebagai moderator
+UPDATE users SET IsModerator = TRUE
+WHERE UserID IN (
+	SELECT ur.UserID FROM user_roles ur
+	JOIN roles r ON r.RoleID = ur.RoleID
+	WHERE r.Name IN ('moderator', 'admin')
+);
+
+ALTER TABLE users DROP COLUMN IF EXISTS BanReason;
+ALTER TABLE users DROP COLUMN IF EXISTS BannedAt;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,66 @@
+-- Role dan permission disimpan di database supaya bisa diatur lewat /api/admin tanpa deploy ulang
+CREATE TABLE IF NOT EXISTS roles (
+	RoleID SERIAL PRIMARY KEY,
+	Name VARCHAR(30) NOT NULL UNIQUE,
+	Description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+	PermissionID SERIAL PRIMARY KEY,
+	Name VARCHAR(50) NOT NULL UNIQUE,
+	Description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+	RoleID INTEGER NOT NULL REFERENCES roles(RoleID) ON DELETE CASCADE,
+	PermissionID INTEGER NOT NULL REFERENCES permissions(PermissionID) ON DELETE CASCADE,
+	PRIMARY KEY (RoleID, PermissionID)
+);
+
+-- AssignedBy NULL berarti role diberikan lewat CLI bootstrap atau migrasi
+CREATE TABLE IF NOT EXISTS user_roles (
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	RoleID INTEGER NOT NULL REFERENCES roles(RoleID) ON DELETE CASCADE,
+	AssignedBy INTEGER REFERENCES users(UserID) ON DELETE SET NULL,
+	CreatedAt TIMESTAMP DEFAULT NOW(),
+	PRIMARY KEY (UserID, RoleID)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_roleid ON user_roles (RoleID);
+
+-- Ban berlaku sampai dicabut admin, beda dengan suspend yang punya batas waktu
+ALTER TABLE users ADD COLUMN IF NOT EXISTS BannedAt TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS BanReason TEXT;
+
+INSERT INTO roles (Name, Description) VALUES
+	('moderator', 'Handles the report queue'),
+	('admin', 'Manages users and roles')
+ON CONFLICT (Name) DO NOTHING;
+
+INSERT INTO permissions (Name, Description) VALUES
+	('reports:moderate', 'View, claim and resolve reports'),
+	('users:read', 'View users and their account status'),
+	('users:suspend', 'Suspend and unsuspend users'),
+	('users:ban', 'Ban and unban users'),
+	('users:reset', 'Reset user passwords'),
+	('roles:assign', 'Assign and revoke roles')
+ON CONFLICT (Name) DO NOTHING;
+
+INSERT INTO role_permissions (RoleID, PermissionID)
+SELECT r.RoleID, p.PermissionID
+FROM roles r
+JOIN permissions p ON (r.Name = 'admin') OR (r.Name = 'moderator' AND p.Name IN ('reports:moderate', 'users:read'))
+ON CONFLICT DO NOTHING;
+
+-- Moderator dari kolom lama users.IsModerator dipindah ke role moderator
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'ismoderator') THEN
+		INSERT INTO user_roles (UserID, RoleID)
+		SELECT u.UserID, r.RoleID FROM users u, roles r
+		WHERE u.IsModerator AND r.Name = 'moderator'
+		ON CONFLICT DO NOTHING;
+	END IF;
+END $$;
+
+ALTER TABLE users DROP COLUMN IF EXISTS IsModerator;
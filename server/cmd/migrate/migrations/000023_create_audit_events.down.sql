@@ -0,0 +1,4 @@
+DELETE FROM permissions WHERE Name = 'audit:read';
+DROP TABLE IF EXISTS audit_events_archive;
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS prevent_audit_event_changes();
@@ -0,0 +1,65 @@
+-- Log audit hanya ditambah. ActorID dan TargetID sengaja tanpa foreign key supaya barisnya tidak ikut berubah
+-- (ON DELETE SET NULL) atau terhapus waktu user/kontennya dihapus, karena itu akan merusak rantai hash-nya.
+CREATE TABLE IF NOT EXISTS audit_events (
+	EventID BIGSERIAL PRIMARY KEY,
+	ActorID INTEGER,
+	Action VARCHAR(50) NOT NULL,
+	TargetType VARCHAR(20) NOT NULL DEFAULT '',
+	TargetID INTEGER,
+	Metadata TEXT NOT NULL DEFAULT '{}',
+	IPAddress VARCHAR(45) NOT NULL DEFAULT '',
+	PrevHash CHAR(64) NOT NULL,
+	Hash CHAR(64) NOT NULL UNIQUE,
+	CreatedAt TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_createdat ON audit_events (CreatedAt DESC, EventID DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (ActorID, CreatedAt DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (TargetType, TargetID, CreatedAt DESC);
+
+-- Event yang sudah lewat masa retensi dipindah ke sini oleh job arsip, rantai hash-nya tetap nyambung
+CREATE TABLE IF NOT EXISTS audit_events_archive (
+	EventID BIGINT PRIMARY KEY,
+	ActorID INTEGER,
+	Action VARCHAR(50) NOT NULL,
+	TargetType VARCHAR(20) NOT NULL DEFAULT '',
+	TargetID INTEGER,
+	Metadata TEXT NOT NULL DEFAULT '{}',
+	IPAddress VARCHAR(45) NOT NULL DEFAULT '',
+	PrevHash CHAR(64) NOT NULL,
+	Hash CHAR(64) NOT NULL UNIQUE,
+	CreatedAt TIMESTAMP NOT NULL,
+	ArchivedAt TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_archive_createdat ON audit_events_archive (CreatedAt);
+
+-- UPDATE selalu ditolak. DELETE cuma boleh dari job arsip, yang menyalakan audit.allow_archive di transaksinya sendiri.
+CREATE OR REPLACE FUNCTION prevent_audit_event_changes() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' AND current_setting('audit.allow_archive', true) = 'on' THEN
+		RETURN OLD;
+	END IF;
+	RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+	BEFORE UPDATE OR DELETE ON audit_events
+	FOR EACH ROW EXECUTE FUNCTION prevent_audit_event_changes();
+
+DROP TRIGGER IF EXISTS audit_events_archive_append_only ON audit_events_archive;
+CREATE TRIGGER audit_events_archive_append_only
+	BEFORE UPDATE OR DELETE ON audit_events_archive
+	FOR EACH ROW EXECUTE FUNCTION prevent_audit_event_changes();
+
+INSERT INTO permissions (Name, Description) VALUES
+	('audit:read', 'Query and verify the audit log')
+ON CONFLICT (Name) DO NOTHING;
+
+INSERT INTO role_permissions (RoleID, PermissionID)
+SELECT r.RoleID, p.PermissionID
+FROM roles r, permissions p
+WHERE r.Name = 'admin' AND p.Name = 'audit:read'
+ON CONFLICT DO NOTHING;
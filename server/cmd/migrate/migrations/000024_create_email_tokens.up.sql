@@ -0,0 +1,25 @@
+-- EmailVerifiedAt NULL berarti email belum diverifikasi.
+-- Akun yang sudah ada sebelum fitur ini dianggap terverifikasi supaya tidak tiba-tiba kehilangan akses chat.
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'emailverifiedat') THEN
+		ALTER TABLE users ADD COLUMN EmailVerifiedAt TIMESTAMP;
+		UPDATE users SET EmailVerifiedAt = CreatedAt;
+	END IF;
+END $$;
+
+-- Token verifikasi email dan reset password. Yang disimpan cuma hash-nya, token aslinya hanya ada di email.
+-- Email dicatat supaya token verifikasi tidak berlaku lagi kalau user sudah ganti email.
+CREATE TABLE IF NOT EXISTS email_tokens (
+	TokenID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	Purpose VARCHAR(30) NOT NULL,
+	Email VARCHAR(100) NOT NULL,
+	TokenHash CHAR(64) NOT NULL UNIQUE,
+	ExpiresAt TIMESTAMP NOT NULL,
+	UsedAt TIMESTAMP,
+	CreatedAt TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_tokens_user_purpose ON email_tokens (UserID, Purpose) WHERE UsedAt IS NULL;
+CREATE INDEX IF NOT EXISTS idx_email_tokens_expiresat ON email_tokens (ExpiresAt);
@@ -0,0 +1,3 @@
+ALTER TABLE roles DROP COLUMN IF EXISTS RequireMFA;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
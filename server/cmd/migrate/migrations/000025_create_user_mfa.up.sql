@@ -0,0 +1,23 @@
+-- Secret TOTP disimpan terenkripsi (AES-GCM, kunci dari MFA_SECRET_KEY).
+-- EnabledAt NULL berarti user baru mulai enroll dan belum memasukkan kode pertamanya.
+-- LastUsedStep periode 30 detik terakhir yang kodenya sudah dipakai, supaya kode yang sama tidak bisa dipakai ulang.
+CREATE TABLE IF NOT EXISTS user_mfa (
+	UserID INTEGER PRIMARY KEY REFERENCES users(UserID) ON DELETE CASCADE,
+	Secret BYTEA NOT NULL,
+	EnabledAt TIMESTAMP,
+	LastUsedStep BIGINT NOT NULL DEFAULT 0,
+	CreatedAt TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Recovery code sekali pakai, yang disimpan cuma hash-nya
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+	CodeID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	CodeHash CHAR(64) NOT NULL,
+	UsedAt TIMESTAMP,
+	CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (UserID, CodeHash)
+);
+
+-- Role dengan RequireMFA = TRUE hanya memberi permission-nya ke user yang sudah mengaktifkan 2FA
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS RequireMFA BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS data_exports;
+DROP INDEX IF EXISTS idx_users_deletion_scheduled;
+ALTER TABLE users DROP COLUMN IF EXISTS DeletedAt;
+ALTER TABLE users DROP COLUMN IF EXISTS DeletionScheduledAt;
@@ -0,0 +1,24 @@
+-- DeletionScheduledAt diisi waktu user minta akunnya dihapus, akunnya baru benar-benar dihapus setelah waktu ini lewat.
+-- Akun yang sudah dihapus tidak dibuang dari tabel users: barisnya dianonimkan (DeletedAt terisi) supaya pesan
+-- yang pernah dikirim ke user lain tetap ada di inbox mereka, dengan pengirim "Deleted user".
+ALTER TABLE users ADD COLUMN IF NOT EXISTS DeletionScheduledAt TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS DeletedAt TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled ON users (DeletionScheduledAt) WHERE DeletionScheduledAt IS NOT NULL AND DeletedAt IS NULL;
+
+-- Arsip ekspor data user (zip berisi JSON dan media), dibuat di background lalu disimpan di media storage
+CREATE TABLE IF NOT EXISTS data_exports (
+	ExportID SERIAL PRIMARY KEY,
+	UserID INTEGER NOT NULL REFERENCES users(UserID) ON DELETE CASCADE,
+	Status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (Status IN ('pending', 'processing', 'ready', 'failed')),
+	StorageKey TEXT,
+	SizeBytes BIGINT,
+	Error TEXT NOT NULL DEFAULT '',
+	StartedAt TIMESTAMP,
+	CompletedAt TIMESTAMP,
+	ExpiresAt TIMESTAMP,
+	CreatedAt TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user ON data_exports (UserID, CreatedAt DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports (Status, CreatedAt);
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_friends_outgoing;
+DROP INDEX IF EXISTS idx_friends_incoming;
+DROP INDEX IF EXISTS idx_friends_pair;
+ALTER TABLE IF EXISTS friends ADD COLUMN IF NOT EXISTS FriendStatus BOOLEAN DEFAULT FALSE;
+
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT 1 FROM information_schema.columns WHERE table_name = 'friends' AND column_name = 'status'
+	) THEN
+		DELETE FROM friends WHERE Status NOT IN ('pending', 'accepted');
+		UPDATE friends SET FriendStatus = (Status = 'accepted');
+	END IF;
+END $$;
+
+ALTER TABLE IF EXISTS friends DROP CONSTRAINT IF EXISTS friends_status_check;
+ALTER TABLE IF EXISTS friends DROP COLUMN IF EXISTS RespondedAt;
+ALTER TABLE IF EXISTS friends DROP COLUMN IF EXISTS Status;
@@ -0,0 +1,37 @@
+-- Status pertemanan yang dulu cuma boolean FriendStatus (TRUE = accepted, FALSE = pending) diganti enum Status,
+-- supaya permintaan yang ditolak, dibatalkan, atau putus karena blokir bisa dibedakan.
+-- RespondedAt diisi waktu statusnya berubah dari pending, dipakai untuk cooldown setelah permintaan ditolak.
+ALTER TABLE friends ADD COLUMN IF NOT EXISTS Status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE friends ADD COLUMN IF NOT EXISTS RespondedAt TIMESTAMP;
+
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT 1 FROM information_schema.columns WHERE table_name = 'friends' AND column_name = 'friendstatus'
+	) THEN
+		UPDATE friends SET Status = CASE WHEN FriendStatus THEN 'accepted' ELSE 'pending' END;
+		ALTER TABLE friends DROP COLUMN FriendStatus;
+	END IF;
+
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'friends_status_check') THEN
+		ALTER TABLE friends ADD CONSTRAINT friends_status_check
+			CHECK (Status IN ('pending', 'accepted', 'declined', 'cancelled', 'blocked'));
+	END IF;
+END $$;
+
+-- Satu pasangan user cuma boleh punya satu baris, apa pun arahnya. Kalau sudah terlanjur dobel (A->B dan B->A),
+-- yang dipertahankan yang sudah accepted, selain itu yang paling lama.
+DELETE FROM friends WHERE FriendID IN (
+	SELECT FriendID FROM (
+		SELECT FriendID, ROW_NUMBER() OVER (
+			PARTITION BY LEAST(UserID, FriendUserID), GREATEST(UserID, FriendUserID)
+			ORDER BY (Status = 'accepted') DESC, CreatedAt, FriendID
+		) AS rn
+		FROM friends
+	) AS ranked
+	WHERE rn > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friends_pair ON friends (LEAST(UserID, FriendUserID), GREATEST(UserID, FriendUserID));
+CREATE INDEX IF NOT EXISTS idx_friends_incoming ON friends (FriendUserID, Status, CreatedAt DESC);
+CREATE INDEX IF NOT EXISTS idx_friends_outgoing ON friends (UserID, Status, CreatedAt DESC);
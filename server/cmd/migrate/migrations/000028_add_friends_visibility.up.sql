@@ -0,0 +1,4 @@
+-- Siapa saja yang boleh melihat daftar teman user, termasuk lewat mutual friend dan "people you may know".
+-- Defaultnya public karena sebelum kolom ini ada daftar teman memang bisa dilihat siapa saja.
+-- Default di sini harus sama dengan entity.DefaultUserProfile.
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS FriendsVisibility VARCHAR(10) NOT NULL DEFAULT 'public';
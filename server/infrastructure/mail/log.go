@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogMailer dipakai untuk development: email tidak benar-benar dikirim, cukup ditulis ke log
+// (dan ke file .eml kalau Dir diisi, supaya link verifikasinya gampang dibuka)
+type LogMailer struct {
+	From string
+	Dir  string
+}
+
+func NewLogMailer(config Config) *LogMailer {
+	return &LogMailer{
+		From: config.From,
+		Dir:  config.LogDir,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	log.Printf("LogMailer: to=%s subject=%q\n%s", message.To, message.Subject, message.Text)
+	if m.Dir == "" {
+		return nil
+	}
+
+	data, err := buildMIME(m.From, message)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), filepath.Base(message.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), data, 0o644)
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"os"
+)
+
+// Message satu email yang siap dikirim. Text selalu diisi, HTML opsional.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer mengirim email. SMTPMailer dipakai di production, LogMailer untuk development
+// dan CaptureMailer untuk testing.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+type Config struct {
+	Driver       string // "log" (default), "smtp" atau "capture"
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	LogDir       string // kalau diisi, LogMailer juga menyimpan setiap email sebagai file .eml
+}
+
+func NewMailer() Mailer {
+	// step 1: ambil konfigurasi dari environment variable (.env sudah di-load waktu konek database)
+	config := Config{
+		Driver:       os.Getenv("MAIL_DRIVER"),
+		From:         os.Getenv("MAIL_FROM"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		LogDir:       os.Getenv("MAIL_LOG_DIR"),
+	}
+	if config.From == "" {
+		config.From = "Mood Bridge <no-reply@moodbridge.local>"
+	}
+
+	// step 2: pilih driver-nya
+	switch config.Driver {
+	case "smtp":
+		if config.SMTPHost == "" {
+			log.Fatal("SMTP mailer requires SMTP_HOST")
+		}
+		return NewSMTPMailer(config)
+	case "", "log":
+		return NewLogMailer(config)
+	case "capture":
+		return NewCaptureMailer()
+	default:
+		log.Fatalf("Unknown MAIL_DRIVER: %s", config.Driver)
+		return nil
+	}
+}
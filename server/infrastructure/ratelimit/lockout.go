@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LockoutPolicy: setelah Threshold kegagalan dalam FailureWindow, key di-lock selama BaseDuration.
+// Setiap kegagalan berikutnya setelah lock selesai menggandakan durasinya sampai maksimal MaxDuration.
+type LockoutPolicy struct {
+	Threshold     int
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+	FailureWindow time.Duration
+}
+
+var DefaultLoginLockout = LockoutPolicy{
+	Threshold:     5,
+	BaseDuration:  time.Minute,
+	MaxDuration:   time.Hour,
+	FailureWindow: 24 * time.Hour,
+}
+
+// Lockout mengunci sebuah identitas (misalnya username) setelah gagal berulang kali
+type Lockout struct {
+	Store  Store
+	Name   string
+	Policy LockoutPolicy
+}
+
+func NewLockout(store Store, name string, policy LockoutPolicy) *Lockout {
+	return &Lockout{
+		Store:  store,
+		Name:   name,
+		Policy: policy,
+	}
+}
+
+func (l *Lockout) failuresKey(id string) string {
+	return fmt.Sprintf("lockout:%s:%s:failures", l.Name, id)
+}
+
+func (l *Lockout) lockKey(id string) string {
+	return fmt.Sprintf("lockout:%s:%s:locked", l.Name, id)
+}
+
+// LockedFor mengembalikan sisa waktu lock, 0 kalau tidak sedang di-lock
+func (l *Lockout) LockedFor(ctx context.Context, id string) (time.Duration, error) {
+	return l.Store.LockedFor(ctx, l.lockKey(id))
+}
+
+// Fail mencatat satu kegagalan dan mengembalikan durasi lock kalau kegagalan ini memicu lock
+func (l *Lockout) Fail(ctx context.Context, id string) (time.Duration, error) {
+	failures, err := l.Store.Increment(ctx, l.failuresKey(id), l.Policy.FailureWindow)
+	if err != nil {
+		return 0, err
+	}
+	if failures < l.Policy.Threshold {
+		return 0, nil
+	}
+
+	// durasi lock naik dua kali lipat untuk setiap kegagalan di atas threshold
+	duration := l.Policy.BaseDuration
+	for i := l.Policy.Threshold; i < failures && duration < l.Policy.MaxDuration; i++ {
+		duration *= 2
+	}
+	duration = min(duration, l.Policy.MaxDuration)
+
+	if err := l.Store.Lock(ctx, l.lockKey(id), duration); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// Reset menghapus riwayat kegagalan, dipanggil setelah berhasil
+func (l *Lockout) Reset(ctx context.Context, id string) error {
+	return l.Store.Reset(ctx, l.failuresKey(id), l.lockKey(id))
+}
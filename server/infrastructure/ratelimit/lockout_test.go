@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock dipakai MemoryStore.Now supaya test bisa memajukan waktu tanpa sleep
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.Now = clock.Now
+	return store, clock
+}
+
+func TestLockoutEscalation(t *testing.T) {
+	policy := LockoutPolicy{
+		Threshold:     3,
+		BaseDuration:  time.Minute,
+		MaxDuration:   5 * time.Minute,
+		FailureWindow: time.Hour,
+	}
+
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{name: "below threshold", failures: 2, want: 0},
+		{name: "reaching threshold locks for base duration", failures: 3, want: time.Minute},
+		{name: "one over threshold doubles", failures: 4, want: 2 * time.Minute},
+		{name: "two over threshold doubles again", failures: 5, want: 4 * time.Minute},
+		{name: "capped at max duration", failures: 6, want: 5 * time.Minute},
+		{name: "stays at max duration", failures: 10, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, clock := newTestStore()
+			lockout := NewLockout(store, "login", policy)
+
+			var got time.Duration
+			for i := 0; i < tt.failures; i++ {
+				var err error
+				if got, err = lockout.Fail(ctx, "alice"); err != nil {
+					t.Fatalf("Fail() error = %v", err)
+				}
+				// tunggu lock sebelumnya selesai, seperti user yang mencoba lagi setelah Retry-After
+				clock.Advance(got)
+			}
+			if got != tt.want {
+				t.Fatalf("lock after %d failures = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutLockedFor(t *testing.T) {
+	policy := LockoutPolicy{Threshold: 2, BaseDuration: time.Minute, MaxDuration: time.Hour, FailureWindow: time.Hour}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		reset   bool
+		want    time.Duration
+	}{
+		{name: "just locked", elapsed: 0, want: time.Minute},
+		{name: "partway through lock", elapsed: 40 * time.Second, want: 20 * time.Second},
+		{name: "lock expired", elapsed: time.Minute, want: 0},
+		{name: "reset clears lock", elapsed: 0, reset: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, clock := newTestStore()
+			lockout := NewLockout(store, "login", policy)
+
+			for i := 0; i < policy.Threshold; i++ {
+				if _, err := lockout.Fail(ctx, "alice"); err != nil {
+					t.Fatalf("Fail() error = %v", err)
+				}
+			}
+			if tt.reset {
+				if err := lockout.Reset(ctx, "alice"); err != nil {
+					t.Fatalf("Reset() error = %v", err)
+				}
+			}
+			clock.Advance(tt.elapsed)
+
+			got, err := lockout.LockedFor(ctx, "alice")
+			if err != nil {
+				t.Fatalf("LockedFor() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("LockedFor() = %v, want %v", got, tt.want)
+			}
+			if other, _ := lockout.LockedFor(ctx, "bob"); other != 0 {
+				t.Fatalf("LockedFor(bob) = %v, want 0", other)
+			}
+		})
+	}
+}
+
+func TestLockoutFailureWindowExpires(t *testing.T) {
+	ctx := context.Background()
+	store, clock := newTestStore()
+	lockout := NewLockout(store, "login", LockoutPolicy{Threshold: 2, BaseDuration: time.Minute, MaxDuration: time.Hour, FailureWindow: 10 * time.Minute})
+
+	if _, err := lockout.Fail(ctx, "alice"); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+
+	// kegagalan pertama sudah keluar dari window, jadi ini dihitung sebagai kegagalan pertama lagi
+	got, err := lockout.Fail(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if got != 0 {
+		t.Fatalf("Fail() after window = %v, want 0", got)
+	}
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore menyimpan state di memori proses. Dipakai untuk testing dan development dengan satu instance,
+// di production kuotanya tidak terbagi antar instance jadi pakai RedisStore.
+type MemoryStore struct {
+	// Now bisa diganti di test untuk mengatur waktu
+	Now func() time.Time
+
+	mu       sync.Mutex
+	hits     map[string][]time.Time
+	counters map[string]memoryEntry
+	locks    map[string]time.Time
+}
+
+type memoryEntry struct {
+	value     int
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Now:      time.Now,
+		hits:     make(map[string][]time.Time),
+		counters: make(map[string]memoryEntry),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryStore) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	if policy.Limit <= 0 {
+		return unlimited(policy), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// step 1: buang hit yang sudah keluar dari window
+	now := s.Now()
+	key = limitKey(policy, key)
+	hits := s.hits[key]
+	start := 0
+	for start < len(hits) && !hits[start].After(now.Add(-policy.Window)) {
+		start++
+	}
+	hits = hits[start:]
+
+	// step 2: catat hit baru kalau kuotanya masih ada
+	allowed := len(hits) < policy.Limit
+	if allowed {
+		hits = append(hits, now)
+	}
+	if len(hits) == 0 {
+		delete(s.hits, key)
+	} else {
+		s.hits[key] = hits
+	}
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     policy.Limit,
+		Remaining: max(policy.Limit-len(hits), 0),
+	}
+	if len(hits) > 0 {
+		result.ResetAfter = hits[len(hits)-1].Add(policy.Window).Sub(now)
+		if !allowed {
+			result.RetryAfter = hits[0].Add(policy.Window).Sub(now)
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	entry, ok := s.counters[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		entry = memoryEntry{expiresAt: now.Add(ttl)}
+	}
+	entry.value++
+	s.counters[key] = entry
+	return entry.value, nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = s.Now().Add(ttl)
+	return nil
+}
+
+func (s *MemoryStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.locks[key]
+	if !ok {
+		return 0, nil
+	}
+	remaining := until.Sub(s.Now())
+	if remaining <= 0 {
+		delete(s.locks, key)
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.counters, key)
+		delete(s.locks, key)
+	}
+	return nil
+}
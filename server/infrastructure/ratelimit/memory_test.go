@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreAllow(t *testing.T) {
+	policy := Policy{Name: "test", Limit: 2, Window: time.Minute}
+
+	type hit struct {
+		after time.Duration // jeda dari hit sebelumnya
+		want  Result
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		hits   []hit
+	}{
+		{
+			name:   "within quota",
+			policy: policy,
+			hits: []hit{
+				{want: Result{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: time.Minute}},
+				{after: 10 * time.Second, want: Result{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Minute}},
+			},
+		},
+		{
+			name:   "rejected hit reports retry after oldest hit leaves window",
+			policy: policy,
+			hits: []hit{
+				{want: Result{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: time.Minute}},
+				{after: 10 * time.Second, want: Result{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Minute}},
+				{after: 20 * time.Second, want: Result{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: 30 * time.Second, ResetAfter: 40 * time.Second}},
+			},
+		},
+		{
+			name:   "slot frees up once oldest hit leaves window",
+			policy: policy,
+			hits: []hit{
+				{want: Result{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: time.Minute}},
+				{after: 30 * time.Second, want: Result{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Minute}},
+				{after: 30 * time.Second, want: Result{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Minute}},
+			},
+		},
+		{
+			name:   "disabled policy is unlimited",
+			policy: Policy{Name: "off", Limit: 0, Window: time.Minute},
+			hits: []hit{
+				{want: Result{Allowed: true}},
+				{want: Result{Allowed: true}},
+				{want: Result{Allowed: true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, clock := newTestStore()
+
+			for i, h := range tt.hits {
+				clock.Advance(h.after)
+				got, err := store.Allow(ctx, "ip:127.0.0.1", tt.policy)
+				if err != nil {
+					t.Fatalf("hit %d: Allow() error = %v", i, err)
+				}
+				if got != h.want {
+					t.Fatalf("hit %d: Allow() = %+v, want %+v", i, got, h.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreAllowSeparatesKeysAndPolicies(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore()
+	login := Policy{Name: PolicyLogin, Limit: 1, Window: time.Minute}
+	post := Policy{Name: PolicyPost, Limit: 1, Window: time.Minute}
+
+	if got, _ := store.Allow(ctx, "ip:1", login); !got.Allowed {
+		t.Fatalf("first login hit rejected")
+	}
+	if got, _ := store.Allow(ctx, "ip:1", login); got.Allowed {
+		t.Fatalf("second login hit allowed")
+	}
+	if got, _ := store.Allow(ctx, "ip:2", login); !got.Allowed {
+		t.Fatalf("other key shares the login quota")
+	}
+	if got, _ := store.Allow(ctx, "ip:1", post); !got.Allowed {
+		t.Fatalf("other policy shares the login quota")
+	}
+}
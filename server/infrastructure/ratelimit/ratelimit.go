@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Nama policy bawaan, dipakai sebagai bagian dari key supaya kuota tiap route terpisah
+const (
+	PolicyLogin    = "login"
+	PolicyRegister = "register"
+	PolicyPost     = "post"
+	PolicyComment  = "comment"
+	PolicyUpload   = "upload"
+	PolicyChat     = "chat"
+	PolicyEmail    = "email"  // kirim ulang email verifikasi dan minta reset password
+	PolicyExport   = "export" // minta arsip ekspor data, berat karena ikut mengambil semua media
+)
+
+// Policy membatasi maksimal Limit hit dalam satu sliding window sepanjang Window.
+// Limit <= 0 berarti tidak dibatasi.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// Result hasil pengecekan satu hit. RetryAfter hanya terisi kalau hit-nya ditolak,
+// ResetAfter adalah waktu sampai kuota kembali penuh.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// Store menyimpan state rate limit dan lockout. RedisStore dipakai di production supaya kuota
+// berlaku di semua instance server, MemoryStore untuk testing dan development.
+type Store interface {
+	// Allow mencatat satu hit untuk key di bawah policy, hit yang ditolak tidak ikut dihitung
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+	// Increment menaikkan counter, TTL di-set waktu counter pertama kali dibuat
+	Increment(ctx context.Context, key string, ttl time.Duration) (int, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	// LockedFor mengembalikan sisa waktu lock, 0 kalau key tidak sedang di-lock
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset menghapus counter dan lock milik key yang dipakai di Increment/Lock.
+	// Kuota Allow disimpan di bawah key policy-nya sendiri (limitKey), jadi tidak ikut di-reset.
+	Reset(ctx context.Context, keys ...string) error
+}
+
+// Policies berisi policy per nama, diambil lewat Get supaya nama yang tidak dikenal tetap punya Name
+type Policies map[string]Policy
+
+func (p Policies) Get(name string) Policy {
+	if policy, ok := p[name]; ok {
+		return policy
+	}
+	return Policy{Name: name}
+}
+
+var defaultPolicies = Policies{
+	PolicyLogin:    {Name: PolicyLogin, Limit: 10, Window: time.Minute},
+	PolicyRegister: {Name: PolicyRegister, Limit: 5, Window: time.Hour},
+	PolicyPost:     {Name: PolicyPost, Limit: 5, Window: time.Minute},
+	PolicyComment:  {Name: PolicyComment, Limit: 20, Window: time.Minute},
+	PolicyUpload:   {Name: PolicyUpload, Limit: 30, Window: 10 * time.Minute},
+	PolicyChat:     {Name: PolicyChat, Limit: 20, Window: 10 * time.Second},
+	PolicyEmail:    {Name: PolicyEmail, Limit: 5, Window: time.Hour},
+	PolicyExport:   {Name: PolicyExport, Limit: 3, Window: 24 * time.Hour},
+}
+
+// LoadPolicies mengambil policy bawaan yang bisa di-override lewat environment variable
+// RATE_LIMIT_<NAMA> dengan format "<limit>/<window>", misalnya RATE_LIMIT_LOGIN=10/1m. Limit 0 mematikan policy-nya.
+func LoadPolicies() Policies {
+	policies := make(Policies, len(defaultPolicies))
+	for name, policy := range defaultPolicies {
+		if value := os.Getenv("RATE_LIMIT_" + strings.ToUpper(name)); value != "" {
+			override, err := ParsePolicy(name, value)
+			if err != nil {
+				log.Printf("ratelimit: ignoring RATE_LIMIT_%s: %v", strings.ToUpper(name), err)
+			} else {
+				policy = override
+			}
+		}
+		policies[name] = policy
+	}
+	return policies
+}
+
+// ParsePolicy membaca format "<limit>/<window>", window pakai format time.ParseDuration
+func ParsePolicy(name, value string) (Policy, error) {
+	limitPart, windowPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("policy %q must look like <limit>/<window>", value)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(limitPart))
+	if err != nil || limit < 0 {
+		return Policy{}, fmt.Errorf("invalid limit %q", limitPart)
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(windowPart))
+	if err != nil || window <= 0 {
+		return Policy{}, fmt.Errorf("invalid window %q", windowPart)
+	}
+	return Policy{Name: name, Limit: limit, Window: window}, nil
+}
+
+// NewStore memilih backend lewat RATE_LIMIT_DRIVER: "redis" (default) atau "memory"
+func NewStore(redisClient *redis.Client) Store {
+	switch driver := os.Getenv("RATE_LIMIT_DRIVER"); driver {
+	case "", "redis":
+		return NewRedisStore(redisClient)
+	case "memory":
+		return NewMemoryStore()
+	default:
+		log.Fatalf("Unknown RATE_LIMIT_DRIVER: %s", driver)
+		return nil
+	}
+}
+
+func limitKey(policy Policy, key string) string {
+	return fmt.Sprintf("ratelimit:%s:%s", policy.Name, key)
+}
+
+// unlimited dipakai kalau policy-nya dimatikan
+func unlimited(policy Policy) Result {
+	return Result{Allowed: true, Limit: policy.Limit}
+}
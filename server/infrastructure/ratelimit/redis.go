@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript menyimpan setiap hit sebagai member sorted set dengan score waktu (ms).
+// Hit yang sudah keluar dari window dibuang dulu, lalu hit baru hanya dicatat kalau kuotanya masih ada.
+// Semuanya jalan atomik di Redis jadi aman dipakai banyak instance server sekaligus.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local newest = redis.call('ZRANGE', key, -1, -1, 'WITHSCORES')
+return {allowed, count, tonumber(oldest[2] or now), tonumber(newest[2] or now)}
+`)
+
+// incrementScript menaikkan counter dan hanya memasang TTL waktu counter baru dibuat,
+// jadi window-nya tidak ikut bergeser setiap kali counter naik
+var incrementScript = redis.NewScript(`
+local value = redis.call('INCR', KEYS[1])
+if value == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return value
+`)
+
+type RedisStore struct {
+	Client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{Client: client}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	if policy.Limit <= 0 {
+		return unlimited(policy), nil
+	}
+
+	now := time.Now().UnixMilli()
+	window := policy.Window.Milliseconds()
+	member := fmt.Sprintf("%d-%d", now, rand.Int63())
+	values, err := slidingWindowScript.Run(ctx, s.Client, []string{limitKey(policy, key)}, now, window, policy.Limit, member).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	allowed, count, oldest, newest := values[0] == 1, int(values[1]), values[2], values[3]
+
+	result := Result{
+		Allowed:    allowed,
+		Limit:      policy.Limit,
+		Remaining:  max(policy.Limit-count, 0),
+		ResetAfter: time.Duration(newest+window-now) * time.Millisecond,
+	}
+	if !allowed {
+		// slot berikutnya kosong waktu hit paling lama keluar dari window
+		result.RetryAfter = time.Duration(oldest+window-now) * time.Millisecond
+	}
+	return result, nil
+}
+
+func (s *RedisStore) Increment(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	value, err := incrementScript.Run(ctx, s.Client, []string{key}, ttl.Milliseconds()).Int()
+	if err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return s.Client.Set(ctx, key, 1, ttl).Err()
+}
+
+func (s *RedisStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.Client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL mengembalikan nilai negatif kalau key tidak ada
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RedisStore) Reset(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.Client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Storage bicara langsung ke API S3 (path-style, SigV4) supaya bisa dipakai dengan AWS S3 maupun
+// layanan yang kompatibel seperti MinIO atau Cloudflare R2 tanpa perlu SDK tambahan
+type S3Storage struct {
+	Endpoint  string // misalnya https://s3.ap-southeast-1.amazonaws.com
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+	client    *http.Client
+}
+
+func NewS3Storage(config Config) *S3Storage {
+	storage := &S3Storage{
+		Endpoint:  strings.TrimRight(config.S3Endpoint, "/"),
+		Bucket:    config.S3Bucket,
+		Region:    config.S3Region,
+		AccessKey: config.S3AccessKey,
+		SecretKey: config.S3SecretKey,
+		PublicURL: config.PublicURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	if storage.Region == "" {
+		storage.Region = "us-east-1"
+	}
+	if storage.PublicURL == "" {
+		storage.PublicURL = storage.Endpoint + "/" + storage.Bucket
+	}
+	return storage
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := s.do(ctx, http.MethodPut, key, data, contentType)
+	return err
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, key, nil, "")
+}
+
+// Delete di S3 sudah idempotent, object yang tidak ada tetap dijawab 204
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.do(ctx, http.MethodDelete, key, nil, "")
+	return err
+}
+
+func (s *S3Storage) URL(key string) string {
+	return strings.TrimRight(s.PublicURL, "/") + "/" + escapeKey(key)
+}
+
+// do mengembalikan isi response, yang cuma dipakai oleh Get
+func (s *S3Storage) do(ctx context.Context, method, key string, body []byte, contentType string) ([]byte, error) {
+	// step 1: buat request-nya (path-style: endpoint/bucket/key)
+	path := "/" + s.Bucket + "/" + escapeKey(key)
+	req, err := http.NewRequestWithContext(ctx, method, s.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if method == http.MethodPut {
+		req.Header.Set("Cache-Control", CacheControl) // disimpan sebagai metadata object dan ikut dikirim waktu file-nya diakses
+	}
+
+	// step 2: tanda tangani request-nya
+	s.sign(req, path, body, time.Now().UTC())
+
+	// step 3: kirim dan cek status-nya
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 %s %s failed: %s %s", method, key, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// sign mengisi header Authorization sesuai AWS Signature Version 4
+func (s *S3Storage) sign(req *http.Request, path string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// header yang ditandatangani harus urut abjad dan huruf kecil semua
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers = append([]string{"content-type"}, headers...)
+		values["content-type"] = contentType
+	}
+	var canonicalHeaders strings.Builder
+	for _, header := range headers {
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(values[header]) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		"", // tidak pakai query string
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, s.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+// escapeKey meng-encode setiap segmen key tapi tetap mempertahankan "/"
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"os"
+)
+
+// Storage menyimpan file media (gambar postingan, avatar, dst). Key selalu pakai "/" sebagai pemisah,
+// implementasinya yang menerjemahkan ke path di disk atau object key di bucket.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// CacheControl dikirim bersama setiap file media. Key-nya selalu acak dan tidak pernah ditimpa
+// (ganti avatar berarti key baru), jadi browser dan CDN boleh menyimpannya selamanya.
+const CacheControl = "public, max-age=31536000, immutable"
+
+type Config struct {
+	Driver      string // "local" (default) atau "s3"
+	LocalDir    string
+	PublicURL   string // prefix URL yang dikirim ke client, kosong berarti pakai default driver-nya
+	S3Endpoint  string
+	S3Bucket    string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+func NewStorage() Storage {
+	// step 1: ambil konfigurasi dari environment variable (.env sudah di-load waktu konek database)
+	config := Config{
+		Driver:      os.Getenv("STORAGE_DRIVER"),
+		LocalDir:    os.Getenv("MEDIA_LOCAL_DIR"),
+		PublicURL:   os.Getenv("MEDIA_PUBLIC_URL"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3Region:    os.Getenv("S3_REGION"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	}
+
+	// step 2: pilih driver-nya
+	switch config.Driver {
+	case "s3":
+		if config.S3Endpoint == "" || config.S3Bucket == "" || config.S3AccessKey == "" || config.S3SecretKey == "" {
+			log.Fatal("S3 storage requires S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY")
+		}
+		return NewS3Storage(config)
+	case "", "local":
+		return NewLocalStorage(config)
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER: %s", config.Driver)
+		return nil
+	}
+}
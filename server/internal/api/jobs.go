@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/infrastructure/mail"
+	"mood-bridge-v2/server/infrastructure/storage"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/service"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StartBackgroundJobs menjalankan job-job periodik di goroutine terpisah sampai ctx dibatalkan
+func StartBackgroundJobs(ctx context.Context, db *sql.DB, redisClient *redis.Client, mediaStorage storage.Storage) {
+	tagService := service.NewTagService(db, repository.NewTagRepository(), nil, redisClient) // job trending tidak butuh PostService
+	go tagService.RunTrendingJob(ctx, 10*time.Minute)
+
+	mediaService := service.NewMediaService(db, repository.NewMediaRepository(), mediaStorage)
+	go mediaService.RunCleanupJob(ctx, time.Hour)
+
+	auditService := service.NewAuditService(db, repository.NewAuditRepository())
+	go auditService.RunRetentionJob(ctx, 6*time.Hour)
+
+	// job cleanup token tidak pernah mengirim email, jadi cukup pakai LogMailer
+	verificationService := service.NewVerificationService(db, repository.NewUserRepository(), repository.NewEmailTokenRepository(), mail.NewLogMailer(mail.Config{}), auditService, nil)
+	go verificationService.RunCleanupJob(ctx, 6*time.Hour)
+
+	// job akun melanjutkan ekspor yang tertunda dan menghapus akun yang masa tenggangnya sudah lewat.
+	// History chat AI cuma ada di memori instance milik handler, jadi di sini tidak ikut dibersihkan.
+	userRepository := repository.NewUserRepository()
+	roleRepository := repository.NewRoleRepository()
+	accessService := service.NewAccessService(db, userRepository, roleRepository, redisClient)
+	journalService := service.NewJournalService(db, repository.NewJournalRepository(), service.NewMoodPredictionService())
+	accountService := service.NewAccountService(db, repository.NewAccountRepository(), repository.NewDataExportRepository(), userRepository, roleRepository, repository.NewProfileRepository(), repository.NewAIContextRepository(), journalService, nil, accessService, mediaStorage, redisClient, auditService)
+	go accountService.RunJob(ctx, 15*time.Minute)
+}
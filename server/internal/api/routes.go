@@ -2,6 +2,10 @@ package api
 
 import (
 	"database/sql"
+	"mood-bridge-v2/server/infrastructure/mail"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"mood-bridge-v2/server/infrastructure/storage"
+	"mood-bridge-v2/server/internal/entity"
 	"mood-bridge-v2/server/internal/handler"
 	"mood-bridge-v2/server/internal/middleware"
 	"mood-bridge-v2/server/internal/repository"
@@ -20,41 +24,127 @@ type Handlers struct {
 	FriendHandler handler.FriendHandler
 	ChatHandler    handler.ChatHandler
     AIHandler *handler.AIChatHandler
+	JournalHandler handler.JournalHandler
+	ReactionHandler handler.ReactionHandler
+	TagHandler handler.TagHandler
+	FeedHandler handler.FeedHandler
+	BookmarkHandler handler.BookmarkHandler
+	MediaHandler handler.MediaHandler
+	ProfileHandler handler.ProfileHandler
+	BlockHandler handler.BlockHandler
+	NotificationHandler handler.NotificationHandler
+	ModerationHandler handler.ModerationHandler
+	AdminHandler handler.AdminHandler
+	AuditHandler handler.AuditHandler
+	VerificationHandler handler.VerificationHandler
+	MFAHandler handler.MFAHandler
+	AccountHandler handler.AccountHandler
+	MediaStorage storage.Storage
+	RateLimiter ratelimit.Store
+	RateLimitPolicies ratelimit.Policies
 }
 
 // step 2: buat method untuk setiap route yang ada dalam api kita. misal kita mau bikin route untuk create user, kita bisa bikin method CreateUser
-func SetupRoutes(db *sql.DB, redisClient *redis.Client) *gin.Engine {
-	return initRoutes(initHandler(db, redisClient))
+func SetupRoutes(db *sql.DB, redisClient *redis.Client, mediaStorage storage.Storage) *gin.Engine {
+	return initRoutes(initHandler(db, redisClient, mediaStorage))
 }
 
-func initHandler(db *sql.DB, redisClient *redis.Client) Handlers {
+func initHandler(db *sql.DB, redisClient *redis.Client, mediaStorage storage.Storage) Handlers {
 	// Inisialisasi validator juga
 	validator := validator.New()
 
+	// Rate limiter dipakai middleware, lockout login, dan WebSocket chat
+	rateLimiter := ratelimit.NewStore(redisClient)
+	rateLimitPolicies := ratelimit.LoadPolicies()
+	loginLockout := ratelimit.NewLockout(rateLimiter, "login", ratelimit.DefaultLoginLockout)
+
 	// Inisialisasi repository, handler, dan services disini
 	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(db, userRepository)
+	roleRepository := repository.NewRoleRepository()
+	// auditService dipakai hampir semua service untuk mencatat kejadian penting ke log audit
+	auditService := service.NewAuditService(db, repository.NewAuditRepository())
+	auditHandler := handler.NewAuditHandler(auditService)
+	// verificationService mengirim email verifikasi dan reset password lewat mailer (SMTP, log, atau capture)
+	verificationService := service.NewVerificationService(db, userRepository, repository.NewEmailTokenRepository(), mail.NewMailer(), auditService, loginLockout)
+	verificationHandler := handler.NewVerificationHandler(verificationService, *validator)
+	// Authenticate mengecek suspend/ban dan permission terbaru lewat accessService di setiap request
+	accessService := service.NewAccessService(db, userRepository, roleRepository, redisClient)
+	middleware.SetAccessChecker(accessService)
+
+	// mfaService menangani 2FA (TOTP dan recovery code), termasuk langkah kedua login
+	mfaRepository := repository.NewMFARepository()
+	mfaService := service.NewMFAService(db, mfaRepository, userRepository, roleRepository, accessService, redisClient, auditService)
+	mfaHandler := handler.NewMFAHandler(mfaService, *validator)
+	userService := service.NewUserService(db, userRepository, roleRepository, mediaStorage, auditService, loginLockout, verificationService, mfaService)
 	userHandler := handler.NewUserHandler(userService, *validator)
 
+	moodPredictionService := service.NewMoodPredictionService()
+
+	friendRepository := repository.NewFriendRepository()
+	// blockService dibuat lebih dulu karena dipakai service lain untuk mengecek blokir/mute
+	blockService := service.NewBlockService(db, repository.NewBlockRepository(), friendRepository, userRepository, redisClient)
+	blockHandler := handler.NewBlockHandler(blockService)
+	profileRepository := repository.NewProfileRepository()
+	profileService := service.NewProfileService(db, profileRepository, userRepository, friendRepository, redisClient)
+	profileHandler := handler.NewProfileHandler(profileService, *validator)
+	reactionRepository := repository.NewReactionRepository()
+	tagRepository := repository.NewTagRepository()
+	bookmarkRepository := repository.NewBookmarkRepository()
+	mediaRepository := repository.NewMediaRepository()
 	postRepository := repository.NewPostRepository()
-	postService := service.NewPostService(db, postRepository, userRepository, service.NewMoodPredictionService(), redisClient)
+	postService := service.NewPostService(db, postRepository, userRepository, friendRepository, reactionRepository, tagRepository, bookmarkRepository, mediaRepository, moodPredictionService, blockService, auditService, accessService, mediaStorage, redisClient)
 	postHandler := handler.NewPostHandler(postService, *validator)
 
+	mediaService := service.NewMediaService(db, mediaRepository, mediaStorage)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+
+	tagService := service.NewTagService(db, tagRepository, postService, redisClient)
+	tagHandler := handler.NewTagHandler(tagService)
+
+	bookmarkService := service.NewBookmarkService(db, bookmarkRepository, postService)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService, *validator)
+
+	feedService := service.NewFeedService(db, postRepository, postService, redisClient)
+	feedHandler := handler.NewFeedHandler(feedService)
+
+	reactionService := service.NewReactionService(db, reactionRepository, userRepository, postService, redisClient)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+
 	commentRepository := repository.NewCommentRepository()
-	commentService := service.NewCommentService(commentRepository, userRepository, postRepository, db, redisClient)
+	commentService := service.NewCommentService(commentRepository, userRepository, postService, moodPredictionService, blockService, auditService, accessService, db, redisClient)
 	commentHandler := handler.NewCommentHandler(commentService, *validator)
 
-	friendRepository := repository.NewFriendRepository()
-	friendService := service.NewFriendService(friendRepository, userRepository, db, redisClient)
-	friendHandler := handler.NewFriendHandler(friendService, *validator)
-
 	chatRepository := repository.NewChatRepository(db)
 	websocketHub := service.NewConcreteHub(chatRepository)
-	chatService := service.NewChatService(chatRepository, websocketHub)
+	chatService := service.NewChatService(chatRepository, websocketHub, blockService, auditService, verificationService, rateLimiter, rateLimitPolicies.Get(ratelimit.PolicyChat))
 	chatHandler := handler.NewChatHandler(chatService)
+
+	notificationService := service.NewNotificationService(db, repository.NewNotificationRepository())
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// friendService mengabari penerima friend request lewat notifikasi dan, kalau dia online, lewat WebSocket
+	friendService := service.NewFriendService(friendRepository, userRepository, profileRepository, blockService, auditService, notificationService, websocketHub, db, redisClient)
+	friendHandler := handler.NewFriendHandler(friendService, *validator)
+
+	moderationRepository := repository.NewModerationRepository()
+	moderationService := service.NewModerationService(db, moderationRepository, userRepository, roleRepository, postRepository, commentRepository, chatRepository, postService, commentService, notificationService, accessService, auditService, redisClient)
+	moderationHandler := handler.NewModerationHandler(moderationService, *validator)
+
+	adminService := service.NewAdminService(db, userRepository, roleRepository, moderationRepository, mfaRepository, accessService, notificationService, auditService)
+	adminHandler := handler.NewAdminHandler(adminService, *validator)
 	
-    aiService := service.NewDialoGPTService()
-    aiHandler := handler.NewAIChatHandler(aiService)
+    aiContextRepository := repository.NewAIContextRepository()
+    aiContextService := service.NewAIContextService(db, aiContextRepository, postRepository)
+    aiService := service.NewDialoGPTService(aiContextService)
+    aiHandler := handler.NewAIChatHandler(aiService, aiContextService)
+
+	journalRepository := repository.NewJournalRepository()
+	journalService := service.NewJournalService(db, journalRepository, moodPredictionService)
+	journalHandler := handler.NewJournalHandler(journalService, *validator)
+
+	// accountService menangani ekspor data dan penghapusan akun, history chat AI ikut diekspor dari aiService
+	accountService := service.NewAccountService(db, repository.NewAccountRepository(), repository.NewDataExportRepository(), userRepository, roleRepository, profileRepository, aiContextRepository, journalService, aiService, accessService, mediaStorage, redisClient, auditService)
+	accountHandler := handler.NewAccountHandler(accountService, *validator)
 
 	return Handlers{
 		UserHandler:    userHandler,
@@ -63,6 +153,24 @@ func initHandler(db *sql.DB, redisClient *redis.Client) Handlers {
 		FriendHandler: friendHandler,
 		ChatHandler:    chatHandler,
 		AIHandler:      aiHandler,
+		JournalHandler: journalHandler,
+		ReactionHandler: reactionHandler,
+		TagHandler: tagHandler,
+		FeedHandler: feedHandler,
+		BookmarkHandler: bookmarkHandler,
+		MediaHandler: mediaHandler,
+		ProfileHandler: profileHandler,
+		BlockHandler: blockHandler,
+		NotificationHandler: notificationHandler,
+		ModerationHandler: moderationHandler,
+		AdminHandler: adminHandler,
+		AuditHandler: auditHandler,
+		VerificationHandler: verificationHandler,
+		MFAHandler: mfaHandler,
+		AccountHandler: accountHandler,
+		MediaStorage: mediaStorage,
+		RateLimiter: rateLimiter,
+		RateLimitPolicies: rateLimitPolicies,
 	}
 }
 
@@ -74,6 +182,19 @@ func initRoutes(h Handlers) *gin.Engine {
 	router.Use(middleware.HandlePanic())
 	// Terapkan middleware untuk CORS
 	router.Use(middleware.CORSMiddleware())
+	// Simpan IP client ke context untuk log audit
+	router.Use(middleware.ClientIP())
+
+	// Kalau media disimpan di disk lokal, server ini sendiri yang menyajikan file-nya
+	if localStorage, ok := h.MediaStorage.(*storage.LocalStorage); ok {
+		media := router.Group(storage.LocalRoutePrefix, middleware.CacheControl(storage.CacheControl))
+		media.Static("/", localStorage.Dir)
+	}
+
+	// rateLimit membuat middleware untuk policy dengan nama tertentu, kuotanya dihitung per IP atau per user
+	rateLimit := func(name string, keyFn middleware.RateLimitKey) gin.HandlerFunc {
+		return middleware.RateLimit(h.RateLimiter, h.RateLimitPolicies.Get(name), keyFn)
+	}
 
 	// Lakukan grouping
 	api := router.Group("/api")
@@ -90,61 +211,209 @@ func initRoutes(h Handlers) *gin.Engine {
 	// Buat routes untuk user
 	user := api.Group("/user")
 	{
-		user.POST("/register", h.UserHandler.Create)
-		user.POST("/login", h.UserHandler.Login)
+		user.POST("/register", rateLimit(ratelimit.PolicyRegister, middleware.ByIP), h.UserHandler.Create)
+		user.POST("/login", rateLimit(ratelimit.PolicyLogin, middleware.ByIP), h.UserHandler.Login)
+		user.POST("/login/mfa", rateLimit(ratelimit.PolicyLogin, middleware.ByIP), h.UserHandler.LoginMFA)
+		user.POST("/verify-email", h.VerificationHandler.ConfirmEmail)
+		user.POST("/password-reset", rateLimit(ratelimit.PolicyEmail, middleware.ByIP), h.VerificationHandler.RequestPasswordReset)
+		user.POST("/password-reset/confirm", h.VerificationHandler.ResetPassword)
 		user.GET("/by-username/:username", h.UserHandler.Find)
 		user.GET("/by-id/:id", h.UserHandler.FindByID)
+		user.GET("/profile/:id", middleware.OptionalAuthenticate(), h.ProfileHandler.Find)
 
 		user.Use(middleware.Authenticate())
 		user.GET("/by-email", h.UserHandler.FindByEmail)
+		user.POST("/verify-email/resend", rateLimit(ratelimit.PolicyEmail, middleware.ByUser), h.VerificationHandler.ResendVerification)
+		user.GET("/mfa", h.MFAHandler.GetStatus)
+		user.POST("/mfa/enroll", h.MFAHandler.Enroll)
+		user.POST("/mfa/confirm", rateLimit(ratelimit.PolicyLogin, middleware.ByUser), h.MFAHandler.ConfirmEnrollment)
+		user.POST("/mfa/disable", rateLimit(ratelimit.PolicyLogin, middleware.ByUser), h.MFAHandler.Disable)
+		user.POST("/mfa/recovery-codes", rateLimit(ratelimit.PolicyLogin, middleware.ByUser), h.MFAHandler.RegenerateRecoveryCodes)
+		user.POST("/export", rateLimit(ratelimit.PolicyExport, middleware.ByUser), h.AccountHandler.RequestExport)
+		user.GET("/export", h.AccountHandler.GetExports)
+		user.GET("/export/:id/download", h.AccountHandler.DownloadExport)
+		user.GET("/me/deletion", h.AccountHandler.GetDeletion)
+		user.DELETE("/me", rateLimit(ratelimit.PolicyLogin, middleware.ByUser), h.AccountHandler.RequestDeletion)
+		user.POST("/me/cancel-deletion", h.AccountHandler.CancelDeletion)
 		user.GET("/all", h.UserHandler.FindAll)
 		user.PUT("/update/:id", h.UserHandler.Update)
+		user.PUT("/avatar", h.UserHandler.UploadAvatar)
+		user.DELETE("/avatar", h.UserHandler.DeleteAvatar)
+		user.PATCH("/profile", h.ProfileHandler.Update)
+		user.GET("/blocks", h.BlockHandler.GetBlocked)
+		user.PUT("/blocks/:id", h.BlockHandler.Block)
+		user.DELETE("/blocks/:id", h.BlockHandler.Unblock)
+		user.GET("/mutes", h.BlockHandler.GetMuted)
+		user.PUT("/mutes/:id", h.BlockHandler.Mute)
+		user.DELETE("/mutes/:id", h.BlockHandler.Unmute)
 	}
 
 	post := api.Group("/post")
 	{
-		post.GET("/all", h.PostHandler.FindAll)
-		post.GET("/by-id/:id", h.PostHandler.Find)
-		post.GET("/by-userid/:id", h.PostHandler.FindByUserID)
+		// login opsional: tanpa token cuma postingan public yang kelihatan
+		post.GET("/all", middleware.OptionalAuthenticate(), h.PostHandler.FindAll)
+		post.GET("/by-id/:id", middleware.OptionalAuthenticate(), h.PostHandler.Find)
+		post.GET("/by-userid/:id", middleware.OptionalAuthenticate(), h.PostHandler.FindByUserID)
+		post.GET("/search", middleware.OptionalAuthenticate(), h.PostHandler.Search)
 
 		post.Use(middleware.Authenticate())
-		post.POST("/create", h.PostHandler.Create)
+		post.POST("/create", rateLimit(ratelimit.PolicyPost, middleware.ByUser), h.PostHandler.Create)
 		post.PUT("/update/:id", h.PostHandler.Update)
 		post.DELETE("/delete/:id", h.PostHandler.Delete)
+		post.POST("/media", rateLimit(ratelimit.PolicyUpload, middleware.ByUser), h.MediaHandler.Upload)
 		post.GET("/friend-posts/:id", h.PostHandler.GetFriendPosts)
+		post.GET("/:id/revisions", h.PostHandler.GetRevisions)
+	}
+
+	// PUT dan DELETE sengaja dipakai supaya toggle reaksinya idempotent
+	reaction := api.Group("/reaction")
+	{
+		reaction.GET("/by-postid/:id", middleware.OptionalAuthenticate(), h.ReactionHandler.GetReactions)
+		reaction.GET("/reactors/:id", middleware.OptionalAuthenticate(), h.ReactionHandler.GetReactors)
+
+		reaction.Use(middleware.Authenticate())
+		reaction.PUT("/:id/:type", h.ReactionHandler.React)
+		reaction.DELETE("/:id/:type", h.ReactionHandler.Unreact)
+	}
+
+	// Home feed yang sudah diranking (teman + tag yang di-follow + postingan suportif)
+	feed := api.Group("/feed")
+	{
+		feed.Use(middleware.Authenticate())
+		feed.GET("", h.FeedHandler.GetFeed)
+	}
+
+	tag := api.Group("/tag")
+	{
+		tag.GET("/trending", h.TagHandler.GetTrending)
+		tag.GET("/:name", middleware.OptionalAuthenticate(), h.TagHandler.Find)
+		tag.GET("/:name/posts", middleware.OptionalAuthenticate(), h.TagHandler.GetPosts)
+
+		tag.Use(middleware.Authenticate())
+		tag.GET("/following", h.TagHandler.GetFollowedTags)
+		tag.PUT("/:name/follow", h.TagHandler.Follow)
+		tag.DELETE("/:name/follow", h.TagHandler.Unfollow)
+	}
+
+	// Koleksi bookmark bersifat privat, semua route-nya wajib login dan hanya bisa akses milik sendiri
+	bookmark := api.Group("/bookmark")
+	{
+		bookmark.Use(middleware.Authenticate())
+		bookmark.GET("/collections", h.BookmarkHandler.GetCollections)
+		bookmark.POST("/collections", h.BookmarkHandler.CreateCollection)
+		bookmark.PUT("/collections/:id", h.BookmarkHandler.RenameCollection)
+		bookmark.DELETE("/collections/:id", h.BookmarkHandler.DeleteCollection)
+		bookmark.GET("/collections/:id/posts", h.BookmarkHandler.GetPosts)
+		bookmark.PUT("/collections/:id/posts/:postId", h.BookmarkHandler.AddPost)
+		bookmark.DELETE("/collections/:id/posts/:postId", h.BookmarkHandler.RemovePost)
 	}
 
 	comment := api.Group("/comment")
 	{
-		comment.GET("/by-postid/:id", h.CommentHandler.GetAllByPostID)
-		comment.GET("/by-id/:id", h.CommentHandler.GetByID)
+		comment.GET("/by-postid/:id", middleware.OptionalAuthenticate(), h.CommentHandler.GetByPostID)
+		comment.GET("/replies/:id", middleware.OptionalAuthenticate(), h.CommentHandler.GetReplies)
+		comment.GET("/by-id/:id", middleware.OptionalAuthenticate(), h.CommentHandler.GetByID)
 
 		comment.Use(middleware.Authenticate())
-		comment.POST("/create", h.CommentHandler.Create)
+		comment.POST("/create", rateLimit(ratelimit.PolicyComment, middleware.ByUser), h.CommentHandler.Create)
+		comment.PUT("/update/:id", h.CommentHandler.Update)
 		comment.DELETE("/delete/:id", h.CommentHandler.Delete)
+		comment.GET("/history/:id", h.CommentHandler.GetRevisions)
+		comment.GET("/needs-review", h.CommentHandler.GetNeedsReview)
 	}
 
 	friend := api.Group("/friend")
 	{
-		friend.GET("/all/:id", h.FriendHandler.GetFriends)
-		friend.GET("/requests/:id", h.FriendHandler.GetFriendRequests)
+		friend.GET("/all/:id", middleware.OptionalAuthenticate(), h.FriendHandler.GetFriends)
 		friend.Use(middleware.Authenticate())
 		friend.POST("/add", h.FriendHandler.AddFriend)
 		friend.POST("/accept", h.FriendHandler.AcceptRequest)
+		friend.POST("/decline/:id", h.FriendHandler.DeclineRequest)
+		friend.POST("/cancel/:id", h.FriendHandler.CancelRequest)
+		friend.GET("/incoming", h.FriendHandler.GetFriendRequests)
+		friend.GET("/outgoing", h.FriendHandler.GetOutgoingRequests)
 		friend.DELETE("/delete/:id", h.FriendHandler.Delete)
 		friend.GET("/recommendation/:id", h.FriendHandler.GetFriendRecommendation)
+		friend.GET("/mutual/:id", h.FriendHandler.GetMutualFriends)
+		friend.GET("/suggestions", h.FriendHandler.GetFriendSuggestions)
 	}
 
 	chat := api.Group("/chat")
 	{
-		chat.GET("/ws", h.ChatHandler.HandleWebSocketConnection)
-		chat.GET("/history", h.ChatHandler.HandleFetchChatHistory)
-		chat.POST("/messages/:message_id/read", h.ChatHandler.HandleMarkMessageAsRead)
+		chat.GET("/ws", middleware.AuthenticateWebSocket(), h.ChatHandler.HandleWebSocketConnection)
+		chat.GET("/history", middleware.Authenticate(), h.ChatHandler.HandleFetchChatHistory)
+		chat.POST("/messages/:message_id/read", middleware.Authenticate(), h.ChatHandler.HandleMarkMessageAsRead)
 	}
 
 	ai := api.Group("/ai")
 	{
+		ai.Use(middleware.Authenticate())
 		ai.POST("/chat", h.AIHandler.HandleChat)
+		ai.GET("/context", h.AIHandler.GetContext)
+		ai.GET("/context/settings", h.AIHandler.GetContextSettings)
+		ai.PUT("/context/settings", h.AIHandler.UpdateContextSettings)
+	}
+
+	// Jurnal bersifat privat, jadi semua route-nya wajib login dan hanya bisa akses milik sendiri
+	journal := api.Group("/journal")
+	{
+		journal.Use(middleware.Authenticate())
+		journal.POST("/create", h.JournalHandler.Create)
+		journal.GET("/all", h.JournalHandler.FindAll)
+		journal.GET("/by-id/:id", h.JournalHandler.FindByID)
+		journal.PUT("/update/:id", h.JournalHandler.Update)
+		journal.DELETE("/delete/:id", h.JournalHandler.Delete)
+		journal.GET("/streak", h.JournalHandler.GetStreak)
+		journal.GET("/checkin", h.JournalHandler.GetDailyCheckIn)
+		journal.GET("/export", h.JournalHandler.Export)
+	}
+
+	// Laporan bisa dibuat semua user yang login untuk postingan, komentar, pesan, atau profil user lain
+	report := api.Group("/report")
+	{
+		report.Use(middleware.Authenticate())
+		report.POST("", h.ModerationHandler.Report)
+	}
+
+	notification := api.Group("/notification")
+	{
+		notification.Use(middleware.Authenticate())
+		notification.GET("", h.NotificationHandler.GetNotifications)
+		notification.GET("/unread-count", h.NotificationHandler.CountUnread)
+		notification.PUT("/read-all", h.NotificationHandler.MarkAllRead)
+		notification.PUT("/:id/read", h.NotificationHandler.MarkRead)
+	}
+
+	// Antrian moderasi khusus user dengan permission reports:moderate (role moderator atau admin)
+	moderation := api.Group("/moderation")
+	{
+		moderation.Use(middleware.Authenticate(), middleware.RequirePermission(entity.PermissionModerateReports))
+		moderation.GET("/reports", h.ModerationHandler.GetQueue)
+		moderation.GET("/reports/:id", h.ModerationHandler.GetReport)
+		moderation.PUT("/reports/:id/claim", h.ModerationHandler.Claim)
+		moderation.POST("/reports/:id/resolve", h.ModerationHandler.Resolve)
+		moderation.GET("/actions", h.ModerationHandler.GetActions)
+	}
+
+	// Manajemen user untuk staff, setiap route dijaga permission-nya masing-masing
+	admin := api.Group("/admin")
+	{
+		admin.Use(middleware.Authenticate())
+		admin.GET("/roles", middleware.RequirePermission(entity.PermissionAssignRoles), h.AdminHandler.GetRoles)
+		admin.GET("/users", middleware.RequirePermission(entity.PermissionViewUsers), h.AdminHandler.GetUsers)
+		admin.GET("/users/:id", middleware.RequirePermission(entity.PermissionViewUsers), h.AdminHandler.GetUser)
+		admin.PUT("/users/:id/suspend", middleware.RequirePermission(entity.PermissionSuspendUsers), h.AdminHandler.Suspend)
+		admin.DELETE("/users/:id/suspend", middleware.RequirePermission(entity.PermissionSuspendUsers), h.AdminHandler.Unsuspend)
+		admin.PUT("/users/:id/ban", middleware.RequirePermission(entity.PermissionBanUsers), h.AdminHandler.Ban)
+		admin.DELETE("/users/:id/ban", middleware.RequirePermission(entity.PermissionBanUsers), h.AdminHandler.Unban)
+		admin.POST("/users/:id/reset-password", middleware.RequirePermission(entity.PermissionResetUsers), h.AdminHandler.ResetPassword)
+		admin.PUT("/users/:id/roles/:role", middleware.RequirePermission(entity.PermissionAssignRoles), h.AdminHandler.AssignRole)
+		admin.DELETE("/users/:id/roles/:role", middleware.RequirePermission(entity.PermissionAssignRoles), h.AdminHandler.RevokeRole)
+		admin.DELETE("/users/:id/mfa", middleware.RequirePermission(entity.PermissionResetUsers), h.AdminHandler.ResetMFA)
+		admin.PUT("/roles/:role/require-mfa", middleware.RequirePermission(entity.PermissionAssignRoles), h.AdminHandler.RequireRoleMFA)
+		admin.DELETE("/roles/:role/require-mfa", middleware.RequirePermission(entity.PermissionAssignRoles), h.AdminHandler.UnrequireRoleMFA)
+		admin.GET("/audit", middleware.RequirePermission(entity.PermissionReadAudit), h.AuditHandler.GetEvents)
+		admin.GET("/audit/verify", middleware.RequirePermission(entity.PermissionReadAudit), h.AuditHandler.Verify)
 	}
 
 
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Pengaturan apa saja yang boleh dibagikan ke AI companion (default-nya mati, user harus opt-in dulu)
+type AIContextSetting struct {
+	UserID           int       `gorm:"primaryKey" json:"userid"`
+	Enabled          bool      `gorm:"default:false" json:"enabled"`
+	IncludeMoodTrend bool      `gorm:"default:true" json:"include_mood_trend"`
+	IncludePosts     bool      `gorm:"default:true" json:"include_posts"`
+	UpdatedAt        time.Time `json:"updatedat"`
+}
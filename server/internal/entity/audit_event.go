@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// Nama event audit, formatnya "<area>.<kejadian>"
+const (
+	AuditUserRegistered         = "user.registered"
+	AuditLoginSucceeded         = "auth.login"
+	AuditLoginFailed            = "auth.login_failed"
+	AuditLoginRejected          = "auth.login_rejected" // password benar tapi akunnya di-suspend/di-ban
+	AuditLoginLocked            = "auth.login_locked"   // terlalu banyak password salah, akun dikunci sementara
+	AuditPasswordChanged        = "user.password_changed"
+	AuditDeletionRequested      = "user.deletion_requested"
+	AuditDeletionCancelled      = "user.deletion_cancelled"
+	AuditAccountDeleted         = "user.deleted"
+	AuditDataExportRequested    = "user.export_requested"
+	AuditEmailVerified          = "user.email_verified"
+	AuditPasswordResetRequested = "auth.password_reset_requested"
+	AuditPasswordReset          = "auth.password_reset"
+	AuditMFAEnabled             = "auth.mfa_enabled"
+	AuditMFADisabled            = "auth.mfa_disabled"
+	AuditMFAFailed              = "auth.mfa_failed"
+	AuditMFARecoveryCodeUsed    = "auth.mfa_recovery_code_used"
+	AuditMFARecoveryCodesReset  = "auth.mfa_recovery_codes_regenerated"
+	AuditPostDeleted            = "post.deleted"
+	AuditCommentDeleted         = "comment.deleted"
+	AuditFriendRequested        = "friend.requested"
+	AuditFriendAccepted         = "friend.accepted"
+	AuditFriendRemoved          = "friend.removed"
+	AuditFriendDeclined         = "friend.declined"
+	AuditFriendCancelled        = "friend.cancelled"
+	AuditChatConnected          = "chat.connected"
+	AuditChatMessageBlocked     = "chat.message_blocked"
+	AuditModeration             = "moderation." // diikuti nama ModerationAction*, misalnya "moderation.hide"
+	AuditAdmin                  = "admin."      // diikuti nama ModerationAction*, misalnya "admin.ban"
+	AuditRoleMFAChanged         = "admin.role_mfa_changed"
+)
+
+// Jenis target event audit. Nilainya sengaja sama dengan ReportTarget* supaya event lama tetap cocok waktu difilter,
+// tapi log audit punya konstantanya sendiri dan tidak bergantung pada enum laporan moderasi.
+const (
+	AuditTargetUser       = "user"
+	AuditTargetPost       = "post"
+	AuditTargetComment    = "comment"
+	AuditTargetFriendship = "friendship"
+	AuditTargetReport     = "report"
+	AuditTargetRole       = "role"
+)
+
+// AuditEvent hanya ditambah, tidak pernah diubah. Hash dihitung dari isi event dan PrevHash (hash event sebelumnya),
+// jadi kalau ada baris yang diubah atau dihapus di tengah, rantai hash-nya putus.
+type AuditEvent struct {
+	EventID    int       `json:"event_id"`
+	ActorID    *int      `json:"actor_id"` // nil berarti sistem atau user yang belum login
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   *int      `json:"target_id"`
+	Metadata   string    `json:"metadata"` // JSON, disimpan apa adanya supaya hash-nya bisa dihitung ulang
+	IPAddress  string    `json:"ip_address"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type BookmarkCollection struct {
+	CollectionID int       `gorm:"primaryKey" json:"collection_id"`
+	UserID       int       `json:"user_id"`
+	Name         string    `json:"name"`
+	PostCount    int       `json:"post_count"` // dihitung saat query, bukan kolom
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Bookmark struct {
+	CollectionID int       `gorm:"primaryKey" json:"collection_id"`
+	PostID       int       `gorm:"primaryKey" json:"post_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
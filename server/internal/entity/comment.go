@@ -3,11 +3,28 @@ package entity
 import "time"
 
 type Comment struct {
-	CommentID int       `gorm:"primaryKey;autoIncrement" json:"comment_id"`
-	UserID    int       `gorm:"not null" json:"user_id"`
-	User      User      `gorm:"foreignKey:UserID" json:"user"` // Relasi ke User
-	PostID    int       `gorm:"not null" json:"post_id"`
-	Post      Post      `gorm:"foreignKey:PostID" json:"post"` // Relasi ke Post
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	CommentID       int        `gorm:"primaryKey;autoIncrement" json:"comment_id"`
+	UserID          int        `gorm:"not null" json:"user_id"`
+	User            User       `gorm:"foreignKey:UserID" json:"user"` // Relasi ke User
+	PostID          int        `gorm:"not null" json:"post_id"`
+	Post            Post       `gorm:"foreignKey:PostID" json:"post"` // Relasi ke Post
+	ParentCommentID *int       `json:"parent_comment_id"`             // nil berarti komentar top-level
+	Depth           int        `gorm:"default:0" json:"depth"`
+	ReplyCount      int        `gorm:"default:0" json:"reply_count"`
+	IsDeleted       bool       `gorm:"default:false" json:"is_deleted"` // soft delete, baris tetap ada supaya thread-nya ga putus
+	Content         string     `json:"content"`
+	Mood            *string    `json:"mood"`         // hasil klasifikasi (async), nil kalau belum selesai diklasifikasi
+	NeedsReview     bool       `json:"needs_review"` // ditandai kalau mood-nya masuk kategori distress, buat antrian moderator
+	CreatedAt       time.Time  `json:"created_at"`
+	EditedAt        *time.Time `json:"edited_at"`
+	DeletedAt       *time.Time `json:"deleted_at"`
+	HiddenAt        *time.Time `json:"hidden_at"` // disembunyikan moderator, isinya diganti placeholder
+}
+
+type CommentRevision struct {
+	RevisionID int       `gorm:"primaryKey;autoIncrement" json:"revision_id"`
+	CommentID  int       `gorm:"not null" json:"comment_id"`
+	Content    string    `json:"content"` // isi komentar sebelum diedit
+	Mood       *string   `json:"mood"`
+	EditedAt   time.Time `json:"edited_at"`
 }
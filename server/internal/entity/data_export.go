@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Status ekspor data: pending -> processing -> ready/failed
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+)
+
+type DataExport struct {
+	ExportID    int        `json:"export_id"`
+	UserID      int        `json:"userid"`
+	Status      string     `json:"status"`
+	StorageKey  string     `json:"-"` // kosong sampai arsipnya selesai dibuat
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `json:"error"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"` // arsip dihapus dari storage setelah waktu ini
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Kegunaan token yang dikirim lewat email, token untuk satu kegunaan tidak bisa dipakai untuk kegunaan lain
+const (
+	EmailTokenVerifyEmail   = "verify_email"
+	EmailTokenPasswordReset = "password_reset"
+)
+
+type EmailToken struct {
+	TokenID   int        `json:"token_id"`
+	UserID    int        `json:"userid"`
+	Purpose   string     `json:"purpose"`
+	Email     string     `json:"email"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
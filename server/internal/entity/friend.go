@@ -2,12 +2,21 @@ package entity
 
 import "time"
 
+const (
+	FriendPending   = "pending"
+	FriendAccepted  = "accepted"
+	FriendDeclined  = "declined"  // ditolak penerima, pengirim harus menunggu cooldown sebelum bisa mengirim lagi
+	FriendCancelled = "cancelled" // dibatalkan pengirim sebelum dijawab
+	FriendBlocked   = "blocked"   // putus karena salah satu memblokir yang lain
+)
+
 type Friend struct {
 	FriendID     	int       	`gorm:"primaryKey;autoIncrement" json:"id"`
 	UserID       	int       	`gorm:"not null" json:"userid"`            // orang yang menambahkan teman
 	FriendUserID 	int       	`gorm:"not null" json:"frienduserid"`      // orang yang jadi temannya
-	FriendStatus 	bool      	`gorm:"default:false" json:"friendstatus"` // status teman
+	Status       	string    	`gorm:"default:pending" json:"status"`     // status teman, lihat konstanta Friend* di atas
 	CreatedAt    	time.Time 	`gorm:"autoCreateTime" json:"createdat"`
+	RespondedAt  	*time.Time	`json:"respondedat"`                       // waktu statusnya berubah dari pending
 	User       		*User 		`gorm:"foreignKey:FriendUserID;references:ID"`
 }
 
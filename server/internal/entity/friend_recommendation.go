@@ -1,6 +1,15 @@
 package entity
 
 type FriendRecommendation struct {
-	User        User   `json:"user"`
-	OverallMood string `json:"overall_mood"`
-}
\ No newline at end of file
+	User            User     `json:"user"`
+	OverallMood     string   `json:"overall_mood"`
+	SharedInterests []string `json:"shared_interests"` // minat yang sama (dari profil public kandidat)
+	SupportOffered  []string `json:"support_offered"`  // dukungan yang dicari user dan ditawarkan kandidat
+	SupportSought   []string `json:"support_sought"`   // dukungan yang dicari kandidat dan bisa ditawarkan user
+}
+
+// FriendSuggestion kandidat "people you may know": teman dari teman user yang belum berteman dengan user
+type FriendSuggestion struct {
+	User          User `json:"user"`
+	MutualFriends int  `json:"mutual_friends"`
+}
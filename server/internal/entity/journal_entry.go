@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Isi jurnal disimpan dalam bentuk terenkripsi, jadi Content dan EmotionTags di sini masih berupa ciphertext
+type JournalEntry struct {
+	EntryID       int       `gorm:"primaryKey;autoIncrement" json:"entryid"`
+	UserID        int       `gorm:"not null" json:"userid"`
+	Content       []byte    `gorm:"not null" json:"-"`
+	EmotionTags   []byte    `json:"-"`
+	SelfMood      *int      `json:"self_mood"` // skala 1-5, opsional
+	PredictedMood *string   `json:"predicted_mood"`
+	EntryDate     time.Time `json:"entrydate"`
+	CreatedAt     time.Time `json:"createdat"`
+	UpdatedAt     time.Time `json:"updatedat"`
+}
+
+// Kunci per-user yang di-wrap (dienkripsi) pakai master key dari environment
+type JournalKey struct {
+	UserID     int       `gorm:"primaryKey" json:"userid"`
+	WrappedKey []byte    `gorm:"not null" json:"-"`
+	CreatedAt  time.Time `json:"createdat"`
+}
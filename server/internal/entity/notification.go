@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+const (
+	NotificationReportResolved    = "report_resolved"    // laporan yang dikirim user sudah ditangani moderator
+	NotificationModerationWarning = "moderation_warning" // user dapat peringatan dari moderator
+	NotificationAccountSuspended  = "account_suspended"
+	NotificationFriendRequest     = "friend_request"  // ada permintaan pertemanan baru, ReferenceID = friendid
+	NotificationFriendAccepted    = "friend_accepted" // permintaan pertemanan yang dikirim user diterima
+)
+
+type Notification struct {
+	NotificationID int        `json:"notification_id"`
+	UserID         int        `json:"userid"`
+	Type           string     `json:"type"`
+	Message        string     `json:"message"`
+	ReferenceID    *int       `json:"reference_id"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
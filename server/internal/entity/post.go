@@ -2,11 +2,31 @@ package entity
 
 import "time"
 
+const (
+	VisibilityPublic  = "public"  // bisa dilihat semua orang, termasuk yang belum login
+	VisibilityFriends = "friends" // cuma bisa dilihat teman (status pertemanan accepted)
+	VisibilityPrivate = "private" // cuma bisa dilihat pemilik postingan
+)
+
 type Post struct {
-	PostID    int       `gorm:"primaryKey;autoIncrement"`
-	UserID    int       `gorm:"not null" json:"user_id"`
-	User      User      `gorm:"foreignKey:UserID" json:"user"` // Ini perlu biar bisa db.Preload("User").Find(&posts)
-	Content   string    `json:"content"`
-	Mood      string    `json:"mood"`
-	CreatedAt time.Time `json:"created_at"`
+	PostID       int        `gorm:"primaryKey;autoIncrement"`
+	UserID       int        `gorm:"not null" json:"user_id"`
+	User         User       `gorm:"foreignKey:UserID" json:"user"` // Ini perlu biar bisa db.Preload("User").Find(&posts)
+	Content      string     `json:"content"`
+	Mood         string     `json:"mood"`          // hasil prediksi model, selalu dihitung di server
+	DeclaredMood *string    `json:"declared_mood"` // mood yang dipilih sendiri oleh user (opsional)
+	Visibility   string     `gorm:"default:public" json:"visibility"`
+	IsAnonymous  bool       `gorm:"default:false" json:"is_anonymous"` // author disembunyikan dari user lain, tapi tetap tercatat di database
+	CreatedAt    time.Time  `json:"created_at"`
+	EditedAt     *time.Time `json:"edited_at"`
+	HiddenAt     *time.Time `json:"hidden_at"` // disembunyikan moderator, nil kalau tampil normal
+}
+
+type PostRevision struct {
+	RevisionID   int       `gorm:"primaryKey;autoIncrement" json:"revision_id"`
+	PostID       int       `gorm:"not null" json:"post_id"`
+	Content      string    `json:"content"` // isi postingan sebelum diedit
+	Mood         string    `json:"mood"`
+	DeclaredMood *string   `json:"declared_mood"`
+	CreatedAt    time.Time `json:"created_at"` // waktu postingan diedit
 }
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type PostMedia struct {
+	MediaID      int       `gorm:"primaryKey" json:"media_id"`
+	UserID       int       `json:"user_id"`
+	PostID       *int      `json:"post_id"` // nil selama belum dipasang ke postingan
+	Position     int       `json:"position"`
+	StorageKey   string    `json:"storage_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	ContentType  string    `json:"content_type"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Blurhash     string    `json:"blurhash"`
+	SizeBytes    int       `json:"size_bytes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type PostReaction struct {
+	PostID       int       `gorm:"primaryKey" json:"post_id"`
+	UserID       int       `gorm:"primaryKey" json:"user_id"`
+	ReactionType string    `gorm:"primaryKey" json:"reaction_type"`
+	CreatedAt    time.Time `json:"created_at"`
+}
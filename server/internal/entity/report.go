@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+// Jenis konten yang bisa dilaporkan
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+	ReportTargetMessage = "message"
+	ReportTargetUser    = "user" // profil user
+)
+
+// Kategori alasan laporan
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHateSpeech     = "hate_speech"
+	ReportReasonSelfHarm       = "self_harm"
+	ReportReasonViolence       = "violence"
+	ReportReasonSexualContent  = "sexual_content"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonImpersonation  = "impersonation"
+	ReportReasonOther          = "other" // wajib disertai penjelasan
+)
+
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusClaimed  = "claimed" // sedang ditangani satu moderator
+	ReportStatusResolved = "resolved"
+)
+
+// Tindakan moderator, juga dipakai sebagai Resolution laporan
+const (
+	ModerationActionHide     = "hide"
+	ModerationActionDelete   = "delete"
+	ModerationActionWarn     = "warn"
+	ModerationActionSuspend  = "suspend"
+	ModerationActionDismiss  = "dismiss"   // laporan tidak terbukti, konten yang sempat disembunyikan otomatis dimunculkan lagi
+	ModerationActionClaim    = "claim"     // cuma dicatat di log
+	ModerationActionAutoHide = "auto_hide" // disembunyikan sistem karena jumlah laporan melewati batas
+)
+
+// Tindakan admin lewat /api/admin, dicatat di log yang sama dengan target user
+const (
+	ModerationActionUnsuspend     = "unsuspend"
+	ModerationActionBan           = "ban"
+	ModerationActionUnban         = "unban"
+	ModerationActionResetPassword = "reset_password"
+	ModerationActionAssignRole    = "assign_role"
+	ModerationActionRevokeRole    = "revoke_role"
+	ModerationActionResetMFA      = "reset_mfa" // mematikan 2FA user yang kehilangan authenticator dan recovery code-nya
+)
+
+type Report struct {
+	ReportID       int        `json:"report_id"`
+	ReporterID     int        `json:"reporter_id"`
+	TargetType     string     `json:"target_type"`
+	TargetID       int        `json:"target_id"`
+	TargetOwnerID  *int       `json:"target_owner_id"`
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details"`
+	Status         string     `json:"status"`
+	ClaimedBy      *int       `json:"claimed_by"`
+	ClaimedAt      *time.Time `json:"claimed_at"`
+	Resolution     *string    `json:"resolution"`
+	ResolutionNote *string    `json:"resolution_note"`
+	ResolvedBy     *int       `json:"resolved_by"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	OpenReports    int        `json:"open_reports"` // jumlah laporan yang belum selesai untuk target yang sama (cuma diisi di antrian)
+}
+
+type ModerationAction struct {
+	ActionID    int       `json:"action_id"`
+	ModeratorID *int      `json:"moderator_id"`
+	ReportID    *int      `json:"report_id"`
+	Action      string    `json:"action"`
+	TargetType  string    `json:"target_type"`
+	TargetID    int       `json:"target_id"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
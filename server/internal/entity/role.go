@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Nama permission harus sama dengan yang di-seed di migrasi (000022_create_roles, 000023_create_audit_events)
+const (
+	PermissionModerateReports = "reports:moderate"
+	PermissionViewUsers       = "users:read"
+	PermissionSuspendUsers    = "users:suspend"
+	PermissionBanUsers        = "users:ban"
+	PermissionResetUsers      = "users:reset"
+	PermissionAssignRoles     = "roles:assign"
+	PermissionReadAudit       = "audit:read"
+)
+
+type Role struct {
+	RoleID      int      `json:"roleid"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	RequireMFA  bool     `json:"require_mfa"` // permission role ini hanya berlaku kalau user-nya sudah mengaktifkan 2FA
+}
+
+// AccountRestriction berisi status suspend/ban user, nil berarti akunnya tidak dibatasi
+type AccountRestriction struct {
+	SuspendedUntil *time.Time `json:"suspended_until"`
+	BannedAt       *time.Time `json:"banned_at"`
+	BanReason      string     `json:"ban_reason"`
+	DeletedAt      *time.Time `json:"deleted_at"` // akunnya sudah dihapus, token lama tidak berlaku lagi
+}
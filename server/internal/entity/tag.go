@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+type TagFollow struct {
+	UserID    int       `gorm:"primaryKey" json:"user_id"`
+	Tag       string    `gorm:"primaryKey" json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TrendingTag struct {
+	Tag        string    `gorm:"primaryKey" json:"tag"`
+	PostCount  int       `json:"post_count"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+type TagResource struct {
+	ResourceID  int    `gorm:"primaryKey" json:"resource_id"`
+	Tag         string `json:"tag"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
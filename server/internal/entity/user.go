@@ -11,7 +11,7 @@ type User struct {
 	Fullname   string         `gorm:"type:varchar(100);not null" json:"fullname"`
 	Email      string         `gorm:"type:varchar(100);unique;not null" json:"email"`
 	Password   string         `gorm:"type:varchar(255);not null" json:"-"`
-	ProfileUrl sql.NullString `gorm:"type:varchar(255)" json:"profileurl"`
+	AvatarKey  sql.NullString `gorm:"type:varchar(255)" json:"-"` // prefix key avatar di media storage, NULL berarti pakai avatar default
 	Posts      []Post         `gorm:"foreignKey:UserID"`
 	CreatedAt  time.Time      `json:"createdat"`
 }
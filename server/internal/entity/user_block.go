@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type UserBlock struct {
+	UserID        int       `json:"userid"`          // yang memblokir
+	BlockedUserID int       `json:"blocked_user_id"` // yang diblokir
+	CreatedAt     time.Time `json:"created_at"`
+	User          *User     `json:"user"` // user yang diblokir
+}
+
+type UserMute struct {
+	UserID      int       `json:"userid"`        // yang me-mute
+	MutedUserID int       `json:"muted_user_id"` // yang di-mute
+	CreatedAt   time.Time `json:"created_at"`
+	User        *User     `json:"user"` // user yang di-mute
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// UserMFA secret TOTP milik user. EnabledAt nil berarti enrollment belum dikonfirmasi dengan kode pertama.
+type UserMFA struct {
+	UserID       int        `json:"userid"`
+	Secret       []byte     `json:"-"` // terenkripsi
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
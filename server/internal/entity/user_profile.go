@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Jenis dukungan yang bisa dicari atau ditawarkan user, dipakai juga buat mencocokkan rekomendasi teman
+const (
+	SupportListening        = "listening"
+	SupportAdvice           = "advice"
+	SupportSharedExperience = "shared_experience"
+	SupportEncouragement    = "encouragement"
+	SupportResources        = "resources"
+	SupportCheckIns         = "check_ins"
+)
+
+// UserProfile menyimpan profil tambahan user. Setiap field punya visibility sendiri
+// (public/friends/private, sama seperti visibility postingan).
+type UserProfile struct {
+	UserID                    int       `gorm:"primaryKey" json:"userid"`
+	Bio                       string    `json:"bio"`
+	Pronouns                  string    `json:"pronouns"`
+	Location                  string    `json:"location"` // kasar saja: kota/provinsi/negara
+	Interests                 []string  `json:"interests"`
+	SupportSeeking            []string  `json:"support_seeking"`
+	SupportOffering           []string  `json:"support_offering"`
+	Language                  string    `json:"language"`
+	BioVisibility             string    `json:"bio_visibility"`
+	PronounsVisibility        string    `json:"pronouns_visibility"`
+	LocationVisibility        string    `json:"location_visibility"`
+	InterestsVisibility       string    `json:"interests_visibility"`
+	SupportSeekingVisibility  string    `json:"support_seeking_visibility"`
+	SupportOfferingVisibility string    `json:"support_offering_visibility"`
+	LanguageVisibility        string    `json:"language_visibility"`
+	FriendsVisibility         string    `json:"friends_visibility"` // daftar teman, mutual friend, dan "people you may know"
+	UpdatedAt                 time.Time `json:"updated_at"`
+}
+
+// DefaultUserProfile dipakai untuk user yang belum pernah mengisi profil (harus sama dengan default di migration).
+// Lokasi dan dukungan yang dicari defaultnya cuma untuk teman karena lebih sensitif.
+func DefaultUserProfile(userID int) *UserProfile {
+	return &UserProfile{
+		UserID:                    userID,
+		Interests:                 []string{},
+		SupportSeeking:            []string{},
+		SupportOffering:           []string{},
+		BioVisibility:             VisibilityPublic,
+		PronounsVisibility:        VisibilityPublic,
+		LocationVisibility:        VisibilityFriends,
+		InterestsVisibility:       VisibilityPublic,
+		SupportSeekingVisibility:  VisibilityFriends,
+		SupportOfferingVisibility: VisibilityPublic,
+		LanguageVisibility:        VisibilityPublic,
+		FriendsVisibility:         VisibilityPublic,
+	}
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type AccountHandler interface {
+	RequestExport(c *gin.Context)
+	GetExports(c *gin.Context)
+	DownloadExport(c *gin.Context)
+	RequestDeletion(c *gin.Context)
+	CancelDeletion(c *gin.Context)
+	GetDeletion(c *gin.Context)
+}
+
+type AccountHandlerImpl struct {
+	AccountService service.AccountService
+	validate       validator.Validate
+}
+
+func NewAccountHandler(accountService service.AccountService, validate validator.Validate) AccountHandler {
+	return &AccountHandlerImpl{
+		AccountService: accountService,
+		validate:       validate,
+	}
+}
+
+func (h *AccountHandlerImpl) RequestExport(c *gin.Context) {
+	h.handle(c, http.StatusAccepted, nil, "Your data export is being prepared, check back in a few minutes", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.AccountService.RequestExport(ctx, userID)
+	})
+}
+
+func (h *AccountHandlerImpl) GetExports(c *gin.Context) {
+	h.handle(c, http.StatusOK, nil, "Success", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.AccountService.GetExports(ctx, userID)
+	})
+}
+
+func (h *AccountHandlerImpl) DownloadExport(c *gin.Context) {
+	// step 1: ambil user yang login dan ID ekspornya
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+	exportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Export ID format",
+		})
+		return
+	}
+
+	// step 2: ambil arsipnya, bisa cukup besar kalau user punya banyak media
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Minute)
+	defer cancel()
+
+	archive, err := h.AccountService.DownloadExport(ctx, userID, exportID)
+	if err != nil {
+		status := accountErrorStatus(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// step 3: kirim sebagai file
+	filename := fmt.Sprintf("mood-bridge-export-%d.zip", exportID)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func (h *AccountHandlerImpl) RequestDeletion(c *gin.Context) {
+	var req request.DeleteAccountRequest
+	h.handle(c, http.StatusOK, &req, "Your account is scheduled for deletion, you can cancel before the scheduled time", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.AccountService.RequestDeletion(ctx, userID, req.Password)
+	})
+}
+
+func (h *AccountHandlerImpl) CancelDeletion(c *gin.Context) {
+	h.handle(c, http.StatusOK, nil, "Account deletion cancelled", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.AccountService.CancelDeletion(ctx, userID)
+	})
+}
+
+func (h *AccountHandlerImpl) GetDeletion(c *gin.Context) {
+	h.handle(c, http.StatusOK, nil, "Success", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.AccountService.GetDeletion(ctx, userID)
+	})
+}
+
+// handle alurnya sama dengan MFAHandler: ambil user yang login, bind body (kalau ada), lalu panggil service
+func (h *AccountHandlerImpl) handle(c *gin.Context, successStatus int, req interface{}, message string, apply func(ctx context.Context, userID int) (interface{}, error)) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+	if req != nil && !h.bind(c, req) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := apply(ctx, userID)
+	if err != nil {
+		status := accountErrorStatus(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(successStatus, gin.H{
+		"code":    successStatus,
+		"message": message,
+		"data":    response,
+	})
+}
+
+func (h *AccountHandlerImpl) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrExportNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrDeletionAlreadyScheduled), errors.Is(err, service.ErrDeletionNotScheduled), errors.Is(err, service.ErrExportNotReady):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrLastAdminDeletion):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"context"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Semua route admin sudah dijaga middleware.RequirePermission, jadi handler di sini tidak mengecek role lagi
+type AdminHandler interface {
+	GetUsers(c *gin.Context)
+	GetUser(c *gin.Context)
+	GetRoles(c *gin.Context)
+	Suspend(c *gin.Context)
+	Unsuspend(c *gin.Context)
+	Ban(c *gin.Context)
+	Unban(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	AssignRole(c *gin.Context)
+	RevokeRole(c *gin.Context)
+	ResetMFA(c *gin.Context)
+	RequireRoleMFA(c *gin.Context)
+	UnrequireRoleMFA(c *gin.Context)
+}
+
+type AdminHandlerImpl struct {
+	AdminService service.AdminService
+	validate     validator.Validate
+}
+
+func NewAdminHandler(adminService service.AdminService, validate validator.Validate) AdminHandler {
+	return &AdminHandlerImpl{
+		AdminService: adminService,
+		validate:     validate,
+	}
+}
+
+func (h *AdminHandlerImpl) GetUsers(c *gin.Context) {
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.AdminService.GetUsers(ctx, cursor, limit)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *AdminHandlerImpl) GetUser(c *gin.Context) {
+	userID, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.AdminService.GetUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    http.StatusNotFound,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *AdminHandlerImpl) GetRoles(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.AdminService.GetRoles(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *AdminHandlerImpl) Suspend(c *gin.Context) {
+	var req request.AdminSuspendRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	h.userAction(c, "User suspended successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.Suspend(ctx, adminID, userID, req)
+	})
+}
+
+func (h *AdminHandlerImpl) Unsuspend(c *gin.Context) {
+	h.userAction(c, "User unsuspended successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.Unsuspend(ctx, adminID, userID)
+	})
+}
+
+func (h *AdminHandlerImpl) Ban(c *gin.Context) {
+	var req request.AdminBanRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	h.userAction(c, "User banned successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.Ban(ctx, adminID, userID, req)
+	})
+}
+
+func (h *AdminHandlerImpl) Unban(c *gin.Context) {
+	h.userAction(c, "User unbanned successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.Unban(ctx, adminID, userID)
+	})
+}
+
+func (h *AdminHandlerImpl) ResetPassword(c *gin.Context) {
+	h.userAction(c, "Password reset successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.ResetPassword(ctx, adminID, userID)
+	})
+}
+
+func (h *AdminHandlerImpl) AssignRole(c *gin.Context) {
+	h.userAction(c, "Role assigned successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.AssignRole(ctx, adminID, userID, c.Param("role"))
+	})
+}
+
+func (h *AdminHandlerImpl) RevokeRole(c *gin.Context) {
+	h.userAction(c, "Role revoked successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.RevokeRole(ctx, adminID, userID, c.Param("role"))
+	})
+}
+
+func (h *AdminHandlerImpl) ResetMFA(c *gin.Context) {
+	h.userAction(c, "Two-factor authentication reset successfully", func(ctx context.Context, adminID, userID int) (interface{}, error) {
+		return h.AdminService.ResetMFA(ctx, adminID, userID)
+	})
+}
+
+func (h *AdminHandlerImpl) RequireRoleMFA(c *gin.Context) {
+	h.setRoleMFA(c, true, "Two-factor authentication is now required for this role")
+}
+
+func (h *AdminHandlerImpl) UnrequireRoleMFA(c *gin.Context) {
+	h.setRoleMFA(c, false, "Two-factor authentication is no longer required for this role")
+}
+
+func (h *AdminHandlerImpl) setRoleMFA(c *gin.Context, required bool, message string) {
+	adminID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.AdminService.SetRoleMFA(ctx, adminID, c.Param("role"), required)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": message,
+		"data":    response,
+	})
+}
+
+// userAction dipakai bersama semua tindakan admin terhadap satu user karena alurnya sama
+func (h *AdminHandlerImpl) userAction(c *gin.Context, message string, apply func(ctx context.Context, adminID, userID int) (interface{}, error)) {
+	adminID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	userID, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := apply(ctx, adminID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": message,
+		"data":    response,
+	})
+}
+
+func (h *AdminHandlerImpl) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseAdminUserID(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid User ID format",
+		})
+		return 0, false
+	}
+	return userID, true
+}
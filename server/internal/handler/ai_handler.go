@@ -3,17 +3,23 @@ package handler
 import (
     "context"
     "net/http"
+    "mood-bridge-v2/server/internal/model/request"
     "mood-bridge-v2/server/internal/service"
+    "time"
 
     "github.com/gin-gonic/gin"
 )
 
 type AIChatHandler struct {
-    AIService *service.DialoGPTService
+    AIService        *service.DialoGPTService
+    AIContextService service.AIContextService
 }
 
-func NewAIChatHandler(aiService *service.DialoGPTService) *AIChatHandler {
-    return &AIChatHandler{AIService: aiService}
+func NewAIChatHandler(aiService *service.DialoGPTService, aiContextService service.AIContextService) *AIChatHandler {
+    return &AIChatHandler{
+        AIService:        aiService,
+        AIContextService: aiContextService,
+    }
 }
 
 type ChatRequest struct {
@@ -32,7 +38,14 @@ func (h *AIChatHandler) HandleChat(c *gin.Context) {
         return
     }
 
-    reply, err := h.AIService.Chat(context.Background(), req.UserID, req.Message)
+    // userID selalu diambil dari token, supaya konteks personal user lain tidak bisa diminta lewat body
+    userID, ok := c.Request.Context().Value("userID").(int)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+
+    reply, err := h.AIService.Chat(context.Background(), userID, req.Message)
     if err != nil {
         c.Error(err)
         c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get response from AI"})
@@ -44,3 +57,99 @@ func (h *AIChatHandler) HandleChat(c *gin.Context) {
     })
 }
 
+// GetContext menunjukkan ke user persis konteks apa yang akan dikirim ke AI untuk pesan tertentu
+func (h *AIChatHandler) GetContext(c *gin.Context) {
+    userID, ok := c.Request.Context().Value("userID").(int)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "code":    http.StatusUnauthorized,
+            "message": "Unauthorized",
+        })
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+    defer cancel()
+
+    response, err := h.AIContextService.BuildContext(ctx, userID, c.Query("message"))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "code":    http.StatusInternalServerError,
+            "message": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "code":    http.StatusOK,
+        "message": "AI context retrieved successfully",
+        "data":    response,
+    })
+}
+
+func (h *AIChatHandler) GetContextSettings(c *gin.Context) {
+    userID, ok := c.Request.Context().Value("userID").(int)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "code":    http.StatusUnauthorized,
+            "message": "Unauthorized",
+        })
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+    defer cancel()
+
+    response, err := h.AIContextService.GetSettings(ctx, userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "code":    http.StatusInternalServerError,
+            "message": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "code":    http.StatusOK,
+        "message": "AI context settings retrieved successfully",
+        "data":    response,
+    })
+}
+
+func (h *AIChatHandler) UpdateContextSettings(c *gin.Context) {
+    userID, ok := c.Request.Context().Value("userID").(int)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "code":    http.StatusUnauthorized,
+            "message": "Unauthorized",
+        })
+        return
+    }
+
+    var req request.UpdateAIContextSettingsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "code":    http.StatusBadRequest,
+            "message": "Invalid request",
+        })
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+    defer cancel()
+
+    response, err := h.AIContextService.UpdateSettings(ctx, userID, req)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "code":    http.StatusInternalServerError,
+            "message": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "code":    http.StatusOK,
+        "message": "AI context settings updated successfully",
+        "data":    response,
+    })
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler interface {
+	GetEvents(c *gin.Context)
+	Verify(c *gin.Context)
+}
+
+type AuditHandlerImpl struct {
+	AuditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) AuditHandler {
+	return &AuditHandlerImpl{
+		AuditService: auditService,
+	}
+}
+
+func (h *AuditHandlerImpl) GetEvents(c *gin.Context) {
+	var query request.AuditEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid filter, actor_id/target_id must be numbers and from/to must be RFC3339 timestamps",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.AuditService.GetEvents(ctx, query, cursor, limit)
+	if err != nil {
+		status := pageErrorStatus(err)
+		if errors.Is(err, service.ErrInvalidAuditRange) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+// Verify bisa lama kalau log-nya besar, jadi batas waktunya lebih panjang dari endpoint lain
+func (h *AuditHandlerImpl) Verify(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Minute)
+	defer cancel()
+
+	response, err := h.AuditService.Verify(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
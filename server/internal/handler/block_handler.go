@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler interface {
+	GetBlocked(c *gin.Context)
+	Block(c *gin.Context)
+	Unblock(c *gin.Context)
+	GetMuted(c *gin.Context)
+	Mute(c *gin.Context)
+	Unmute(c *gin.Context)
+}
+
+type BlockHandlerImpl struct {
+	BlockService service.BlockService
+}
+
+func NewBlockHandler(blockService service.BlockService) BlockHandler {
+	return &BlockHandlerImpl{
+		BlockService: blockService,
+	}
+}
+
+func (h *BlockHandlerImpl) GetBlocked(c *gin.Context) {
+	h.list(c, h.BlockService.GetBlocked)
+}
+
+func (h *BlockHandlerImpl) GetMuted(c *gin.Context) {
+	h.list(c, h.BlockService.GetMuted)
+}
+
+func (h *BlockHandlerImpl) Block(c *gin.Context) {
+	h.change(c, h.BlockService.Block)
+}
+
+func (h *BlockHandlerImpl) Unblock(c *gin.Context) {
+	h.change(c, h.BlockService.Unblock)
+}
+
+func (h *BlockHandlerImpl) Mute(c *gin.Context) {
+	h.change(c, h.BlockService.Mute)
+}
+
+func (h *BlockHandlerImpl) Unmute(c *gin.Context) {
+	h.change(c, h.BlockService.Unmute)
+}
+
+// list dan change dipakai bersama oleh endpoint blokir dan mute karena bentuk request/response-nya sama
+func (h *BlockHandlerImpl) list(c *gin.Context, find func(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.BlockedUserResponse], error)) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := find(ctx, userID, cursor, limit)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *BlockHandlerImpl) change(c *gin.Context, apply func(ctx context.Context, userID, targetID int) (string, error)) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid User ID format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	message, err := apply(ctx, userID, targetID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": message,
+	})
+}
+
+// blockedErrorStatus dipakai endpoint yang bisa ditolak karena blokir, sisanya tetap pakai status bawaan endpoint-nya
+func blockedErrorStatus(err error, fallback int) int {
+	if errors.Is(err, service.ErrBlocked) {
+		return http.StatusForbidden
+	}
+	return fallback
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type BookmarkHandler interface {
+	CreateCollection(c *gin.Context)
+	GetCollections(c *gin.Context)
+	RenameCollection(c *gin.Context)
+	DeleteCollection(c *gin.Context)
+	GetPosts(c *gin.Context)
+	AddPost(c *gin.Context)
+	RemovePost(c *gin.Context)
+}
+
+type BookmarkHandlerImpl struct {
+	BookmarkService service.BookmarkService
+	validate        validator.Validate
+}
+
+func NewBookmarkHandler(bookmarkService service.BookmarkService, validate validator.Validate) BookmarkHandler {
+	return &BookmarkHandlerImpl{
+		BookmarkService: bookmarkService,
+		validate:        validate,
+	}
+}
+
+func (h *BookmarkHandlerImpl) CreateCollection(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	req, ok := h.bindCollectionRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.BookmarkService.CreateCollection(ctx, userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Bookmark collection created successfully",
+		"data":    response,
+	})
+}
+
+func (h *BookmarkHandlerImpl) GetCollections(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.BookmarkService.GetCollections(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *BookmarkHandlerImpl) RenameCollection(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindCollectionRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.BookmarkService.RenameCollection(ctx, userID, collectionID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Bookmark collection renamed successfully",
+		"data":    response,
+	})
+}
+
+func (h *BookmarkHandlerImpl) DeleteCollection(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	message, err := h.BookmarkService.DeleteCollection(ctx, userID, collectionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    http.StatusNotFound,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": message,
+	})
+}
+
+func (h *BookmarkHandlerImpl) GetPosts(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.BookmarkService.GetPosts(ctx, userID, collectionID, cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *BookmarkHandlerImpl) AddPost(c *gin.Context) {
+	h.togglePost(c, true)
+}
+
+func (h *BookmarkHandlerImpl) RemovePost(c *gin.Context) {
+	h.togglePost(c, false)
+}
+
+func (h *BookmarkHandlerImpl) togglePost(c *gin.Context, on bool) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+	postID, err := strconv.Atoi(c.Param("postId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Post ID format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var response interface{}
+	if on {
+		response, err = h.BookmarkService.AddPost(ctx, userID, collectionID, postID)
+	} else {
+		response, err = h.BookmarkService.RemovePost(ctx, userID, collectionID, postID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *BookmarkHandlerImpl) bindCollectionRequest(c *gin.Context) (request.BookmarkCollectionRequest, bool) {
+	var req request.BookmarkCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return req, false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return req, false
+	}
+	return req, true
+}
+
+func parseCollectionID(c *gin.Context) (int, bool) {
+	collectionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Collection ID format",
+		})
+		return 0, false
+	}
+	return collectionID, true
+}
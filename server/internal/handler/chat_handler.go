@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"mood-bridge-v2/server/internal/service"
 	"net/http"
@@ -37,28 +38,30 @@ func NewChatHandler(chatService service.ChatService) ChatHandler {
 }
 
 func (h *ChatHandlerImpl) HandleWebSocketConnection(c *gin.Context) {
-	// step 1: ambil userID dari query
-	userIDStr := c.Query("id")
-	if userIDStr == "" {
-		log.Println("Handler: User ID not provided in query")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    http.StatusBadRequest,
-			"message": "User ID is required",
+	// step 1: ambil userID dari token (query ?token= sudah divalidasi oleh AuthenticateWebSocket)
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		log.Println("Handler: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
 		})
 		return
 	}
 
-	// step 2: ubah ke int
-	userID, err := strconv.Atoi(userIDStr)
-	if err != nil || userID <= 0 {
-		log.Printf("Handler: Invalid user ID: %s", userIDStr)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    http.StatusBadRequest,
-			"message": "Invalid user ID",
+	// Appendix: cek dulu user boleh chat atau tidak, setelah di-upgrade sudah tidak bisa kirim response JSON
+	if err := h.chatService.Authorize(c.Request.Context(), userID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
 		})
 		return
 	}
-	
+
 	log.Printf("Handler: Attempting WebSocket upgrade for UserID: %d", userID)// buat test doang
 
 	// step 2: upgrade koneksi ke WebSocket
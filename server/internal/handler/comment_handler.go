@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
 	"mood-bridge-v2/server/internal/service"
 	"net/http"
 	"strconv"
@@ -14,9 +16,13 @@ import (
 
 type CommentHandler interface {
 	Create(c *gin.Context)
-	GetAllByPostID(c *gin.Context)
+	GetByPostID(c *gin.Context)
+	GetReplies(c *gin.Context)
+	Update(c *gin.Context)
 	Delete(c *gin.Context)
 	GetByID(c *gin.Context)
+	GetRevisions(c *gin.Context)
+	GetNeedsReview(c *gin.Context)
 }
 
 type CommentHandlerImpl struct {
@@ -47,8 +53,8 @@ func (h *CommentHandlerImpl) Create(c *gin.Context) {
 
 	response, err := h.CommentService.Create(ctx, request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(blockedErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"code":    blockedErrorStatus(err, http.StatusInternalServerError),
 			"message": err.Error(),
 		})
 		return
@@ -62,7 +68,7 @@ func (h *CommentHandlerImpl) Create(c *gin.Context) {
 	}
 }
 
-func (h *CommentHandlerImpl) GetAllByPostID(c *gin.Context) {
+func (h *CommentHandlerImpl) GetByPostID(c *gin.Context) {
 	postID := c.Param("id")
 	if postID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -82,17 +88,25 @@ func (h *CommentHandlerImpl) GetAllByPostID(c *gin.Context) {
 		return
 	}
 
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.CommentService.GetAllByPostID(ctx, postIDInt)
+	response, err := h.CommentService.GetByPostID(ctx, postIDInt, viewerID, cursor, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(commentErrorStatus(err), gin.H{
+			"code":    commentErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
 	} else {
+		applyCommentMoodOption(c, response.Items...)
 		c.JSON(http.StatusOK, gin.H{
 			"code":    http.StatusOK,
 			"message": "Comments Retrieved Successfully",
@@ -102,6 +116,179 @@ func (h *CommentHandlerImpl) GetAllByPostID(c *gin.Context) {
 	}
 }
 
+func (h *CommentHandlerImpl) GetReplies(c *gin.Context) {
+	commentIDInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Comment ID format",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.CommentService.GetReplies(ctx, commentIDInt, viewerID, cursor, limit)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{
+			"code":    commentErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+	applyCommentMoodOption(c, response.Items...)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Replies Retrieved Successfully",
+		"data":    response,
+	})
+}
+
+// applyCommentMoodOption menyembunyikan hasil klasifikasi mood kecuali client minta lewat ?include_mood=true
+func applyCommentMoodOption(c *gin.Context, comments ...*response.CreateCommentResponse) {
+	if c.Query("include_mood") == "true" {
+		return
+	}
+	for _, comment := range comments {
+		comment.Mood = ""
+	}
+}
+
+func (h *CommentHandlerImpl) Update(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	commentIDInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Comment ID format",
+		})
+		return
+	}
+
+	var req request.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.CommentService.Update(ctx, commentIDInt, userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Comment Updated Successfully",
+		"data":    response,
+	})
+}
+
+func (h *CommentHandlerImpl) GetRevisions(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	commentIDInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Comment ID format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.CommentService.GetRevisions(ctx, commentIDInt, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Comment History Retrieved Successfully",
+		"data":    response,
+	})
+}
+
+func (h *CommentHandlerImpl) GetNeedsReview(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.CommentService.GetNeedsReview(ctx, userID, cursor, limit)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{
+			"code":    commentErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Comments Needing Review Retrieved Successfully",
+		"data":    response,
+	})
+}
+
 func (h *CommentHandlerImpl) Delete(c *gin.Context) {
 	commentID := c.Param("id")
 	if commentID == "" {
@@ -121,13 +308,22 @@ func (h *CommentHandlerImpl) Delete(c *gin.Context) {
 		return
 	}
 
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	message, err := h.CommentService.Delete(ctx, commentIDInt)
+	message, err := h.CommentService.Delete(ctx, commentIDInt, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(commentErrorStatus(err), gin.H{
+			"code":    commentErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
@@ -167,13 +363,15 @@ func (h *CommentHandlerImpl) GetByID(c *gin.Context) {
 		return
 	}
 
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.CommentService.GetByID(ctx, commentIDInt)
+	response, err := h.CommentService.GetByID(ctx, commentIDInt, viewerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(commentErrorStatus(err), gin.H{
+			"code":    commentErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
@@ -185,6 +383,7 @@ func (h *CommentHandlerImpl) GetByID(c *gin.Context) {
 		})
 		return
 	} else {
+		applyCommentMoodOption(c, response)
 		c.JSON(http.StatusOK, gin.H{
 			"code":    http.StatusOK,
 			"message": "Comment Retrieved Successfully",
@@ -193,3 +392,14 @@ func (h *CommentHandlerImpl) GetByID(c *gin.Context) {
 		return
 	}
 }
+
+// commentErrorStatus: komentar atau post-nya tidak ada (atau tidak boleh dilihat) jadi 404, hapus komentar orang lain / antrian review tanpa akses moderator jadi 403, cursor rusak jadi 400
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCommentNotFound) || errors.Is(err, service.ErrPostNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCommentForbidden) || errors.Is(err, service.ErrCommentReviewForbidden):
+		return http.StatusForbidden
+	}
+	return pageErrorStatus(err)
+}
@@ -0,0 +1,12 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// getAuthenticatedUserID mengambil userID yang disimpan oleh middleware.Authenticate ke dalam request context
+func getAuthenticatedUserID(c *gin.Context) (int, bool) {
+	userID, ok := c.Request.Context().Value("userID").(int)
+	if !ok || userID <= 0 {
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler interface {
+	GetFeed(c *gin.Context)
+}
+
+type FeedHandlerImpl struct {
+	FeedService service.FeedService
+}
+
+func NewFeedHandler(feedService service.FeedService) FeedHandler {
+	return &FeedHandlerImpl{
+		FeedService: feedService,
+	}
+}
+
+func (h *FeedHandlerImpl) GetFeed(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.FeedService.GetFeed(ctx, userID, cursor, limit)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
 	"mood-bridge-v2/server/internal/service"
 	"net/http"
 	"strconv"
@@ -15,10 +17,15 @@ import (
 type FriendHandler interface {
 	AddFriend(c *gin.Context)
 	AcceptRequest(c *gin.Context)
+	DeclineRequest(c *gin.Context)
+	CancelRequest(c *gin.Context)
+	GetOutgoingRequests(c *gin.Context)
 	GetFriends(c *gin.Context)
 	Delete(c *gin.Context)
 	GetFriendRequests(c *gin.Context)
 	GetFriendRecommendation(c *gin.Context)
+	GetMutualFriends(c *gin.Context)
+	GetFriendSuggestions(c *gin.Context)
 }
 
 type FriendHandlerImpl struct {
@@ -48,8 +55,8 @@ func (h *FriendHandlerImpl) AddFriend(c *gin.Context) {
 
 	response, err := h.FriendService.AddFriend(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":   http.StatusInternalServerError,
+		c.JSON(friendErrorStatus(err), gin.H{
+			"code":   friendErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
@@ -77,8 +84,8 @@ func (h *FriendHandlerImpl) AcceptRequest(c *gin.Context) {
 
 	response, err := h.FriendService.AcceptRequest(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":   http.StatusInternalServerError,
+		c.JSON(friendErrorStatus(err), gin.H{
+			"code":   friendErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
@@ -91,6 +98,85 @@ func (h *FriendHandlerImpl) AcceptRequest(c *gin.Context) {
 	}
 }
 
+func (h *FriendHandlerImpl) DeclineRequest(c *gin.Context) {
+	h.respond(c, "Friend request declined", h.FriendService.DeclineRequest)
+}
+
+func (h *FriendHandlerImpl) CancelRequest(c *gin.Context) {
+	h.respond(c, "Friend request cancelled", h.FriendService.CancelRequest)
+}
+
+// respond dipakai decline dan cancel: ambil user yang login dan ID friend request dari path, lalu panggil service-nya
+func (h *FriendHandlerImpl) respond(c *gin.Context, message string, apply func(ctx context.Context, userID int, friendID int) (*response.FriendResponse, error)) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	friendID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":   http.StatusBadRequest,
+			"message": "Invalid Friend ID format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	friend, err := apply(ctx, userID, friendID)
+	if err != nil {
+		c.JSON(friendErrorStatus(err), gin.H{
+			"code":   friendErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code": http.StatusOK,
+		"message": message,
+		"data": friend,
+	})
+}
+
+func (h *FriendHandlerImpl) GetOutgoingRequests(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	outgoingRequests, err := h.FriendService.GetOutgoingRequests(ctx, userID, cursor, limit)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":   pageErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code": http.StatusOK,
+		"message": "Outgoing friend requests retrieved successfully",
+		"data": outgoingRequests,
+	})
+}
+
 func (h *FriendHandlerImpl) GetFriends(c *gin.Context) {
 	userID := c.Param("id")
 	if userID == "" {
@@ -110,13 +196,19 @@ func (h *FriendHandlerImpl) GetFriends(c *gin.Context) {
 		return
 	}
 
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	friends, err := h.FriendService.GetFriends(ctx, userIDInt)
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	friends, err := h.FriendService.GetFriends(ctx, userIDInt, viewerID, cursor, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":   http.StatusInternalServerError,
+		c.JSON(friendErrorStatus(err), gin.H{
+			"code":   friendErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
@@ -173,7 +265,42 @@ func (h *FriendHandlerImpl) Delete(c *gin.Context) {
 	}
 }
 
+// GetFriendRequests daftar permintaan pertemanan yang masuk ke user yang sedang login
 func (h *FriendHandlerImpl) GetFriendRequests(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// halaman kosong tetap 200, client cukup cek items
+	friendRequests, err := h.FriendService.GetFriendRequests(ctx, userID, cursor, limit)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":   pageErrorStatus(err),
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code": http.StatusOK,
+		"message": "Friend requests retrieved successfully",
+		"data": friendRequests,
+	})
+}
+
+func (h *FriendHandlerImpl) GetFriendRecommendation(c *gin.Context) {
 	userID := c.Param("id")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -195,7 +322,7 @@ func (h *FriendHandlerImpl) GetFriendRequests(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	friendRequests, err := h.FriendService.GetFriendRequests(ctx, userIDInt)
+	recommendations, err := h.FriendService.GetFriendRecommendation(ctx, userIDInt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":   http.StatusInternalServerError,
@@ -203,32 +330,32 @@ func (h *FriendHandlerImpl) GetFriendRequests(c *gin.Context) {
 		})
 		return
 	}
-	if len(friendRequests) == 0 {
+	if len(recommendations) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{
 			"code":   http.StatusNotFound,
-			"message": "No friend requests found",
+			"message": "No friend recommendations found",
 		})
 		return
 	} else {
 		c.JSON(http.StatusOK, gin.H{
 			"code": http.StatusOK,
-			"message": "Friend requests retrieved successfully",
-			"data": friendRequests,
+			"message": "Friend recommendations retrieved successfully",
+			"data": recommendations,
 		})
 	}
 }
 
-func (h *FriendHandlerImpl) GetFriendRecommendation(c *gin.Context) {
-	userID := c.Param("id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":   http.StatusBadRequest,
-			"message": "User ID is required",
+func (h *FriendHandlerImpl) GetMutualFriends(c *gin.Context) {
+	viewerID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
 		})
 		return
 	}
 
-	userIDInt, err := strconv.Atoi(userID)
+	userID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":   http.StatusBadRequest,
@@ -237,28 +364,71 @@ func (h *FriendHandlerImpl) GetFriendRecommendation(c *gin.Context) {
 		return
 	}
 
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	recommendations, err := h.FriendService.GetFriendRecommendation(ctx, userIDInt)
+	mutualFriends, err := h.FriendService.GetMutualFriends(ctx, viewerID, userID, cursor, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":   http.StatusInternalServerError,
+		c.JSON(friendErrorStatus(err), gin.H{
+			"code":   friendErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
 	}
-	if len(recommendations) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":   http.StatusNotFound,
-			"message": "No friend recommendations found",
+	c.JSON(http.StatusOK, gin.H{
+		"code": http.StatusOK,
+		"message": "Mutual friends retrieved successfully",
+		"data": mutualFriends,
+	})
+}
+
+func (h *FriendHandlerImpl) GetFriendSuggestions(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
 		})
 		return
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"code": http.StatusOK,
-			"message": "Friend recommendations retrieved successfully",
-			"data": recommendations,
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// tidak ada saran tetap 200 dengan array kosong
+	suggestions, err := h.FriendService.GetFriendSuggestions(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":   http.StatusInternalServerError,
+			"message": err.Error(),
 		})
+		return
 	}
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{
+		"code": http.StatusOK,
+		"message": "Friend suggestions retrieved successfully",
+		"data": suggestions,
+	})
+}
+
+func friendErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrFriendRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrFriendRequestExists), errors.Is(err, service.ErrFriendRequestIncoming), errors.Is(err, service.ErrAlreadyFriends):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrFriendRequestCooldown):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrFriendListHidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrMutualFriendsWithSelf):
+		return http.StatusBadRequest
+	default:
+		return blockedErrorStatus(err, pageErrorStatus(err))
+	}
+}
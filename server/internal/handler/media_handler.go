@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"mood-bridge-v2/server/internal/media"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler interface {
+	Upload(c *gin.Context)
+}
+
+type MediaHandlerImpl struct {
+	MediaService service.MediaService
+}
+
+func NewMediaHandler(mediaService service.MediaService) MediaHandler {
+	return &MediaHandlerImpl{
+		MediaService: mediaService,
+	}
+}
+
+// Upload menerima satu gambar (multipart field "image"). Id yang dikembalikan dipakai di media_ids waktu bikin postingan.
+func (h *MediaHandlerImpl) Upload(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	// step 1: ambil file-nya
+	data, ok := readImageUpload(c, "image")
+	if !ok {
+		return
+	}
+
+	// step 2: proses dan simpan (resize + upload ke storage bisa agak lama)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	response, err := h.MediaService.Upload(ctx, userID, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Image uploaded successfully",
+		"data":    response,
+	})
+}
+
+// readImageUpload membaca satu file gambar dari multipart form. Ukurannya dibatasi juga waktu dibaca
+// (header Size dari client tidak bisa dipercaya). Kalau gagal, response error-nya sudah dikirim.
+func readImageUpload(c *gin.Context, field string) ([]byte, bool) {
+	fileHeader, err := c.FormFile(field)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Image file is required",
+		})
+		return nil, false
+	}
+	if fileHeader.Size > media.MaxImageBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"code":    http.StatusRequestEntityTooLarge,
+			"message": "Image must be at most 5 MB",
+		})
+		return nil, false
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid image file",
+		})
+		return nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, media.MaxImageBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid image file",
+		})
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type MFAHandler interface {
+	GetStatus(c *gin.Context)
+	Enroll(c *gin.Context)
+	ConfirmEnrollment(c *gin.Context)
+	Disable(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+}
+
+type MFAHandlerImpl struct {
+	MFAService service.MFAService
+	validate   validator.Validate
+}
+
+func NewMFAHandler(mfaService service.MFAService, validate validator.Validate) MFAHandler {
+	return &MFAHandlerImpl{
+		MFAService: mfaService,
+		validate:   validate,
+	}
+}
+
+func (h *MFAHandlerImpl) GetStatus(c *gin.Context) {
+	h.handle(c, nil, "Success", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.MFAService.GetStatus(ctx, userID)
+	})
+}
+
+func (h *MFAHandlerImpl) Enroll(c *gin.Context) {
+	h.handle(c, nil, "Scan the QR code with your authenticator app, then confirm with the first code", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.MFAService.Enroll(ctx, userID)
+	})
+}
+
+func (h *MFAHandlerImpl) ConfirmEnrollment(c *gin.Context) {
+	var req request.MFACodeRequest
+	h.handle(c, &req, "Two-factor authentication enabled, store the recovery codes somewhere safe", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.MFAService.ConfirmEnrollment(ctx, userID, req.Code)
+	})
+}
+
+func (h *MFAHandlerImpl) Disable(c *gin.Context) {
+	var req request.MFACodeRequest
+	h.handle(c, &req, "Two-factor authentication disabled", func(ctx context.Context, userID int) (interface{}, error) {
+		return nil, h.MFAService.Disable(ctx, userID, req.Code)
+	})
+}
+
+func (h *MFAHandlerImpl) RegenerateRecoveryCodes(c *gin.Context) {
+	var req request.MFACodeRequest
+	h.handle(c, &req, "Recovery codes regenerated, the old codes no longer work", func(ctx context.Context, userID int) (interface{}, error) {
+		return h.MFAService.RegenerateRecoveryCodes(ctx, userID, req.Code)
+	})
+}
+
+// handle dipakai semua endpoint 2FA karena alurnya sama: ambil user yang login, bind body (kalau ada), lalu panggil service
+func (h *MFAHandlerImpl) handle(c *gin.Context, req interface{}, message string, apply func(ctx context.Context, userID int) (interface{}, error)) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+	if req != nil && !h.bind(c, req) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := apply(ctx, userID)
+	if err != nil {
+		status := mfaErrorStatus(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": message,
+		"data":    response,
+	})
+}
+
+func (h *MFAHandlerImpl) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrInvalidMFAToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrMFANotEnabled), errors.Is(err, service.ErrMFANotEnrolled):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrMFARequiredByRole):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ModerationHandler interface {
+	Report(c *gin.Context)
+	GetQueue(c *gin.Context)
+	GetReport(c *gin.Context)
+	Claim(c *gin.Context)
+	Resolve(c *gin.Context)
+	GetActions(c *gin.Context)
+}
+
+type ModerationHandlerImpl struct {
+	ModerationService service.ModerationService
+	validate          validator.Validate
+}
+
+func NewModerationHandler(moderationService service.ModerationService, validate validator.Validate) ModerationHandler {
+	return &ModerationHandlerImpl{
+		ModerationService: moderationService,
+		validate:          validate,
+	}
+}
+
+func (h *ModerationHandlerImpl) Report(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	var req request.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ModerationService.Report(ctx, userID, req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrAlreadyReported) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    http.StatusCreated,
+		"message": "Report submitted successfully",
+		"data":    response,
+	})
+}
+
+func (h *ModerationHandlerImpl) GetQueue(c *gin.Context) {
+	moderatorID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ModerationService.GetQueue(ctx, moderatorID, c.Query("status"), cursor, limit)
+	if err != nil {
+		status := moderationErrorStatus(err, pageErrorStatus(err))
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *ModerationHandlerImpl) GetReport(c *gin.Context) {
+	moderatorID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	reportID, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ModerationService.GetReport(ctx, moderatorID, reportID)
+	if err != nil {
+		status := moderationErrorStatus(err, http.StatusNotFound)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *ModerationHandlerImpl) Claim(c *gin.Context) {
+	moderatorID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	reportID, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ModerationService.Claim(ctx, moderatorID, reportID)
+	if err != nil {
+		status := moderationErrorStatus(err, http.StatusBadRequest)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Report claimed successfully",
+		"data":    response,
+	})
+}
+
+func (h *ModerationHandlerImpl) Resolve(c *gin.Context) {
+	moderatorID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	reportID, ok := parseReportID(c)
+	if !ok {
+		return
+	}
+
+	var req request.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.ModerationService.Resolve(ctx, moderatorID, reportID, req)
+	if err != nil {
+		status := moderationErrorStatus(err, http.StatusBadRequest)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Report resolved successfully",
+		"data":    response,
+	})
+}
+
+func (h *ModerationHandlerImpl) GetActions(c *gin.Context) {
+	moderatorID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ModerationService.GetActions(ctx, moderatorID, cursor, limit)
+	if err != nil {
+		status := moderationErrorStatus(err, pageErrorStatus(err))
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func parseReportID(c *gin.Context) (int, bool) {
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Report ID format",
+		})
+		return 0, false
+	}
+	return reportID, true
+}
+
+// moderationErrorStatus: 403 untuk yang bukan moderator, 409 kalau laporannya sudah diambil/diselesaikan moderator lain
+func moderationErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrNotModerator):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrReportClaimed), errors.Is(err, service.ErrReportResolved):
+		return http.StatusConflict
+	}
+	return fallback
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"errors"
+	"mood-bridge-v2/server/internal/pagination"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePageParams membaca query cursor dan limit untuk endpoint list, langsung kirim 400 kalau limit tidak valid
+func parsePageParams(c *gin.Context) (string, int, bool) {
+	limit, err := pagination.ParseLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid limit parameter, must be between 1 and 50",
+		})
+		return "", 0, false
+	}
+	return c.Query("cursor"), limit, true
+}
+
+// pageErrorStatus membedakan cursor rusak/palsu (salah client) dari error server biasa
+func pageErrorStatus(err error) int {
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/service"
 	"net/http"
@@ -20,6 +21,8 @@ type PostHandler interface {
 	Update (c *gin.Context)
 	Delete (c *gin.Context)
 	GetFriendPosts(c *gin.Context)
+	GetRevisions(c *gin.Context)
+	Search(c *gin.Context)
 }
 
 type PostHandlerImpl struct {
@@ -45,6 +48,11 @@ func (h *PostHandlerImpl) Create(c *gin.Context) {
 		return
 	}
 
+	// author selalu diambil dari token, supaya postingan (termasuk yang anonim) tetap bisa ditelusuri ke pemiliknya
+	if userID, ok := getAuthenticatedUserID(c); ok {
+		req.UserID = userID
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
@@ -87,7 +95,8 @@ func (h *PostHandlerImpl) Find(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.PostService.Find(ctx, postID)
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.PostService.Find(ctx, postID, viewerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
@@ -112,52 +121,30 @@ func (h *PostHandlerImpl) Find(c *gin.Context) {
 }
 
 func(h *PostHandlerImpl) FindAll(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	defer cancel()
-
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":   http.StatusBadRequest,
-			"message": "Invalid limit parameter, must be a positive integer",
-		})
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
 		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    http.StatusBadRequest,
-			"message": "Invalid offset parameter, must be a non-negative integer",
-		})
-		return
-	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
 
-	response, err := h.PostService.FindAll(ctx, limit, offset)
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.PostService.FindAll(ctx, viewerID, cursor, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
-	} 
-	if len(response) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    http.StatusNotFound,
-			"message": "No posts found",
-		})
-		return
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"code":    http.StatusOK,
-			"message": "Success",
-			"data":    response,
-		})
-		return
 	}
+
+	// halaman kosong tetap 200, client cukup cek items
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
 }
 
 func (h *PostHandlerImpl) FindByUserID(c *gin.Context) {
@@ -178,34 +165,40 @@ func (h *PostHandlerImpl) FindByUserID(c *gin.Context) {
 		return
 	}
 
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.PostService.FindByUserID(ctx, userID)
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.PostService.FindByUserID(ctx, userID, viewerID, cursor, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
-	} 
-	if len(response) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    http.StatusNotFound,
-			"message": "No posts found for this user",
-		})
-		return
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"code":    http.StatusOK,
-			"message": "Success",
-			"data":    response,
-		})
-		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
 }
 
 func (h *PostHandlerImpl) Update(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
 	var req request.CreatePostRequest
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
@@ -236,7 +229,7 @@ func (h *PostHandlerImpl) Update(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.PostService.Update(ctx, postID, req)
+	response, err := h.PostService.Update(ctx, postID, userID, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
@@ -278,13 +271,22 @@ func (h *PostHandlerImpl) Delete(c *gin.Context) {
 		return
 	}
 
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.PostService.Delete(ctx, postID)
+	response, err := h.PostService.Delete(ctx, postID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(postErrorStatus(err), gin.H{
+			"code":    postErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
@@ -315,22 +317,53 @@ func (h *PostHandlerImpl) GetFriendPosts(c *gin.Context) {
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
+	// feed teman isinya postingan friends-only, jadi cuma boleh dibuka sama pemilik akunnya sendiri
+	viewerID, ok := getAuthenticatedUserID(c)
+	if !ok || viewerID != userIDInt {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code":    http.StatusForbidden,
+			"message": "You can only view your own friend feed",
+		})
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    http.StatusBadRequest,
-			"message": "Invalid limit parameter, must be a positive integer",
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.PostService.GetFriendPosts(ctx, userIDInt, cursor, limit)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
+			"message": err.Error(),
 		})
 		return
 	}
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+func (h *PostHandlerImpl) GetRevisions(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    http.StatusBadRequest,
-			"message": "Invalid offset parameter, must be a non-negative integer",
+			"message": "Invalid Post ID format",
 		})
 		return
 	}
@@ -338,27 +371,66 @@ func (h *PostHandlerImpl) GetFriendPosts(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.PostService.GetFriendPosts(ctx, userIDInt, limit, offset)
+	response, err := h.PostService.GetRevisions(ctx, postID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(postErrorStatus(err), gin.H{
+			"code":    postErrorStatus(err),
 			"message": err.Error(),
 		})
 		return
 	}
 
-	if len(response) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    http.StatusNotFound,
-			"message": "No friend posts found",
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *PostHandlerImpl) Search(c *gin.Context) {
+	var req request.SearchPostRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid search parameters",
 		})
 		return
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"code":    http.StatusOK,
-			"message": "Success",
-			"data":    response,
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
 		})
 		return
 	}
-}
\ No newline at end of file
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.PostService.Search(ctx, viewerID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+// postErrorStatus: post yang tidak ada (atau tidak boleh dilihat) jadi 404, akses ke riwayat / hapus post orang lain jadi 403
+func postErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPostNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPostHistoryForbidden) || errors.Is(err, service.ErrPostForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ProfileHandler interface {
+	Find(c *gin.Context)
+	Update(c *gin.Context)
+}
+
+type ProfileHandlerImpl struct {
+	ProfileService service.ProfileService
+	validate       validator.Validate
+}
+
+func NewProfileHandler(profileService service.ProfileService, validate validator.Validate) ProfileHandler {
+	return &ProfileHandlerImpl{
+		ProfileService: profileService,
+		validate:       validate,
+	}
+}
+
+// Find mengembalikan profil user, field yang tidak boleh dilihat viewer tidak ikut dikirim
+func (h *ProfileHandlerImpl) Find(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid User ID format",
+		})
+		return
+	}
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ProfileService.Find(ctx, userID, viewerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    http.StatusNotFound,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+// Update mengubah profil user yang sedang login, cuma field yang dikirim yang diubah
+func (h *ProfileHandlerImpl) Update(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	// step 1: ambil dan validasi request-nya
+	var req request.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// step 2: call service-nya
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.ProfileService.Update(ctx, userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Profile updated successfully",
+		"data":    response,
+	})
+}
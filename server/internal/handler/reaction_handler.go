@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionHandler interface {
+	React(c *gin.Context)
+	Unreact(c *gin.Context)
+	GetReactions(c *gin.Context)
+	GetReactors(c *gin.Context)
+}
+
+type ReactionHandlerImpl struct {
+	ReactionService service.ReactionService
+}
+
+func NewReactionHandler(reactionService service.ReactionService) ReactionHandler {
+	return &ReactionHandlerImpl{
+		ReactionService: reactionService,
+	}
+}
+
+func (h *ReactionHandlerImpl) React(c *gin.Context) {
+	h.toggle(c, true)
+}
+
+func (h *ReactionHandlerImpl) Unreact(c *gin.Context) {
+	h.toggle(c, false)
+}
+
+func (h *ReactionHandlerImpl) toggle(c *gin.Context, on bool) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Post ID format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var response interface{}
+	if on {
+		response, err = h.ReactionService.React(ctx, postID, userID, c.Param("type"))
+	} else {
+		response, err = h.ReactionService.Unreact(ctx, postID, userID, c.Param("type"))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *ReactionHandlerImpl) GetReactions(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Post ID format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.ReactionService.GetReactions(ctx, postID, viewerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    http.StatusNotFound,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *ReactionHandlerImpl) GetReactors(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid Post ID format",
+		})
+		return
+	}
+
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.ReactionService.GetReactors(ctx, postID, viewerID, c.Query("type"), cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
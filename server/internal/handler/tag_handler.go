@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler interface {
+	Find(c *gin.Context)
+	GetPosts(c *gin.Context)
+	Follow(c *gin.Context)
+	Unfollow(c *gin.Context)
+	GetFollowedTags(c *gin.Context)
+	GetTrending(c *gin.Context)
+}
+
+type TagHandlerImpl struct {
+	TagService service.TagService
+}
+
+func NewTagHandler(tagService service.TagService) TagHandler {
+	return &TagHandlerImpl{
+		TagService: tagService,
+	}
+}
+
+func (h *TagHandlerImpl) Find(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.TagService.Find(ctx, c.Param("name"), viewerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *TagHandlerImpl) GetPosts(c *gin.Context) {
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	viewerID, _ := getAuthenticatedUserID(c) // 0 kalau belum login
+	response, err := h.TagService.GetPosts(ctx, c.Param("name"), viewerID, cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *TagHandlerImpl) Follow(c *gin.Context) {
+	h.toggleFollow(c, true)
+}
+
+func (h *TagHandlerImpl) Unfollow(c *gin.Context) {
+	h.toggleFollow(c, false)
+}
+
+func (h *TagHandlerImpl) toggleFollow(c *gin.Context, on bool) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var response interface{}
+	var err error
+	if on {
+		response, err = h.TagService.Follow(ctx, c.Param("name"), userID)
+	} else {
+		response, err = h.TagService.Unfollow(ctx, c.Param("name"), userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *TagHandlerImpl) GetFollowedTags(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.TagService.GetFollowedTags(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
+
+func (h *TagHandlerImpl) GetTrending(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid limit parameter, must be between 1 and 50",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.TagService.GetTrending(ctx, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Success",
+		"data":    response,
+	})
+}
@@ -2,6 +2,9 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/service"
 	"net/http"
@@ -19,7 +22,10 @@ type UserHandler interface {
 	FindByID(c *gin.Context)
 	FindAll(c *gin.Context)
 	Login(c *gin.Context)
+	LoginMFA(c *gin.Context)
 	Update(c *gin.Context)
+	UploadAvatar(c *gin.Context)
+	DeleteAvatar(c *gin.Context)
 }
 
 type UserHandlerImpl struct {
@@ -178,15 +184,21 @@ func (h *UserHandlerImpl) FindByID(c *gin.Context) {
 }
 
 func (h *UserHandlerImpl) FindAll(c *gin.Context) {
-	// step 1: buat context buat ngatur time-out (handle connection time-out)
+	// step 1: ambil cursor dan limit dari query parameter
+	cursor, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	// step 2: buat context buat ngatur time-out (handle connection time-out)
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	// step 2: call service-nya buat find all user-nya
-	response, err := h.UserService.FindAll(ctx)
+	// step 3: call service-nya buat find all user-nya
+	response, err := h.UserService.FindAll(ctx, cursor, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(pageErrorStatus(err), gin.H{
+			"code":    pageErrorStatus(err),
 			"message": "Failed to find all users",
 		})
 		return
@@ -226,21 +238,96 @@ func (h *UserHandlerImpl) Login(c *gin.Context) {
 	defer cancel()
 
 	// step 3: call service-nya buat login user-nya
-	token, err := h.UserService.Login(ctx, request)
+	result, err := h.UserService.Login(ctx, request)
+	if respondAccountLocked(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
 			"message": "Failed to login user",
 		})
 		return
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"code":    http.StatusOK,
-			"message": "User logged in successfully",
-			"data":    token,
+	}
+
+	// step 4: user dengan 2FA dapat token "mfa pending" dulu, JWT-nya baru dikirim oleh LoginMFA
+	if result.MFAChallenge != nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"code":    http.StatusAccepted,
+			"message": "Two-factor code required",
+			"data":    result.MFAChallenge,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "User logged in successfully",
+		"data":    result.Token,
+	})
+}
+
+func (h *UserHandlerImpl) LoginMFA(c *gin.Context) {
+	// step 1: ambil request dari body sekaligus validasi
+	var request request.LoginMFARequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return
+	}
+	if err := h.validate.Struct(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return
+	}
+
+	// step 2: buat context buat ngatur time-out (handle connection time-out)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// step 3: tukar token "mfa pending" dan kodenya dengan JWT
+	token, err := h.UserService.LoginMFA(ctx, request)
+	if respondAccountLocked(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrInvalidMFACode) || errors.Is(err, service.ErrInvalidMFAToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": "Failed to login user",
 		})
 		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "User logged in successfully",
+		"data":    token,
+	})
+}
+
+// respondAccountLocked membalas 429 dengan Retry-After kalau login ditolak karena akunnya sedang dikunci
+func respondAccountLocked(c *gin.Context, err error) bool {
+	var lockedErr *service.AccountLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+	retryAfter := int(math.Ceil(lockedErr.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"code":    http.StatusTooManyRequests,
+		"message": fmt.Sprintf("Too many failed login attempts, try again in %d seconds", retryAfter),
+	})
+	return true
 }
 
 func (h *UserHandlerImpl) Update(c *gin.Context) {
@@ -274,15 +361,25 @@ func (h *UserHandlerImpl) Update(c *gin.Context) {
 		return
 	}
 
+	// step 2.1: yang boleh update cuma pemilik akunnya, jadi ID-nya diambil dari token
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
 	// step 3: buat context buat ngatur time-out (handle connection time-out)
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	// step 4: call service-nya buat update user-nya
-	response, err := h.UserService.Update(ctx, idInt, request)
+	response, err := h.UserService.Update(ctx, userID, idInt, request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		c.JSON(userUpdateErrorStatus(err), gin.H{
+			"code":    userUpdateErrorStatus(err),
 			"error":   err.Error(),
 			"message": "Failed to update user",
 		})
@@ -295,4 +392,82 @@ func (h *UserHandlerImpl) Update(c *gin.Context) {
 		})
 		return
 	}
-}
\ No newline at end of file
+}
+
+// UploadAvatar mengganti avatar user yang sedang login (multipart field "avatar")
+func (h *UserHandlerImpl) UploadAvatar(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	// step 1: ambil file-nya
+	data, ok := readImageUpload(c, "avatar")
+	if !ok {
+		return
+	}
+
+	// step 2: proses dan simpan (crop + resize + upload ke storage bisa agak lama)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	response, err := h.UserService.UploadAvatar(ctx, userID, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Avatar updated successfully",
+		"data":    response,
+	})
+}
+
+// DeleteAvatar mengembalikan user yang sedang login ke avatar default
+func (h *UserHandlerImpl) DeleteAvatar(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.UserService.DeleteAvatar(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Avatar removed successfully",
+		"data":    response,
+	})
+}
+
+// userUpdateErrorStatus: update akun orang lain jadi 403, password sekarang salah jadi 400
+func userUpdateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUserUpdateForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrCurrentPasswordMismatch):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondAccountLockedRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		failures       int
+		elapsed        time.Duration
+		wantLocked     bool
+		wantRetryAfter string
+	}{
+		{name: "not locked below threshold", failures: 4},
+		{name: "first lock", failures: 5, wantLocked: true, wantRetryAfter: "60"},
+		{name: "partway through lock rounds up", failures: 5, elapsed: 20500 * time.Millisecond, wantLocked: true, wantRetryAfter: "40"},
+		{name: "escalated lock", failures: 7, wantLocked: true, wantRetryAfter: "240"},
+		{name: "lock expired", failures: 5, elapsed: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			store := ratelimit.NewMemoryStore()
+			store.Now = func() time.Time { return now }
+			lockout := ratelimit.NewLockout(store, "login", ratelimit.DefaultLoginLockout)
+
+			for i := 0; i < tt.failures; i++ {
+				if _, err := lockout.Fail(ctx, "alice"); err != nil {
+					t.Fatalf("Fail() error = %v", err)
+				}
+			}
+			now = now.Add(tt.elapsed)
+
+			// sama seperti UserService.Login: lock yang masih aktif dikembalikan sebagai AccountLockedError
+			lockedFor, err := lockout.LockedFor(ctx, "alice")
+			if err != nil {
+				t.Fatalf("LockedFor() error = %v", err)
+			}
+			var loginErr error = fmt.Errorf("invalid credentials")
+			if lockedFor > 0 {
+				loginErr = &service.AccountLockedError{RetryAfter: lockedFor}
+			}
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			locked := respondAccountLocked(c, loginErr)
+
+			if locked != tt.wantLocked {
+				t.Fatalf("respondAccountLocked() = %v, want %v", locked, tt.wantLocked)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if tt.wantLocked && rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type VerificationHandler interface {
+	ConfirmEmail(c *gin.Context)
+	ResendVerification(c *gin.Context)
+	RequestPasswordReset(c *gin.Context)
+	ResetPassword(c *gin.Context)
+}
+
+type VerificationHandlerImpl struct {
+	VerificationService service.VerificationService
+	validate            validator.Validate
+}
+
+func NewVerificationHandler(verificationService service.VerificationService, validate validator.Validate) VerificationHandler {
+	return &VerificationHandlerImpl{
+		VerificationService: verificationService,
+		validate:            validate,
+	}
+}
+
+func (h *VerificationHandlerImpl) ConfirmEmail(c *gin.Context) {
+	var req request.VerifyEmailRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.VerificationService.ConfirmEmail(ctx, req.Token); err != nil {
+		status := verificationErrorStatus(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Email verified successfully",
+	})
+}
+
+func (h *VerificationHandlerImpl) ResendVerification(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "Unauthorized",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.VerificationService.SendVerification(ctx, userID); err != nil {
+		status := verificationErrorStatus(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Verification email sent",
+	})
+}
+
+// RequestPasswordReset selalu membalas sukses walaupun email-nya tidak terdaftar
+func (h *VerificationHandlerImpl) RequestPasswordReset(c *gin.Context) {
+	var req request.PasswordResetRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.VerificationService.RequestPasswordReset(ctx, req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": "Failed to request password reset",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+func (h *VerificationHandlerImpl) ResetPassword(c *gin.Context) {
+	var req request.ConfirmPasswordResetRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.VerificationService.ResetPassword(ctx, req.Token, req.Password); err != nil {
+		status := verificationErrorStatus(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Password reset successfully",
+	})
+}
+
+func (h *VerificationHandlerImpl) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid request",
+		})
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func verificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidEmailToken):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,70 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+)
+
+// Ukuran avatar (sisi persegi, pixel) yang dibuat untuk setiap upload
+const (
+	AvatarSmall  = 64
+	AvatarMedium = 256
+	AvatarLarge  = 512
+)
+
+var AvatarSizes = []int{AvatarSmall, AvatarMedium, AvatarLarge}
+
+// ProcessAvatar memotong gambar jadi persegi di tengah lalu membuat versi JPEG untuk setiap ukuran di AvatarSizes.
+// Sama seperti Process, hasilnya di-encode ulang jadi metadata aslinya tidak ikut tersimpan.
+func ProcessAvatar(data []byte) (map[int][]byte, error) {
+	// step 1: validasi, decode, dan rapikan orientasinya
+	img, _, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: potong jadi persegi lalu ratakan transparansinya (avatar selalu JPEG)
+	img = flatten(cropSquare(img))
+	side := img.Bounds().Dx()
+
+	// step 3: encode setiap ukuran, gambar yang lebih kecil dari ukuran target tidak diperbesar
+	result := make(map[int][]byte, len(AvatarSizes))
+	for _, size := range AvatarSizes {
+		target := size
+		if target > side {
+			target = side
+		}
+		result[size], err = encodeJPEG(resize(img, target, target))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// cropSquare mengambil bagian persegi terbesar di tengah gambar
+func cropSquare(src *image.RGBA) *image.RGBA {
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if width == height {
+		return src
+	}
+	side := width
+	if height < side {
+		side = height
+	}
+	offset := image.Pt((width-side)/2, (height-side)/2)
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, offset, draw.Src)
+	return dst
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,191 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	MaxImageBytes    = 5 << 20    // 5 MB per gambar
+	MaxImagePixels   = 40_000_000 // tolak gambar raksasa sebelum di-decode (decompression bomb)
+	MaxImagesPerPost = 4
+	MaxDimension     = 2048 // sisi terpanjang gambar yang disimpan
+	ThumbnailSize    = 320  // sisi terpanjang thumbnail
+	jpegQuality      = 85
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image format, only JPEG and PNG are allowed")
+
+// ProcessedImage hasil olahan satu gambar yang siap disimpan. Data dan Thumbnail sudah di-encode ulang,
+// jadi metadata asli (EXIF, lokasi GPS, nama kamera, dll) tidak ikut tersimpan.
+type ProcessedImage struct {
+	Data            []byte
+	ContentType     string
+	Extension       string
+	Width           int
+	Height          int
+	Thumbnail       []byte // selalu JPEG
+	ThumbnailWidth  int
+	ThumbnailHeight int
+	Blurhash        string
+}
+
+// Process memvalidasi, merapikan orientasi, mengecilkan, dan membuat thumbnail + blurhash dari gambar yang di-upload
+func Process(data []byte) (*ProcessedImage, error) {
+	// step 1-3: validasi, decode, dan rapikan orientasinya
+	img, contentType, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: kecilkan kalau terlalu besar lalu encode ulang
+	width, height := fit(img.Bounds().Dx(), img.Bounds().Dy(), MaxDimension)
+	img = resize(img, width, height)
+
+	result := &ProcessedImage{
+		ContentType: contentType,
+		Width:       width,
+		Height:      height,
+	}
+	var buf bytes.Buffer
+	if contentType == "image/png" {
+		result.Extension = "png"
+		err = png.Encode(&buf, img)
+	} else {
+		result.Extension = "jpg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Data = buf.Bytes()
+
+	// step 5: thumbnail (JPEG, transparansi diganti latar putih) dan blurhash dari thumbnail-nya
+	result.ThumbnailWidth, result.ThumbnailHeight = fit(width, height, ThumbnailSize)
+	thumbnail := flatten(resize(img, result.ThumbnailWidth, result.ThumbnailHeight))
+	var thumbBuf bytes.Buffer
+	if err := jpeg.Encode(&thumbBuf, thumbnail, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	result.Thumbnail = thumbBuf.Bytes()
+
+	hashWidth, hashHeight := fit(result.ThumbnailWidth, result.ThumbnailHeight, 32)
+	result.Blurhash = EncodeBlurhash(resize(thumbnail, hashWidth, hashHeight), 4, 3)
+
+	return result, nil
+}
+
+// decode memvalidasi gambar lalu men-decode dan memutarnya sesuai orientasi EXIF, dipakai bersama oleh Process dan ProcessAvatar
+func decode(data []byte) (*image.RGBA, string, error) {
+	// step 1: validasi ukuran file dan format aslinya
+	if len(data) == 0 {
+		return nil, "", fmt.Errorf("image is empty")
+	}
+	if len(data) > MaxImageBytes {
+		return nil, "", fmt.Errorf("image must be at most %d MB", MaxImageBytes>>20)
+	}
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	// step 2: cek dimensinya dulu sebelum decode penuh
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image: %v", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxImagePixels {
+		return nil, "", fmt.Errorf("image dimensions are too large")
+	}
+
+	// step 3: decode lalu putar sesuai orientasi EXIF (karena EXIF-nya nanti dibuang)
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image: %v", err)
+	}
+	img := toRGBA(decoded)
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, exifOrientation(data))
+	}
+
+	return img, contentType, nil
+}
+
+// toRGBA menyalin gambar ke *image.RGBA dengan origin (0,0) supaya bisa diolah langsung lewat Pix
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// flatten menaruh gambar di atas latar putih, dipakai sebelum encode ke JPEG yang tidak punya alpha
+func flatten(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, image.Point{}, draw.Over)
+	return dst
+}
+
+// fit menghitung ukuran baru supaya sisi terpanjangnya tidak lebih dari max (tidak pernah memperbesar)
+func fit(width, height, max int) (int, int) {
+	if width <= max && height <= max {
+		return width, height
+	}
+	if width >= height {
+		return max, maxInt(1, height*max/width)
+	}
+	return maxInt(1, width*max/height), max
+}
+
+// resize mengecilkan gambar dengan box filter: setiap pixel tujuan adalah rata-rata area pixel asal yang ditutupinya
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if width == srcWidth && height == srcHeight {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := maxInt((y+1)*srcHeight/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := maxInt((x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+					count++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / count)
+			dst.Pix[i+1] = uint8(g / count)
+			dst.Pix[i+2] = uint8(b / count)
+			dst.Pix[i+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
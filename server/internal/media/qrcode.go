@@ -0,0 +1,479 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Implementasi encoder QR code (ISO/IEC 18004) untuk menampilkan URI otpauth waktu enroll 2FA.
+// Cukup mode byte dengan error correction level M sampai versi 10 (maksimal 213 byte),
+// URI otpauth biasanya tidak sampai 150 byte.
+
+var ErrQRCodeTooLong = errors.New("qr code content is too long")
+
+// qrQuietZone lebar margin putih di sekeliling QR code (dalam modul), minimal 4 menurut spesifikasi
+const qrQuietZone = 4
+
+type qrVersion struct {
+	ecPerBlock int   // jumlah codeword error correction di setiap block
+	blocks     []int // jumlah codeword data di setiap block
+	alignment  []int // posisi baris/kolom alignment pattern
+}
+
+// qrVersions untuk level M, index 0 adalah versi 1
+var qrVersions = []qrVersion{
+	{10, []int{16}, nil},
+	{16, []int{28}, []int{6, 18}},
+	{26, []int{44}, []int{6, 22}},
+	{18, []int{32, 32}, []int{6, 26}},
+	{24, []int{43, 43}, []int{6, 30}},
+	{16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+func (v qrVersion) dataCodewords() int {
+	total := 0
+	for _, n := range v.blocks {
+		total += n
+	}
+	return total
+}
+
+// EncodeQRCodePNG menghasilkan gambar PNG hitam putih, setiap modul berukuran scale x scale piksel
+func EncodeQRCodePNG(content string, scale int) ([]byte, error) {
+	modules, err := encodeQRCode([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	if scale < 1 {
+		scale = 1
+	}
+
+	size := len(modules)
+	pixels := (size + 2*qrQuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, pixels, pixels), color.Palette{color.White, color.Black})
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if !modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				offset := ((y+qrQuietZone)*scale+dy)*img.Stride + (x+qrQuietZone)*scale
+				for dx := 0; dx < scale; dx++ {
+					img.Pix[offset+dx] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeQRCode mengembalikan matriks modul (true = hitam) dengan versi terkecil yang cukup dan mask terbaik
+func encodeQRCode(data []byte) ([][]bool, error) {
+	// step 1: pilih versi terkecil yang muat
+	version := 0
+	for i, v := range qrVersions {
+		if qrDataBits(i+1, len(data)) <= v.dataCodewords()*8 {
+			version = i + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrQRCodeTooLong
+	}
+
+	// step 2: susun codeword data + error correction
+	codewords := qrCodewords(data, version)
+
+	// step 3: coba semua mask dan ambil yang penalty-nya paling kecil
+	var best [][]bool
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		modules := qrBuildMatrix(codewords, version, mask)
+		if penalty := qrPenalty(modules); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = modules, penalty
+		}
+	}
+	return best, nil
+}
+
+func qrCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+func qrDataBits(version, length int) int {
+	return 4 + qrCountBits(version) + length*8
+}
+
+// qrCodewords menyusun bitstream mode byte, membaginya ke block, menambahkan error correction Reed-Solomon,
+// lalu menyelang-nyeling (interleave) codeword dari setiap block
+func qrCodewords(data []byte, version int) []byte {
+	v := qrVersions[version-1]
+	capacity := v.dataCodewords()
+
+	// step 1: mode indicator (0100 = byte), jumlah karakter, lalu datanya
+	var bits qrBitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), qrCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	// step 2: terminator, genapkan ke byte, lalu isi sisa kapasitas dengan pad byte 0xEC 0x11 bergantian
+	bits.append(0, min(4, capacity*8-bits.length))
+	bits.append(0, (8-bits.length%8)%8)
+	for pad := 0xEC; bits.length < capacity*8; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	// step 3: bagi ke block dan hitung error correction setiap block
+	generator := qrGeneratorPolynomial(v.ecPerBlock)
+	dataBlocks := make([][]byte, len(v.blocks))
+	ecBlocks := make([][]byte, len(v.blocks))
+	offset := 0
+	for i, n := range v.blocks {
+		dataBlocks[i] = bits.bytes[offset : offset+n]
+		ecBlocks[i] = qrRemainder(dataBlocks[i], generator)
+		offset += n
+	}
+
+	// step 4: interleave, codeword ke-i dari setiap block dulu baru ke-(i+1)
+	result := make([]byte, 0, capacity+v.ecPerBlock*len(v.blocks))
+	for i := 0; i < v.blocks[len(v.blocks)-1]; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+type qrBitBuffer struct {
+	bytes  []byte
+	length int
+}
+
+func (b *qrBitBuffer) append(value, count int) {
+	for i := count - 1; i >= 0; i-- {
+		if b.length%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if (value>>i)&1 == 1 {
+			b.bytes[b.length/8] |= 0x80 >> (b.length % 8)
+		}
+		b.length++
+	}
+}
+
+// Aritmetika GF(256) dengan polinomial x^8 + x^4 + x^3 + x^2 + 1 (0x11D)
+var qrExp, qrLog = qrGaloisTables()
+
+func qrGaloisTables() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}
+
+func qrMultiply(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return qrExp[int(qrLog[a])+int(qrLog[b])]
+}
+
+// qrGeneratorPolynomial (x - a^0)(x - a^1)...(x - a^(degree-1)), koefisien dari pangkat tertinggi tanpa leading 1
+func qrGeneratorPolynomial(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 0x02)
+	}
+	return result
+}
+
+func qrRemainder(data, generator []byte) []byte {
+	result := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range generator {
+			result[i] ^= qrMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// qrBuildMatrix menggambar function pattern, data, mask, dan format/version information
+func qrBuildMatrix(codewords []byte, version, mask int) [][]bool {
+	size := version*4 + 17
+	modules := make([][]bool, size)
+	reserved := make([][]bool, size)
+	for i := range modules {
+		modules[i] = make([]bool, size)
+		reserved[i] = make([]bool, size)
+	}
+	set := func(row, col int, dark bool) {
+		modules[row][col] = dark
+		reserved[row][col] = true
+	}
+
+	// step 1: finder pattern di tiga sudut beserta separator putihnya
+	for _, corner := range [][2]int{{0, 0}, {0, size - 7}, {size - 7, 0}} {
+		for dr := -1; dr <= 7; dr++ {
+			for dc := -1; dc <= 7; dc++ {
+				row, col := corner[0]+dr, corner[1]+dc
+				if row < 0 || row >= size || col < 0 || col >= size {
+					continue
+				}
+				ring := max(abs(dr-3), abs(dc-3))
+				set(row, col, ring != 2 && ring != 4)
+			}
+		}
+	}
+
+	// step 2: timing pattern
+	for i := 8; i < size-8; i++ {
+		set(6, i, i%2 == 0)
+		set(i, 6, i%2 == 0)
+	}
+
+	// step 3: alignment pattern, kecuali yang bertabrakan dengan finder pattern
+	alignment := qrVersions[version-1].alignment
+	for i, row := range alignment {
+		for j, col := range alignment {
+			last := len(alignment) - 1
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dr := -2; dr <= 2; dr++ {
+				for dc := -2; dc <= 2; dc++ {
+					set(row+dr, col+dc, max(abs(dr), abs(dc)) != 1)
+				}
+			}
+		}
+	}
+
+	// step 4: cadangkan area format dan version information (diisi setelah mask dipilih)
+	qrDrawFormat(set, size, mask)
+	if version >= 7 {
+		qrDrawVersion(set, size, version)
+	}
+
+	// step 5: isi data secara zig-zag dua kolom dari kanan bawah, kolom timing (6) dilewati
+	bit := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vertical := 0; vertical < size; vertical++ {
+			row := vertical
+			if upward {
+				row = size - 1 - vertical
+			}
+			for j := 0; j < 2; j++ {
+				col := right - j
+				if reserved[row][col] {
+					continue
+				}
+				if bit < len(codewords)*8 {
+					modules[row][col] = (codewords[bit/8]>>(7-bit%8))&1 == 1
+					bit++
+				}
+				if qrMask(mask, row, col) {
+					modules[row][col] = !modules[row][col]
+				}
+			}
+		}
+	}
+	return modules
+}
+
+func qrMask(mask, row, col int) bool {
+	switch mask {
+	case 0:
+		return (row+col)%2 == 0
+	case 1:
+		return row%2 == 0
+	case 2:
+		return col%3 == 0
+	case 3:
+		return (row+col)%3 == 0
+	case 4:
+		return (row/2+col/3)%2 == 0
+	case 5:
+		return (row*col)%2+(row*col)%3 == 0
+	case 6:
+		return ((row*col)%2+(row*col)%3)%2 == 0
+	default:
+		return ((row+col)%2+(row*col)%3)%2 == 0
+	}
+}
+
+// qrDrawFormat menulis level error correction (M = 00) dan nomor mask, dilindungi kode BCH(15,5)
+func qrDrawFormat(set func(row, col int, dark bool), size, mask int) {
+	data := mask // bit level M adalah 00
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+	bitAt := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	// salinan pertama di sekitar finder kiri atas
+	for i := 0; i <= 5; i++ {
+		set(i, 8, bitAt(i))
+	}
+	set(7, 8, bitAt(6))
+	set(8, 8, bitAt(7))
+	set(8, 7, bitAt(8))
+	for i := 9; i < 15; i++ {
+		set(8, 14-i, bitAt(i))
+	}
+
+	// salinan kedua dibagi di finder kanan atas dan kiri bawah, ditambah dark module yang selalu hitam
+	for i := 0; i < 8; i++ {
+		set(8, size-1-i, bitAt(i))
+	}
+	for i := 8; i < 15; i++ {
+		set(size-15+i, 8, bitAt(i))
+	}
+	set(size-8, 8, true)
+}
+
+// qrDrawVersion menulis nomor versi (hanya versi 7 ke atas), dilindungi kode BCH(18,6)
+func qrDrawVersion(set func(row, col int, dark bool), size, version int) {
+	remainder := version
+	for i := 0; i < 12; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+	bits := version<<12 | remainder
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := size-11+i%3, i/3
+		set(b, a, dark)
+		set(a, b, dark)
+	}
+}
+
+// qrPenalty menghitung skor penalty sesuai spesifikasi, makin kecil makin mudah dibaca scanner
+func qrPenalty(modules [][]bool) int {
+	size := len(modules)
+	penalty := 0
+	at := func(row, col int, transpose bool) bool {
+		if transpose {
+			return modules[col][row]
+		}
+		return modules[row][col]
+	}
+
+	for _, transpose := range []bool{false, true} {
+		for row := 0; row < size; row++ {
+			// N1: lima atau lebih modul berwarna sama berturut-turut
+			run := 1
+			for col := 1; col < size; col++ {
+				if at(row, col, transpose) == at(row, col-1, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				penalty += run - 2
+			}
+
+			// N3: pola mirip finder (1:1:3:1:1) dengan empat modul putih di salah satu sisinya
+			for col := 0; col+11 <= size; col++ {
+				if qrFinderLike(func(i int) bool { return at(row, col+i, transpose) }) {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	// N2: blok 2x2 berwarna sama
+	dark := 0
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			if modules[row][col] {
+				dark++
+			}
+			if row+1 < size && col+1 < size {
+				c := modules[row][col]
+				if modules[row][col+1] == c && modules[row+1][col] == c && modules[row+1][col+1] == c {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	// N4: proporsi modul hitam yang jauh dari 50%
+	total := size * size
+	penalty += abs(dark*20-total*10) / total * 10
+	return penalty
+}
+
+var (
+	qrFinderBefore = [11]bool{false, false, false, false, true, false, true, true, true, false, true}
+	qrFinderAfter  = [11]bool{true, false, true, true, true, false, true, false, false, false, false}
+)
+
+func qrFinderLike(module func(i int) bool) bool {
+	matchBefore, matchAfter := true, true
+	for i := 0; i < 11; i++ {
+		value := module(i)
+		matchBefore = matchBefore && value == qrFinderBefore[i]
+		matchAfter = matchAfter && value == qrFinderAfter[i]
+	}
+	return matchBefore || matchAfter
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/service"
 	"net/http"
 	"os"
 	"strings"
@@ -15,10 +17,24 @@ import (
 )
 
 type Claims struct {
-	User *response.CreateUserResponse `json:"user"`
+	User        *response.CreateUserResponse `json:"user"`
+	Roles       []string                     `json:"roles,omitempty"`
+	Permissions []string                     `json:"permissions,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// AccessChecker mengecek status akun dan permission terbaru dari database, dipasang sekali lewat SetAccessChecker.
+// Kalau belum dipasang, permission diambil dari token saja dan akun yang di-suspend tidak ditolak.
+type AccessChecker interface {
+	CheckAccess(ctx context.Context, userID int) ([]string, error)
+}
+
+var accessChecker AccessChecker
+
+func SetAccessChecker(checker AccessChecker) {
+	accessChecker = checker
+}
+
 func Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// step 1: ambil header authorization dari request
@@ -71,13 +87,84 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		// step 4: set context ke request, agar bisa diakses di handler
+		// step 4: tolak akun yang sedang di-suspend/di-ban, sekalian ambil permission terbaru (role bisa berubah setelah token dibuat)
+		permissions := parsedClaims.Permissions
+		if accessChecker != nil {
+			permissions, err = accessChecker.CheckAccess(ctx, parsedClaims.User.UserID)
+			if err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, service.ErrAccountSuspended) || errors.Is(err, service.ErrAccountBanned) {
+					status = http.StatusForbidden
+				} else if errors.Is(err, service.ErrAccountDeleted) {
+					status = http.StatusUnauthorized
+				}
+				c.JSON(status, gin.H{
+					"code":    status,
+					"message": err.Error(),
+				})
+				c.Abort()
+				return
+			}
+		}
+		ctx = context.WithValue(ctx, "permissions", permissions)
+
+		// step 5: set context ke request, agar bisa diakses di handler
 		c.Request = c.Request.WithContext(ctx)
 
-		// step 5: simpan token ke context untuk digunakan di handler
+		// step 6: simpan token ke context untuk digunakan di handler
 		c.Set("token", authHeader)
 
-		// step 6: lanjutkan ke handler berikutnya
+		// step 7: lanjutkan ke handler berikutnya
+		c.Next()
+	}
+}
+
+// OptionalAuthenticate dipakai buat endpoint yang boleh diakses tanpa login tapi hasilnya beda kalau user login.
+// Kalau header-nya kosong request tetap lanjut sebagai anonim, tapi kalau token dikirim dan tidak valid tetap ditolak.
+func OptionalAuthenticate() gin.HandlerFunc {
+	authenticate := Authenticate()
+	return func(c *gin.Context) {
+		if c.GetHeader("authorization") == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// AuthenticateWebSocket dipakai buat endpoint WebSocket, browser tidak bisa set header authorization saat upgrade.
+// Kalau header-nya kosong token diambil dari query ?token= lalu divalidasi lewat Authenticate yang sama.
+func AuthenticateWebSocket() gin.HandlerFunc {
+	authenticate := Authenticate()
+	return func(c *gin.Context) {
+		if c.GetHeader("authorization") == "" {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("authorization", "Bearer "+token)
+			}
+		}
+		authenticate(c)
+	}
+}
+
+// RequirePermission dipasang setelah Authenticate, request ditolak kalau user tidak punya semua permission yang diminta
+func RequirePermission(required ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, _ := c.Request.Context().Value("permissions").([]string)
+		granted := make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			granted[permission] = true
+		}
+
+		for _, permission := range required {
+			if !granted[permission] {
+				c.JSON(http.StatusForbidden, gin.H{
+					"code":    http.StatusForbidden,
+					"message": fmt.Sprintf("Forbidden - missing permission %s", permission),
+				})
+				c.Abort()
+				return
+			}
+		}
 		c.Next()
 	}
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -55,4 +56,13 @@ func CORSMiddleware() gin.HandlerFunc {
 
         c.Next()
     }
-}
\ No newline at end of file
+}
+
+// ClientIP menyimpan IP client ke request context supaya service bisa mencatatnya di log audit
+func ClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), "clientIP", c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKey menentukan siapa yang dihitung kuotanya untuk sebuah request
+type RateLimitKey func(c *gin.Context) string
+
+// ByIP menghitung kuota per alamat IP, dipakai untuk route yang bisa diakses tanpa login
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser menghitung kuota per user yang login, harus dipasang setelah Authenticate.
+// Kalau request-nya anonim, kuotanya jatuh ke IP.
+func ByUser(c *gin.Context) string {
+	if userID, ok := c.Request.Context().Value("userID").(int); ok && userID > 0 {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	return ByIP(c)
+}
+
+// RateLimit menolak request dengan 429 kalau kuota policy untuk key-nya sudah habis.
+// Kalau store-nya error (misalnya Redis mati) request tetap dilanjutkan supaya API tidak ikut mati.
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, keyFn RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		// step 1: catat hit-nya
+		result, err := store.Allow(c.Request.Context(), keyFn(c), policy)
+		if err != nil {
+			log.Printf("RateLimit: failed to check policy %s: %v", policy.Name, err)
+			c.Next()
+			return
+		}
+
+		// step 2: kirim sisa kuota ke client
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		// step 3: tolak kalau kuotanya habis
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"code":    http.StatusTooManyRequests,
+				"message": fmt.Sprintf("Too many requests, try again in %d seconds", retryAfter),
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds membulatkan ke atas, supaya client yang menunggu sesuai Retry-After tidak ditolak lagi
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		after          time.Duration // jeda dari request sebelumnya
+		wantStatus     int
+		wantRemaining  string
+		wantReset      string
+		wantRetryAfter string // kosong berarti header-nya tidak boleh ada
+	}
+	tests := []struct {
+		name     string
+		policy   ratelimit.Policy
+		requests []request
+	}{
+		{
+			name:   "within quota",
+			policy: ratelimit.Policy{Name: ratelimit.PolicyLogin, Limit: 2, Window: time.Minute},
+			requests: []request{
+				{wantStatus: http.StatusOK, wantRemaining: "1", wantReset: "60"},
+				{after: time.Second, wantStatus: http.StatusOK, wantRemaining: "0", wantReset: "60"},
+			},
+		},
+		{
+			name:   "over quota sets Retry-After",
+			policy: ratelimit.Policy{Name: ratelimit.PolicyLogin, Limit: 2, Window: time.Minute},
+			requests: []request{
+				{wantStatus: http.StatusOK, wantRemaining: "1", wantReset: "60"},
+				{after: 15 * time.Second, wantStatus: http.StatusOK, wantRemaining: "0", wantReset: "60"},
+				{after: 15 * time.Second, wantStatus: http.StatusTooManyRequests, wantRemaining: "0", wantReset: "45", wantRetryAfter: "30"},
+			},
+		},
+		{
+			name:   "Retry-After rounds up partial seconds",
+			policy: ratelimit.Policy{Name: ratelimit.PolicyLogin, Limit: 1, Window: time.Minute},
+			requests: []request{
+				{wantStatus: http.StatusOK, wantRemaining: "0", wantReset: "60"},
+				{after: 1500 * time.Millisecond, wantStatus: http.StatusTooManyRequests, wantRemaining: "0", wantReset: "59", wantRetryAfter: "59"},
+			},
+		},
+		{
+			name:   "allowed again after waiting Retry-After",
+			policy: ratelimit.Policy{Name: ratelimit.PolicyLogin, Limit: 1, Window: time.Minute},
+			requests: []request{
+				{wantStatus: http.StatusOK, wantRemaining: "0", wantReset: "60"},
+				{after: 20 * time.Second, wantStatus: http.StatusTooManyRequests, wantRemaining: "0", wantReset: "40", wantRetryAfter: "40"},
+				{after: 40 * time.Second, wantStatus: http.StatusOK, wantRemaining: "0", wantReset: "60"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			store := ratelimit.NewMemoryStore()
+			store.Now = func() time.Time { return now }
+
+			router := gin.New()
+			router.GET("/limited", RateLimit(store, tt.policy, ByIP), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			for i, r := range tt.requests {
+				now = now.Add(r.after)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/limited", nil))
+
+				if rec.Code != r.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d", i, rec.Code, r.wantStatus)
+				}
+				if got := rec.Header().Get("X-RateLimit-Limit"); got != strconv.Itoa(tt.policy.Limit) {
+					t.Fatalf("request %d: X-RateLimit-Limit = %q, want %d", i, got, tt.policy.Limit)
+				}
+				if got := rec.Header().Get("X-RateLimit-Remaining"); got != r.wantRemaining {
+					t.Fatalf("request %d: X-RateLimit-Remaining = %q, want %q", i, got, r.wantRemaining)
+				}
+				if got := rec.Header().Get("X-RateLimit-Reset"); got != r.wantReset {
+					t.Fatalf("request %d: X-RateLimit-Reset = %q, want %q", i, got, r.wantReset)
+				}
+				if got := rec.Header().Get("Retry-After"); got != r.wantRetryAfter {
+					t.Fatalf("request %d: Retry-After = %q, want %q", i, got, r.wantRetryAfter)
+				}
+			}
+		})
+	}
+}
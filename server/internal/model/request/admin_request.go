@@ -0,0 +1,10 @@
+package request
+
+type AdminSuspendRequest struct {
+	Days   int    `json:"days" validate:"required,min=1,max=365"`
+	Reason string `json:"reason" validate:"max=1000"` // ikut dikirim ke user lewat notifikasi
+}
+
+type AdminBanRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"` // ditampilkan ke user waktu login ditolak
+}
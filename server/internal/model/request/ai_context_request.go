@@ -0,0 +1,8 @@
+package request
+
+// Pakai pointer supaya field yang tidak dikirim tidak ikut meng-overwrite pengaturan yang lama
+type UpdateAIContextSettingsRequest struct {
+	Enabled          *bool `json:"enabled"`
+	IncludeMoodTrend *bool `json:"include_mood_trend"`
+	IncludePosts     *bool `json:"include_posts"`
+}
@@ -0,0 +1,13 @@
+package request
+
+import "time"
+
+// AuditEventQuery dibaca dari query string, semua filter opsional. from/to pakai format RFC3339.
+type AuditEventQuery struct {
+	ActorID    *int       `form:"actor_id"`
+	Action     string     `form:"action"`
+	TargetType string     `form:"target_type"`
+	TargetID   *int       `form:"target_id"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type BookmarkCollectionRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
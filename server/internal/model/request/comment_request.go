@@ -1,7 +1,12 @@
 package request
 
 type CreateCommentRequest struct {
-	PostID  int    `json:"postid" validate:"required"`
-	UserID  int    `json:"userid" validate:"required"`
+	PostID          int    `json:"postid" validate:"required"`
+	UserID          int    `json:"userid" validate:"required"`
+	ParentCommentID *int   `json:"parent_commentid"` // diisi kalau komentar ini balasan dari komentar lain
+	Content         string `json:"content" validate:"required,min=1,max=500"`
+}
+
+type UpdateCommentRequest struct {
 	Content string `json:"content" validate:"required,min=1,max=500"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,7 @@
+package request
+
+type CreateJournalEntryRequest struct {
+	Content     string   `json:"content" validate:"required,min=1,max=5000"`
+	SelfMood    *int     `json:"self_mood" validate:"omitempty,min=1,max=5"`
+	EmotionTags []string `json:"emotion_tags" validate:"omitempty,max=10"`
+}
@@ -1,7 +1,21 @@
 package request
 
 type CreatePostRequest struct {
-	UserID  int    `json:"userid" validate:"required"`
-	Content string `json:"content" validate:"required,min=1,max=500"`
-	Mood    string `json:"mood" validate:"required,min=1,max=50"`
-}
\ No newline at end of file
+	UserID      int    `json:"userid" validate:"required"`
+	Content     string `json:"content" validate:"required,min=1,max=500"`
+	DeclaredMood *string `json:"declared_mood" validate:"omitempty,max=50"` // mood pilihan user sendiri, mood hasil prediksi tetap dihitung di server
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=public friends private"`
+	IsAnonymous *bool  `json:"is_anonymous"`
+	MediaIDs    []int  `json:"media_ids" validate:"omitempty,max=4,unique,dive,min=1"` // id dari upload gambar sebelumnya, urutannya jadi urutan tampil
+}
+
+// SearchPostRequest dibaca dari query string, semua field-nya opsional
+type SearchPostRequest struct {
+	Query  string `form:"q" validate:"max=200"`
+	Mood   string `form:"mood" validate:"max=50"`
+	Author string `form:"author" validate:"max=50"`
+	From   string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To     string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,25 @@
+package request
+
+// UpdateProfileRequest dipakai untuk PATCH, field yang tidak dikirim (nil) tidak diubah.
+// Kirim string/array kosong untuk mengosongkan field-nya.
+type UpdateProfileRequest struct {
+	Bio             *string                   `json:"bio" validate:"omitempty,max=300"`
+	Pronouns        *string                   `json:"pronouns" validate:"omitempty,max=30"`
+	Location        *string                   `json:"location" validate:"omitempty,max=100"`
+	Interests       *[]string                 `json:"interests" validate:"omitempty,max=10"`
+	SupportSeeking  *[]string                 `json:"support_seeking" validate:"omitempty,max=6"`
+	SupportOffering *[]string                 `json:"support_offering" validate:"omitempty,max=6"`
+	Language        *string                   `json:"language" validate:"omitempty,max=10"`
+	Visibility      *ProfileVisibilityRequest `json:"visibility"`
+}
+
+type ProfileVisibilityRequest struct {
+	Bio             *string `json:"bio" validate:"omitempty,oneof=public friends private"`
+	Pronouns        *string `json:"pronouns" validate:"omitempty,oneof=public friends private"`
+	Location        *string `json:"location" validate:"omitempty,oneof=public friends private"`
+	Interests       *string `json:"interests" validate:"omitempty,oneof=public friends private"`
+	SupportSeeking  *string `json:"support_seeking" validate:"omitempty,oneof=public friends private"`
+	SupportOffering *string `json:"support_offering" validate:"omitempty,oneof=public friends private"`
+	Language        *string `json:"language" validate:"omitempty,oneof=public friends private"`
+	Friends         *string `json:"friends" validate:"omitempty,oneof=public friends private"`
+}
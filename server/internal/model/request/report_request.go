@@ -0,0 +1,14 @@
+package request
+
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" validate:"required,oneof=post comment message user"`
+	TargetID   int    `json:"target_id" validate:"required,min=1"`
+	Reason     string `json:"reason" validate:"required,oneof=spam harassment hate_speech self_harm violence sexual_content misinformation impersonation other"`
+	Details    string `json:"details" validate:"max=1000"` // wajib diisi kalau reason = other
+}
+
+type ResolveReportRequest struct {
+	Action      string `json:"action" validate:"required,oneof=hide delete warn suspend dismiss"`
+	Note        string `json:"note" validate:"max=1000"`                        // catatan internal, ikut dikirim ke user yang diperingatkan/di-suspend
+	SuspendDays int    `json:"suspend_days" validate:"omitempty,min=1,max=365"` // cuma dipakai untuk action suspend, default 7 hari
+}
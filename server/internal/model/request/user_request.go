@@ -17,5 +17,32 @@ type UpdateUserRequest struct {
 	Fullname string `json:"fullname" validate:"required,min=3,max=50"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
-	Profile  string `json:"profile"`
+	// CurrentPassword wajib diisi kalau Password-nya diganti
+	CurrentPassword string `json:"current_password"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,max=32"` // kode dari authenticator app atau recovery code
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"` // password diminta ulang sebelum akun dijadwalkan untuk dihapus
 }
@@ -0,0 +1,92 @@
+package response
+
+import (
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+type DataExportResponse struct {
+	ExportID    int        `json:"export_id"`
+	Status      string     `json:"status"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	DownloadURL string     `json:"download_url,omitempty"` // cuma ada kalau status-nya ready
+}
+
+// AccountDeletionResponse ScheduledAt nil berarti akunnya tidak sedang dijadwalkan untuk dihapus
+type AccountDeletionResponse struct {
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+// Isi arsip ekspor. Semua file JSON-nya pakai struct di bawah, supaya bentuknya tidak ikut berubah kalau response API berubah.
+
+type AccountExport struct {
+	UserID          int                      `json:"userid"`
+	Username        string                   `json:"username"`
+	Fullname        string                   `json:"fullname"`
+	Email           string                   `json:"email"`
+	CreatedAt       time.Time                `json:"created_at"`
+	Roles           []string                 `json:"roles"`
+	Profile         *entity.UserProfile      `json:"profile"`
+	AIContext       *entity.AIContextSetting `json:"ai_context_settings"`
+	ExportedAt      time.Time                `json:"exported_at"`
+	DeletionPending *time.Time               `json:"deletion_scheduled_at"`
+}
+
+type PostExport struct {
+	PostID       int        `json:"post_id"`
+	Content      string     `json:"content"`
+	Mood         string     `json:"mood"`
+	DeclaredMood *string    `json:"declared_mood"`
+	Visibility   string     `json:"visibility"`
+	IsAnonymous  bool       `json:"is_anonymous"`
+	CreatedAt    time.Time  `json:"created_at"`
+	EditedAt     *time.Time `json:"edited_at"`
+	Media        []string   `json:"media"` // path file di dalam arsip
+}
+
+type CommentExport struct {
+	CommentID       int        `json:"comment_id"`
+	PostID          int        `json:"post_id"`
+	ParentCommentID *int       `json:"parent_comment_id"`
+	Content         string     `json:"content"`
+	Mood            *string    `json:"mood"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EditedAt        *time.Time `json:"edited_at"`
+}
+
+type MessageExport struct {
+	MessageID     int       `json:"message_id"`
+	Direction     string    `json:"direction"` // "sent" atau "received"
+	OtherUserID   int       `json:"other_userid"`
+	OtherUsername string    `json:"other_username"`
+	Content       string    `json:"content"`
+	Status        string    `json:"status"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type FriendExport struct {
+	UserID    int       `json:"userid"`
+	Username  string    `json:"username"`
+	Fullname  string    `json:"fullname"`
+	Status    string    `json:"status"` // "accepted", "request_sent", atau "request_received"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MoodEntryExport satu titik riwayat mood, dari postingan, komentar, atau jurnal
+type MoodEntryExport struct {
+	Source       string    `json:"source"`
+	SourceID     int       `json:"source_id"`
+	Mood         *string   `json:"mood"`
+	DeclaredMood *string   `json:"declared_mood,omitempty"`
+	SelfMood     *int      `json:"self_mood,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+type AIConversationExport struct {
+	Note       string `json:"note"`
+	Transcript string `json:"transcript"`
+}
@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+// AdminUserResponse cuma dipakai di /api/admin, jadi boleh menampilkan status akun
+type AdminUserResponse struct {
+	UserID         int        `json:"id"`
+	Username       string     `json:"username"`
+	Fullname       string     `json:"fullname"`
+	Email          string     `json:"email"`
+	Roles          []string   `json:"roles"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+	BannedAt       *time.Time `json:"banned_at"`
+	BanReason      string     `json:"ban_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	RequireMFA  bool     `json:"require_mfa"`
+}
+
+// PasswordResetResponse cuma dikirim sekali ke admin, yang meneruskannya ke user
+type PasswordResetResponse struct {
+	TemporaryPassword string `json:"temporary_password"`
+}
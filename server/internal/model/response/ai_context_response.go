@@ -0,0 +1,36 @@
+package response
+
+import "time"
+
+type AIContextSettingsResponse struct {
+	UserID           int       `json:"userid"`
+	Enabled          bool      `json:"enabled"`
+	IncludeMoodTrend bool      `json:"include_mood_trend"`
+	IncludePosts     bool      `json:"include_posts"`
+	UpdatedAt        time.Time `json:"updatedat"`
+}
+
+type MoodTrendSummary struct {
+	DominantMood string         `json:"dominant_mood"`
+	LatestMood   string         `json:"latest_mood"`
+	MoodCounts   map[string]int `json:"mood_counts"`
+	PostCount    int            `json:"post_count"`
+	WindowDays   int            `json:"window_days"`
+	Summary      string         `json:"summary"`
+}
+
+type SharedPost struct {
+	PostID    int       `json:"postid"`
+	Content   string    `json:"content"`
+	Mood      string    `json:"mood"`
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"createdat"`
+}
+
+// Ini yang ditunjukin ke user supaya dia tau persis apa yang dikirim ke AI
+type AIContextResponse struct {
+	Settings    AIContextSettingsResponse `json:"settings"`
+	MoodTrend   *MoodTrendSummary         `json:"mood_trend,omitempty"`
+	SharedPosts []SharedPost              `json:"shared_posts"`
+	Prompt      string                    `json:"prompt"`
+}
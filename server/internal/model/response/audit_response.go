@@ -0,0 +1,26 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEventResponse struct {
+	EventID    int             `json:"event_id"`
+	ActorID    *int            `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type,omitempty"`
+	TargetID   *int            `json:"target_id"`
+	Metadata   json.RawMessage `json:"metadata"`
+	IPAddress  string          `json:"ip_address,omitempty"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditVerifyResponse hasil pengecekan rantai hash, BrokenAt diisi event pertama yang tidak cocok
+type AuditVerifyResponse struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt *int   `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "time"
+
+// BlockedUserResponse dipakai untuk daftar user yang diblokir maupun yang di-mute
+type BlockedUserResponse struct {
+	User      UserSummary `json:"user"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type BookmarkCollectionResponse struct {
+	CollectionID int       `json:"collection_id"`
+	Name         string    `json:"name"`
+	PostCount    int       `json:"post_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type BookmarkResponse struct {
+	Post         *CreatePostResponse `json:"post"`
+	BookmarkedAt time.Time           `json:"bookmarked_at"`
+}
@@ -3,10 +3,26 @@ package response
 import "time"
 
 type CreateCommentResponse struct {
-	CommentID int         `json:"commentid"`
-	PostID    int         `json:"postid"`
-	UserID    int         `json:"userid"`
-	User      UserSummary `json:"user"`
-	Content   string      `json:"content"`
-	CreatedAt time.Time   `json:"created_at"`
-}
\ No newline at end of file
+	CommentID       int         `json:"commentid"`
+	PostID          int         `json:"postid"`
+	UserID          int         `json:"userid"`
+	User            UserSummary `json:"user"`
+	ParentCommentID *int        `json:"parent_commentid"`
+	Depth           int         `json:"depth"`
+	ReplyCount      int         `json:"reply_count"`
+	IsDeleted       bool        `json:"is_deleted"`
+	IsHidden        bool        `json:"is_hidden"` // disembunyikan moderator, isinya diganti placeholder
+	Content         string      `json:"content"`
+	Mood            string      `json:"mood,omitempty"` // cuma dikirim kalau diminta (include_mood=true) dan sudah selesai diklasifikasi
+	IsEdited        bool        `json:"is_edited"`
+	EditedAt        *time.Time  `json:"edited_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+type CommentRevisionResponse struct {
+	RevisionID int       `json:"revisionid"`
+	CommentID  int       `json:"commentid"`
+	Content    string    `json:"content"`
+	Mood       string    `json:"mood,omitempty"`
+	EditedAt   time.Time `json:"edited_at"`
+}
@@ -6,15 +6,36 @@ type FriendResponse struct {
 	FriendID     int         `json:"id"`           // ID teman
 	UserID       int         `json:"userid"`       // ID orang yang menambahkan teman
 	FriendUserID int         `json:"frienduserid"` // ID orang yang jadi temannya
-	FriendStatus bool        `json:"friendstatus"` // Status teman
+	FriendStatus bool        `json:"friendstatus"` // true kalau sudah berteman, tetap dikirim untuk client lama
+	Status       string      `json:"status"`       // pending, accepted, declined, cancelled, atau blocked
 	CreatedAt    time.Time   `json:"createdat"`    // Waktu saat teman ditambahkan
 	User         UserSummary `json:"user"`         // Data pengguna yang menambahkan teman
 }
 
 type FriendRecommendationResponse struct {
-	UserID      int    `json:"userid"`
-	Username    string `json:"username"`
-	Fullname    string `json:"fullname"`
-	Email       string `json:"email"`
-	OverallMood string `json:"overall_mood"`
-}
\ No newline at end of file
+	UserID          int      `json:"userid"`
+	Username        string   `json:"username"`
+	Fullname        string   `json:"fullname"`
+	Email           string   `json:"email"`
+	OverallMood     string   `json:"overall_mood"`
+	SharedInterests []string `json:"shared_interests"`
+	SupportOffered  []string `json:"support_offered"` // dukungan yang kamu cari dan dia tawarkan
+	SupportSought   []string `json:"support_sought"`  // dukungan yang dia cari dan bisa kamu tawarkan
+	MutualFriends   int      `json:"mutual_friends"`
+}
+
+// MutualFriendResponse teman yang dimiliki viewer dan user yang sedang dilihat
+type MutualFriendResponse struct {
+	UserID    int       `json:"userid"`
+	Username  string    `json:"username"`
+	Fullname  string    `json:"fullname"`
+	CreatedAt time.Time `json:"createdat"` // waktu akunnya dibuat, dipakai juga untuk cursor
+}
+
+// FriendSuggestionResponse satu kartu "people you may know"
+type FriendSuggestionResponse struct {
+	UserID        int    `json:"userid"`
+	Username      string `json:"username"`
+	Fullname      string `json:"fullname"`
+	MutualFriends int    `json:"mutual_friends"`
+}
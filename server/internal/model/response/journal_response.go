@@ -0,0 +1,35 @@
+package response
+
+import "time"
+
+type JournalEntryResponse struct {
+	EntryID       int       `json:"entryid"`
+	UserID        int       `json:"userid"`
+	Content       string    `json:"content"`
+	SelfMood      *int      `json:"self_mood"`
+	EmotionTags   []string  `json:"emotion_tags"`
+	PredictedMood *string   `json:"predicted_mood"`
+	EntryDate     string    `json:"entrydate"`
+	CreatedAt     time.Time `json:"createdat"`
+	UpdatedAt     time.Time `json:"updatedat"`
+}
+
+type JournalStreakResponse struct {
+	CurrentStreak  int     `json:"current_streak"`
+	LongestStreak  int     `json:"longest_streak"`
+	TotalDays      int     `json:"total_days"`
+	LastEntryDate  *string `json:"last_entry_date"`
+	CheckedInToday bool    `json:"checked_in_today"`
+}
+
+type CheckInPrompt struct {
+	PromptID int    `json:"promptid"`
+	TextEN   string `json:"text_en"`
+	TextID   string `json:"text_id"`
+}
+
+type DailyCheckInResponse struct {
+	Date   string                `json:"date"`
+	Prompt CheckInPrompt         `json:"prompt"`
+	Streak JournalStreakResponse `json:"streak"`
+}
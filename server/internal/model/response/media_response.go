@@ -0,0 +1,11 @@
+package response
+
+type MediaResponse struct {
+	MediaID      int    `json:"media_id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Blurhash     string `json:"blurhash"` // placeholder buram selagi gambar asli dimuat
+}
@@ -0,0 +1,26 @@
+package response
+
+type MFAStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"` // salah satu role user mewajibkan 2FA
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// MFAEnrollmentResponse secret-nya cuma ditampilkan sekali, waktu enroll
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"` // data URI PNG berisi OTPAuthURI
+}
+
+// MFARecoveryCodesResponse kode aslinya cuma ditampilkan sekali, yang disimpan hanya hash-nya
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse dikirim Login kalau user mengaktifkan 2FA, token-nya ditukar dengan JWT lewat /api/user/login/mfa
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"` // detik
+}
@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+type NotificationResponse struct {
+	NotificationID int       `json:"notification_id"`
+	Type           string    `json:"type"`
+	Message        string    `json:"message"`
+	ReferenceID    *int      `json:"reference_id"`
+	IsRead         bool      `json:"is_read"`
+	CreatedAt      time.Time `json:"created_at"`
+}
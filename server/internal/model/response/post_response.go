@@ -9,12 +9,35 @@ type CreatePostResponse struct {
 	UserID    	int       		`json:"userid"`
 	User 		UserSummary 	`json:"user"`
 	Content   	string    		`json:"content"`
-	Mood      	string    		`json:"mood"`
+	Mood      	string    		`json:"mood"`				// hasil prediksi model
+	DeclaredMood	string			`json:"declared_mood,omitempty"`	// mood yang dipilih sendiri oleh user
+	Visibility	string			`json:"visibility"`
+	IsAnonymous	bool			`json:"is_anonymous"`
+	ReactionCounts	map[string]int	`json:"reaction_counts"`
+	Tags		[]string		`json:"tags"`
+	Media		[]*MediaResponse	`json:"media"`
+	IsEdited	bool			`json:"is_edited"`
+	EditedAt	*time.Time		`json:"editedat,omitempty"`
+	IsHidden	bool			`json:"is_hidden,omitempty"`	// disembunyikan moderator, cuma terlihat oleh penulisnya
 	CreatedAt 	time.Time 		`json:"createdat"`
 }
 
+type PostRevisionResponse struct {
+	RevisionID		int			`json:"revisionid"`
+	PostID			int			`json:"postid"`
+	Content			string		`json:"content"`
+	Mood			string		`json:"mood"`
+	DeclaredMood	string		`json:"declared_mood,omitempty"`
+	CreatedAt		time.Time	`json:"createdat"`
+}
+
 type UserSummary struct {
 	UserID    	int       		`json:"userid"`
 	Username  	string    		`json:"username"`
 	FullName  	string    		`json:"fullname"`
-}
\ No newline at end of file
+}
+type PostSearchResult struct {
+	Post		*CreatePostResponse	`json:"post"`
+	Snippet		string				`json:"snippet"` // potongan isi postingan, kata yang cocok dibungkus <mark>
+	Rank		float64				`json:"rank"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+// ProfileResponse cuma berisi field yang boleh dilihat viewer-nya, field yang disembunyikan tidak ikut dikirim.
+// Visibility cuma dikirim ke pemilik profil.
+type ProfileResponse struct {
+	UserID          int                        `json:"userid"`
+	Bio             *string                    `json:"bio,omitempty"`
+	Pronouns        *string                    `json:"pronouns,omitempty"`
+	Location        *string                    `json:"location,omitempty"`
+	Interests       *[]string                  `json:"interests,omitempty"`
+	SupportSeeking  *[]string                  `json:"support_seeking,omitempty"`
+	SupportOffering *[]string                  `json:"support_offering,omitempty"`
+	Language        *string                    `json:"language,omitempty"`
+	MutualFriends   *int                       `json:"mutual_friends,omitempty"` // cuma untuk viewer yang login dan bukan pemiliknya
+	Visibility      *ProfileVisibilityResponse `json:"visibility,omitempty"`
+	UpdatedAt       *time.Time                 `json:"updated_at,omitempty"`
+}
+
+type ProfileVisibilityResponse struct {
+	Bio             string `json:"bio"`
+	Pronouns        string `json:"pronouns"`
+	Location        string `json:"location"`
+	Interests       string `json:"interests"`
+	SupportSeeking  string `json:"support_seeking"`
+	SupportOffering string `json:"support_offering"`
+	Language        string `json:"language"`
+	Friends         string `json:"friends"`
+}
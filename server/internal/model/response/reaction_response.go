@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+type PostReactionResponse struct {
+	PostID         int            `json:"postid"`
+	ReactionCounts map[string]int `json:"reaction_counts"`
+	MyReactions    []string       `json:"my_reactions"`
+}
+
+type ReactorResponse struct {
+	User         UserSummary `json:"user"`
+	ReactionType string      `json:"reaction_type"`
+	CreatedAt    time.Time   `json:"createdat"`
+}
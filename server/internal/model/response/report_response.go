@@ -0,0 +1,43 @@
+package response
+
+import "time"
+
+// ReportResponse dipakai pelapor dan moderator. Field khusus moderator (pelapor, pemilik target, catatan)
+// dikosongkan waktu dikirim ke pelapor, terutama supaya penulis postingan anonim tidak ketahuan.
+type ReportResponse struct {
+	ReportID       int                   `json:"report_id"`
+	TargetType     string                `json:"target_type"`
+	TargetID       int                   `json:"target_id"`
+	Reason         string                `json:"reason"`
+	Details        string                `json:"details"`
+	Status         string                `json:"status"`
+	Resolution     string                `json:"resolution,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	ResolvedAt     *time.Time            `json:"resolved_at,omitempty"`
+	ReporterID     int                   `json:"reporter_id,omitempty"`
+	TargetOwnerID  *int                  `json:"target_owner_id,omitempty"`
+	ClaimedBy      *int                  `json:"claimed_by,omitempty"`
+	ClaimedAt      *time.Time            `json:"claimed_at,omitempty"`
+	ResolvedBy     *int                  `json:"resolved_by,omitempty"`
+	ResolutionNote string                `json:"resolution_note,omitempty"`
+	OpenReports    int                   `json:"open_reports,omitempty"`
+	Target         *ReportTargetResponse `json:"target,omitempty"` // cuma diisi di detail laporan
+}
+
+// ReportTargetResponse adalah cuplikan konten yang dilaporkan, supaya moderator tidak perlu membuka kontennya satu-satu
+type ReportTargetResponse struct {
+	Exists   bool   `json:"exists"` // false kalau kontennya sudah dihapus
+	Content  string `json:"content,omitempty"`
+	IsHidden bool   `json:"is_hidden"`
+}
+
+type ModerationActionResponse struct {
+	ActionID    int       `json:"action_id"`
+	ModeratorID *int      `json:"moderator_id"` // nil berarti tindakan otomatis
+	ReportID    *int      `json:"report_id"`
+	Action      string    `json:"action"`
+	TargetType  string    `json:"target_type"`
+	TargetID    int       `json:"target_id"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
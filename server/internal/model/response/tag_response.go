@@ -0,0 +1,22 @@
+package response
+
+import "time"
+
+type TagResponse struct {
+	Name        string                 `json:"name"`
+	PostCount   int                    `json:"post_count"`
+	IsFollowing bool                   `json:"is_following"`
+	Resources   []*TagResourceResponse `json:"resources"` // bacaan/bantuan yang dikurasi, kosong untuk kebanyakan tag
+}
+
+type TagResourceResponse struct {
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+type TrendingTagResponse struct {
+	Name       string    `json:"name"`
+	PostCount  int       `json:"post_count"`
+	ComputedAt time.Time `json:"computed_at"`
+}
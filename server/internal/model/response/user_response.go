@@ -7,9 +7,22 @@ type CreateUserResponse struct {
 	Username  	string    	`json:"username"`
 	Fullname  	string    	`json:"fullname"`
 	Email     	string    	`json:"email"`
+	Avatar    	*AvatarResponse `json:"avatar"` // null berarti user belum upload avatar, client pakai gambar default
 	CreatedAt 	time.Time 	`json:"created_at"`
 }
 
+type AvatarResponse struct {
+	Small  string `json:"small"`  // 64x64
+	Medium string `json:"medium"` // 256x256
+	Large  string `json:"large"`  // 512x512
+}
+
 type ValidateUserResponse struct {
 	Token string `json:"token"`
 }
+
+// LoginResponse berisi Token kalau login langsung berhasil, atau MFAChallenge kalau user masih harus memasukkan kode 2FA
+type LoginResponse struct {
+	Token        *string
+	MFAChallenge *MFAChallengeResponse
+}
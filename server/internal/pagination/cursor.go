@@ -0,0 +1,146 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor dipakai buat keyset pagination: posisi terakhir yang sudah dikirim ke client (createdat + id sebagai tie-breaker)
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// ScoreCursor dipakai buat hasil yang diurutkan berdasarkan skor (ranking search, feed), id sebagai tie-breaker
+type ScoreCursor struct {
+	Score float64
+	ID    int
+}
+
+var (
+	signingKey     []byte
+	signingKeyOnce sync.Once
+)
+
+// Cursor ditandatangani pakai HMAC supaya client tidak bisa bikin/ngubah cursor sendiri.
+// Kuncinya dari CURSOR_SECRET_KEY, kalau tidak ada pakai JWT_SECRET_KEY, kalau dua-duanya kosong pakai kunci acak
+// (artinya cursor lama jadi tidak valid setiap server restart, client tinggal mulai lagi dari halaman pertama).
+func key() []byte {
+	signingKeyOnce.Do(func() {
+		for _, name := range []string{"CURSOR_SECRET_KEY", "JWT_SECRET_KEY"} {
+			if secret := os.Getenv(name); secret != "" {
+				signingKey = []byte(secret)
+				return
+			}
+		}
+		log.Println("WARNING: CURSOR_SECRET_KEY is not set, using a random key for pagination cursors")
+		signingKey = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, signingKey); err != nil {
+			log.Fatalf("failed to generate cursor signing key: %v", err)
+		}
+	})
+	return signingKey
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, key())
+	mac.Write([]byte(payload))
+	// 16 byte pertama sudah cukup, biar cursor-nya tidak kepanjangan di URL
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func encode(payload string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + sign(payload)
+}
+
+func decode(cursor string) ([]string, error) {
+	encodedPayload, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	payload := string(raw)
+	if !hmac.Equal([]byte(signature), []byte(sign(payload))) {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(payload, ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	return parts, nil
+}
+
+// EncodeCursor mengubah posisi terakhir jadi string opaque supaya client ga perlu tahu isinya
+func EncodeCursor(createdAt time.Time, id int) string {
+	return encode(strconv.FormatInt(createdAt.UnixNano(), 10) + ":" + strconv.Itoa(id))
+}
+
+// DecodeCursor mengembalikan nil kalau cursor-nya kosong (artinya mulai dari halaman pertama)
+func DecodeCursor(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	parts, err := decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	// pakai UTC karena kolom TIMESTAMP (tanpa timezone) kita dibaca driver sebagai waktu UTC
+	return &Cursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
+
+func EncodeScoreCursor(score float64, id int) string {
+	return encode(strconv.FormatFloat(score, 'g', -1, 64) + ":" + strconv.Itoa(id))
+}
+
+func DecodeScoreCursor(cursor string) (*ScoreCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	parts, err := decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	score, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &ScoreCursor{Score: score, ID: id}, nil
+}
+
+// After mengembalikan posisi cursor dalam bentuk yang langsung bisa dipakai di query keyset:
+// ID 0 berarti halaman pertama (lihat pola "$n = 0 OR (createdat, id) < (...)" di repository)
+func (c *Cursor) After() (time.Time, int) {
+	if c == nil {
+		return time.Time{}, 0
+	}
+	return c.CreatedAt, c.ID
+}
@@ -0,0 +1,47 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+// Page adalah envelope yang sama untuk semua endpoint list
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// ParseLimit membaca query limit: kosong berarti DefaultLimit, selain itu harus 1 sampai MaxLimit
+func ParseLimit(raw string) (int, error) {
+	if raw == "" {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 || limit > MaxLimit {
+		return 0, fmt.Errorf("invalid limit parameter, must be between 1 and %d", MaxLimit)
+	}
+	return limit, nil
+}
+
+// NewPage dipakai bareng query yang ngambil limit+1 baris: baris tambahan cuma penanda masih ada halaman berikutnya
+// dan tidak ikut dikirim. Cursor diambil dari item terakhir yang dikirim.
+func NewPage[T any](items []T, limit int, cursorOf func(T) string) *Page[T] {
+	page := &Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(page.Items) > limit {
+		page.HasMore = true
+		page.Items = page.Items[:limit]
+	}
+	if page.HasMore && len(page.Items) > 0 {
+		page.NextCursor = cursorOf(page.Items[len(page.Items)-1])
+	}
+	return page
+}
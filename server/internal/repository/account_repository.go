@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AccountRepository berisi query lintas tabel untuk ekspor data dan penghapusan akun
+type AccountRepository interface {
+	FindPostsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.Post, error)
+	FindCommentsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.Comment, error)
+	FindMessagesByUserID(ctx context.Context, db *sql.DB, userID int) ([]*AccountMessage, error)
+	FindFriendsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*AccountFriend, error)
+	FindMediaByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.PostMedia, error)
+	ScheduleDeletion(ctx context.Context, tx *sql.Tx, userID int, at time.Time) (bool, error)
+	CancelDeletion(ctx context.Context, db *sql.DB, userID int) (bool, error)
+	FindDeletionSchedule(ctx context.Context, db *sql.DB, userID int) (*time.Time, error)
+	FindDueDeletions(ctx context.Context, db *sql.DB, limit int) ([]int, error)
+	Purge(ctx context.Context, tx *sql.Tx, userID int) (*AccountPurgeResult, error)
+}
+
+// AccountMessage adalah pesan yang dikirim atau diterima user, beserta username lawan bicaranya
+type AccountMessage struct {
+	entity.Message
+	OtherUsername string
+}
+
+// AccountFriend adalah relasi pertemanan (termasuk yang masih pending) dilihat dari sisi user
+type AccountFriend struct {
+	UserID    int
+	Username  string
+	Fullname  string
+	Accepted  bool
+	Outgoing  bool // true kalau user sendiri yang mengirim permintaan pertemanannya
+	CreatedAt time.Time
+}
+
+// AccountPurgeResult berisi data yang masih dibutuhkan service setelah akun dihapus, untuk membersihkan cache dan storage
+type AccountPurgeResult struct {
+	Username       string
+	AvatarKey      sql.NullString
+	PostIDs        []int
+	ReactedPostIDs []int
+	CommentIDs     []int
+	CommentPostIDs []int
+	FriendIDs      []int
+	ObjectKeys     []string // file media postingan dan arsip ekspor yang harus dihapus dari storage
+}
+
+type AccountRepositoryImpl struct {
+}
+
+func NewAccountRepository() AccountRepository {
+	return &AccountRepositoryImpl{}
+}
+
+// FindPostsByUserID mengambil semua postingan user, termasuk yang anonim, private, dan yang disembunyikan moderator
+func (r *AccountRepositoryImpl) FindPostsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.userid = $1 ORDER BY p.createdat, p.postid`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPosts(rows)
+}
+
+// FindCommentsByUserID tidak mengambil komentar yang sudah dihapus karena isinya memang sudah dibuang
+func (r *AccountRepositoryImpl) FindCommentsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE userid = $1 AND isdeleted = FALSE ORDER BY createdat, commentid`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *AccountRepositoryImpl) FindMessagesByUserID(ctx context.Context, db *sql.DB, userID int) ([]*AccountMessage, error) {
+	query := `
+		SELECT m.messageid, m.senderid, m.recipientid, m.content, m.timestamp, m.status, u.username
+		FROM messages m
+		JOIN users u ON u.userid = CASE WHEN m.senderid = $1 THEN m.recipientid ELSE m.senderid END
+		WHERE m.senderid = $1 OR m.recipientid = $1
+		ORDER BY m.timestamp, m.messageid`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*AccountMessage
+	for rows.Next() {
+		var message AccountMessage
+		if err := rows.Scan(&message.ID, &message.SenderID, &message.RecipientID, &message.Content, &message.Timestamp, &message.Status, &message.OtherUsername); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *AccountRepositoryImpl) FindFriendsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*AccountFriend, error) {
+	query := `
+		SELECT u.userid, u.username, u.fullname, f.status = 'accepted', f.userid = $1, f.createdat
+		FROM friends f
+		JOIN users u ON u.userid = CASE WHEN f.userid = $1 THEN f.frienduserid ELSE f.userid END
+		WHERE (f.userid = $1 OR f.frienduserid = $1) AND f.status IN ('pending', 'accepted')
+		ORDER BY f.createdat, f.friendid`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var friends []*AccountFriend
+	for rows.Next() {
+		var friend AccountFriend
+		if err := rows.Scan(&friend.UserID, &friend.Username, &friend.Fullname, &friend.Accepted, &friend.Outgoing, &friend.CreatedAt); err != nil {
+			return nil, err
+		}
+		friends = append(friends, &friend)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return friends, nil
+}
+
+// FindMediaByUserID cuma mengambil media yang sudah menempel di postingan, upload yang belum dipakai dibersihkan job media
+func (r *AccountRepositoryImpl) FindMediaByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.PostMedia, error) {
+	query := `SELECT ` + mediaColumns + ` FROM post_media WHERE userid = $1 AND postid IS NOT NULL ORDER BY postid, position`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*entity.PostMedia
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// ScheduleDeletion return false kalau penghapusan akunnya sudah dijadwalkan sebelumnya
+func (r *AccountRepositoryImpl) ScheduleDeletion(ctx context.Context, tx *sql.Tx, userID int, at time.Time) (bool, error) {
+	query := `UPDATE users SET deletionscheduledat = $2 WHERE userid = $1 AND deletionscheduledat IS NULL AND deletedat IS NULL`
+	return execChanged(tx.ExecContext(ctx, query, userID, at))
+}
+
+// CancelDeletion return false kalau tidak ada penghapusan yang sedang dijadwalkan
+func (r *AccountRepositoryImpl) CancelDeletion(ctx context.Context, db *sql.DB, userID int) (bool, error) {
+	query := `UPDATE users SET deletionscheduledat = NULL WHERE userid = $1 AND deletionscheduledat IS NOT NULL AND deletedat IS NULL`
+	return execChanged(db.ExecContext(ctx, query, userID))
+}
+
+// FindDeletionSchedule return nil kalau akunnya tidak sedang dijadwalkan untuk dihapus
+func (r *AccountRepositoryImpl) FindDeletionSchedule(ctx context.Context, db *sql.DB, userID int) (*time.Time, error) {
+	var scheduledAt sql.NullTime
+	err := db.QueryRowContext(ctx, `SELECT deletionscheduledat FROM users WHERE userid = $1 AND deletedat IS NULL`, userID).Scan(&scheduledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !scheduledAt.Valid {
+		return nil, nil
+	}
+	return &scheduledAt.Time, nil
+}
+
+func (r *AccountRepositoryImpl) FindDueDeletions(ctx context.Context, db *sql.DB, limit int) ([]int, error) {
+	query := `
+		SELECT userid FROM users
+		WHERE deletionscheduledat <= NOW() AND deletedat IS NULL
+		ORDER BY deletionscheduledat
+		LIMIT $1`
+
+	rows, err := db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+// Purge menghapus semua data milik user lalu menganonimkan baris users-nya. Barisnya sengaja tidak dihapus:
+// pesan di inbox user lain tetap ada dengan pengirim "Deleted user", dan laporan, log moderasi, serta log audit
+// tetap bisa ditelusuri. Return nil kalau jadwal penghapusannya sudah dibatalkan atau akunnya sudah dihapus.
+func (r *AccountRepositoryImpl) Purge(ctx context.Context, tx *sql.Tx, userID int) (*AccountPurgeResult, error) {
+	// step 1: kunci baris user-nya dan pastikan jadwalnya masih berlaku (bisa saja baru dibatalkan)
+	result := &AccountPurgeResult{}
+	query := `
+		SELECT username, avatarkey FROM users
+		WHERE userid = $1 AND deletedat IS NULL AND deletionscheduledat <= NOW()
+		FOR UPDATE`
+	err := tx.QueryRowContext(ctx, query, userID).Scan(&result.Username, &result.AvatarKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// step 2: hapus reaksi user di postingan orang lain sambil mengurangi counter-nya
+	query = `
+		WITH removed AS (
+			DELETE FROM post_reactions WHERE userid = $1 RETURNING postid, reactiontype
+		), totals AS (
+			SELECT postid, reactiontype, COUNT(*) AS total FROM removed GROUP BY postid, reactiontype
+		), adjusted AS (
+			UPDATE post_reaction_counts c SET count = GREATEST(c.count - t.total, 0)
+			FROM totals t
+			WHERE c.postid = t.postid AND c.reactiontype = t.reactiontype
+		)
+		SELECT DISTINCT postid FROM removed`
+	if result.ReactedPostIDs, err = queryIDs(ctx, tx, query, userID); err != nil {
+		return nil, err
+	}
+
+	// step 3: komentar di postingan orang lain dibuat "[deleted]" seperti hapus komentar biasa, supaya balasan orang lain tetap utuh
+	if _, err = tx.ExecContext(ctx, `DELETE FROM comment_revisions WHERE commentid IN (SELECT commentid FROM comments WHERE userid = $1)`, userID); err != nil {
+		return nil, err
+	}
+	query = `
+		UPDATE comments
+		SET isdeleted = TRUE, content = '[deleted]', mood = NULL, needsreview = FALSE, deletedat = NOW()
+		WHERE userid = $1 AND isdeleted = FALSE
+		RETURNING commentid, postid`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	commentPostIDs := map[int]bool{}
+	for rows.Next() {
+		var commentID, postID int
+		if err = rows.Scan(&commentID, &postID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.CommentIDs = append(result.CommentIDs, commentID)
+		if !commentPostIDs[postID] {
+			commentPostIDs[postID] = true
+			result.CommentPostIDs = append(result.CommentPostIDs, postID)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// step 4: kumpulkan key file media dulu, lalu hapus postingannya (komentar, reaksi, tag, revisi, bookmark ikut ter-cascade)
+	if result.ObjectKeys, err = queryStrings(ctx, tx, `SELECT storagekey FROM post_media WHERE userid = $1 UNION ALL SELECT thumbnailkey FROM post_media WHERE userid = $1`, userID); err != nil {
+		return nil, err
+	}
+	if result.PostIDs, err = queryIDs(ctx, tx, `DELETE FROM posts WHERE userid = $1 RETURNING postid`, userID); err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM post_media WHERE userid = $1`, userID); err != nil {
+		return nil, err
+	}
+
+	// step 5: putuskan semua pertemanan, ID lawannya dikembalikan untuk membersihkan cache daftar teman mereka
+	query = `
+		DELETE FROM friends WHERE userid = $1 OR frienduserid = $1
+		RETURNING CASE WHEN userid = $1 THEN frienduserid ELSE userid END`
+	if result.FriendIDs, err = queryIDs(ctx, tx, query, userID); err != nil {
+		return nil, err
+	}
+
+	// step 6: data pribadi lain yang tidak perlu disimpan sama sekali
+	statements := []string{
+		`DELETE FROM user_blocks WHERE userid = $1 OR blockeduserid = $1`,
+		`DELETE FROM user_mutes WHERE userid = $1 OR muteduserid = $1`,
+		`DELETE FROM tag_follows WHERE userid = $1`,
+		`DELETE FROM bookmark_collections WHERE userid = $1`,
+		`DELETE FROM user_profiles WHERE userid = $1`,
+		`DELETE FROM notifications WHERE userid = $1`,
+		`DELETE FROM journal_entries WHERE userid = $1`,
+		`DELETE FROM journal_keys WHERE userid = $1`,
+		`DELETE FROM ai_context_settings WHERE userid = $1`,
+		`DELETE FROM email_tokens WHERE userid = $1`,
+		`DELETE FROM user_recovery_codes WHERE userid = $1`,
+		`DELETE FROM user_mfa WHERE userid = $1`,
+		`DELETE FROM user_roles WHERE userid = $1`,
+	}
+	for _, statement := range statements {
+		if _, err = tx.ExecContext(ctx, statement, userID); err != nil {
+			return nil, err
+		}
+	}
+	exportKeys, err := queryStrings(ctx, tx, `DELETE FROM data_exports WHERE userid = $1 RETURNING COALESCE(storagekey, '')`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range exportKeys {
+		if key != "" {
+			result.ObjectKeys = append(result.ObjectKeys, key)
+		}
+	}
+
+	// step 7: anonimkan baris user-nya. Password diganti nilai acak supaya tidak ada yang bisa login lagi.
+	password := make([]byte, 32)
+	if _, err = rand.Read(password); err != nil {
+		return nil, err
+	}
+	query = `
+		UPDATE users
+		SET username = 'deleted_' || userid, fullname = 'Deleted user', email = 'deleted_' || userid || '@deleted.invalid',
+			password = $2, avatarkey = NULL, emailverifiedat = NULL, deletionscheduledat = NULL, deletedat = NOW()
+		WHERE userid = $1`
+	if _, err = tx.ExecContext(ctx, query, userID, hex.EncodeToString(password)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+func scanIDs(rows *sql.Rows) ([]int, error) {
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func queryStrings(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]string, error) {
+	var values pq.StringArray
+	// dibungkus CTE supaya query-nya boleh berupa DELETE ... RETURNING
+	err := tx.QueryRowContext(ctx, `WITH q(v) AS (`+query+`) SELECT COALESCE(array_agg(v), '{}') FROM q`, args...).Scan(&values)
+	return values, err
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+)
+
+type AIContextRepository interface {
+	FindSettings(ctx context.Context, db *sql.DB, userID int) (*entity.AIContextSetting, error)
+	UpsertSettings(ctx context.Context, tx *sql.Tx, setting *entity.AIContextSetting) (*entity.AIContextSetting, error)
+}
+
+type AIContextRepositoryImpl struct {
+}
+
+func NewAIContextRepository() AIContextRepository {
+	return &AIContextRepositoryImpl{}
+}
+
+func (r *AIContextRepositoryImpl) FindSettings(ctx context.Context, db *sql.DB, userID int) (*entity.AIContextSetting, error) {
+	// step 1: define query-nya
+	query := `SELECT userid, enabled, includemoodtrend, includeposts, updatedat FROM ai_context_settings WHERE userid = $1`
+
+	// step 2: jalankan query-nya
+	row := db.QueryRowContext(ctx, query, userID)
+
+	// step 3: scan hasilnya, kalau belum pernah diatur berarti pakai default (opt-in, jadi masih mati)
+	var setting entity.AIContextSetting
+	err := row.Scan(&setting.UserID, &setting.Enabled, &setting.IncludeMoodTrend, &setting.IncludePosts, &setting.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &entity.AIContextSetting{
+				UserID:           userID,
+				Enabled:          false,
+				IncludeMoodTrend: true,
+				IncludePosts:     true,
+			}, nil
+		}
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+func (r *AIContextRepositoryImpl) UpsertSettings(ctx context.Context, tx *sql.Tx, setting *entity.AIContextSetting) (*entity.AIContextSetting, error) {
+	// step 1: define query-nya (insert kalau belum ada, update kalau sudah ada)
+	query := `
+		INSERT INTO ai_context_settings (userid, enabled, includemoodtrend, includeposts, updatedat)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (userid) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+			includemoodtrend = EXCLUDED.includemoodtrend,
+			includeposts = EXCLUDED.includeposts,
+			updatedat = NOW()
+		RETURNING userid, enabled, includemoodtrend, includeposts, updatedat
+	`
+
+	// step 2: jalankan query-nya
+	row := tx.QueryRowContext(ctx, query, setting.UserID, setting.Enabled, setting.IncludeMoodTrend, setting.IncludePosts)
+
+	// step 3: scan hasilnya
+	var updatedSetting entity.AIContextSetting
+	if err := row.Scan(&updatedSetting.UserID, &updatedSetting.Enabled, &updatedSetting.IncludeMoodTrend, &updatedSetting.IncludePosts, &updatedSetting.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &updatedSetting, nil
+}
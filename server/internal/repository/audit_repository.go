@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"strings"
+	"time"
+)
+
+// AuditEventFilter semua field-nya opsional, nilai kosong berarti tidak difilter
+type AuditEventFilter struct {
+	ActorID    *int
+	Action     string
+	TargetType string
+	TargetID   *int
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditRepository interface {
+	LockChain(ctx context.Context, tx *sql.Tx) error
+	LastHash(ctx context.Context, tx *sql.Tx) (string, error)
+	Insert(ctx context.Context, tx *sql.Tx, event *entity.AuditEvent) (int, error)
+	Find(ctx context.Context, db *sql.DB, filter AuditEventFilter, afterCreatedAt time.Time, afterID, limit int) ([]*entity.AuditEvent, error)
+	FindChain(ctx context.Context, db *sql.DB, afterID, limit int) ([]*entity.AuditEvent, error)
+	Archive(ctx context.Context, tx *sql.Tx, before time.Time, limit int) (int64, error)
+	PurgeArchive(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error)
+}
+
+type AuditRepositoryImpl struct {
+}
+
+func NewAuditRepository() AuditRepository {
+	return &AuditRepositoryImpl{}
+}
+
+const auditEventColumns = `eventid, actorid, action, targettype, targetid, metadata, ipaddress, prevhash, hash, createdat`
+
+// LockChain mengunci rantai hash sampai transaksinya selesai, supaya dua event yang ditulis bersamaan
+// tidak sama-sama memakai hash terakhir yang sama sebagai PrevHash
+func (r *AuditRepositoryImpl) LockChain(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit_events'))`)
+	return err
+}
+
+// LastHash return string kosong kalau belum ada event sama sekali (termasuk yang sudah diarsip)
+func (r *AuditRepositoryImpl) LastHash(ctx context.Context, tx *sql.Tx) (string, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT hash FROM audit_events ORDER BY eventid DESC LIMIT 1),
+			(SELECT hash FROM audit_events_archive ORDER BY eventid DESC LIMIT 1),
+			''
+		)`
+
+	var hash string
+	err := tx.QueryRowContext(ctx, query).Scan(&hash)
+	return hash, err
+}
+
+func (r *AuditRepositoryImpl) Insert(ctx context.Context, tx *sql.Tx, event *entity.AuditEvent) (int, error) {
+	query := `
+		INSERT INTO audit_events (actorid, action, targettype, targetid, metadata, ipaddress, prevhash, hash, createdat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING eventid`
+
+	var eventID int
+	err := tx.QueryRowContext(ctx, query, event.ActorID, event.Action, event.TargetType, event.TargetID,
+		event.Metadata, event.IPAddress, event.PrevHash, event.Hash, event.CreatedAt).Scan(&eventID)
+	return eventID, err
+}
+
+func (r *AuditRepositoryImpl) Find(ctx context.Context, db *sql.DB, filter AuditEventFilter, afterCreatedAt time.Time, afterID, limit int) ([]*entity.AuditEvent, error) {
+	// step 1: susun kondisi sesuai filter yang diisi
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+	if filter.ActorID != nil {
+		addCondition("actorid = $%d", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		addCondition("targettype = $%d", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		addCondition("targetid = $%d", *filter.TargetID)
+	}
+	if filter.From != nil {
+		addCondition("createdat >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("createdat < $%d", *filter.To)
+	}
+	if afterID != 0 {
+		args = append(args, afterCreatedAt, afterID)
+		conditions = append(conditions, fmt.Sprintf("(createdat, eventid) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// step 2: event terbaru duluan
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT %s FROM audit_events %s ORDER BY createdat DESC, eventid DESC LIMIT $%d`, auditEventColumns, where, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAuditEvents(rows)
+}
+
+// FindChain mengambil event berurutan dari yang paling lama, dipakai untuk memverifikasi rantai hash
+func (r *AuditRepositoryImpl) FindChain(ctx context.Context, db *sql.DB, afterID, limit int) ([]*entity.AuditEvent, error) {
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events WHERE eventid > $1 ORDER BY eventid ASC LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAuditEvents(rows)
+}
+
+// Archive memindahkan event yang lebih lama dari before ke audit_events_archive, paling banyak limit baris per panggilan
+func (r *AuditRepositoryImpl) Archive(ctx context.Context, tx *sql.Tx, before time.Time, limit int) (int64, error) {
+	if err := allowAuditArchive(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	query := `
+		WITH moved AS (
+			DELETE FROM audit_events
+			WHERE eventid IN (SELECT eventid FROM audit_events WHERE createdat < $1 ORDER BY eventid LIMIT $2)
+			RETURNING ` + auditEventColumns + `
+		)
+		INSERT INTO audit_events_archive (` + auditEventColumns + `)
+		SELECT ` + auditEventColumns + ` FROM moved`
+
+	result, err := tx.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeArchive menghapus permanen arsip yang sudah lewat masa simpan
+func (r *AuditRepositoryImpl) PurgeArchive(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	if err := allowAuditArchive(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM audit_events_archive WHERE createdat < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// allowAuditArchive menyalakan izin DELETE di trigger append-only, cuma untuk transaksi ini saja
+func allowAuditArchive(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `SELECT set_config('audit.allow_archive', 'on', true)`)
+	return err
+}
+
+func scanAuditEvents(rows *sql.Rows) ([]*entity.AuditEvent, error) {
+	var events []*entity.AuditEvent
+	for rows.Next() {
+		var event entity.AuditEvent
+		var actorID, targetID sql.NullInt64
+		err := rows.Scan(&event.EventID, &actorID, &event.Action, &event.TargetType, &targetID,
+			&event.Metadata, &event.IPAddress, &event.PrevHash, &event.Hash, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event.ActorID = nullIntPtr(actorID)
+		event.TargetID = nullIntPtr(targetID)
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+// Subquery userid yang punya hubungan blokir dengan user $1, dua arah (yang diblokir $1 dan yang memblokir $1).
+// Semua query yang menampilkan konten user lain ke viewer $1 wajib menyaring pakai ini (atau hiddenUserIDsSubquery).
+const blockedUserIDsSubquery = `
+	SELECT blockeduserid FROM user_blocks WHERE userid = $1
+	UNION
+	SELECT userid FROM user_blocks WHERE blockeduserid = $1`
+
+// Subquery userid yang kontennya disembunyikan dari feed/list milik user $1: yang terblokir ditambah yang di-mute $1
+const hiddenUserIDsSubquery = blockedUserIDsSubquery + `
+	UNION
+	SELECT muteduserid FROM user_mutes WHERE userid = $1`
+
+type BlockRepository interface {
+	Block(ctx context.Context, tx *sql.Tx, userID, blockedUserID int) (bool, error)
+	Unblock(ctx context.Context, db *sql.DB, userID, blockedUserID int) (bool, error)
+	Mute(ctx context.Context, db *sql.DB, userID, mutedUserID int) (bool, error)
+	Unmute(ctx context.Context, db *sql.DB, userID, mutedUserID int) (bool, error)
+	IsBlocked(ctx context.Context, db *sql.DB, userID, otherUserID int) (bool, error)
+	FindBlocked(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.UserBlock, error)
+	FindMuted(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.UserMute, error)
+	FindHiddenUserIDs(ctx context.Context, db *sql.DB, userID int) ([]int, error)
+}
+
+type BlockRepositoryImpl struct {
+}
+
+func NewBlockRepository() BlockRepository {
+	return &BlockRepositoryImpl{}
+}
+
+// Block, Unblock, Mute, dan Unmute idempotent, return true kalau datanya benar-benar berubah
+func (r *BlockRepositoryImpl) Block(ctx context.Context, tx *sql.Tx, userID, blockedUserID int) (bool, error) {
+	query := `INSERT INTO user_blocks (userid, blockeduserid) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	return execChanged(tx.ExecContext(ctx, query, userID, blockedUserID))
+}
+
+func (r *BlockRepositoryImpl) Unblock(ctx context.Context, db *sql.DB, userID, blockedUserID int) (bool, error) {
+	query := `DELETE FROM user_blocks WHERE userid = $1 AND blockeduserid = $2`
+	return execChanged(db.ExecContext(ctx, query, userID, blockedUserID))
+}
+
+func (r *BlockRepositoryImpl) Mute(ctx context.Context, db *sql.DB, userID, mutedUserID int) (bool, error) {
+	query := `INSERT INTO user_mutes (userid, muteduserid) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	return execChanged(db.ExecContext(ctx, query, userID, mutedUserID))
+}
+
+func (r *BlockRepositoryImpl) Unmute(ctx context.Context, db *sql.DB, userID, mutedUserID int) (bool, error) {
+	query := `DELETE FROM user_mutes WHERE userid = $1 AND muteduserid = $2`
+	return execChanged(db.ExecContext(ctx, query, userID, mutedUserID))
+}
+
+// IsBlocked ngecek blokir dari dua arah
+func (r *BlockRepositoryImpl) IsBlocked(ctx context.Context, db *sql.DB, userID, otherUserID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (userid = $1 AND blockeduserid = $2) OR (userid = $2 AND blockeduserid = $1)
+		)`
+	var blocked bool
+	err := db.QueryRowContext(ctx, query, userID, otherUserID).Scan(&blocked)
+	return blocked, err
+}
+
+func (r *BlockRepositoryImpl) FindBlocked(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.UserBlock, error) {
+	query := `
+		SELECT b.userid, b.blockeduserid, b.createdat, u.userid, u.username, u.fullname
+		FROM user_blocks b
+		JOIN users u ON u.userid = b.blockeduserid
+		WHERE b.userid = $1
+			AND ($3 = 0 OR (b.createdat, b.blockeduserid) < ($2, $3))
+		ORDER BY b.createdat DESC, b.blockeduserid DESC
+		LIMIT $4`
+
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []*entity.UserBlock{}
+	for rows.Next() {
+		var block entity.UserBlock
+		var user entity.User
+		if err := rows.Scan(&block.UserID, &block.BlockedUserID, &block.CreatedAt, &user.ID, &user.Username, &user.Fullname); err != nil {
+			return nil, err
+		}
+		block.User = &user
+		blocks = append(blocks, &block)
+	}
+	return blocks, rows.Err()
+}
+
+func (r *BlockRepositoryImpl) FindMuted(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.UserMute, error) {
+	query := `
+		SELECT m.userid, m.muteduserid, m.createdat, u.userid, u.username, u.fullname
+		FROM user_mutes m
+		JOIN users u ON u.userid = m.muteduserid
+		WHERE m.userid = $1
+			AND ($3 = 0 OR (m.createdat, m.muteduserid) < ($2, $3))
+		ORDER BY m.createdat DESC, m.muteduserid DESC
+		LIMIT $4`
+
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutes := []*entity.UserMute{}
+	for rows.Next() {
+		var mute entity.UserMute
+		var user entity.User
+		if err := rows.Scan(&mute.UserID, &mute.MutedUserID, &mute.CreatedAt, &user.ID, &user.Username, &user.Fullname); err != nil {
+			return nil, err
+		}
+		mute.User = &user
+		mutes = append(mutes, &mute)
+	}
+	return mutes, rows.Err()
+}
+
+// FindHiddenUserIDs dipakai untuk menyaring list yang tidak bisa disaring langsung di query (misalnya halaman komentar yang di-cache)
+func (r *BlockRepositoryImpl) FindHiddenUserIDs(ctx context.Context, db *sql.DB, userID int) ([]int, error) {
+	rows, err := db.QueryContext(ctx, hiddenUserIDsSubquery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+func execChanged(result sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
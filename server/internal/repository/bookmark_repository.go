@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+type BookmarkRepository interface {
+	CreateCollection(ctx context.Context, db *sql.DB, userID int, name string) (*entity.BookmarkCollection, error)
+	FindCollection(ctx context.Context, db *sql.DB, collectionID int) (*entity.BookmarkCollection, error)
+	FindCollectionsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.BookmarkCollection, error)
+	CountCollections(ctx context.Context, db *sql.DB, userID int) (int, error)
+	RenameCollection(ctx context.Context, db *sql.DB, collectionID int, name string) (*entity.BookmarkCollection, error)
+	DeleteCollection(ctx context.Context, db *sql.DB, collectionID int) (bool, error)
+	Add(ctx context.Context, db *sql.DB, collectionID, postID int) (bool, error)
+	Remove(ctx context.Context, db *sql.DB, collectionID, postID int) (bool, error)
+	FindByCollectionID(ctx context.Context, db *sql.DB, collectionID int, afterCreatedAt time.Time, afterPostID, limit int) ([]*entity.Bookmark, error)
+	DeleteInaccessible(ctx context.Context, tx *sql.Tx, postID int) (int64, error)
+}
+
+type BookmarkRepositoryImpl struct {
+}
+
+func NewBookmarkRepository() BookmarkRepository {
+	return &BookmarkRepositoryImpl{}
+}
+
+// Jumlah bookmark dihitung langsung lewat subquery, koleksi biasanya kecil jadi tidak perlu counter terpisah
+const bookmarkCollectionColumns = `c.collectionid, c.userid, c.name,
+	(SELECT COUNT(*) FROM bookmarks b WHERE b.collectionid = c.collectionid) AS postcount,
+	c.createdat, c.updatedat`
+
+func scanBookmarkCollection(scanner interface{ Scan(dest ...any) error }) (*entity.BookmarkCollection, error) {
+	var collection entity.BookmarkCollection
+	err := scanner.Scan(&collection.CollectionID, &collection.UserID, &collection.Name, &collection.PostCount, &collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// CreateCollection mengembalikan nil kalau user sudah punya koleksi dengan nama yang sama
+func (r *BookmarkRepositoryImpl) CreateCollection(ctx context.Context, db *sql.DB, userID int, name string) (*entity.BookmarkCollection, error) {
+	query := `
+		INSERT INTO bookmark_collections AS c (userid, name) VALUES ($1, $2)
+		ON CONFLICT (userid, name) DO NOTHING
+		RETURNING ` + bookmarkCollectionColumns
+
+	collection, err := scanBookmarkCollection(db.QueryRowContext(ctx, query, userID, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (r *BookmarkRepositoryImpl) FindCollection(ctx context.Context, db *sql.DB, collectionID int) (*entity.BookmarkCollection, error) {
+	query := `SELECT ` + bookmarkCollectionColumns + ` FROM bookmark_collections c WHERE c.collectionid = $1`
+
+	collection, err := scanBookmarkCollection(db.QueryRowContext(ctx, query, collectionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (r *BookmarkRepositoryImpl) FindCollectionsByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.BookmarkCollection, error) {
+	query := `
+		SELECT ` + bookmarkCollectionColumns + `
+		FROM bookmark_collections c
+		WHERE c.userid = $1
+		ORDER BY c.createdat ASC, c.collectionid ASC`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []*entity.BookmarkCollection
+	for rows.Next() {
+		collection, err := scanBookmarkCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+func (r *BookmarkRepositoryImpl) CountCollections(ctx context.Context, db *sql.DB, userID int) (int, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bookmark_collections WHERE userid = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// RenameCollection mengembalikan nil kalau koleksinya tidak ada atau namanya bentrok dengan koleksi lain milik user yang sama
+func (r *BookmarkRepositoryImpl) RenameCollection(ctx context.Context, db *sql.DB, collectionID int, name string) (*entity.BookmarkCollection, error) {
+	query := `
+		UPDATE bookmark_collections AS c
+		SET name = $1, updatedat = NOW()
+		WHERE c.collectionid = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM bookmark_collections other
+				WHERE other.userid = c.userid AND other.name = $1 AND other.collectionid <> c.collectionid
+			)
+		RETURNING ` + bookmarkCollectionColumns
+
+	collection, err := scanBookmarkCollection(db.QueryRowContext(ctx, query, name, collectionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return collection, nil
+}
+
+// DeleteCollection ikut menghapus semua bookmark di dalamnya (ON DELETE CASCADE)
+func (r *BookmarkRepositoryImpl) DeleteCollection(ctx context.Context, db *sql.DB, collectionID int) (bool, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM bookmark_collections WHERE collectionid = $1`, collectionID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Add mengembalikan false kalau postingannya sudah ada di koleksi (aman dipanggil berkali-kali)
+func (r *BookmarkRepositoryImpl) Add(ctx context.Context, db *sql.DB, collectionID, postID int) (bool, error) {
+	query := `
+		INSERT INTO bookmarks (collectionid, postid) VALUES ($1, $2)
+		ON CONFLICT (collectionid, postid) DO NOTHING`
+
+	result, err := db.ExecContext(ctx, query, collectionID, postID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *BookmarkRepositoryImpl) Remove(ctx context.Context, db *sql.DB, collectionID, postID int) (bool, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM bookmarks WHERE collectionid = $1 AND postid = $2`, collectionID, postID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// FindByCollectionID pakai keyset pagination di (createdat, postid), yang terakhir disimpan muncul duluan
+func (r *BookmarkRepositoryImpl) FindByCollectionID(ctx context.Context, db *sql.DB, collectionID int, afterCreatedAt time.Time, afterPostID, limit int) ([]*entity.Bookmark, error) {
+	query := `
+		SELECT collectionid, postid, createdat
+		FROM bookmarks
+		WHERE collectionid = $1
+			AND ($3 = 0 OR (createdat, postid) < ($2, $3))
+		ORDER BY createdat DESC, postid DESC
+		LIMIT $4`
+
+	rows, err := db.QueryContext(ctx, query, collectionID, afterCreatedAt, afterPostID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookmarks []*entity.Bookmark
+	for rows.Next() {
+		var bookmark entity.Bookmark
+		if err := rows.Scan(&bookmark.CollectionID, &bookmark.PostID, &bookmark.CreatedAt); err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, &bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+// DeleteInaccessible dipanggil setelah visibility postingan berubah: bookmark milik user yang sudah tidak boleh
+// melihat postingannya dibuang. Aturannya sama dengan FindAll (public, milik sendiri, atau friends dan sudah berteman).
+func (r *BookmarkRepositoryImpl) DeleteInaccessible(ctx context.Context, tx *sql.Tx, postID int) (int64, error) {
+	query := `
+		DELETE FROM bookmarks b
+		USING bookmark_collections c, posts p
+		WHERE b.collectionid = c.collectionid
+			AND p.postid = b.postid
+			AND b.postid = $1
+			AND c.userid <> p.userid
+			AND p.visibility <> 'public'
+			AND NOT (
+				p.visibility = 'friends'
+				AND EXISTS (
+					SELECT 1 FROM friends f
+					WHERE f.status = 'accepted'
+						AND (
+							(f.userid = c.userid AND f.frienduserid = p.userid)
+							OR (f.userid = p.userid AND f.frienduserid = c.userid)
+						)
+				)
+			)`
+
+	result, err := tx.ExecContext(ctx, query, postID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
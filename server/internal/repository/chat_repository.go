@@ -13,6 +13,8 @@ type ChatRepository interface {
 	GetMessagesForConversation(ctx context.Context, senderID, recipientID, limit, offset int) ([]*entity.Message, error)
 	UpdateMessageStatus(ctx context.Context, messageID int, newStatus entity.MessageStatus) error
 	GetUnreadMessagesForUser(ctx context.Context, userID int, afterTimestamp time.Time) ([]*entity.Message, error)
+	FindMessageByID(ctx context.Context, messageID int) (*entity.Message, error)
+	DeleteMessage(ctx context.Context, messageID int) error
 }
 
 type ChatRepositoryImpl struct {
@@ -141,4 +143,26 @@ func (r *ChatRepositoryImpl) GetUnreadMessagesForUser(ctx context.Context, userI
 
 	// step 6: jika berhasil, return slice of unread messages
 	return messages, nil
-}
\ No newline at end of file
+}
+
+// FindMessageByID return nil kalau pesannya tidak ada (dipakai waktu pesan dilaporkan)
+func (r *ChatRepositoryImpl) FindMessageByID(ctx context.Context, messageID int) (*entity.Message, error) {
+	query := `SELECT messageid, senderid, recipientid, content, timestamp, status FROM messages WHERE messageid = $1`
+
+	var msg entity.Message
+	err := r.DB.QueryRowContext(ctx, query, messageID).Scan(&msg.ID, &msg.SenderID, &msg.RecipientID, &msg.Content, &msg.Timestamp, &msg.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving message: %w", err)
+	}
+	return &msg, nil
+}
+
+func (r *ChatRepositoryImpl) DeleteMessage(ctx context.Context, messageID int) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM messages WHERE messageid = $1`, messageID); err != nil {
+		return fmt.Errorf("error deleting message: %w", err)
+	}
+	return nil
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"mood-bridge-v2/server/internal/entity"
-	"strconv"
+	"time"
 )
 
 type CommentRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, comment *entity.Comment) (*entity.Comment, error)
-	GetAllByPostID(ctx context.Context, db *sql.DB, postID int) ([]*entity.Comment, error)
-	Delete(ctx context.Context, tx *sql.Tx, commentID int) (string, error)
+	GetTopLevelByPostID(ctx context.Context, db *sql.DB, postID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Comment, error)
+	GetReplies(ctx context.Context, db *sql.DB, parentCommentID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Comment, error)
+	GetNeedsReview(ctx context.Context, db *sql.DB, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Comment, error)
+	SoftDeleteThread(ctx context.Context, tx *sql.Tx, commentID int) ([]int, error)
 	GetByID(ctx context.Context, db *sql.DB, commentID int) (*entity.Comment, error)
+	Update(ctx context.Context, tx *sql.Tx, commentID int, content string) (*entity.Comment, error)
+	UpdateMood(ctx context.Context, db *sql.DB, commentID int, classifiedContent, mood string, needsReview bool) (bool, error)
+	GetRevisions(ctx context.Context, db *sql.DB, commentID int) ([]*entity.CommentRevision, error)
 }
 
 type CommentRepositoryImpl struct {
@@ -21,35 +26,105 @@ func NewCommentRepository() CommentRepository {
 	return &CommentRepositoryImpl{}
 }
 
-func (r *CommentRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, comment *entity.Comment) (*entity.Comment, error) {
-	query := `INSERT INTO comments (postid, userid, content, createdat) VALUES ($1, $2, $3, $4) RETURNING commentid, postid, userid, content, createdat`
+const commentColumns = `commentid, postid, userid, parentcommentid, depth, replycount, isdeleted, content, mood, needsreview, createdat, editedat, hiddenat`
+
+func scanComment(scanner interface{ Scan(dest ...any) error }) (*entity.Comment, error) {
+	var comment entity.Comment
+	var parentCommentID sql.NullInt64
+	var mood sql.NullString
+	var editedAt, hiddenAt sql.NullTime
+	err := scanner.Scan(&comment.CommentID, &comment.PostID, &comment.UserID, &parentCommentID, &comment.Depth, &comment.ReplyCount, &comment.IsDeleted, &comment.Content, &mood, &comment.NeedsReview, &comment.CreatedAt, &editedAt, &hiddenAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentCommentID.Valid {
+		parentID := int(parentCommentID.Int64)
+		comment.ParentCommentID = &parentID
+	}
+	if mood.Valid {
+		comment.Mood = &mood.String
+	}
+	if editedAt.Valid {
+		comment.EditedAt = &editedAt.Time
+	}
+	if hiddenAt.Valid {
+		comment.HiddenAt = &hiddenAt.Time
+	}
+	return &comment, nil
+}
 
-	row := tx.QueryRowContext(ctx, query, comment.PostID, comment.UserID, comment.Content, comment.CreatedAt)
+func (r *CommentRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, comment *entity.Comment) (*entity.Comment, error) {
+	query := `INSERT INTO comments (postid, userid, parentcommentid, depth, content, createdat) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ` + commentColumns
 
-	var createdComment entity.Comment
-	err := row.Scan(&createdComment.CommentID, &createdComment.PostID, &createdComment.UserID, &createdComment.Content, &createdComment.CreatedAt)
+	row := tx.QueryRowContext(ctx, query, comment.PostID, comment.UserID, comment.ParentCommentID, comment.Depth, comment.Content, comment.CreatedAt)
+	createdComment, err := scanComment(row)
 	if err != nil {
 		return nil, err
 	}
 
-	return &createdComment, nil
+	// kalau ini balasan, jumlah balasan di komentar induknya ikut naik (masih dalam transaction yang sama)
+	if comment.ParentCommentID != nil {
+		_, err = tx.ExecContext(ctx, `UPDATE comments SET replycount = replycount + 1 WHERE commentid = $1`, *comment.ParentCommentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return createdComment, nil
 }
 
-func (r *CommentRepositoryImpl) GetAllByPostID(ctx context.Context, db *sql.DB, postID int) ([]*entity.Comment, error) {
-	query := `SELECT commentid, postid, userid, content, createdat FROM comments WHERE postid = $1;`
-	rows, err := db.QueryContext(ctx, query, postID)
+// afterID = 0 berarti ambil dari awal, selain itu ambil komentar setelah posisi cursor (createdat, commentid)
+func (r *CommentRepositoryImpl) GetTopLevelByPostID(ctx context.Context, db *sql.DB, postID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE postid = $1 AND parentcommentid IS NULL
+		AND ($3 = 0 OR (createdat, commentid) > ($2, $3))
+		ORDER BY createdat ASC, commentid ASC
+		LIMIT $4`
+
+	return r.queryComments(ctx, db, query, postID, afterCreatedAt, afterID, limit)
+}
+
+func (r *CommentRepositoryImpl) GetReplies(ctx context.Context, db *sql.DB, parentCommentID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE parentcommentid = $1
+		AND ($3 = 0 OR (createdat, commentid) > ($2, $3))
+		ORDER BY createdat ASC, commentid ASC
+		LIMIT $4`
+
+	return r.queryComments(ctx, db, query, parentCommentID, afterCreatedAt, afterID, limit)
+}
+
+// GetNeedsReview: komentar yang ditandai classifier perlu dilihat moderator, yang paling lama duluan
+func (r *CommentRepositoryImpl) GetNeedsReview(ctx context.Context, db *sql.DB, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE needsreview = TRUE AND isdeleted = FALSE
+		AND ($2 = 0 OR (createdat, commentid) > ($1, $2))
+		ORDER BY createdat ASC, commentid ASC
+		LIMIT $3`
+
+	return r.queryComments(ctx, db, query, afterCreatedAt, afterID, limit)
+}
+
+func (r *CommentRepositoryImpl) queryComments(ctx context.Context, db *sql.DB, query string, args ...any) ([]*entity.Comment, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+
 	var comments []*entity.Comment
 	for rows.Next() {
-		var comment entity.Comment
-		err := rows.Scan(&comment.CommentID, &comment.PostID, &comment.UserID, &comment.Content, &comment.CreatedAt)
+		comment, err := scanComment(rows)
 		if err != nil {
 			return nil, err
 		}
-		comments = append(comments, &comment)
+		comments = append(comments, comment)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -57,28 +132,51 @@ func (r *CommentRepositoryImpl) GetAllByPostID(ctx context.Context, db *sql.DB,
 	return comments, nil
 }
 
-func (r *CommentRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, commentID int) (string, error) {
-	query := `DELETE FROM comments WHERE commentid = $1 RETURNING commentid`
-	row := tx.QueryRowContext(ctx, query, commentID)
+// SoftDeleteThread menandai komentar beserta semua balasan di bawahnya sebagai "[deleted]".
+// Barisnya tidak dihapus supaya struktur thread tetap utuh, isi komentarnya saja yang dibuang.
+func (r *CommentRepositoryImpl) SoftDeleteThread(ctx context.Context, tx *sql.Tx, commentID int) ([]int, error) {
+	threadQuery := `
+		WITH RECURSIVE thread AS (
+			SELECT commentid FROM comments WHERE commentid = $1
+			UNION ALL
+			SELECT c.commentid FROM comments c JOIN thread t ON c.parentcommentid = t.commentid
+		)`
+
+	// riwayat edit-nya juga ikut dibuang, jangan sampai isi lama masih bisa dibaca
+	if _, err := tx.ExecContext(ctx, threadQuery+` DELETE FROM comment_revisions WHERE commentid IN (SELECT commentid FROM thread)`, commentID); err != nil {
+		return nil, err
+	}
+
+	query := threadQuery + `
+		UPDATE comments
+		SET isdeleted = TRUE, content = '[deleted]', mood = NULL, needsreview = FALSE, deletedat = NOW()
+		WHERE commentid IN (SELECT commentid FROM thread) AND isdeleted = FALSE
+		RETURNING commentid`
 
-	var deletedCommentID int
-	err := row.Scan(&deletedCommentID)
+	rows, err := tx.QueryContext(ctx, query, commentID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", nil // Comment not found
-		}
-		return "", err // Other error
+		return nil, err
 	}
+	defer rows.Close()
 
-	return "Comment with ID " + strconv.Itoa(deletedCommentID) + " deleted successfully", nil
+	var deletedIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		deletedIDs = append(deletedIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deletedIDs, nil
 }
 
 func (r *CommentRepositoryImpl) GetByID(ctx context.Context, db *sql.DB, commentID int) (*entity.Comment, error) {
-	query := `SELECT commentid, postid, userid, content, createdat FROM comments WHERE commentid = $1;`
-	row := db.QueryRowContext(ctx, query, commentID)
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE commentid = $1;`
 
-	var selectedComment entity.Comment
-	err := row.Scan(&selectedComment.CommentID, &selectedComment.PostID, &selectedComment.UserID, &selectedComment.Content, &selectedComment.CreatedAt)
+	selectedComment, err := scanComment(db.QueryRowContext(ctx, query, commentID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Comment not found
@@ -86,5 +184,74 @@ func (r *CommentRepositoryImpl) GetByID(ctx context.Context, db *sql.DB, comment
 		return nil, err // Other error
 	}
 
-	return &selectedComment, nil
-}
\ No newline at end of file
+	return selectedComment, nil
+}
+
+// Update menyimpan isi lama ke comment_revisions dulu, baru isi komentarnya diganti.
+// Mood-nya dikosongkan karena harus diklasifikasi ulang dari isi yang baru.
+func (r *CommentRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, commentID int, content string) (*entity.Comment, error) {
+	revisionQuery := `
+		INSERT INTO comment_revisions (commentid, content, mood)
+		SELECT commentid, content, mood FROM comments WHERE commentid = $1 AND isdeleted = FALSE`
+	if _, err := tx.ExecContext(ctx, revisionQuery, commentID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE comments
+		SET content = $1, mood = NULL, needsreview = FALSE, editedat = NOW()
+		WHERE commentid = $2 AND isdeleted = FALSE
+		RETURNING ` + commentColumns
+
+	updatedComment, err := scanComment(tx.QueryRowContext(ctx, query, content, commentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Comment not found
+		}
+		return nil, err
+	}
+	return updatedComment, nil
+}
+
+// UpdateMood cuma nyimpen hasil klasifikasi kalau isi komentarnya belum berubah sejak diklasifikasi
+// (kalau keburu diedit, hasil klasifikasi yang lama dibuang dan nunggu hasil yang baru)
+func (r *CommentRepositoryImpl) UpdateMood(ctx context.Context, db *sql.DB, commentID int, classifiedContent, mood string, needsReview bool) (bool, error) {
+	query := `UPDATE comments SET mood = $1, needsreview = $2 WHERE commentid = $3 AND content = $4 AND isdeleted = FALSE`
+
+	result, err := db.ExecContext(ctx, query, mood, needsReview, commentID, classifiedContent)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *CommentRepositoryImpl) GetRevisions(ctx context.Context, db *sql.DB, commentID int) ([]*entity.CommentRevision, error) {
+	query := `SELECT revisionid, commentid, content, mood, editedat FROM comment_revisions WHERE commentid = $1 ORDER BY editedat DESC, revisionid DESC`
+
+	rows, err := db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*entity.CommentRevision
+	for rows.Next() {
+		var revision entity.CommentRevision
+		var mood sql.NullString
+		if err := rows.Scan(&revision.RevisionID, &revision.CommentID, &revision.Content, &mood, &revision.EditedAt); err != nil {
+			return nil, err
+		}
+		if mood.Valid {
+			revision.Mood = &mood.String
+		}
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
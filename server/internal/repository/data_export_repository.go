@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, db *sql.DB, userID int) (*entity.DataExport, error)
+	FindActive(ctx context.Context, db *sql.DB, userID int) (*entity.DataExport, error)
+	FindByID(ctx context.Context, db *sql.DB, exportID int) (*entity.DataExport, error)
+	FindByUserID(ctx context.Context, db *sql.DB, userID int, limit int) ([]*entity.DataExport, error)
+	FindPendingIDs(ctx context.Context, db *sql.DB, staleBefore time.Time, limit int) ([]int, error)
+	Claim(ctx context.Context, db *sql.DB, exportID int, staleBefore time.Time) (bool, error)
+	MarkReady(ctx context.Context, db *sql.DB, exportID int, storageKey string, sizeBytes int64, expiresAt time.Time) (bool, error)
+	MarkFailed(ctx context.Context, db *sql.DB, exportID int, reason string) error
+	FindExpired(ctx context.Context, db *sql.DB, before time.Time, limit int) ([]*entity.DataExport, error)
+	Delete(ctx context.Context, db *sql.DB, exportID int) (bool, error)
+}
+
+type DataExportRepositoryImpl struct {
+}
+
+func NewDataExportRepository() DataExportRepository {
+	return &DataExportRepositoryImpl{}
+}
+
+const dataExportColumns = `exportid, userid, status, storagekey, sizebytes, error, startedat, completedat, expiresat, createdat`
+
+func scanDataExport(scanner interface{ Scan(dest ...any) error }) (*entity.DataExport, error) {
+	var export entity.DataExport
+	var storageKey sql.NullString
+	var sizeBytes sql.NullInt64
+	var startedAt, completedAt, expiresAt sql.NullTime
+	err := scanner.Scan(&export.ExportID, &export.UserID, &export.Status, &storageKey, &sizeBytes, &export.Error, &startedAt, &completedAt, &expiresAt, &export.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	export.StorageKey = storageKey.String
+	export.SizeBytes = sizeBytes.Int64
+	if startedAt.Valid {
+		export.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepositoryImpl) queryDataExports(ctx context.Context, db *sql.DB, query string, args ...any) ([]*entity.DataExport, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []*entity.DataExport
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *DataExportRepositoryImpl) Create(ctx context.Context, db *sql.DB, userID int) (*entity.DataExport, error) {
+	query := `INSERT INTO data_exports (userid) VALUES ($1) RETURNING ` + dataExportColumns
+	return scanDataExport(db.QueryRowContext(ctx, query, userID))
+}
+
+// FindActive mengambil ekspor yang masih diproses atau yang sudah jadi dan belum expired, nil kalau tidak ada
+func (r *DataExportRepositoryImpl) FindActive(ctx context.Context, db *sql.DB, userID int) (*entity.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + ` FROM data_exports
+		WHERE userid = $1 AND (status IN ('pending', 'processing') OR (status = 'ready' AND expiresat > NOW()))
+		ORDER BY createdat DESC, exportid DESC
+		LIMIT 1`
+
+	export, err := scanDataExport(db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// FindByID return nil kalau ekspornya tidak ada
+func (r *DataExportRepositoryImpl) FindByID(ctx context.Context, db *sql.DB, exportID int) (*entity.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE exportid = $1`
+
+	export, err := scanDataExport(db.QueryRowContext(ctx, query, exportID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *DataExportRepositoryImpl) FindByUserID(ctx context.Context, db *sql.DB, userID int, limit int) ([]*entity.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE userid = $1 ORDER BY createdat DESC, exportid DESC LIMIT $2`
+	return r.queryDataExports(ctx, db, query, userID, limit)
+}
+
+// FindPendingIDs mengambil ekspor yang belum juga diproses atau prosesnya macet (misalnya server restart di tengah jalan).
+// Ekspor yang baru dibuat dibiarkan, karena biasanya sedang diambil goroutine dari request-nya sendiri.
+func (r *DataExportRepositoryImpl) FindPendingIDs(ctx context.Context, db *sql.DB, staleBefore time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT exportid FROM data_exports
+		WHERE (status = 'pending' AND createdat < $1) OR (status = 'processing' AND startedat < $1)
+		ORDER BY createdat
+		LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, query, staleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+// Claim menandai ekspor sedang diproses. Return false kalau ekspornya sudah diambil proses lain,
+// jadi goroutine request dan job background tidak membuat arsip yang sama dua kali.
+func (r *DataExportRepositoryImpl) Claim(ctx context.Context, db *sql.DB, exportID int, staleBefore time.Time) (bool, error) {
+	query := `
+		UPDATE data_exports SET status = 'processing', startedat = NOW()
+		WHERE exportid = $1 AND (status = 'pending' OR (status = 'processing' AND startedat < $2))`
+	return execChanged(db.ExecContext(ctx, query, exportID, staleBefore))
+}
+
+// MarkReady return false kalau ekspornya sudah tidak ada (akunnya keburu dihapus), arsipnya harus dibuang pemanggil
+func (r *DataExportRepositoryImpl) MarkReady(ctx context.Context, db *sql.DB, exportID int, storageKey string, sizeBytes int64, expiresAt time.Time) (bool, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'ready', storagekey = $2, sizebytes = $3, expiresat = $4, completedat = NOW(), error = ''
+		WHERE exportid = $1 AND status = 'processing'`
+	return execChanged(db.ExecContext(ctx, query, exportID, storageKey, sizeBytes, expiresAt))
+}
+
+func (r *DataExportRepositoryImpl) MarkFailed(ctx context.Context, db *sql.DB, exportID int, reason string) error {
+	query := `UPDATE data_exports SET status = 'failed', error = $2, completedat = NOW() WHERE exportid = $1`
+	_, err := db.ExecContext(ctx, query, exportID, reason)
+	return err
+}
+
+// FindExpired mengambil arsip yang sudah lewat masa simpannya, termasuk ekspor gagal yang sudah lama
+func (r *DataExportRepositoryImpl) FindExpired(ctx context.Context, db *sql.DB, before time.Time, limit int) ([]*entity.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + ` FROM data_exports
+		WHERE (status = 'ready' AND expiresat <= NOW()) OR (status = 'failed' AND createdat < $1)
+		ORDER BY createdat
+		LIMIT $2`
+	return r.queryDataExports(ctx, db, query, before, limit)
+}
+
+func (r *DataExportRepositoryImpl) Delete(ctx context.Context, db *sql.DB, exportID int) (bool, error) {
+	return execChanged(db.ExecContext(ctx, `DELETE FROM data_exports WHERE exportid = $1`, exportID))
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+type EmailTokenRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, token *entity.EmailToken) error
+	RevokeByUserID(ctx context.Context, tx *sql.Tx, userID int, purpose string) error
+	Consume(ctx context.Context, tx *sql.Tx, tokenHash, purpose string) (*entity.EmailToken, error)
+	DeleteExpired(ctx context.Context, db *sql.DB, before time.Time) (int64, error)
+}
+
+type EmailTokenRepositoryImpl struct {
+}
+
+func NewEmailTokenRepository() EmailTokenRepository {
+	return &EmailTokenRepositoryImpl{}
+}
+
+func (r *EmailTokenRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, token *entity.EmailToken) error {
+	query := `
+		INSERT INTO email_tokens (userid, purpose, email, tokenhash, expiresat)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING tokenid, createdat`
+	return tx.QueryRowContext(ctx, query, token.UserID, token.Purpose, token.Email, token.TokenHash, token.ExpiresAt).Scan(&token.TokenID, &token.CreatedAt)
+}
+
+// RevokeByUserID menandai semua token user untuk kegunaan ini sebagai terpakai, jadi hanya token terbaru yang berlaku
+func (r *EmailTokenRepositoryImpl) RevokeByUserID(ctx context.Context, tx *sql.Tx, userID int, purpose string) error {
+	query := `UPDATE email_tokens SET usedat = NOW() WHERE userid = $1 AND purpose = $2 AND usedat IS NULL`
+	_, err := tx.ExecContext(ctx, query, userID, purpose)
+	return err
+}
+
+// Consume menandai token sebagai terpakai dalam satu query, supaya token yang sama tidak bisa dipakai dua kali
+// walaupun request-nya datang bersamaan. Return nil kalau token tidak ada, sudah dipakai, atau sudah expired.
+func (r *EmailTokenRepositoryImpl) Consume(ctx context.Context, tx *sql.Tx, tokenHash, purpose string) (*entity.EmailToken, error) {
+	query := `
+		UPDATE email_tokens SET usedat = NOW()
+		WHERE tokenhash = $1 AND purpose = $2 AND usedat IS NULL AND expiresat > NOW()
+		RETURNING tokenid, userid, purpose, email, tokenhash, expiresat, usedat, createdat`
+
+	var token entity.EmailToken
+	var usedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, query, tokenHash, purpose).Scan(&token.TokenID, &token.UserID, &token.Purpose, &token.Email, &token.TokenHash, &token.ExpiresAt, &usedAt, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	return &token, nil
+}
+
+func (r *EmailTokenRepositoryImpl) DeleteExpired(ctx context.Context, db *sql.DB, before time.Time) (int64, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM email_tokens WHERE expiresat < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
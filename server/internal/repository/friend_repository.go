@@ -5,17 +5,28 @@ import (
 	"database/sql"
 	"fmt"
 	"mood-bridge-v2/server/internal/entity"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type FriendRepository interface {
 	AddFriend(ctx context.Context, tx *sql.Tx, friend *entity.Friend) (*entity.Friend, error)
 	AcceptRequest(ctx context.Context, tx *sql.Tx, friend *entity.Friend) (*entity.Friend, error)
-	GetFriends(ctx context.Context, db *sql.DB, userID int) (*[]entity.Friend, error)
+	GetFriends(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) (*[]entity.Friend, error)
 	Delete(ctx context.Context, tx *sql.Tx, friendID int) (string, error)
-	IsFriendExist(ctx context.Context, db *sql.DB, userID int, friendUserID int) (bool, error)
+	FindBetween(ctx context.Context, db *sql.DB, userID int, otherUserID int) (*entity.Friend, error)
 	IsFriendAlreadyAccepted(ctx context.Context, db *sql.DB, userID int, friendUserID int) (bool, error)
-	GetFriendRequests(ctx context.Context, db *sql.DB, userID int) (*[]entity.Friend, error)
+	GetFriendRequests(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) (*[]entity.Friend, error)
+	GetOutgoingRequests(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) (*[]entity.Friend, error)
+	Decline(ctx context.Context, tx *sql.Tx, friendID int, recipientID int) (*entity.Friend, error)
+	Cancel(ctx context.Context, tx *sql.Tx, friendID int, requesterID int) (*entity.Friend, error)
 	GetFriendRecommendation(ctx context.Context, db *sql.DB, userID int) (*[]entity.FriendRecommendation, error)
+	GetFriendIDs(ctx context.Context, db *sql.DB, userID int) ([]int, error)
+	GetMutualFriends(ctx context.Context, db *sql.DB, viewerID int, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.User, error)
+	CountMutualFriends(ctx context.Context, db *sql.DB, viewerID int, userIDs []int) (map[int]int, error)
+	GetFriendSuggestions(ctx context.Context, db *sql.DB, userID int, limit int) ([]*entity.FriendSuggestion, error)
+	BlockBetween(ctx context.Context, tx *sql.Tx, userID int, otherUserID int) error
 }
 
 type FriendRepositoryImpl struct {
@@ -25,16 +36,42 @@ func NewFriendRepository() FriendRepository {
 	return &FriendRepositoryImpl{}
 }
 
+const friendColumns = `friendid, userid, frienduserid, status, createdat, respondedat`
+
+func scanFriend(scanner interface{ Scan(dest ...any) error }) (*entity.Friend, error) {
+	var friend entity.Friend
+	var respondedAt sql.NullTime
+	if err := scanner.Scan(&friend.FriendID, &friend.UserID, &friend.FriendUserID, &friend.Status, &friend.CreatedAt, &respondedAt); err != nil {
+		return nil, err
+	}
+	if respondedAt.Valid {
+		friend.RespondedAt = &respondedAt.Time
+	}
+	return &friend, nil
+}
+
+// AddFriend membuat permintaan pertemanan baru. Satu pasangan user cuma punya satu baris (unique index idx_friends_pair),
+// jadi baris lama yang sudah declined, cancelled, atau blocked dipakai ulang sebagai permintaan baru dari pengirim ini.
+// Return nil kalau pasangan itu masih pending atau sudah berteman.
 func (r *FriendRepositoryImpl) AddFriend(ctx context.Context, tx *sql.Tx, friend *entity.Friend) (*entity.Friend, error) {
 	// step 1: define query-nya
-	query := `INSERT INTO friends (userid, frienduserid, friendstatus, createdat) VALUES ($1, $2, $3, $4) RETURNING friendid, userid, frienduserid, friendstatus, createdat`
+	query := `
+		INSERT INTO friends (userid, frienduserid, status, createdat) VALUES ($1, $2, 'pending', $3)
+		ON CONFLICT ((LEAST(userid, frienduserid)), (GREATEST(userid, frienduserid)))
+		DO UPDATE SET userid = EXCLUDED.userid, frienduserid = EXCLUDED.frienduserid, status = 'pending',
+			createdat = EXCLUDED.createdat, respondedat = NULL
+		WHERE friends.status IN ('declined', 'cancelled', 'blocked')
+		RETURNING ` + friendColumns
 
 	// step 2: jalankan query-nya
-	row := tx.QueryRowContext(ctx, query, friend.UserID, friend.FriendUserID, friend.FriendStatus, friend.CreatedAt)
+	row := tx.QueryRowContext(ctx, query, friend.UserID, friend.FriendUserID, friend.CreatedAt)
 
 	// step 3: ambil hasilnya
-	var newFriend entity.Friend
-	if err := row.Scan(&newFriend.FriendID, &newFriend.UserID, &newFriend.FriendUserID, &newFriend.FriendStatus, &newFriend.CreatedAt); err != nil {
+	newFriend, err := scanFriend(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // pasangan ini masih pending atau sudah berteman
+		}
 		return nil, err
 	}
 
@@ -50,24 +87,23 @@ func (r *FriendRepositoryImpl) AddFriend(ctx context.Context, tx *sql.Tx, friend
 	newFriend.User = &user // set user ke friend
 
 	// step 4: return hasilnya
-	return &newFriend, nil
+	return newFriend, nil
 }
 
 func (r *FriendRepositoryImpl) AcceptRequest(ctx context.Context, tx *sql.Tx, friend *entity.Friend) (*entity.Friend, error) {
 	// step 1: define query-nya
 	query := `
 		UPDATE friends
-		SET friendstatus = TRUE
-		WHERE userid = $2 AND frienduserid = $1 AND friendstatus = FALSE
-		RETURNING friendid, userid, frienduserid, friendstatus, createdat
-	`
+		SET status = 'accepted', respondedat = NOW()
+		WHERE userid = $2 AND frienduserid = $1 AND status = 'pending'
+		RETURNING ` + friendColumns
 
 	// step 2: jalankan query-nya
 	row := tx.QueryRowContext(ctx, query, friend.UserID, friend.FriendUserID)
 
 	// step 3: ambil hasilnya
-	var updatedFriend entity.Friend
-	if err := row.Scan(&updatedFriend.FriendID, &updatedFriend.UserID, &updatedFriend.FriendUserID, &updatedFriend.FriendStatus, &updatedFriend.CreatedAt); err != nil {
+	updatedFriend, err := scanFriend(row)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // tidak ada data yang diupdate
 		}
@@ -75,15 +111,16 @@ func (r *FriendRepositoryImpl) AcceptRequest(ctx context.Context, tx *sql.Tx, fr
 	}
 
 	// step 4: return hasilnya
-	return &updatedFriend, nil
+	return updatedFriend, nil
 }
 
-func (r *FriendRepositoryImpl) GetFriends(ctx context.Context, db *sql.DB, userID int) (*[]entity.Friend, error) {
+// GetFriends dan GetFriendRequests pakai keyset pagination (terbaru duluan), afterID = 0 berarti halaman pertama
+func (r *FriendRepositoryImpl) GetFriends(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) (*[]entity.Friend, error) {
 	// step 1: define query-nya
 	query := `
 	SELECT 
-		f.friendid, f.userid, f.frienduserid, f.friendstatus, f.createdat,
-		u.userid, u.username, u.fullname, u.email, u.profileurl, u.createdat
+		f.friendid, f.userid, f.frienduserid, f.status, f.createdat,
+		u.userid, u.username, u.fullname, u.email, u.avatarkey, u.createdat
 	FROM friends f
 	JOIN users u 
 		ON (
@@ -91,11 +128,14 @@ func (r *FriendRepositoryImpl) GetFriends(ctx context.Context, db *sql.DB, userI
 			OR
 			(f.frienduserid = $1 AND u.userid = f.userid)
 		)
-	WHERE (f.userid = $1 OR f.frienduserid = $1) AND f.friendstatus = true
+	WHERE (f.userid = $1 OR f.frienduserid = $1) AND f.status = 'accepted'
+		AND ($3 = 0 OR (f.createdat, f.friendid) < ($2, $3))
+	ORDER BY f.createdat DESC, f.friendid DESC
+	LIMIT $4
 	`
 
 	// step 2: jalankan query-nya
-	rows, err := db.QueryContext(ctx, query, userID)
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -111,13 +151,13 @@ func (r *FriendRepositoryImpl) GetFriends(ctx context.Context, db *sql.DB, userI
 		if err := rows.Scan(&friend.FriendID, 
 							&friend.UserID, 
 							&friend.FriendUserID, 
-							&friend.FriendStatus, 
+							&friend.Status, 
 							&friend.CreatedAt, 
 							&user.ID, 
 							&user.Username, 
 							&user.Fullname, 
 							&user.Email, 
-							&user.ProfileUrl,
+							&user.AvatarKey,
 							&user.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -153,28 +193,28 @@ func (r *FriendRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, friendID
 	return "Friend deleted successfully", nil
 }
 
-func (r *FriendRepositoryImpl) IsFriendExist(ctx context.Context, db *sql.DB, userID int, friendUserID int) (bool, error) {
-	// step 1: define query untuk cek apakah dia udah berteman atau belum
-	query := `SELECT 1 FROM friends WHERE ((userid = $1 AND frienduserid = $2) OR (userid = $2 AND frienduserid = $1)) LIMIT 1`
+// FindBetween mengambil hubungan dua user apa pun arah dan status-nya, nil kalau mereka belum pernah terhubung
+func (r *FriendRepositoryImpl) FindBetween(ctx context.Context, db *sql.DB, userID int, otherUserID int) (*entity.Friend, error) {
+	// step 1: define query-nya (satu pasangan cuma punya satu baris)
+	query := `SELECT ` + friendColumns + ` FROM friends WHERE (userid = $1 AND frienduserid = $2) OR (userid = $2 AND frienduserid = $1)`
 
 	// step 2: jalankan query-nya
-	row := db.QueryRowContext(ctx, query, userID, friendUserID)
-
-	var temp int // buat ambil hasil dari row (1 berarti udah berteman, 0 berarti belum)
-	if err := row.Scan(&temp); err == sql.ErrNoRows {
-		return false, nil // gaada data yang ditemukan, berarti belum berteman
-	} else if err != nil {
-		return false, err // ada error lain
+	friend, err := scanFriend(db.QueryRowContext(ctx, query, userID, otherUserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // belum pernah berteman atau saling request
+		}
+		return nil, err
 	}
 
-	// step 3: kalau ada hasilnya, berarti udah berteman
-	return true, nil
+	// step 3: return hasilnya
+	return friend, nil
 }
 
 func (r *FriendRepositoryImpl) IsFriendAlreadyAccepted(ctx context.Context, db *sql.DB, userID int, friendUserID int) (bool, error) {
 	query := `SELECT 1 FROM friends
 	WHERE ((userid = $1 AND frienduserid = $2) OR (userid = $2 AND frienduserid = $1))
-	AND friendstatus = true
+	AND status = 'accepted'
 	LIMIT 1`
 	
 	row := db.QueryRowContext(ctx, query, userID, friendUserID)
@@ -187,7 +227,7 @@ func (r *FriendRepositoryImpl) IsFriendAlreadyAccepted(ctx context.Context, db *
 	return true, nil
 }
 
-func (r *FriendRepositoryImpl) GetFriendRequests(ctx context.Context, db *sql.DB, userID int) (*[]entity.Friend, error) {
+func (r *FriendRepositoryImpl) GetFriendRequests(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) (*[]entity.Friend, error) {
 	query := `
 	SELECT
 		f.friendid,
@@ -202,11 +242,13 @@ func (r *FriendRepositoryImpl) GetFriendRequests(ctx context.Context, db *sql.DB
 		users u ON f.userid = u.userid
 	WHERE
 		f.frienduserid = $1
-		AND f.friendstatus = false
-	ORDER BY f.createdat DESC
+		AND f.status = 'pending'
+		AND ($3 = 0 OR (f.createdat, f.friendid) < ($2, $3))
+	ORDER BY f.createdat DESC, f.friendid DESC
+	LIMIT $4
 	`
 
-	rows, err := db.QueryContext(ctx, query, userID)
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +257,7 @@ func (r *FriendRepositoryImpl) GetFriendRequests(ctx context.Context, db *sql.DB
 
 	var friendRequests []entity.Friend
 	for rows.Next() {
-		var friend entity.Friend
+		friend := entity.Friend{Status: entity.FriendPending}
 		var user entity.User
 		if err := rows.Scan(&friend.FriendID, &friend.UserID, &friend.FriendUserID, &user.Username, &user.Fullname, &friend.CreatedAt); err != nil {
 			return nil, err
@@ -231,8 +273,73 @@ func (r *FriendRepositoryImpl) GetFriendRequests(ctx context.Context, db *sql.DB
 	return &friendRequests, nil
 }
 
+// GetOutgoingRequests permintaan pertemanan yang dikirim user dan belum dijawab, User-nya si penerima
+func (r *FriendRepositoryImpl) GetOutgoingRequests(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) (*[]entity.Friend, error) {
+	query := `
+	SELECT f.friendid, f.userid, f.frienduserid, u.userid, u.username, u.fullname, f.createdat
+	FROM friends f
+	JOIN users u ON f.frienduserid = u.userid
+	WHERE
+		f.userid = $1
+		AND f.status = 'pending'
+		AND ($3 = 0 OR (f.createdat, f.friendid) < ($2, $3))
+	ORDER BY f.createdat DESC, f.friendid DESC
+	LIMIT $4
+	`
+
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outgoingRequests []entity.Friend
+	for rows.Next() {
+		friend := entity.Friend{Status: entity.FriendPending}
+		var user entity.User
+		if err := rows.Scan(&friend.FriendID, &friend.UserID, &friend.FriendUserID, &user.ID, &user.Username, &user.Fullname, &friend.CreatedAt); err != nil {
+			return nil, err
+		}
+		friend.User = &user
+		outgoingRequests = append(outgoingRequests, friend)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &outgoingRequests, nil
+}
+
+// Decline dipanggil penerima, Cancel dipanggil pengirim. Keduanya cuma berlaku untuk permintaan yang masih pending,
+// return nil kalau permintaannya tidak ada, bukan milik user itu, atau sudah dijawab.
+func (r *FriendRepositoryImpl) Decline(ctx context.Context, tx *sql.Tx, friendID int, recipientID int) (*entity.Friend, error) {
+	query := `
+		UPDATE friends SET status = 'declined', respondedat = NOW()
+		WHERE friendid = $1 AND frienduserid = $2 AND status = 'pending'
+		RETURNING ` + friendColumns
+	return r.respond(ctx, tx, query, friendID, recipientID)
+}
+
+func (r *FriendRepositoryImpl) Cancel(ctx context.Context, tx *sql.Tx, friendID int, requesterID int) (*entity.Friend, error) {
+	query := `
+		UPDATE friends SET status = 'cancelled', respondedat = NOW()
+		WHERE friendid = $1 AND userid = $2 AND status = 'pending'
+		RETURNING ` + friendColumns
+	return r.respond(ctx, tx, query, friendID, requesterID)
+}
+
+func (r *FriendRepositoryImpl) respond(ctx context.Context, tx *sql.Tx, query string, friendID int, userID int) (*entity.Friend, error) {
+	friend, err := scanFriend(tx.QueryRowContext(ctx, query, friendID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return friend, nil
+}
+
 func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *sql.DB, userID int) (*[]entity.FriendRecommendation, error) {
-	// step 1: hitung overall mood dari user
+	// step 1: hitung overall mood dari user (postingan + komentar yang sudah selesai diklasifikasi)
 	query := `
 	SELECT mood FROM (
 		SELECT mood,
@@ -241,8 +348,11 @@ func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *
 				ROW_NUMBER() OVER (
 				ORDER BY COUNT(*) DESC, MAX(createdat) DESC
 				) AS rn
-		FROM posts
-		WHERE userid = $1
+		FROM (
+			SELECT mood, createdat FROM posts WHERE userid = $1
+			UNION ALL
+			SELECT mood, createdat FROM comments WHERE userid = $1 AND mood IS NOT NULL AND isdeleted = FALSE
+		) AS activity
 		GROUP BY mood
 	) AS ranked
 	WHERE rn = 1;
@@ -253,11 +363,9 @@ func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *
 		return nil, fmt.Errorf("no mood found for user with id %d", userID)
 	}
 
+	// user yang belum punya postingan/komentar belum punya mood, rekomendasinya cuma berdasarkan profil
 	var overallMood string
-	if err := row.Scan(&overallMood); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no mood found for user with id %d", userID)
-		}
+	if err := row.Scan(&overallMood); err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get overall mood for user with id %d: %v", userID, err)
 	}
 
@@ -275,16 +383,27 @@ func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *
 
 	// step 3: buat query buat ngambil rekomendasi teman berdasarkan overall mood user
 	var moodCondition string
-	if isAtRisk {
+	switch {
+	case overallMood == "":
+		// Belum ada mood, semua kandidat dipertimbangkan
+	case isAtRisk:
 		// Rekomendasikan user dengan mood positif (mood tidak negatif)
-		moodCondition = "NOT IN ('Depression', 'Anxiety', 'Suicidal', 'Personality disorder', 'Bipolar', 'Stress')"
-	} else {
+		moodCondition = "AND mood NOT IN ('Depression', 'Anxiety', 'Suicidal', 'Personality disorder', 'Bipolar', 'Stress')"
+	default:
 		// Rekomendasikan user dengan mood negatif
-		moodCondition = "IN ('Depression', 'Anxiety', 'Suicidal', 'Personality disorder', 'Bipolar', 'Stress')"
+		moodCondition = "AND mood IN ('Depression', 'Anxiety', 'Suicidal', 'Personality disorder', 'Bipolar', 'Stress')"
 	}
 
+	// step 3.1: urutkan kandidat berdasarkan kecocokan profil. Profil kandidat cuma dipakai kalau field-nya public
+	// (kandidat pasti bukan teman), sedangkan profil user sendiri dipakai semua karena hasilnya cuma untuk dia.
+	// Bobot: kandidat menawarkan dukungan yang dicari user > kandidat mencari dukungan yang bisa user tawarkan >
+	// minat yang sama > bahasa yang sama.
 	recommendationQuery := fmt.Sprintf(`
-		SELECT u.userid, u.username, u.fullname, u.email, ranked.mood
+		WITH me AS (
+			SELECT interests, supportseeking, supportoffering, language FROM user_profiles WHERE userid = $1
+		)
+		SELECT u.userid, u.username, u.fullname, u.email, ranked.mood,
+			matched.sharedinterests, matched.supportoffered, matched.supportsought
 		FROM (
 			SELECT userid, mood,
 				ROW_NUMBER() OVER (
@@ -295,14 +414,33 @@ func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *
 			GROUP BY userid, mood
 		) AS ranked
 		JOIN users u ON u.userid = ranked.userid
+		LEFT JOIN user_profiles p ON p.userid = u.userid
+		LEFT JOIN me ON TRUE
+		CROSS JOIN LATERAL (
+			SELECT
+				ARRAY(SELECT unnest(CASE WHEN p.interestsvisibility = 'public' THEN p.interests END)
+					INTERSECT SELECT unnest(me.interests)) AS sharedinterests,
+				ARRAY(SELECT unnest(CASE WHEN p.supportofferingvisibility = 'public' THEN p.supportoffering END)
+					INTERSECT SELECT unnest(me.supportseeking)) AS supportoffered,
+				ARRAY(SELECT unnest(CASE WHEN p.supportseekingvisibility = 'public' THEN p.supportseeking END)
+					INTERSECT SELECT unnest(me.supportoffering)) AS supportsought,
+				COALESCE(p.languagevisibility = 'public' AND p.language <> '' AND p.language = me.language, FALSE) AS samelanguage
+		) AS matched
 		WHERE rn = 1
-		AND mood %s
+		%s
 		AND u.userid != $1
 		AND u.userid NOT IN (
-				SELECT frienduserid FROM friends WHERE userid = $1 AND friendstatus = TRUE
+				SELECT frienduserid FROM friends WHERE userid = $1 AND status = 'accepted'
 				UNION
-				SELECT userid FROM friends WHERE frienduserid = $1 AND friendstatus = TRUE
+				SELECT userid FROM friends WHERE frienduserid = $1 AND status = 'accepted'
 		)
+		AND u.userid NOT IN (`+hiddenUserIDsSubquery+`)
+		ORDER BY
+			cardinality(matched.supportoffered) * 3
+			+ cardinality(matched.supportsought) * 2
+			+ cardinality(matched.sharedinterests)
+			+ CASE WHEN matched.samelanguage THEN 1 ELSE 0 END DESC,
+			u.userid
 		LIMIT 10;
 	`, moodCondition)
 
@@ -317,14 +455,13 @@ func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *
 	var recommendations []entity.FriendRecommendation
 	for rows.Next() {
 		var user entity.User
-		var mood string
-		if err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.Email, &mood); err != nil {
+		var recommendation entity.FriendRecommendation
+		if err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.Email, &recommendation.OverallMood,
+			pq.Array(&recommendation.SharedInterests), pq.Array(&recommendation.SupportOffered), pq.Array(&recommendation.SupportSought)); err != nil {
 			return nil, fmt.Errorf("failed to scan recommendation row: %v", err)
 		}
-		recommendations = append(recommendations, entity.FriendRecommendation{
-			User:        user,
-			OverallMood: mood,
-		})
+		recommendation.User = user
+		recommendations = append(recommendations, recommendation)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -333,4 +470,175 @@ func (r *FriendRepositoryImpl) GetFriendRecommendation(ctx context.Context, db *
 
 	// step 5: return hasilnya
 	return &recommendations, nil
-}
\ No newline at end of file
+}
+
+// GetFriendIDs cuma ngambil userid teman yang sudah accepted, tanpa join ke users (dipakai buat fan-out feed)
+func (r *FriendRepositoryImpl) GetFriendIDs(ctx context.Context, db *sql.DB, userID int) ([]int, error) {
+	query := `
+		SELECT CASE WHEN userid = $1 THEN frienduserid ELSE userid END
+		FROM friends
+		WHERE (userid = $1 OR frienduserid = $1) AND status = 'accepted'`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var friendIDs []int
+	for rows.Next() {
+		var friendID int
+		if err := rows.Scan(&friendID); err != nil {
+			return nil, err
+		}
+		friendIDs = append(friendIDs, friendID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return friendIDs, nil
+}
+
+// Teman viewer $1 yang boleh ditampilkan sebagai mutual friend / penghubung "people you may know": akunnya belum dihapus,
+// tidak diblokir atau di-mute viewer, dan daftar temannya tidak private (viewer pasti temannya, jadi "friends" boleh).
+// Graf-nya cuma sampai derajat dua, jadi cukup join ke tabel friends sekali lagi tanpa query rekursif.
+const mutualBridgesCTE = `
+	viewer_friends AS (` + friendIDsSubquery + `),
+	bridges AS (
+		SELECT vf.friend_userid AS userid
+		FROM viewer_friends vf
+		JOIN users u ON u.userid = vf.friend_userid AND u.deletedat IS NULL
+		LEFT JOIN user_profiles p ON p.userid = vf.friend_userid
+		WHERE COALESCE(p.friendsvisibility, 'public') <> 'private'
+			AND vf.friend_userid NOT IN (` + hiddenUserIDsSubquery + `)
+	)`
+
+// GetMutualFriends teman yang dimiliki viewer dan userID sekaligus, terbaru duluan (berdasarkan waktu akunnya dibuat).
+// Visibility daftar teman userID sendiri dicek di service.
+func (r *FriendRepositoryImpl) GetMutualFriends(ctx context.Context, db *sql.DB, viewerID int, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.User, error) {
+	query := `
+		WITH ` + mutualBridgesCTE + `
+		SELECT u.userid, u.username, u.fullname, u.createdat
+		FROM bridges b
+		JOIN friends f ON f.status = 'accepted'
+			AND ((f.userid = b.userid AND f.frienduserid = $2) OR (f.userid = $2 AND f.frienduserid = b.userid))
+		JOIN users u ON u.userid = b.userid
+		WHERE ($4 = 0 OR (u.createdat, u.userid) < ($3, $4))
+		ORDER BY u.createdat DESC, u.userid DESC
+		LIMIT $5`
+
+	rows, err := db.QueryContext(ctx, query, viewerID, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CountMutualFriends menghitung mutual friend viewer dengan banyak user sekaligus (kartu rekomendasi, profil).
+// User yang daftar temannya tidak boleh dilihat viewer tidak ada di map, sama saja dengan 0.
+func (r *FriendRepositoryImpl) CountMutualFriends(ctx context.Context, db *sql.DB, viewerID int, userIDs []int) (map[int]int, error) {
+	counts := make(map[int]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		WITH ` + mutualBridgesCTE + `
+		SELECT t.userid, COUNT(*)
+		FROM unnest($2::int[]) AS t(userid)
+		LEFT JOIN user_profiles tp ON tp.userid = t.userid
+		JOIN friends f ON f.status = 'accepted' AND (f.userid = t.userid OR f.frienduserid = t.userid)
+		JOIN bridges b ON b.userid = CASE WHEN f.userid = t.userid THEN f.frienduserid ELSE f.userid END
+		WHERE t.userid <> $1
+			AND (
+				COALESCE(tp.friendsvisibility, 'public') = 'public'
+				OR (tp.friendsvisibility = 'friends' AND t.userid IN (SELECT friend_userid FROM viewer_friends))
+			)
+		GROUP BY t.userid`
+
+	rows, err := db.QueryContext(ctx, query, viewerID, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID, count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetFriendSuggestions "people you may know": teman dari teman user, diurutkan dari yang mutual friend-nya paling banyak.
+// Yang dilewati: user yang sudah berteman, masih pending atau pernah menolak/ditolak, diblokir atau di-mute,
+// akunnya dihapus, dan yang daftar temannya tidak public (kandidat pasti bukan teman user).
+func (r *FriendRepositoryImpl) GetFriendSuggestions(ctx context.Context, db *sql.DB, userID int, limit int) ([]*entity.FriendSuggestion, error) {
+	query := `
+		WITH ` + mutualBridgesCTE + `
+		SELECT u.userid, u.username, u.fullname, COUNT(*) AS mutualfriends
+		FROM bridges b
+		JOIN friends f ON f.status = 'accepted' AND (f.userid = b.userid OR f.frienduserid = b.userid)
+		JOIN users u ON u.userid = CASE WHEN f.userid = b.userid THEN f.frienduserid ELSE f.userid END
+		LEFT JOIN user_profiles p ON p.userid = u.userid
+		WHERE u.userid <> $1
+			AND u.deletedat IS NULL
+			AND COALESCE(p.friendsvisibility, 'public') = 'public'
+			AND u.userid NOT IN (SELECT friend_userid FROM viewer_friends)
+			AND u.userid NOT IN (` + hiddenUserIDsSubquery + `)
+			AND NOT EXISTS (
+				SELECT 1 FROM friends pf
+				WHERE pf.status IN ('pending', 'declined')
+					AND ((pf.userid = $1 AND pf.frienduserid = u.userid) OR (pf.userid = u.userid AND pf.frienduserid = $1))
+			)
+		GROUP BY u.userid, u.username, u.fullname
+		ORDER BY mutualfriends DESC, u.userid
+		LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []*entity.FriendSuggestion
+	for rows.Next() {
+		var suggestion entity.FriendSuggestion
+		if err := rows.Scan(&suggestion.User.ID, &suggestion.User.Username, &suggestion.User.Fullname, &suggestion.MutualFriends); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// BlockBetween memutus hubungan pertemanan dua user, baik yang masih pending maupun yang sudah accepted (dipakai waktu blokir).
+// Barisnya tidak dihapus, setelah blokirnya dicabut AddFriend bisa memakainya lagi sebagai permintaan baru.
+func (r *FriendRepositoryImpl) BlockBetween(ctx context.Context, tx *sql.Tx, userID int, otherUserID int) error {
+	query := `
+		UPDATE friends SET status = 'blocked', respondedat = NOW()
+		WHERE (userid = $1 AND frienduserid = $2) OR (userid = $2 AND frienduserid = $1)`
+	_, err := tx.ExecContext(ctx, query, userID, otherUserID)
+	return err
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"strconv"
+	"time"
+)
+
+type JournalRepository interface {
+	FindKey(ctx context.Context, db *sql.DB, userID int) (*entity.JournalKey, error)
+	CreateKey(ctx context.Context, db *sql.DB, key *entity.JournalKey) (*entity.JournalKey, error)
+	Create(ctx context.Context, tx *sql.Tx, entry *entity.JournalEntry) (*entity.JournalEntry, error)
+	FindByID(ctx context.Context, db *sql.DB, entryID int) (*entity.JournalEntry, error)
+	FindByUserID(ctx context.Context, db *sql.DB, userID int, afterEntryDate time.Time, afterID, limit int) ([]*entity.JournalEntry, error)
+	Update(ctx context.Context, tx *sql.Tx, entryID int, entry *entity.JournalEntry) (*entity.JournalEntry, error)
+	Delete(ctx context.Context, tx *sql.Tx, entryID int) (string, error)
+	GetEntryDates(ctx context.Context, db *sql.DB, userID int) ([]time.Time, error)
+}
+
+type JournalRepositoryImpl struct {
+}
+
+func NewJournalRepository() JournalRepository {
+	return &JournalRepositoryImpl{}
+}
+
+const journalColumns = `entryid, userid, content, emotiontags, selfmood, predictedmood, entrydate, createdat, updatedat`
+
+func scanJournalEntry(scanner interface{ Scan(dest ...any) error }) (*entity.JournalEntry, error) {
+	var entry entity.JournalEntry
+	var selfMood sql.NullInt32
+	var predictedMood sql.NullString
+	err := scanner.Scan(&entry.EntryID, &entry.UserID, &entry.Content, &entry.EmotionTags, &selfMood, &predictedMood, &entry.EntryDate, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if selfMood.Valid {
+		mood := int(selfMood.Int32)
+		entry.SelfMood = &mood
+	}
+	if predictedMood.Valid {
+		entry.PredictedMood = &predictedMood.String
+	}
+	return &entry, nil
+}
+
+func (r *JournalRepositoryImpl) FindKey(ctx context.Context, db *sql.DB, userID int) (*entity.JournalKey, error) {
+	query := `SELECT userid, wrappedkey, createdat FROM journal_keys WHERE userid = $1`
+
+	var key entity.JournalKey
+	err := db.QueryRowContext(ctx, query, userID).Scan(&key.UserID, &key.WrappedKey, &key.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // user belum pernah nulis jurnal
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *JournalRepositoryImpl) CreateKey(ctx context.Context, db *sql.DB, key *entity.JournalKey) (*entity.JournalKey, error) {
+	// kalau ternyata ada request lain yang sudah bikin key duluan, kembalikan key yang sudah ada (jangan ditimpa)
+	query := `
+		INSERT INTO journal_keys (userid, wrappedkey) VALUES ($1, $2)
+		ON CONFLICT (userid) DO UPDATE SET userid = EXCLUDED.userid
+		RETURNING userid, wrappedkey, createdat
+	`
+
+	var createdKey entity.JournalKey
+	err := db.QueryRowContext(ctx, query, key.UserID, key.WrappedKey).Scan(&createdKey.UserID, &createdKey.WrappedKey, &createdKey.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &createdKey, nil
+}
+
+func (r *JournalRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, entry *entity.JournalEntry) (*entity.JournalEntry, error) {
+	query := `INSERT INTO journal_entries (userid, content, emotiontags, selfmood, predictedmood, entrydate) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ` + journalColumns
+
+	row := tx.QueryRowContext(ctx, query, entry.UserID, entry.Content, entry.EmotionTags, entry.SelfMood, entry.PredictedMood, entry.EntryDate)
+	return scanJournalEntry(row)
+}
+
+func (r *JournalRepositoryImpl) FindByID(ctx context.Context, db *sql.DB, entryID int) (*entity.JournalEntry, error) {
+	query := `SELECT ` + journalColumns + ` FROM journal_entries WHERE entryid = $1`
+
+	entry, err := scanJournalEntry(db.QueryRowContext(ctx, query, entryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Entry not found
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// FindByUserID pakai keyset pagination di (entrydate, entryid), afterID = 0 berarti halaman pertama
+func (r *JournalRepositoryImpl) FindByUserID(ctx context.Context, db *sql.DB, userID int, afterEntryDate time.Time, afterID, limit int) ([]*entity.JournalEntry, error) {
+	query := `
+		SELECT ` + journalColumns + `
+		FROM journal_entries
+		WHERE userid = $1
+			AND ($3 = 0 OR (entrydate, entryid) < ($2::date, $3))
+		ORDER BY entrydate DESC, entryid DESC
+		LIMIT $4`
+
+	rows, err := db.QueryContext(ctx, query, userID, afterEntryDate, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.JournalEntry
+	for rows.Next() {
+		entry, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *JournalRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, entryID int, entry *entity.JournalEntry) (*entity.JournalEntry, error) {
+	query := `
+		UPDATE journal_entries
+		SET content = $1, emotiontags = $2, selfmood = $3, predictedmood = $4, updatedat = NOW()
+		WHERE entryid = $5
+		RETURNING ` + journalColumns
+
+	row := tx.QueryRowContext(ctx, query, entry.Content, entry.EmotionTags, entry.SelfMood, entry.PredictedMood, entryID)
+	updatedEntry, err := scanJournalEntry(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Entry not found
+		}
+		return nil, err
+	}
+	return updatedEntry, nil
+}
+
+func (r *JournalRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, entryID int) (string, error) {
+	query := `DELETE FROM journal_entries WHERE entryid = $1 RETURNING entryid`
+
+	var deletedEntryID int
+	if err := tx.QueryRowContext(ctx, query, entryID).Scan(&deletedEntryID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil // Entry not found
+		}
+		return "", err
+	}
+	return "Journal entry with ID " + strconv.Itoa(deletedEntryID) + " deleted successfully", nil
+}
+
+func (r *JournalRepositoryImpl) GetEntryDates(ctx context.Context, db *sql.DB, userID int) ([]time.Time, error) {
+	query := `SELECT DISTINCT entrydate FROM journal_entries WHERE userid = $1 ORDER BY entrydate DESC`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
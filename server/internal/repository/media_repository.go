@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MediaRepository interface {
+	Create(ctx context.Context, db *sql.DB, media *entity.PostMedia) (*entity.PostMedia, error)
+	AttachToPost(ctx context.Context, tx *sql.Tx, postID, userID int, mediaIDs []int) error
+	GetByPostIDs(ctx context.Context, db *sql.DB, postIDs []int) (map[int][]*entity.PostMedia, error)
+	FindPending(ctx context.Context, db *sql.DB, before time.Time, limit int) ([]*entity.PostMedia, error)
+	DeletePending(ctx context.Context, db *sql.DB, mediaID int) (bool, error)
+}
+
+type MediaRepositoryImpl struct {
+}
+
+func NewMediaRepository() MediaRepository {
+	return &MediaRepositoryImpl{}
+}
+
+const mediaColumns = `mediaid, userid, postid, position, storagekey, thumbnailkey, contenttype, width, height, blurhash, sizebytes, createdat`
+
+func scanMedia(scanner interface{ Scan(dest ...any) error }) (*entity.PostMedia, error) {
+	var media entity.PostMedia
+	var postID sql.NullInt64
+	err := scanner.Scan(&media.MediaID, &media.UserID, &postID, &media.Position, &media.StorageKey, &media.ThumbnailKey,
+		&media.ContentType, &media.Width, &media.Height, &media.Blurhash, &media.SizeBytes, &media.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if postID.Valid {
+		id := int(postID.Int64)
+		media.PostID = &id
+	}
+	return &media, nil
+}
+
+func (r *MediaRepositoryImpl) Create(ctx context.Context, db *sql.DB, media *entity.PostMedia) (*entity.PostMedia, error) {
+	query := `
+		INSERT INTO post_media (userid, storagekey, thumbnailkey, contenttype, width, height, blurhash, sizebytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + mediaColumns
+
+	row := db.QueryRowContext(ctx, query, media.UserID, media.StorageKey, media.ThumbnailKey, media.ContentType,
+		media.Width, media.Height, media.Blurhash, media.SizeBytes)
+	return scanMedia(row)
+}
+
+// AttachToPost memasang gambar yang sudah di-upload ke postingan, urutannya mengikuti urutan mediaIDs.
+// Gambar harus milik user yang sama dan belum pernah dipasang ke postingan lain.
+func (r *MediaRepositoryImpl) AttachToPost(ctx context.Context, tx *sql.Tx, postID, userID int, mediaIDs []int) error {
+	query := `UPDATE post_media SET postid = $1, position = $2 WHERE mediaid = $3 AND userid = $4 AND postid IS NULL`
+	for position, mediaID := range mediaIDs {
+		result, err := tx.ExecContext(ctx, query, postID, position, mediaID, userID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("media with ID %d not found or already used", mediaID)
+		}
+	}
+	return nil
+}
+
+func (r *MediaRepositoryImpl) GetByPostIDs(ctx context.Context, db *sql.DB, postIDs []int) (map[int][]*entity.PostMedia, error) {
+	media := make(map[int][]*entity.PostMedia)
+	if len(postIDs) == 0 {
+		return media, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+	query := `SELECT ` + mediaColumns + ` FROM post_media WHERE postid IN (` + strings.Join(placeholders, ", ") + `) ORDER BY postid, position`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media[*item.PostID] = append(media[*item.PostID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// FindPending mengambil gambar yang di-upload sebelum waktu tertentu tapi tidak pernah dipasang ke postingan
+func (r *MediaRepositoryImpl) FindPending(ctx context.Context, db *sql.DB, before time.Time, limit int) ([]*entity.PostMedia, error) {
+	query := `SELECT ` + mediaColumns + ` FROM post_media WHERE postid IS NULL AND createdat < $1 ORDER BY createdat LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*entity.PostMedia
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// DeletePending cuma menghapus kalau gambarnya masih belum dipasang, jadi aman kalau bersamaan ada postingan baru yang memakainya
+func (r *MediaRepositoryImpl) DeletePending(ctx context.Context, db *sql.DB, mediaID int) (bool, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM post_media WHERE mediaid = $1 AND postid IS NULL`, mediaID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+type MFARepository interface {
+	Find(ctx context.Context, db *sql.DB, userID int) (*entity.UserMFA, error)
+	IsEnabled(ctx context.Context, db *sql.DB, userID int) (bool, error)
+	SavePending(ctx context.Context, db *sql.DB, userID int, secret []byte) (bool, error)
+	Enable(ctx context.Context, tx *sql.Tx, userID int, step int64) (bool, error)
+	UseStep(ctx context.Context, db *sql.DB, userID int, step int64) (bool, error)
+	Delete(ctx context.Context, tx *sql.Tx, userID int) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, db *sql.DB, userID int, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, db *sql.DB, userID int) (int, error)
+}
+
+type MFARepositoryImpl struct {
+}
+
+func NewMFARepository() MFARepository {
+	return &MFARepositoryImpl{}
+}
+
+// Find return nil kalau user belum pernah enroll
+func (r *MFARepositoryImpl) Find(ctx context.Context, db *sql.DB, userID int) (*entity.UserMFA, error) {
+	query := `SELECT userid, secret, enabledat, lastusedstep, createdat FROM user_mfa WHERE userid = $1`
+
+	var mfa entity.UserMFA
+	var enabledAt sql.NullTime
+	err := db.QueryRowContext(ctx, query, userID).Scan(&mfa.UserID, &mfa.Secret, &enabledAt, &mfa.LastUsedStep, &mfa.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if enabledAt.Valid {
+		mfa.EnabledAt = &enabledAt.Time
+	}
+	return &mfa, nil
+}
+
+func (r *MFARepositoryImpl) IsEnabled(ctx context.Context, db *sql.DB, userID int) (bool, error) {
+	var enabled bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM user_mfa WHERE userid = $1 AND enabledat IS NOT NULL)`, userID).Scan(&enabled)
+	return enabled, err
+}
+
+// SavePending menyimpan secret baru untuk enrollment. Return false kalau 2FA user sudah aktif,
+// supaya enroll ulang tidak bisa mengganti secret yang sedang dipakai.
+func (r *MFARepositoryImpl) SavePending(ctx context.Context, db *sql.DB, userID int, secret []byte) (bool, error) {
+	query := `
+		INSERT INTO user_mfa (userid, secret) VALUES ($1, $2)
+		ON CONFLICT (userid) DO UPDATE SET secret = EXCLUDED.secret, lastusedstep = 0, createdat = NOW()
+		WHERE user_mfa.enabledat IS NULL`
+	return execChanged(db.ExecContext(ctx, query, userID, secret))
+}
+
+// Enable return false kalau tidak ada enrollment yang menunggu konfirmasi
+func (r *MFARepositoryImpl) Enable(ctx context.Context, tx *sql.Tx, userID int, step int64) (bool, error) {
+	query := `UPDATE user_mfa SET enabledat = NOW(), lastusedstep = $2 WHERE userid = $1 AND enabledat IS NULL`
+	return execChanged(tx.ExecContext(ctx, query, userID, step))
+}
+
+// UseStep mencatat periode kode yang baru dipakai. Return false kalau periode itu (atau yang lebih baru)
+// sudah pernah dipakai, jadi kode yang sama tidak bisa dipakai dua kali walaupun request-nya bersamaan.
+func (r *MFARepositoryImpl) UseStep(ctx context.Context, db *sql.DB, userID int, step int64) (bool, error) {
+	query := `UPDATE user_mfa SET lastusedstep = $2 WHERE userid = $1 AND enabledat IS NOT NULL AND lastusedstep < $2`
+	return execChanged(db.ExecContext(ctx, query, userID, step))
+}
+
+// Delete menghapus secret sekaligus recovery code-nya. Return false kalau user memang belum pernah enroll.
+func (r *MFARepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, userID int) (bool, error) {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE userid = $1`, userID); err != nil {
+		return false, err
+	}
+	return execChanged(tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE userid = $1`, userID))
+}
+
+// ReplaceRecoveryCodes menghapus semua recovery code lama (termasuk yang belum dipakai) lalu menyimpan yang baru
+func (r *MFARepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE userid = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO user_recovery_codes (userid, codehash) SELECT $1, unnest($2::text[])`
+	_, err := tx.ExecContext(ctx, query, userID, pq.Array(codeHashes))
+	return err
+}
+
+// UseRecoveryCode return false kalau kodenya salah atau sudah pernah dipakai
+func (r *MFARepositoryImpl) UseRecoveryCode(ctx context.Context, db *sql.DB, userID int, codeHash string) (bool, error) {
+	query := `UPDATE user_recovery_codes SET usedat = NOW() WHERE userid = $1 AND codehash = $2 AND usedat IS NULL`
+	return execChanged(db.ExecContext(ctx, query, userID, codeHash))
+}
+
+// CountRecoveryCodes menghitung recovery code yang belum dipakai
+func (r *MFARepositoryImpl) CountRecoveryCodes(ctx context.Context, db *sql.DB, userID int) (int, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_recovery_codes WHERE userid = $1 AND usedat IS NULL`, userID).Scan(&count)
+	return count, err
+}
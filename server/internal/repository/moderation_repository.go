@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, tx *sql.Tx, report *entity.Report) (*entity.Report, error)
+	CountOpenReports(ctx context.Context, tx *sql.Tx, targetType string, targetID int) (int, error)
+	FindReportByID(ctx context.Context, db *sql.DB, reportID int) (*entity.Report, error)
+	FindReportForUpdate(ctx context.Context, tx *sql.Tx, reportID int) (*entity.Report, error)
+	FindQueue(ctx context.Context, db *sql.DB, status string, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Report, error)
+	ClaimReport(ctx context.Context, tx *sql.Tx, reportID, moderatorID int) error
+	ResolveReports(ctx context.Context, tx *sql.Tx, targetType string, targetID, moderatorID int, resolution, note string) ([]*entity.Report, error)
+	SetHidden(ctx context.Context, tx *sql.Tx, targetType string, targetID int, hidden bool) (bool, error)
+	LogAction(ctx context.Context, tx *sql.Tx, action *entity.ModerationAction) error
+	FindActions(ctx context.Context, db *sql.DB, afterCreatedAt time.Time, afterID, limit int) ([]*entity.ModerationAction, error)
+}
+
+type ModerationRepositoryImpl struct {
+}
+
+func NewModerationRepository() ModerationRepository {
+	return &ModerationRepositoryImpl{}
+}
+
+const reportColumns = `reportid, reporterid, targettype, targetid, targetownerid, reason, details, status, claimedby, claimedat,
+	resolution, resolutionnote, resolvedby, resolvedat, createdat`
+
+func scanReport(scanner interface{ Scan(dest ...any) error }, extra ...any) (*entity.Report, error) {
+	var report entity.Report
+	var targetOwnerID, claimedBy, resolvedBy sql.NullInt64
+	var claimedAt, resolvedAt sql.NullTime
+	var resolution, resolutionNote sql.NullString
+	dest := []any{&report.ReportID, &report.ReporterID, &report.TargetType, &report.TargetID, &targetOwnerID, &report.Reason, &report.Details,
+		&report.Status, &claimedBy, &claimedAt, &resolution, &resolutionNote, &resolvedBy, &resolvedAt, &report.CreatedAt}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	report.TargetOwnerID = nullIntPtr(targetOwnerID)
+	report.ClaimedBy = nullIntPtr(claimedBy)
+	report.ResolvedBy = nullIntPtr(resolvedBy)
+	if claimedAt.Valid {
+		report.ClaimedAt = &claimedAt.Time
+	}
+	if resolvedAt.Valid {
+		report.ResolvedAt = &resolvedAt.Time
+	}
+	if resolution.Valid {
+		report.Resolution = &resolution.String
+	}
+	if resolutionNote.Valid {
+		report.ResolutionNote = &resolutionNote.String
+	}
+	return &report, nil
+}
+
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
+
+// CreateReport return nil kalau user ini sudah pernah melaporkan target yang sama
+func (r *ModerationRepositoryImpl) CreateReport(ctx context.Context, tx *sql.Tx, report *entity.Report) (*entity.Report, error) {
+	query := `
+		INSERT INTO reports (reporterid, targettype, targetid, targetownerid, reason, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (reporterid, targettype, targetid) DO NOTHING
+		RETURNING ` + reportColumns
+
+	created, err := scanReport(tx.QueryRowContext(ctx, query, report.ReporterID, report.TargetType, report.TargetID, report.TargetOwnerID, report.Reason, report.Details))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// CountOpenReports menghitung laporan yang belum selesai untuk satu target (dipakai buat batas sembunyi otomatis)
+func (r *ModerationRepositoryImpl) CountOpenReports(ctx context.Context, tx *sql.Tx, targetType string, targetID int) (int, error) {
+	query := `SELECT COUNT(*) FROM reports WHERE targettype = $1 AND targetid = $2 AND status <> 'resolved'`
+	var count int
+	err := tx.QueryRowContext(ctx, query, targetType, targetID).Scan(&count)
+	return count, err
+}
+
+func (r *ModerationRepositoryImpl) FindReportByID(ctx context.Context, db *sql.DB, reportID int) (*entity.Report, error) {
+	report, err := scanReport(db.QueryRowContext(ctx, `SELECT `+reportColumns+` FROM reports WHERE reportid = $1`, reportID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return report, err
+}
+
+func (r *ModerationRepositoryImpl) FindReportForUpdate(ctx context.Context, tx *sql.Tx, reportID int) (*entity.Report, error) {
+	report, err := scanReport(tx.QueryRowContext(ctx, `SELECT `+reportColumns+` FROM reports WHERE reportid = $1 FOR UPDATE`, reportID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return report, err
+}
+
+// FindQueue mengurutkan dari laporan paling lama, jadi yang sudah lama menunggu ditangani duluan
+func (r *ModerationRepositoryImpl) FindQueue(ctx context.Context, db *sql.DB, status string, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Report, error) {
+	query := `
+		SELECT ` + reportColumns + `,
+			(SELECT COUNT(*) FROM reports o
+				WHERE o.targettype = reports.targettype AND o.targetid = reports.targetid AND o.status <> 'resolved') AS openreports
+		FROM reports
+		WHERE status = $1
+			AND ($3 = 0 OR (createdat, reportid) > ($2, $3))
+		ORDER BY createdat ASC, reportid ASC
+		LIMIT $4`
+
+	rows, err := db.QueryContext(ctx, query, status, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*entity.Report{}
+	for rows.Next() {
+		var openReports int
+		report, err := scanReport(rows, &openReports)
+		if err != nil {
+			return nil, err
+		}
+		report.OpenReports = openReports
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+func (r *ModerationRepositoryImpl) ClaimReport(ctx context.Context, tx *sql.Tx, reportID, moderatorID int) error {
+	query := `UPDATE reports SET status = 'claimed', claimedby = $2, claimedat = NOW() WHERE reportid = $1`
+	_, err := tx.ExecContext(ctx, query, reportID, moderatorID)
+	return err
+}
+
+// ResolveReports menyelesaikan semua laporan yang belum selesai untuk target yang sama sekaligus,
+// hasilnya dipakai untuk mengirim notifikasi ke setiap pelapor
+func (r *ModerationRepositoryImpl) ResolveReports(ctx context.Context, tx *sql.Tx, targetType string, targetID, moderatorID int, resolution, note string) ([]*entity.Report, error) {
+	query := `
+		UPDATE reports
+		SET status = 'resolved', resolution = $4, resolutionnote = $5, resolvedby = $3, resolvedat = NOW()
+		WHERE targettype = $1 AND targetid = $2 AND status <> 'resolved'
+		RETURNING ` + reportColumns
+
+	rows, err := tx.QueryContext(ctx, query, targetType, targetID, moderatorID, resolution, note)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*entity.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// SetHidden cuma berlaku untuk postingan dan komentar, return true kalau statusnya benar-benar berubah
+func (r *ModerationRepositoryImpl) SetHidden(ctx context.Context, tx *sql.Tx, targetType string, targetID int, hidden bool) (bool, error) {
+	var table, idColumn string
+	switch targetType {
+	case entity.ReportTargetPost:
+		table, idColumn = "posts", "postid"
+	case entity.ReportTargetComment:
+		table, idColumn = "comments", "commentid"
+	default:
+		return false, fmt.Errorf("%s can't be hidden", targetType)
+	}
+
+	condition := "hiddenat IS NULL"
+	value := "NOW()"
+	if !hidden {
+		condition = "hiddenat IS NOT NULL"
+		value = "NULL"
+	}
+	query := fmt.Sprintf(`UPDATE %s SET hiddenat = %s WHERE %s = $1 AND %s`, table, value, idColumn, condition)
+	return execChanged(tx.ExecContext(ctx, query, targetID))
+}
+
+func (r *ModerationRepositoryImpl) LogAction(ctx context.Context, tx *sql.Tx, action *entity.ModerationAction) error {
+	query := `
+		INSERT INTO moderation_actions (moderatorid, reportid, action, targettype, targetid, note)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.ExecContext(ctx, query, action.ModeratorID, action.ReportID, action.Action, action.TargetType, action.TargetID, action.Note)
+	return err
+}
+
+func (r *ModerationRepositoryImpl) FindActions(ctx context.Context, db *sql.DB, afterCreatedAt time.Time, afterID, limit int) ([]*entity.ModerationAction, error) {
+	query := `
+		SELECT actionid, moderatorid, reportid, action, targettype, targetid, note, createdat
+		FROM moderation_actions
+		WHERE ($2 = 0 OR (createdat, actionid) < ($1, $2))
+		ORDER BY createdat DESC, actionid DESC
+		LIMIT $3`
+
+	rows, err := db.QueryContext(ctx, query, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions := []*entity.ModerationAction{}
+	for rows.Next() {
+		var action entity.ModerationAction
+		var moderatorID, reportID sql.NullInt64
+		if err := rows.Scan(&action.ActionID, &moderatorID, &reportID, &action.Action, &action.TargetType, &action.TargetID, &action.Note, &action.CreatedAt); err != nil {
+			return nil, err
+		}
+		action.ModeratorID = nullIntPtr(moderatorID)
+		action.ReportID = nullIntPtr(reportID)
+		actions = append(actions, &action)
+	}
+	return actions, rows.Err()
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"time"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, db *sql.DB, notification *entity.Notification) (*entity.Notification, error)
+	FindByUserID(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Notification, error)
+	CountUnread(ctx context.Context, db *sql.DB, userID int) (int, error)
+	MarkRead(ctx context.Context, db *sql.DB, userID, notificationID int) (bool, error)
+	MarkAllRead(ctx context.Context, db *sql.DB, userID int) error
+}
+
+type NotificationRepositoryImpl struct {
+}
+
+func NewNotificationRepository() NotificationRepository {
+	return &NotificationRepositoryImpl{}
+}
+
+const notificationColumns = `notificationid, userid, type, message, referenceid, readat, createdat`
+
+func scanNotification(scanner interface{ Scan(dest ...any) error }) (*entity.Notification, error) {
+	var notification entity.Notification
+	var referenceID sql.NullInt64
+	var readAt sql.NullTime
+	if err := scanner.Scan(&notification.NotificationID, &notification.UserID, &notification.Type, &notification.Message, &referenceID, &readAt, &notification.CreatedAt); err != nil {
+		return nil, err
+	}
+	if referenceID.Valid {
+		id := int(referenceID.Int64)
+		notification.ReferenceID = &id
+	}
+	if readAt.Valid {
+		notification.ReadAt = &readAt.Time
+	}
+	return &notification, nil
+}
+
+func (r *NotificationRepositoryImpl) Create(ctx context.Context, db *sql.DB, notification *entity.Notification) (*entity.Notification, error) {
+	query := `INSERT INTO notifications (userid, type, message, referenceid) VALUES ($1, $2, $3, $4) RETURNING ` + notificationColumns
+	return scanNotification(db.QueryRowContext(ctx, query, notification.UserID, notification.Type, notification.Message, notification.ReferenceID))
+}
+
+func (r *NotificationRepositoryImpl) FindByUserID(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE userid = $1
+			AND ($3 = 0 OR (createdat, notificationid) < ($2, $3))
+		ORDER BY createdat DESC, notificationid DESC
+		LIMIT $4`
+
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*entity.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *NotificationRepositoryImpl) CountUnread(ctx context.Context, db *sql.DB, userID int) (int, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE userid = $1 AND readat IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead return false kalau notifikasinya tidak ada atau bukan milik user ini
+func (r *NotificationRepositoryImpl) MarkRead(ctx context.Context, db *sql.DB, userID, notificationID int) (bool, error) {
+	query := `UPDATE notifications SET readat = COALESCE(readat, NOW()) WHERE notificationid = $1 AND userid = $2`
+	return execChanged(db.ExecContext(ctx, query, notificationID, userID))
+}
+
+func (r *NotificationRepositoryImpl) MarkAllRead(ctx context.Context, db *sql.DB, userID int) error {
+	_, err := db.ExecContext(ctx, `UPDATE notifications SET readat = NOW() WHERE userid = $1 AND readat IS NULL`, userID)
+	return err
+}
@@ -5,74 +5,108 @@ import (
 	"database/sql"
 	"mood-bridge-v2/server/internal/entity"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type PostRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, post *entity.Post) (*entity.Post, error)
 	Find(ctx context.Context, db *sql.DB, postID int) (*entity.Post, error)
-	FindAll(ctx context.Context, db *sql.DB, limit, offset int) ([]*entity.Post, error)
-	FindByUserID(ctx context.Context, db *sql.DB, postID int) ([]*entity.Post, error)
+	FindAll(ctx context.Context, db *sql.DB, viewerID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error)
+	FindByUserID(ctx context.Context, db *sql.DB, userID, viewerID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error)
 	Update(ctx context.Context, tx *sql.Tx, postID int, post *entity.Post) (*entity.Post, error)
 	Delete(ctx context.Context, tx *sql.Tx, postID int) (string, error)
-	GetFriendPosts(ctx context.Context, db *sql.DB, userID, limit, offset int) ([]*entity.Post, error)
+	GetFriendPosts(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error)
+	FindByTag(ctx context.Context, db *sql.DB, tag string, viewerID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error)
+	FindRecentByUserID(ctx context.Context, db *sql.DB, userID int, since time.Time, limit int) ([]*entity.Post, error)
+	GetRevisions(ctx context.Context, db *sql.DB, postID int) ([]*entity.PostRevision, error)
+	Search(ctx context.Context, db *sql.DB, params PostSearchParams) ([]*PostSearchHit, error)
+	FindFeedCandidates(ctx context.Context, db *sql.DB, userID int, since time.Time, minReactions, limit int) ([]*FeedCandidate, error)
+	FindVisibleByIDs(ctx context.Context, db *sql.DB, viewerID int, postIDs []int) ([]*entity.Post, error)
 }
 
-type PostRepositoryImpl struct {
+// PostSearchParams semua filter-nya opsional, string kosong / nil berarti filter itu tidak dipakai
+type PostSearchParams struct {
+	ViewerID    int
+	Query       string
+	Mood        string
+	Author      string // username
+	From        *time.Time
+	To          *time.Time
+	AfterRank   float64 // posisi cursor, AfterPostID = 0 berarti halaman pertama
+	AfterPostID int
+	Limit       int
 }
 
-func NewPostRepository() PostRepository {
-	return &PostRepositoryImpl{}
+type PostSearchHit struct {
+	Post    *entity.Post
+	Rank    float64
+	Snippet string
 }
 
-func (r *PostRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, post *entity.Post) (*entity.Post, error) {
-	query := `INSERT INTO posts (userid, content, mood) VALUES ($1, $2, $3) RETURNING postid, userid, content, mood, createdat`
-
-	row := tx.QueryRowContext(ctx, query, post.UserID, post.Content, post.Mood)
+// FeedCandidate satu postingan calon isi feed, beserta alasan kenapa masuk dan angka engagement-nya
+type FeedCandidate struct {
+	Post            *entity.Post
+	FromFriend      bool // postingan sendiri atau teman
+	FromFollowedTag bool
+	ReactionCount   int
+	CommentCount    int
+}
 
-	var createdPost entity.Post
-	err := row.Scan(&createdPost.PostID, &createdPost.UserID, &createdPost.Content, &createdPost.Mood, &createdPost.CreatedAt)
-	if err != nil {
-		return nil, err
-	}
-	return &createdPost, nil
+type PostRepositoryImpl struct {
 }
 
-func (r *PostRepositoryImpl) Find(ctx context.Context, db *sql.DB, postID int) (*entity.Post, error) {
-	query := `SELECT postid, userid, content, mood, createdat FROM posts WHERE postid = $1;`
+// Subquery buat ngambil semua userid yang sudah berteman (accepted) dengan user $1
+const friendIDsSubquery = `
+	SELECT
+		CASE
+			WHEN userid = $1 THEN frienduserid
+			ELSE userid
+		END AS friend_userid
+	FROM friends
+	WHERE (userid = $1 OR frienduserid = $1)
+	AND status = 'accepted'`
 
-	row := db.QueryRowContext(ctx, query, postID)
+// Postingan dari user yang terblokir (dua arah) atau di-mute viewer $1 tidak ditampilkan di list.
+// Postingan anonim dikecualikan, kalau ikut hilang user bisa menebak penulisnya dengan cara memblokir.
+const notHiddenCondition = `(p.isanonymous OR p.userid NOT IN (` + hiddenUserIDsSubquery + `))`
 
-	var selectedPost entity.Post
-	err := row.Scan(&selectedPost.PostID, &selectedPost.UserID, &selectedPost.Content, &selectedPost.Mood, &selectedPost.CreatedAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Post not found
-		}
-		return nil, err // Other error
-	}
-	
-	return &selectedPost, nil
-}
+// Postingan yang disembunyikan moderator (atau otomatis karena banyak dilaporkan) cuma muncul untuk penulisnya sendiri
+const notModeratedCondition = `(p.hiddenat IS NULL OR p.userid = $1)`
 
-func (r *PostRepositoryImpl) FindAll(ctx context.Context, db *sql.DB, limit, offset int) ([]*entity.Post, error) {
-	query := `
-		SELECT postid, userid, content, mood, createdat 
-		FROM posts 
-		ORDER BY createdat DESC
-		LIMIT $1 OFFSET $2;`
-	rows, err := db.QueryContext(ctx, query, limit, offset)
+// Semua query postingan pakai alias "p" supaya kolom yang diambil selalu sama dengan urutan di scanPost
+const postColumns = `p.postid, p.userid, p.content, p.mood, p.declaredmood, p.visibility, p.isanonymous, p.createdat, p.editedat, p.hiddenat`
+
+func scanPost(scanner interface{ Scan(dest ...any) error }) (*entity.Post, error) {
+	var post entity.Post
+	var declaredMood sql.NullString
+	var editedAt, hiddenAt sql.NullTime
+	err := scanner.Scan(&post.PostID, &post.UserID, &post.Content, &post.Mood, &declaredMood, &post.Visibility, &post.IsAnonymous, &post.CreatedAt, &editedAt, &hiddenAt)
 	if err != nil {
 		return nil, err
 	}
+	if declaredMood.Valid {
+		post.DeclaredMood = &declaredMood.String
+	}
+	if editedAt.Valid {
+		post.EditedAt = &editedAt.Time
+	}
+	if hiddenAt.Valid {
+		post.HiddenAt = &hiddenAt.Time
+	}
+	return &post, nil
+}
+
+func scanPosts(rows *sql.Rows) ([]*entity.Post, error) {
 	defer rows.Close()
+
 	var posts []*entity.Post
 	for rows.Next() {
-		var post entity.Post
-		err := rows.Scan(&post.PostID, &post.UserID, &post.Content, &post.Mood, &post.CreatedAt)
+		post, err := scanPost(rows)
 		if err != nil {
 			return nil, err
 		}
-		posts = append(posts, &post)
+		posts = append(posts, post)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -80,40 +114,103 @@ func (r *PostRepositoryImpl) FindAll(ctx context.Context, db *sql.DB, limit, off
 	return posts, nil
 }
 
-func (r *PostRepositoryImpl) FindByUserID(ctx context.Context, db *sql.DB, userID int) ([]*entity.Post, error) {
-	query := `SELECT postid, userid, content, mood, createdat FROM posts WHERE userid = $1;`
-	rows, err := db.QueryContext(ctx, query, userID)
+func NewPostRepository() PostRepository {
+	return &PostRepositoryImpl{}
+}
+
+func (r *PostRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, post *entity.Post) (*entity.Post, error) {
+	query := `INSERT INTO posts AS p (userid, content, mood, declaredmood, visibility, isanonymous) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ` + postColumns
+
+	row := tx.QueryRowContext(ctx, query, post.UserID, post.Content, post.Mood, post.DeclaredMood, post.Visibility, post.IsAnonymous)
+	return scanPost(row)
+}
+
+func (r *PostRepositoryImpl) Find(ctx context.Context, db *sql.DB, postID int) (*entity.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts p WHERE p.postid = $1;`
+
+	selectedPost, err := scanPost(db.QueryRowContext(ctx, query, postID))
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var posts []*entity.Post
-	for rows.Next() {
-		var post entity.Post
-		err := rows.Scan(&post.PostID, &post.UserID, &post.Content, &post.Mood, &post.CreatedAt)
-		if err != nil {
-			return nil, err
+		if err == sql.ErrNoRows {
+			return nil, nil // Post not found
 		}
-		posts = append(posts, &post)
+		return nil, err // Other error
 	}
-	if err := rows.Err(); err != nil {
+
+	return selectedPost, nil
+}
+
+// Semua list postingan pakai keyset pagination (terbaru duluan): afterID = 0 berarti halaman pertama,
+// selain itu ambil postingan yang posisinya setelah cursor (createdat, postid)
+func (r *PostRepositoryImpl) FindAll(ctx context.Context, db *sql.DB, viewerID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error) {
+	// viewerID = 0 berarti yang lihat belum login, jadi cuma postingan public yang muncul
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		WHERE (
+				p.visibility = 'public'
+				OR p.userid = $1
+				OR (p.visibility = 'friends' AND p.userid IN (` + friendIDsSubquery + `))
+			)
+			AND ` + notHiddenCondition + `
+			AND ` + notModeratedCondition + `
+			AND ($3 = 0 OR (p.createdat, p.postid) < ($2, $3))
+		ORDER BY p.createdat DESC, p.postid DESC
+		LIMIT $4;`
+	rows, err := db.QueryContext(ctx, query, viewerID, afterCreatedAt, afterID, limit)
+	if err != nil {
 		return nil, err
 	}
-	return posts, nil
+	return scanPosts(rows)
 }
 
-func (r *PostRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, postID int, post *entity.Post) (*entity.Post, error) {
-	// set query-nya
-	query := `UPDATE posts SET content = $1, mood = $2 WHERE postid = $3 RETURNING postid, userid, content, mood, createdat`
+func (r *PostRepositoryImpl) FindByUserID(ctx context.Context, db *sql.DB, userID, viewerID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error) {
+	// $1 = yang lihat, $2 = pemilik postingan
+	// pemilik bisa lihat semua postingannya, orang lain tidak boleh lihat postingan anonim (biar identitasnya tidak ketahuan)
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		WHERE p.userid = $2
+			AND (
+				p.userid = $1
+				OR (
+					p.isanonymous = FALSE
+					AND p.hiddenat IS NULL
+					AND p.userid NOT IN (` + blockedUserIDsSubquery + `)
+					AND (
+						p.visibility = 'public'
+						OR (p.visibility = 'friends' AND p.userid IN (` + friendIDsSubquery + `))
+					)
+				)
+			)
+			AND ($4 = 0 OR (p.createdat, p.postid) < ($3, $4))
+		ORDER BY p.createdat DESC, p.postid DESC
+		LIMIT $5;`
+	rows, err := db.QueryContext(ctx, query, viewerID, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
 
-	// jalankan query-nya
-	row := tx.QueryRowContext(ctx, query, post.Content, post.Mood, postID)
+func (r *PostRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, postID int, post *entity.Post) (*entity.Post, error) {
+	// step 1: simpan versi sebelum diedit ke post_revisions (masih dalam transaction yang sama)
+	revisionQuery := `
+		INSERT INTO post_revisions (postid, content, mood, declaredmood)
+		SELECT postid, content, mood, declaredmood FROM posts WHERE postid = $1`
+	if _, err := tx.ExecContext(ctx, revisionQuery, postID); err != nil {
+		return nil, err
+	}
 
-	// buat variable untuk menampung hasil query
-	var updatedPost entity.Post
+	// step 2: set query-nya
+	query := `
+		UPDATE posts AS p
+		SET content = $1, mood = $2, declaredmood = $3, visibility = $4, isanonymous = $5, editedat = NOW()
+		WHERE p.postid = $6
+		RETURNING ` + postColumns
 
-	// scan hasil query ke variable
-	err := row.Scan(&updatedPost.PostID, &updatedPost.UserID, &updatedPost.Content, &updatedPost.Mood, &updatedPost.CreatedAt)
+	// step 3: jalankan query-nya lalu scan hasilnya
+	row := tx.QueryRowContext(ctx, query, post.Content, post.Mood, post.DeclaredMood, post.Visibility, post.IsAnonymous, postID)
+	updatedPost, err := scanPost(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Post not found
@@ -121,7 +218,7 @@ func (r *PostRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, postID int,
 		return nil, err // Other error
 	}
 
-	return &updatedPost, nil
+	return updatedPost, nil
 }
 
 func (r *PostRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, postID int) (string, error) {
@@ -147,45 +244,283 @@ func (r *PostRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, postID int)
 	return "Post with ID " + strconv.Itoa(deletedPostID) + " deleted successfully", nil
 }
 
-func (r *PostRepositoryImpl) GetFriendPosts(ctx context.Context, db *sql.DB, userID, limit, offset int) ([]*entity.Post, error) {
+func (r *PostRepositoryImpl) GetFriendPosts(ctx context.Context, db *sql.DB, userID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error) {
+	// postingan anonim milik teman tidak dimasukkan ke sini, karena kalau muncul di feed teman identitasnya jadi gampang ditebak
+	// selain postingan teman, postingan public dengan tag yang di-follow user juga masuk feed
 	query := `
-		SELECT p.postid, p.userid, p.content, p.mood, p.createdat
+		SELECT ` + postColumns + `
 		FROM posts p
-		WHERE 
+		WHERE ($3 = 0 OR (p.createdat, p.postid) < ($2, $3))
+		AND ` + notHiddenCondition + `
+		AND ` + notModeratedCondition + `
+		AND (
     		p.userid = $1
-    		OR p.userid IN (
-        	SELECT
-            	CASE 
-                	WHEN userid = $1 THEN frienduserid
-                	ELSE userid
-            	END AS friend_userid
-        	FROM friends
-        	WHERE (userid = $1 OR frienduserid = $1)
-        	AND friendstatus = TRUE
-    	)
-	ORDER BY p.createdat DESC
-	LIMIT $2 OFFSET $3;
+    		OR (
+				p.userid IN (` + friendIDsSubquery + `)
+				AND p.visibility IN ('public', 'friends')
+				AND p.isanonymous = FALSE
+			)
+			OR (
+				p.visibility = 'public'
+				AND p.postid IN (
+					SELECT pt.postid FROM post_tags pt
+					JOIN tag_follows tf ON tf.tag = pt.tag
+					WHERE tf.userid = $1
+				)
+			)
+		)
+	ORDER BY p.createdat DESC, p.postid DESC
+	LIMIT $4;
 	`
 
-	rows, err := db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := db.QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+func (r *PostRepositoryImpl) FindByTag(ctx context.Context, db *sql.DB, tag string, viewerID int, afterCreatedAt time.Time, afterID, limit int) ([]*entity.Post, error) {
+	// aturan visibility-nya sama dengan FindAll, $1 = yang lihat
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		JOIN post_tags pt ON pt.postid = p.postid AND pt.tag = $2
+		WHERE (
+				p.visibility = 'public'
+				OR p.userid = $1
+				OR (p.visibility = 'friends' AND p.userid IN (` + friendIDsSubquery + `))
+			)
+			AND ` + notHiddenCondition + `
+			AND ` + notModeratedCondition + `
+			AND ($4 = 0 OR (p.createdat, p.postid) < ($3, $4))
+		ORDER BY p.createdat DESC, p.postid DESC
+		LIMIT $5;`
+	rows, err := db.QueryContext(ctx, query, viewerID, tag, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+func (r *PostRepositoryImpl) FindRecentByUserID(ctx context.Context, db *sql.DB, userID int, since time.Time, limit int) ([]*entity.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		WHERE p.userid = $1 AND p.createdat >= $2
+		ORDER BY p.createdat DESC
+		LIMIT $3;`
+
+	rows, err := db.QueryContext(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+func (r *PostRepositoryImpl) GetRevisions(ctx context.Context, db *sql.DB, postID int) ([]*entity.PostRevision, error) {
+	query := `
+		SELECT revisionid, postid, content, mood, declaredmood, createdat
+		FROM post_revisions
+		WHERE postid = $1
+		ORDER BY createdat DESC, revisionid DESC;`
+
+	rows, err := db.QueryContext(ctx, query, postID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var posts []*entity.Post
+	var revisions []*entity.PostRevision
+	for rows.Next() {
+		var revision entity.PostRevision
+		var declaredMood sql.NullString
+		if err := rows.Scan(&revision.RevisionID, &revision.PostID, &revision.Content, &revision.Mood, &declaredMood, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		if declaredMood.Valid {
+			revision.DeclaredMood = &declaredMood.String
+		}
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *PostRepositoryImpl) Search(ctx context.Context, db *sql.DB, params PostSearchParams) ([]*PostSearchHit, error) {
+	// Query dicocokkan ke dua konfigurasi sekaligus (English dan Indonesian) karena user kita nulis pakai dua bahasa.
+	// Isi postingan di-escape dulu sebelum dibuat snippet, jadi snippet aman ditampilkan sebagai HTML (cuma <mark> yang asli).
+	// rank di-cast ke float8 supaya nilainya bisa dipakai lagi persis sama di cursor.
+	query := `
+		WITH search AS (
+			SELECT CASE WHEN $2 = '' THEN NULL
+				ELSE websearch_to_tsquery('english', $2) || websearch_to_tsquery('indonesian', $2)
+			END AS tsq
+		)
+		SELECT ` + postColumns + `, p.rank,
+			CASE WHEN p.tsq IS NULL THEN left(p.escaped, 200)
+				ELSE ts_headline('english', p.escaped, p.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')
+			END AS snippet
+		FROM (
+			SELECT posts.*, search.tsq,
+				replace(replace(replace(posts.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;') AS escaped,
+				CASE WHEN search.tsq IS NULL THEN 0 ELSE ts_rank_cd(posts.searchvector, search.tsq)::float8 END AS rank
+			FROM posts
+			CROSS JOIN search
+			JOIN users u ON u.userid = posts.userid
+			WHERE (search.tsq IS NULL OR posts.searchvector @@ search.tsq)
+				AND (
+					posts.visibility = 'public'
+					OR posts.userid = $1
+					OR (posts.visibility = 'friends' AND posts.userid IN (` + friendIDsSubquery + `))
+				)
+				AND (posts.isanonymous OR posts.userid NOT IN (` + hiddenUserIDsSubquery + `))
+				AND (posts.hiddenat IS NULL OR posts.userid = $1)
+				AND ($3 = '' OR posts.mood = $3)
+				AND ($4 = '' OR (u.username = $4 AND (posts.isanonymous = FALSE OR posts.userid = $1)))
+				AND ($5::timestamp IS NULL OR posts.createdat >= $5)
+				AND ($6::timestamp IS NULL OR posts.createdat < $6)
+		) AS p
+		WHERE ($8 = 0 OR p.rank < $7 OR (p.rank = $7 AND p.postid < $8))
+		ORDER BY p.rank DESC, p.postid DESC
+		LIMIT $9;`
+
+	rows, err := db.QueryContext(ctx, query, params.ViewerID, params.Query, params.Mood, params.Author, params.From, params.To, params.AfterRank, params.AfterPostID, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*PostSearchHit
 	for rows.Next() {
 		var post entity.Post
-		err := rows.Scan(&post.PostID, &post.UserID, &post.Content, &post.Mood, &post.CreatedAt)
+		var declaredMood sql.NullString
+		var editedAt, hiddenAt sql.NullTime
+		var hit PostSearchHit
+		err := rows.Scan(&post.PostID, &post.UserID, &post.Content, &post.Mood, &declaredMood, &post.Visibility, &post.IsAnonymous, &post.CreatedAt, &editedAt, &hiddenAt, &hit.Rank, &hit.Snippet)
 		if err != nil {
 			return nil, err
 		}
-		posts = append(posts, &post)
+		if declaredMood.Valid {
+			post.DeclaredMood = &declaredMood.String
+		}
+		if editedAt.Valid {
+			post.EditedAt = &editedAt.Time
+		}
+		if hiddenAt.Valid {
+			post.HiddenAt = &hiddenAt.Time
+		}
+		hit.Post = &post
+		hits = append(hits, &hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// FindFeedCandidates ngumpulin calon isi feed user $1 dari tiga sumber sekaligus:
+// postingan sendiri/teman, postingan public dengan tag yang di-follow, dan postingan public yang banyak dapat reaksi suportif.
+// Skor dan urutannya dihitung di service, query ini cuma nyaring yang boleh masuk.
+func (r *PostRepositoryImpl) FindFeedCandidates(ctx context.Context, db *sql.DB, userID int, since time.Time, minReactions, limit int) ([]*FeedCandidate, error) {
+	query := `
+		SELECT * FROM (
+			SELECT ` + postColumns + `,
+				(
+					p.userid = $1
+					OR (
+						p.userid IN (` + friendIDsSubquery + `)
+						AND p.visibility IN ('public', 'friends')
+						AND p.isanonymous = FALSE
+					)
+				) AS from_friend,
+				(
+					p.visibility = 'public'
+					AND EXISTS (
+						SELECT 1 FROM post_tags pt
+						JOIN tag_follows tf ON tf.tag = pt.tag
+						WHERE pt.postid = p.postid AND tf.userid = $1
+					)
+				) AS from_followed_tag,
+				COALESCE((SELECT SUM(rc.count) FROM post_reaction_counts rc WHERE rc.postid = p.postid), 0) AS reaction_count,
+				(SELECT COUNT(*) FROM comments c WHERE c.postid = p.postid AND c.isdeleted = FALSE) AS comment_count
+			FROM posts p
+			WHERE p.createdat >= $2
+				AND ` + notHiddenCondition + `
+				AND ` + notModeratedCondition + `
+		) AS candidates
+		WHERE from_friend
+			OR from_followed_tag
+			OR (visibility = 'public' AND reaction_count >= $3)
+		ORDER BY createdat DESC
+		LIMIT $4;`
+
+	rows, err := db.QueryContext(ctx, query, userID, since, minReactions, limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
+	var candidates []*FeedCandidate
+	for rows.Next() {
+		var post entity.Post
+		var declaredMood sql.NullString
+		var editedAt, hiddenAt sql.NullTime
+		var candidate FeedCandidate
+		err := rows.Scan(&post.PostID, &post.UserID, &post.Content, &post.Mood, &declaredMood, &post.Visibility, &post.IsAnonymous, &post.CreatedAt, &editedAt, &hiddenAt,
+			&candidate.FromFriend, &candidate.FromFollowedTag, &candidate.ReactionCount, &candidate.CommentCount)
+		if err != nil {
+			return nil, err
+		}
+		if declaredMood.Valid {
+			post.DeclaredMood = &declaredMood.String
+		}
+		if editedAt.Valid {
+			post.EditedAt = &editedAt.Time
+		}
+		if hiddenAt.Valid {
+			post.HiddenAt = &hiddenAt.Time
+		}
+		candidate.Post = &post
+		candidates = append(candidates, &candidate)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return candidates, nil
+}
 
-	return posts, nil
-}
\ No newline at end of file
+// FindVisibleByIDs ngambil postingan berdasarkan id, tapi tetap dengan aturan visibility yang sama dengan FindAll
+// (dipakai waktu baca feed dari Redis, siapa tahu postingannya sudah dihapus atau pertemanannya sudah putus)
+func (r *PostRepositoryImpl) FindVisibleByIDs(ctx context.Context, db *sql.DB, viewerID int, postIDs []int) ([]*entity.Post, error) {
+	if len(postIDs) == 0 {
+		return []*entity.Post{}, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := make([]any, 0, len(postIDs)+1)
+	args = append(args, viewerID)
+	for i, id := range postIDs {
+		placeholders[i] = "$" + strconv.Itoa(i+2)
+		args = append(args, id)
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts p
+		WHERE p.postid IN (` + strings.Join(placeholders, ", ") + `)
+			AND (
+				p.visibility = 'public'
+				OR p.userid = $1
+				OR (p.visibility = 'friends' AND p.userid IN (` + friendIDsSubquery + `))
+			)
+			AND ` + notHiddenCondition + `
+			AND ` + notModeratedCondition + `;`
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+type ProfileRepository interface {
+	FindByUserID(ctx context.Context, db *sql.DB, userID int) (*entity.UserProfile, error)
+	FindForUpdate(ctx context.Context, tx *sql.Tx, userID int) (*entity.UserProfile, error)
+	Update(ctx context.Context, tx *sql.Tx, profile *entity.UserProfile) (*entity.UserProfile, error)
+}
+
+type ProfileRepositoryImpl struct {
+}
+
+func NewProfileRepository() ProfileRepository {
+	return &ProfileRepositoryImpl{}
+}
+
+const profileColumns = `
+	userid, bio, pronouns, location, interests, supportseeking, supportoffering, language,
+	biovisibility, pronounsvisibility, locationvisibility, interestsvisibility,
+	supportseekingvisibility, supportofferingvisibility, languagevisibility, friendsvisibility, updatedat`
+
+// FindByUserID mengembalikan nil kalau user belum pernah mengisi profil
+func (r *ProfileRepositoryImpl) FindByUserID(ctx context.Context, db *sql.DB, userID int) (*entity.UserProfile, error) {
+	query := `SELECT` + profileColumns + ` FROM user_profiles WHERE userid = $1`
+	profile, err := scanProfile(db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return profile, err
+}
+
+// FindForUpdate membuat baris profil default kalau belum ada lalu menguncinya sampai transaksi selesai,
+// supaya dua PATCH yang bersamaan tidak saling menimpa field yang diubah satu sama lain
+func (r *ProfileRepositoryImpl) FindForUpdate(ctx context.Context, tx *sql.Tx, userID int) (*entity.UserProfile, error) {
+	// step 1: pastikan barisnya ada (nilai awalnya dari default kolom)
+	if _, err := tx.ExecContext(ctx, `INSERT INTO user_profiles (userid) VALUES ($1) ON CONFLICT (userid) DO NOTHING`, userID); err != nil {
+		return nil, err
+	}
+
+	// step 2: ambil dan kunci barisnya
+	query := `SELECT` + profileColumns + ` FROM user_profiles WHERE userid = $1 FOR UPDATE`
+	return scanProfile(tx.QueryRowContext(ctx, query, userID))
+}
+
+func (r *ProfileRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, profile *entity.UserProfile) (*entity.UserProfile, error) {
+	query := `
+		UPDATE user_profiles SET
+			bio = $2, pronouns = $3, location = $4, interests = $5, supportseeking = $6, supportoffering = $7, language = $8,
+			biovisibility = $9, pronounsvisibility = $10, locationvisibility = $11, interestsvisibility = $12,
+			supportseekingvisibility = $13, supportofferingvisibility = $14, languagevisibility = $15, friendsvisibility = $16,
+			updatedat = NOW()
+		WHERE userid = $1
+		RETURNING` + profileColumns
+
+	row := tx.QueryRowContext(ctx, query,
+		profile.UserID, profile.Bio, profile.Pronouns, profile.Location,
+		pq.Array(profile.Interests), pq.Array(profile.SupportSeeking), pq.Array(profile.SupportOffering), profile.Language,
+		profile.BioVisibility, profile.PronounsVisibility, profile.LocationVisibility, profile.InterestsVisibility,
+		profile.SupportSeekingVisibility, profile.SupportOfferingVisibility, profile.LanguageVisibility, profile.FriendsVisibility,
+	)
+	return scanProfile(row)
+}
+
+func scanProfile(row *sql.Row) (*entity.UserProfile, error) {
+	var profile entity.UserProfile
+	err := row.Scan(
+		&profile.UserID, &profile.Bio, &profile.Pronouns, &profile.Location,
+		pq.Array(&profile.Interests), pq.Array(&profile.SupportSeeking), pq.Array(&profile.SupportOffering), &profile.Language,
+		&profile.BioVisibility, &profile.PronounsVisibility, &profile.LocationVisibility, &profile.InterestsVisibility,
+		&profile.SupportSeekingVisibility, &profile.SupportOfferingVisibility, &profile.LanguageVisibility, &profile.FriendsVisibility,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
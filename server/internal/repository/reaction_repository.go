@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"strings"
+	"time"
+)
+
+type ReactionRepository interface {
+	Add(ctx context.Context, tx *sql.Tx, reaction *entity.PostReaction) (bool, error)
+	Remove(ctx context.Context, tx *sql.Tx, postID, userID int, reactionType string) (bool, error)
+	GetCounts(ctx context.Context, db *sql.DB, postID int) (map[string]int, error)
+	GetCountsByPostIDs(ctx context.Context, db *sql.DB, postIDs []int) (map[int]map[string]int, error)
+	GetUserReactions(ctx context.Context, db *sql.DB, postID, userID int) ([]string, error)
+	FindReactors(ctx context.Context, db *sql.DB, postID, viewerID int, reactionType string, afterCreatedAt time.Time, afterUserID, limit int) ([]*entity.PostReaction, error)
+}
+
+type ReactionRepositoryImpl struct {
+}
+
+func NewReactionRepository() ReactionRepository {
+	return &ReactionRepositoryImpl{}
+}
+
+// Add mengembalikan false kalau user sudah pernah kasih reaksi yang sama (jadi aman dipanggil berkali-kali)
+func (r *ReactionRepositoryImpl) Add(ctx context.Context, tx *sql.Tx, reaction *entity.PostReaction) (bool, error) {
+	query := `
+		INSERT INTO post_reactions (postid, userid, reactiontype) VALUES ($1, $2, $3)
+		ON CONFLICT (postid, userid, reactiontype) DO NOTHING
+		RETURNING postid`
+
+	var postID int
+	err := tx.QueryRowContext(ctx, query, reaction.PostID, reaction.UserID, reaction.ReactionType).Scan(&postID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil // reaksi sudah ada sebelumnya
+		}
+		return false, err
+	}
+
+	// counter cuma diubah kalau memang ada baris baru, jadi jumlahnya tetap konsisten
+	if err := r.adjustCount(ctx, tx, reaction.PostID, reaction.ReactionType, 1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Remove mengembalikan false kalau reaksinya memang belum ada
+func (r *ReactionRepositoryImpl) Remove(ctx context.Context, tx *sql.Tx, postID, userID int, reactionType string) (bool, error) {
+	query := `DELETE FROM post_reactions WHERE postid = $1 AND userid = $2 AND reactiontype = $3 RETURNING postid`
+
+	var deletedPostID int
+	err := tx.QueryRowContext(ctx, query, postID, userID, reactionType).Scan(&deletedPostID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := r.adjustCount(ctx, tx, postID, reactionType, -1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *ReactionRepositoryImpl) adjustCount(ctx context.Context, tx *sql.Tx, postID int, reactionType string, delta int) error {
+	query := `
+		INSERT INTO post_reaction_counts (postid, reactiontype, count) VALUES ($1, $2, GREATEST($3, 0))
+		ON CONFLICT (postid, reactiontype) DO UPDATE SET count = GREATEST(post_reaction_counts.count + $3, 0)`
+
+	_, err := tx.ExecContext(ctx, query, postID, reactionType, delta)
+	return err
+}
+
+func (r *ReactionRepositoryImpl) GetCounts(ctx context.Context, db *sql.DB, postID int) (map[string]int, error) {
+	counts, err := r.GetCountsByPostIDs(ctx, db, []int{postID})
+	if err != nil {
+		return nil, err
+	}
+	return counts[postID], nil
+}
+
+func (r *ReactionRepositoryImpl) GetCountsByPostIDs(ctx context.Context, db *sql.DB, postIDs []int) (map[int]map[string]int, error) {
+	result := make(map[int]map[string]int)
+	for _, postID := range postIDs {
+		result[postID] = map[string]int{}
+	}
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	// step 1: buat placeholder untuk query (sesuai jumlah ids)
+	placeholders := make([]string, len(postIDs))
+	args := make([]interface{}, len(postIDs))
+	for i, id := range postIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	// step 2: ambil semua counter sekaligus (yang nol ga usah dibawa)
+	query := fmt.Sprintf("SELECT postid, reactiontype, count FROM post_reaction_counts WHERE count > 0 AND postid IN (%s)", strings.Join(placeholders, ","))
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, count int
+		var reactionType string
+		if err := rows.Scan(&postID, &reactionType, &count); err != nil {
+			return nil, err
+		}
+		result[postID][reactionType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *ReactionRepositoryImpl) GetUserReactions(ctx context.Context, db *sql.DB, postID, userID int) ([]string, error) {
+	query := `SELECT reactiontype FROM post_reactions WHERE postid = $1 AND userid = $2 ORDER BY reactiontype`
+
+	rows, err := db.QueryContext(ctx, query, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactionTypes := []string{}
+	for rows.Next() {
+		var reactionType string
+		if err := rows.Scan(&reactionType); err != nil {
+			return nil, err
+		}
+		reactionTypes = append(reactionTypes, reactionType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reactionTypes, nil
+}
+
+// FindReactors mengambil siapa saja yang kasih reaksi, reactionType kosong berarti semua jenis reaksi.
+// Cursor-nya (createdat, userid): satu user bisa kasih beberapa jenis reaksi, tapi hampir tidak mungkin di waktu yang persis sama.
+func (r *ReactionRepositoryImpl) FindReactors(ctx context.Context, db *sql.DB, postID, viewerID int, reactionType string, afterCreatedAt time.Time, afterUserID, limit int) ([]*entity.PostReaction, error) {
+	// $1 = yang lihat, reaksi dari user yang terblokir/di-mute viewer tidak ditampilkan
+	query := `
+		SELECT postid, userid, reactiontype, createdat
+		FROM post_reactions
+		WHERE postid = $2 AND ($3 = '' OR reactiontype = $3)
+			AND userid NOT IN (` + hiddenUserIDsSubquery + `)
+			AND ($5 = 0 OR (createdat, userid) < ($4, $5))
+		ORDER BY createdat DESC, userid DESC, reactiontype ASC
+		LIMIT $6`
+
+	rows, err := db.QueryContext(ctx, query, viewerID, postID, reactionType, afterCreatedAt, afterUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []*entity.PostReaction
+	for rows.Next() {
+		var reaction entity.PostReaction
+		if err := rows.Scan(&reaction.PostID, &reaction.UserID, &reaction.ReactionType, &reaction.CreatedAt); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, &reaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+type RoleRepository interface {
+	FindAll(ctx context.Context, db *sql.DB) ([]*entity.Role, error)
+	FindByName(ctx context.Context, db *sql.DB, name string) (*entity.Role, error)
+	FindRolesByUserID(ctx context.Context, db *sql.DB, userID int) ([]string, error)
+	FindRolesByUserIDs(ctx context.Context, db *sql.DB, userIDs []int) (map[int][]string, error)
+	FindPermissionsByUserID(ctx context.Context, db *sql.DB, userID int) ([]string, error)
+	HasPermission(ctx context.Context, db *sql.DB, userID int, permission string) (bool, error)
+	RequiresMFA(ctx context.Context, db *sql.DB, userID int) (bool, error)
+	SetRequireMFA(ctx context.Context, db *sql.DB, roleID int, required bool) (bool, error)
+	CountUsersWithRole(ctx context.Context, tx *sql.Tx, roleID int) (int, error)
+	Assign(ctx context.Context, tx *sql.Tx, userID, roleID int, assignedBy *int) (bool, error)
+	Revoke(ctx context.Context, tx *sql.Tx, userID, roleID int) (bool, error)
+}
+
+type RoleRepositoryImpl struct {
+}
+
+func NewRoleRepository() RoleRepository {
+	return &RoleRepositoryImpl{}
+}
+
+func (r *RoleRepositoryImpl) FindAll(ctx context.Context, db *sql.DB) ([]*entity.Role, error) {
+	query := `
+		SELECT r.roleid, r.name, r.description, r.requiremfa,
+			COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.roleid = r.roleid
+		LEFT JOIN permissions p ON p.permissionid = rp.permissionid
+		GROUP BY r.roleid
+		ORDER BY r.roleid`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*entity.Role{}
+	for rows.Next() {
+		var role entity.Role
+		if err := rows.Scan(&role.RoleID, &role.Name, &role.Description, &role.RequireMFA, pq.Array(&role.Permissions)); err != nil {
+			return nil, err
+		}
+		roles = append(roles, &role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// FindByName return nil kalau role-nya tidak ada
+func (r *RoleRepositoryImpl) FindByName(ctx context.Context, db *sql.DB, name string) (*entity.Role, error) {
+	var role entity.Role
+	err := db.QueryRowContext(ctx, `SELECT roleid, name, description, requiremfa FROM roles WHERE name = $1`, name).
+		Scan(&role.RoleID, &role.Name, &role.Description, &role.RequireMFA)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepositoryImpl) FindRolesByUserID(ctx context.Context, db *sql.DB, userID int) ([]string, error) {
+	roles, err := r.FindRolesByUserIDs(ctx, db, []int{userID})
+	if err != nil {
+		return nil, err
+	}
+	return roles[userID], nil
+}
+
+// FindRolesByUserIDs selalu mengisi semua userID yang diminta (slice kosong kalau tidak punya role)
+func (r *RoleRepositoryImpl) FindRolesByUserIDs(ctx context.Context, db *sql.DB, userIDs []int) (map[int][]string, error) {
+	result := make(map[int][]string)
+	for _, userID := range userIDs {
+		result[userID] = []string{}
+	}
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT ur.userid, r.name
+		FROM user_roles ur
+		JOIN roles r ON r.roleid = ur.roleid
+		WHERE ur.userid = ANY($1)
+		ORDER BY ur.userid, r.name`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		var name string
+		if err := rows.Scan(&userID, &name); err != nil {
+			return nil, err
+		}
+		result[userID] = append(result[userID], name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Role yang mewajibkan 2FA tidak memberi permission apa pun ke user yang belum mengaktifkan 2FA
+const mfaSatisfiedCondition = `(NOT r.requiremfa OR EXISTS (SELECT 1 FROM user_mfa m WHERE m.userid = ur.userid AND m.enabledat IS NOT NULL))`
+
+func (r *RoleRepositoryImpl) FindPermissionsByUserID(ctx context.Context, db *sql.DB, userID int) ([]string, error) {
+	query := `
+		SELECT DISTINCT p.name
+		FROM user_roles ur
+		JOIN roles r ON r.roleid = ur.roleid
+		JOIN role_permissions rp ON rp.roleid = ur.roleid
+		JOIN permissions p ON p.permissionid = rp.permissionid
+		WHERE ur.userid = $1 AND ` + mfaSatisfiedCondition + `
+		ORDER BY p.name`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepositoryImpl) HasPermission(ctx context.Context, db *sql.DB, userID int, permission string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_roles ur
+			JOIN roles r ON r.roleid = ur.roleid
+			JOIN role_permissions rp ON rp.roleid = ur.roleid
+			JOIN permissions p ON p.permissionid = rp.permissionid
+			WHERE ur.userid = $1 AND p.name = $2 AND ` + mfaSatisfiedCondition + `
+		)`
+
+	var allowed bool
+	err := db.QueryRowContext(ctx, query, userID, permission).Scan(&allowed)
+	return allowed, err
+}
+
+// RequiresMFA return true kalau salah satu role user mewajibkan 2FA
+func (r *RoleRepositoryImpl) RequiresMFA(ctx context.Context, db *sql.DB, userID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_roles ur
+			JOIN roles r ON r.roleid = ur.roleid
+			WHERE ur.userid = $1 AND r.requiremfa
+		)`
+
+	var required bool
+	err := db.QueryRowContext(ctx, query, userID).Scan(&required)
+	return required, err
+}
+
+// SetRequireMFA return false kalau nilainya memang sudah sama
+func (r *RoleRepositoryImpl) SetRequireMFA(ctx context.Context, db *sql.DB, roleID int, required bool) (bool, error) {
+	query := `UPDATE roles SET requiremfa = $2 WHERE roleid = $1 AND requiremfa <> $2`
+	return execChanged(db.ExecContext(ctx, query, roleID, required))
+}
+
+// CountUsersWithRole mengunci baris role-nya dulu, supaya dua admin yang saling mencabut role admin
+// tidak bisa sama-sama lolos pengecekan "masih ada admin lain"
+func (r *RoleRepositoryImpl) CountUsersWithRole(ctx context.Context, tx *sql.Tx, roleID int) (int, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT roleid FROM roles WHERE roleid = $1 FOR UPDATE`, roleID); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_roles WHERE roleid = $1`, roleID).Scan(&count)
+	return count, err
+}
+
+// Assign return false kalau user sudah punya role tersebut
+func (r *RoleRepositoryImpl) Assign(ctx context.Context, tx *sql.Tx, userID, roleID int, assignedBy *int) (bool, error) {
+	query := `INSERT INTO user_roles (userid, roleid, assignedby) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	return execChanged(tx.ExecContext(ctx, query, userID, roleID, assignedBy))
+}
+
+// Revoke return false kalau user memang tidak punya role tersebut
+func (r *RoleRepositoryImpl) Revoke(ctx context.Context, tx *sql.Tx, userID, roleID int) (bool, error) {
+	return execChanged(tx.ExecContext(ctx, `DELETE FROM user_roles WHERE userid = $1 AND roleid = $2`, userID, roleID))
+}
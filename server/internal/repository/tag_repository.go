@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TagRepository interface {
+	ReplacePostTags(ctx context.Context, tx *sql.Tx, postID int, tags []string) error
+	GetTagsByPostIDs(ctx context.Context, db *sql.DB, postIDs []int) (map[int][]string, error)
+	CountPublicPosts(ctx context.Context, db *sql.DB, tag string) (int, error)
+	Follow(ctx context.Context, db *sql.DB, userID int, tag string) (bool, error)
+	Unfollow(ctx context.Context, db *sql.DB, userID int, tag string) (bool, error)
+	IsFollowing(ctx context.Context, db *sql.DB, userID int, tag string) (bool, error)
+	GetFollowedTags(ctx context.Context, db *sql.DB, userID int) ([]string, error)
+	RefreshTrending(ctx context.Context, tx *sql.Tx, since time.Time, limit int) error
+	GetTrending(ctx context.Context, db *sql.DB, limit int) ([]*entity.TrendingTag, error)
+	GetResources(ctx context.Context, db *sql.DB, tag string) ([]*entity.TagResource, error)
+}
+
+type TagRepositoryImpl struct {
+}
+
+func NewTagRepository() TagRepository {
+	return &TagRepositoryImpl{}
+}
+
+// ReplacePostTags dipanggil setiap post dibuat/diedit, tag lama dibuang lalu diganti hasil ekstraksi yang baru
+func (r *TagRepositoryImpl) ReplacePostTags(ctx context.Context, tx *sql.Tx, postID int, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE postid = $1`, postID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		_, err := tx.ExecContext(ctx, `INSERT INTO post_tags (postid, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, postID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TagRepositoryImpl) GetTagsByPostIDs(ctx context.Context, db *sql.DB, postIDs []int) (map[int][]string, error) {
+	tags := make(map[int][]string)
+	if len(postIDs) == 0 {
+		return tags, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+	query := `SELECT postid, tag FROM post_tags WHERE postid IN (` + strings.Join(placeholders, ", ") + `) ORDER BY postid, tag`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID int
+		var tag string
+		if err := rows.Scan(&postID, &tag); err != nil {
+			return nil, err
+		}
+		tags[postID] = append(tags[postID], tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// CountPublicPosts cuma ngitung postingan public, supaya jumlahnya tidak membocorkan postingan private/friends
+func (r *TagRepositoryImpl) CountPublicPosts(ctx context.Context, db *sql.DB, tag string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM post_tags pt
+		JOIN posts p ON p.postid = pt.postid
+		WHERE pt.tag = $1 AND p.visibility = 'public'`
+
+	var count int
+	if err := db.QueryRowContext(ctx, query, tag).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *TagRepositoryImpl) Follow(ctx context.Context, db *sql.DB, userID int, tag string) (bool, error) {
+	result, err := db.ExecContext(ctx, `INSERT INTO tag_follows (userid, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userID, tag)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *TagRepositoryImpl) Unfollow(ctx context.Context, db *sql.DB, userID int, tag string) (bool, error) {
+	result, err := db.ExecContext(ctx, `DELETE FROM tag_follows WHERE userid = $1 AND tag = $2`, userID, tag)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *TagRepositoryImpl) IsFollowing(ctx context.Context, db *sql.DB, userID int, tag string) (bool, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tag_follows WHERE userid = $1 AND tag = $2)`, userID, tag).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *TagRepositoryImpl) GetFollowedTags(ctx context.Context, db *sql.DB, userID int) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT tag FROM tag_follows WHERE userid = $1 ORDER BY tag`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// RefreshTrending ngitung ulang tag paling banyak dipakai di postingan public sejak "since" (sliding window),
+// lalu mengganti isi trending_tags sekaligus dalam satu transaction
+func (r *TagRepositoryImpl) RefreshTrending(ctx context.Context, tx *sql.Tx, since time.Time, limit int) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM trending_tags`); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO trending_tags (tag, postcount, computedat)
+		SELECT pt.tag, COUNT(*) AS postcount, NOW()
+		FROM post_tags pt
+		JOIN posts p ON p.postid = pt.postid
+		WHERE p.visibility = 'public' AND p.createdat >= $1
+		GROUP BY pt.tag
+		ORDER BY postcount DESC, pt.tag ASC
+		LIMIT $2`
+	_, err := tx.ExecContext(ctx, query, since, limit)
+	return err
+}
+
+func (r *TagRepositoryImpl) GetTrending(ctx context.Context, db *sql.DB, limit int) ([]*entity.TrendingTag, error) {
+	query := `SELECT tag, postcount, computedat FROM trending_tags ORDER BY postcount DESC, tag ASC LIMIT $1`
+
+	rows, err := db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trending []*entity.TrendingTag
+	for rows.Next() {
+		var tag entity.TrendingTag
+		if err := rows.Scan(&tag.Tag, &tag.PostCount, &tag.ComputedAt); err != nil {
+			return nil, err
+		}
+		trending = append(trending, &tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return trending, nil
+}
+
+func (r *TagRepositoryImpl) GetResources(ctx context.Context, db *sql.DB, tag string) ([]*entity.TagResource, error) {
+	query := `SELECT resourceid, tag, title, url, COALESCE(description, '') FROM tag_resources WHERE tag = $1 ORDER BY resourceid`
+
+	rows, err := db.QueryContext(ctx, query, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resources []*entity.TagResource
+	for rows.Next() {
+		var resource entity.TagResource
+		if err := rows.Scan(&resource.ResourceID, &resource.Tag, &resource.Title, &resource.URL, &resource.Description); err != nil {
+			return nil, err
+		}
+		resources = append(resources, &resource)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
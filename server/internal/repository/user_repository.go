@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"mood-bridge-v2/server/internal/entity"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type UserRepository interface {
@@ -14,9 +17,20 @@ type UserRepository interface {
 	Find(ctx context.Context, db *sql.DB, username string) (*entity.User, error)
 	FindByID(ctx context.Context, db *sql.DB, id int) (*entity.User, error)
 	FindByEmail(ctx context.Context, db *sql.DB, email string) (*entity.User, error) // for login and validation
-	FindAll(ctx context.Context, db *sql.DB) ([]*entity.User, error)
+	FindAll(ctx context.Context, db *sql.DB, afterCreatedAt time.Time, afterID, limit int) ([]*entity.User, error)
 	Update(ctx context.Context, tx *sql.Tx, id int, user *entity.User) (*entity.User, error)
 	FindByIDs(ctx context.Context, db *sql.DB, ids []int) ([]*entity.User, error)
+	UpdateAvatar(ctx context.Context, db *sql.DB, id int, avatarKey sql.NullString) (sql.NullString, error)
+	Suspend(ctx context.Context, tx *sql.Tx, id int, until time.Time) error
+	Unsuspend(ctx context.Context, tx *sql.Tx, id int) (bool, error)
+	Ban(ctx context.Context, tx *sql.Tx, id int, reason string) (bool, error)
+	Unban(ctx context.Context, tx *sql.Tx, id int) (bool, error)
+	UpdatePassword(ctx context.Context, tx *sql.Tx, id int, password string) error
+	FindRestriction(ctx context.Context, db *sql.DB, id int) (*entity.AccountRestriction, error)
+	FindRestrictions(ctx context.Context, db *sql.DB, ids []int) (map[int]*entity.AccountRestriction, error)
+	FindIDByEmail(ctx context.Context, db *sql.DB, email string) (int, error)
+	IsEmailVerified(ctx context.Context, db *sql.DB, id int) (bool, error)
+	MarkEmailVerified(ctx context.Context, tx *sql.Tx, id int, email string) (bool, error)
 }
 
 type UserRepositoryImpl struct {
@@ -28,14 +42,14 @@ func NewUserRepository() UserRepository {
 
 func (r *UserRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, user *entity.User) (*entity.User, error) {
 	// step 1: define query-nya
-	query := `INSERT INTO users (username, fullname, email, password) VALUES ($1, $2, $3, $4) RETURNING userid, username, fullname, email, password, profileurl, createdat`
+	query := `INSERT INTO users (username, fullname, email, password) VALUES ($1, $2, $3, $4) RETURNING userid, username, fullname, email, password, avatarkey, createdat`
 
 	// step 2: execute query-nya
 	row := tx.QueryRowContext(ctx, query, user.Username, user.Fullname, user.Email, user.Password)
 
 	// step 3: scan hasilnya ke dalam struct user untuk di return.
 	var createdUser entity.User
-	err := row.Scan(&createdUser.ID, &createdUser.Username, &createdUser.Fullname, &createdUser.Email, &createdUser.Password, &createdUser.ProfileUrl, &createdUser.CreatedAt)
+	err := row.Scan(&createdUser.ID, &createdUser.Username, &createdUser.Fullname, &createdUser.Email, &createdUser.Password, &createdUser.AvatarKey, &createdUser.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -44,52 +58,45 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, user *entit
 
 func (r *UserRepositoryImpl) Find(ctx context.Context, db *sql.DB, username string) (*entity.User, error) {
 	// step 1: define query
-	query := `SELECT userid, username, fullname, profileurl, email, password, createdat FROM users WHERE username = $1;`
+	query := `SELECT userid, username, fullname, avatarkey, email, password, createdat FROM users WHERE username = $1;`
 
 	// step 2: execute query
 	row := db.QueryRowContext(ctx, query, username)
 
 	// step 3: scan row-nya ke struct user
 	var selectedUser entity.User
-	err := row.Scan(&selectedUser.ID, &selectedUser.Username, &selectedUser.Fullname, &selectedUser.ProfileUrl, &selectedUser.Email, &selectedUser.Password, &selectedUser.CreatedAt)
+	err := row.Scan(&selectedUser.ID, &selectedUser.Username, &selectedUser.Fullname, &selectedUser.AvatarKey, &selectedUser.Email, &selectedUser.Password, &selectedUser.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	if !selectedUser.ProfileUrl.Valid {
-		selectedUser.ProfileUrl.String = defaultProfileUrl // Default value if NULL
-	}
-
 	return &selectedUser, err
 }
 
 func (r *UserRepositoryImpl) FindByID(ctx context.Context, db *sql.DB, id int) (*entity.User, error) {
-	query := `SELECT userid, username, fullname, profileurl, email, password, createdat FROM users WHERE userid = $1;`
+	query := `SELECT userid, username, fullname, avatarkey, email, password, createdat FROM users WHERE userid = $1;`
 	row := db.QueryRowContext(ctx, query, id)
 	var selectedUser entity.User
-	err := row.Scan(&selectedUser.ID, &selectedUser.Username, &selectedUser.Fullname, &selectedUser.ProfileUrl, &selectedUser.Email, &selectedUser.Password, &selectedUser.CreatedAt)
+	err := row.Scan(&selectedUser.ID, &selectedUser.Username, &selectedUser.Fullname, &selectedUser.AvatarKey, &selectedUser.Email, &selectedUser.Password, &selectedUser.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("user not found")
 		}
 		return nil, err
 	}
-	if !selectedUser.ProfileUrl.Valid {
-		selectedUser.ProfileUrl.String = defaultProfileUrl // Default value if NULL
-	}
 	return &selectedUser, err
 }
 
 func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, db *sql.DB, email string) (*entity.User, error) {
 	// step 1: define query
-	query := `SELECT userid, username, fullname, profileurl, email, password, createdat FROM users WHERE email = $1;`
+	query := `SELECT userid, username, fullname, avatarkey, email, password, createdat FROM users WHERE email = $1;`
 
 	// step 2: execute query
 	row := db.QueryRowContext(ctx, query, email)
 
 	// step 3: scan row-nya ke struct user
 	var selectedUser entity.User
-	err := row.Scan(&selectedUser.ID, &selectedUser.Username, &selectedUser.Fullname, &selectedUser.ProfileUrl, &selectedUser.Email, &selectedUser.Password, &selectedUser.CreatedAt)
+	err := row.Scan(&selectedUser.ID, &selectedUser.Username, &selectedUser.Fullname, &selectedUser.AvatarKey, &selectedUser.Email, &selectedUser.Password, &selectedUser.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) { // more informative error handling (saat ini dibiarin dulu biar saya bisa ngeliat errornya)
 			return nil, fmt.Errorf("user not found")
@@ -97,19 +104,20 @@ func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, db *sql.DB, email
 		return nil, err
 	}
 
-	if !selectedUser.ProfileUrl.Valid {
-		selectedUser.ProfileUrl.String = defaultProfileUrl // Default value if NULL
-	}
-
 	return &selectedUser, err
 }
 
-func (r *UserRepositoryImpl) FindAll(ctx context.Context, db *sql.DB) ([]*entity.User, error) {
-	// step 1: define query
-	query := `SELECT userid, username, fullname, profileurl, email, password, createdat FROM users;`
+func (r *UserRepositoryImpl) FindAll(ctx context.Context, db *sql.DB, afterCreatedAt time.Time, afterID, limit int) ([]*entity.User, error) {
+	// step 1: define query (keyset pagination, user terbaru duluan, afterID = 0 berarti halaman pertama)
+	query := `
+		SELECT userid, username, fullname, avatarkey, email, password, createdat
+		FROM users
+		WHERE deletedat IS NULL AND ($2 = 0 OR (createdat, userid) < ($1, $2))
+		ORDER BY createdat DESC, userid DESC
+		LIMIT $3;`
 
 	// step 2: execute query
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := db.QueryContext(ctx, query, afterCreatedAt, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -120,16 +128,11 @@ func (r *UserRepositoryImpl) FindAll(ctx context.Context, db *sql.DB) ([]*entity
 	for rows.Next() {
 		// Create a new User instance for each row and scan the values into it
 		var user entity.User
-		err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.ProfileUrl, &user.Email, &user.Password, &user.CreatedAt)
+		err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.AvatarKey, &user.Email, &user.Password, &user.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
 
-		// Check if ProfileUrl is NULL and set default value
-		if !user.ProfileUrl.Valid {
-			user.ProfileUrl.String = defaultProfileUrl // Default value if NULL
-		}
-
 		// Append the user to the slice
 		users = append(users, &user)
 	}
@@ -144,15 +147,15 @@ func (r *UserRepositoryImpl) FindAll(ctx context.Context, db *sql.DB) ([]*entity
 }
 
 func (r *UserRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, id int, user *entity.User) (*entity.User, error) {
-	// step 1: define query-nya
-	query := `UPDATE users SET username = $1, fullname = $2, email = $3, password = $4, profileurl = $5 WHERE userid = $6 RETURNING userid, username, fullname, email, password, profileurl, createdat`
+	// step 1: define query-nya (kalau email-nya diganti, status verifikasinya ikut direset)
+	query := `UPDATE users SET username = $1, fullname = $2, email = $3, password = $4, emailverifiedat = CASE WHEN email = $3 THEN emailverifiedat END WHERE userid = $5 RETURNING userid, username, fullname, email, password, avatarkey, createdat`
 
 	// step 2: execute query-nya
-	row := tx.QueryRowContext(ctx, query, user.Username, user.Fullname, user.Email, user.Password, user.ProfileUrl.String, id)
+	row := tx.QueryRowContext(ctx, query, user.Username, user.Fullname, user.Email, user.Password, id)
 
 	// step 3: scan hasilnya ke dalam struct user untuk di return.
 	var updatedUser entity.User
-	err := row.Scan(&updatedUser.ID, &updatedUser.Username, &updatedUser.Fullname, &updatedUser.Email, &updatedUser.Password, &updatedUser.ProfileUrl, &updatedUser.CreatedAt)
+	err := row.Scan(&updatedUser.ID, &updatedUser.Username, &updatedUser.Fullname, &updatedUser.Email, &updatedUser.Password, &updatedUser.AvatarKey, &updatedUser.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +176,7 @@ func (r *UserRepositoryImpl) FindByIDs(ctx context.Context, db *sql.DB, ids []in
 	}
 
 	// step 2: buat query-nya
-	query := fmt.Sprintf("SELECT userid, username, fullname, profileurl, email, password, createdat FROM users WHERE userid IN (%s)", strings.Join(placeholders, ","))
+	query := fmt.Sprintf("SELECT userid, username, fullname, avatarkey, email, password, createdat FROM users WHERE userid IN (%s)", strings.Join(placeholders, ","))
 
 	// step 3: execute query-nya
 	rows, err := db.QueryContext(ctx, query, args...)
@@ -186,13 +189,10 @@ func (r *UserRepositoryImpl) FindByIDs(ctx context.Context, db *sql.DB, ids []in
 	var users []*entity.User
 	for rows.Next() {
 		var user entity.User
-		err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.ProfileUrl, &user.Email, &user.Password, &user.CreatedAt)
+		err := rows.Scan(&user.ID, &user.Username, &user.Fullname, &user.AvatarKey, &user.Email, &user.Password, &user.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
-		if !user.ProfileUrl.Valid {
-			user.ProfileUrl.String = defaultProfileUrl // Default value if NULL
-		}
 		users = append(users, &user)
 	}
 
@@ -205,4 +205,140 @@ func (r *UserRepositoryImpl) FindByIDs(ctx context.Context, db *sql.DB, ids []in
 	return users, nil
 }
 
-const defaultProfileUrl = "https://upload.wikimedia.org/wikipedia/commons/a/ac/Default_pfp.jpg"
\ No newline at end of file
+// UpdateAvatar mengganti avatar user dan mengembalikan key avatar sebelumnya (supaya file lamanya bisa dihapus).
+// Baris user-nya dikunci dulu, jadi dua upload yang bersamaan tidak bisa sama-sama mengira dirinya mengganti avatar yang sama.
+func (r *UserRepositoryImpl) UpdateAvatar(ctx context.Context, db *sql.DB, id int, avatarKey sql.NullString) (sql.NullString, error) {
+	query := `
+		UPDATE users u SET avatarkey = $1
+		FROM (SELECT userid, avatarkey FROM users WHERE userid = $2 FOR UPDATE) old
+		WHERE u.userid = old.userid
+		RETURNING old.avatarkey`
+
+	var previous sql.NullString
+	err := db.QueryRowContext(ctx, query, avatarKey, id).Scan(&previous)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sql.NullString{}, fmt.Errorf("user not found")
+		}
+		return sql.NullString{}, err
+	}
+	return previous, nil
+}
+
+// Suspend tidak pernah memperpendek suspend yang sudah ada
+func (r *UserRepositoryImpl) Suspend(ctx context.Context, tx *sql.Tx, id int, until time.Time) error {
+	query := `UPDATE users SET suspendeduntil = GREATEST(COALESCE(suspendeduntil, $2), $2) WHERE userid = $1`
+	_, err := tx.ExecContext(ctx, query, id, until)
+	return err
+}
+
+// Unsuspend return false kalau user-nya memang tidak sedang di-suspend
+func (r *UserRepositoryImpl) Unsuspend(ctx context.Context, tx *sql.Tx, id int) (bool, error) {
+	query := `UPDATE users SET suspendeduntil = NULL WHERE userid = $1 AND suspendeduntil > NOW()`
+	return execChanged(tx.ExecContext(ctx, query, id))
+}
+
+// Ban return false kalau user-nya sudah di-ban sebelumnya (alasan ban yang lama tidak ditimpa)
+func (r *UserRepositoryImpl) Ban(ctx context.Context, tx *sql.Tx, id int, reason string) (bool, error) {
+	query := `UPDATE users SET bannedat = NOW(), banreason = $2 WHERE userid = $1 AND bannedat IS NULL`
+	return execChanged(tx.ExecContext(ctx, query, id, reason))
+}
+
+// Unban return false kalau user-nya memang tidak sedang di-ban
+func (r *UserRepositoryImpl) Unban(ctx context.Context, tx *sql.Tx, id int) (bool, error) {
+	query := `UPDATE users SET bannedat = NULL, banreason = NULL WHERE userid = $1 AND bannedat IS NOT NULL`
+	return execChanged(tx.ExecContext(ctx, query, id))
+}
+
+func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, tx *sql.Tx, id int, password string) error {
+	changed, err := execChanged(tx.ExecContext(ctx, `UPDATE users SET password = $2 WHERE userid = $1`, id, password))
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// FindRestriction return nil kalau user tidak sedang di-suspend atau di-ban
+func (r *UserRepositoryImpl) FindRestriction(ctx context.Context, db *sql.DB, id int) (*entity.AccountRestriction, error) {
+	restrictions, err := r.FindRestrictions(ctx, db, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	return restrictions[id], nil
+}
+
+// FindRestrictions cuma mengisi user yang sedang di-suspend, di-ban, atau sudah dihapus. Suspend yang sudah lewat dianggap tidak ada.
+func (r *UserRepositoryImpl) FindRestrictions(ctx context.Context, db *sql.DB, ids []int) (map[int]*entity.AccountRestriction, error) {
+	result := make(map[int]*entity.AccountRestriction)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT userid,
+			CASE WHEN suspendeduntil > NOW() THEN suspendeduntil END,
+			bannedat, COALESCE(banreason, ''), deletedat
+		FROM users
+		WHERE userid = ANY($1) AND (suspendeduntil > NOW() OR bannedat IS NOT NULL OR deletedat IS NOT NULL)`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		var suspendedUntil, bannedAt, deletedAt sql.NullTime
+		var restriction entity.AccountRestriction
+		if err := rows.Scan(&userID, &suspendedUntil, &bannedAt, &restriction.BanReason, &deletedAt); err != nil {
+			return nil, err
+		}
+		if suspendedUntil.Valid {
+			restriction.SuspendedUntil = &suspendedUntil.Time
+		}
+		if bannedAt.Valid {
+			restriction.BannedAt = &bannedAt.Time
+		}
+		if deletedAt.Valid {
+			restriction.DeletedAt = &deletedAt.Time
+		}
+		result[userID] = &restriction
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindIDByEmail return 0 kalau tidak ada user dengan email tersebut
+func (r *UserRepositoryImpl) FindIDByEmail(ctx context.Context, db *sql.DB, email string) (int, error) {
+	var id int
+	err := db.QueryRowContext(ctx, `SELECT userid FROM users WHERE email = $1`, email).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *UserRepositoryImpl) IsEmailVerified(ctx context.Context, db *sql.DB, id int) (bool, error) {
+	var verified bool
+	err := db.QueryRowContext(ctx, `SELECT emailverifiedat IS NOT NULL FROM users WHERE userid = $1`, id).Scan(&verified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("user not found")
+		}
+		return false, err
+	}
+	return verified, nil
+}
+
+// MarkEmailVerified hanya berlaku kalau email user masih sama dengan email yang diverifikasi,
+// return false kalau email-nya sudah diganti atau memang sudah terverifikasi
+func (r *UserRepositoryImpl) MarkEmailVerified(ctx context.Context, tx *sql.Tx, id int, email string) (bool, error) {
+	query := `UPDATE users SET emailverifiedat = NOW() WHERE userid = $1 AND email = $2 AND emailverifiedat IS NULL`
+	return execChanged(tx.ExecContext(ctx, query, id, email))
+}
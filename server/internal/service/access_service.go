@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/repository"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrAccountSuspended = errors.New("account suspended")
+	ErrAccountBanned    = errors.New("account banned")
+	ErrAccountDeleted   = errors.New("account deleted")
+)
+
+// Status akun di-cache sebentar saja, perubahan dari admin langsung menghapus cache-nya lewat Invalidate
+const accessCacheTTL = time.Minute
+
+// AccessService dipakai middleware.Authenticate di setiap request, supaya suspend/ban dan perubahan role
+// langsung berlaku walaupun token user yang lama belum expired
+type AccessService interface {
+	CheckAccess(ctx context.Context, userID int) ([]string, error)
+	HasPermission(ctx context.Context, userID int, permission string) (bool, error)
+	Invalidate(ctx context.Context, userID int)
+}
+
+type AccessServiceImpl struct {
+	DB             *sql.DB
+	UserRepository repository.UserRepository
+	RoleRepository repository.RoleRepository
+	RedisClient    *redis.Client
+}
+
+func NewAccessService(db *sql.DB, userRepository repository.UserRepository, roleRepository repository.RoleRepository, redisClient *redis.Client) AccessService {
+	return &AccessServiceImpl{
+		DB:             db,
+		UserRepository: userRepository,
+		RoleRepository: roleRepository,
+		RedisClient:    redisClient,
+	}
+}
+
+type cachedAccess struct {
+	Permissions []string                   `json:"permissions"`
+	Restriction *entity.AccountRestriction `json:"restriction"`
+}
+
+func accessCacheKey(userID int) string {
+	return fmt.Sprintf("user:%d:access:v1", userID)
+}
+
+// CheckAccess mengembalikan permission terbaru user, atau ErrAccountSuspended/ErrAccountBanned kalau akunnya dibatasi
+func (s *AccessServiceImpl) CheckAccess(ctx context.Context, userID int) ([]string, error) {
+	// step 1: cek cache dulu
+	cacheKey := accessCacheKey(userID)
+	if cached, err := s.RedisClient.Get(ctx, cacheKey).Result(); err == nil {
+		var access cachedAccess
+		if err := json.Unmarshal([]byte(cached), &access); err == nil {
+			return access.Permissions, restrictionError(access.Restriction)
+		}
+	}
+
+	// step 2: ambil dari database
+	restriction, err := s.UserRepository.FindRestriction(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.RoleRepository.FindPermissionsByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: simpan ke cache
+	if jsonVal, err := json.Marshal(cachedAccess{Permissions: permissions, Restriction: restriction}); err == nil {
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonVal, accessCacheTTL).Err()
+	}
+
+	return permissions, restrictionError(restriction)
+}
+
+// HasPermission dipakai service lain untuk pengecekan yang bergantung pada datanya (misal: pemilik post atau moderator).
+// Akun yang sedang di-suspend/ban dianggap tidak punya permission apa pun.
+func (s *AccessServiceImpl) HasPermission(ctx context.Context, userID int, permission string) (bool, error) {
+	permissions, err := s.CheckAccess(ctx, userID)
+	if errors.Is(err, ErrAccountSuspended) || errors.Is(err, ErrAccountBanned) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+func (s *AccessServiceImpl) Invalidate(ctx context.Context, userID int) {
+	_ = s.RedisClient.Del(ctx, accessCacheKey(userID)).Err()
+}
+
+// restrictionError dipakai bersama oleh login dan pengecekan di setiap request.
+// Waktu suspend dicek ulang di sini karena status yang di-cache bisa saja sudah lewat masa suspend-nya.
+func restrictionError(restriction *entity.AccountRestriction) error {
+	if restriction == nil {
+		return nil
+	}
+	if restriction.DeletedAt != nil {
+		return ErrAccountDeleted
+	}
+	if restriction.BannedAt != nil {
+		if restriction.BanReason != "" {
+			return fmt.Errorf("%w: %s", ErrAccountBanned, restriction.BanReason)
+		}
+		return ErrAccountBanned
+	}
+	if restriction.SuspendedUntil != nil && restriction.SuspendedUntil.After(time.Now()) {
+		return fmt.Errorf("%w until %s", ErrAccountSuspended, restriction.SuspendedUntil.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,768 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mood-bridge-v2/server/infrastructure/storage"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/media"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/repository"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrInvalidPassword          = errors.New("invalid password")
+	ErrDeletionAlreadyScheduled = errors.New("account deletion is already scheduled")
+	ErrDeletionNotScheduled     = errors.New("account deletion is not scheduled")
+	ErrLastAdminDeletion        = errors.New("the last admin can't delete their account, assign another admin first")
+	ErrExportNotFound           = errors.New("data export not found")
+	ErrExportNotReady           = errors.New("data export is not ready yet")
+)
+
+const (
+	accountDeletionGraceEnv = "ACCOUNT_DELETION_GRACE_DAYS"
+	// Akun baru benar-benar dihapus setelah masa tenggang ini, selama itu user masih bisa membatalkannya
+	defaultAccountDeletionGrace = 30 * 24 * time.Hour
+	// Arsip ekspor dihapus dari storage setelah waktu ini, ekspor gagal juga dibersihkan setelah waktu yang sama
+	dataExportRetention = 7 * 24 * time.Hour
+	dataExportTimeout   = 10 * time.Minute
+	// Ekspor yang statusnya "processing" lebih lama dari ini dianggap macet dan diproses ulang oleh job
+	dataExportStaleAfter = 30 * time.Minute
+	dataExportListLimit  = 10
+	accountJobBatchSize  = 20
+)
+
+// AIConversationStore dipenuhi DialoGPTService, history chat AI cuma ada di memori server
+type AIConversationStore interface {
+	History(userID int) string
+	ClearHistory(userID int)
+}
+
+// JournalExporter dipenuhi JournalService, isi jurnal harus didekripsi dulu sebelum masuk arsip
+type JournalExporter interface {
+	Export(ctx context.Context, userID int) ([]*response.JournalEntryResponse, error)
+}
+
+type AccountService interface {
+	RequestExport(ctx context.Context, userID int) (*response.DataExportResponse, error)
+	GetExports(ctx context.Context, userID int) ([]*response.DataExportResponse, error)
+	DownloadExport(ctx context.Context, userID, exportID int) ([]byte, error)
+	RequestDeletion(ctx context.Context, userID int, password string) (*response.AccountDeletionResponse, error)
+	CancelDeletion(ctx context.Context, userID int) (*response.AccountDeletionResponse, error)
+	GetDeletion(ctx context.Context, userID int) (*response.AccountDeletionResponse, error)
+	RunJob(ctx context.Context, interval time.Duration)
+}
+
+type AccountServiceImpl struct {
+	DB                   *sql.DB
+	AccountRepository    repository.AccountRepository
+	DataExportRepository repository.DataExportRepository
+	UserRepository       repository.UserRepository
+	RoleRepository       repository.RoleRepository
+	ProfileRepository    repository.ProfileRepository
+	AIContextRepository  repository.AIContextRepository
+	Journal              JournalExporter
+	AI                   AIConversationStore // nil di job background, history-nya ada di instance milik handler
+	AccessService        AccessService
+	Storage              storage.Storage
+	RedisClient          *redis.Client
+	audit                AuditLogger
+	deletionGrace        time.Duration
+}
+
+func NewAccountService(db *sql.DB, accountRepository repository.AccountRepository, dataExportRepository repository.DataExportRepository, userRepository repository.UserRepository, roleRepository repository.RoleRepository, profileRepository repository.ProfileRepository, aiContextRepository repository.AIContextRepository, journal JournalExporter, ai AIConversationStore, accessService AccessService, mediaStorage storage.Storage, redisClient *redis.Client, audit AuditLogger) AccountService {
+	deletionGrace := defaultAccountDeletionGrace
+	if value := os.Getenv(accountDeletionGraceEnv); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			deletionGrace = time.Duration(days) * 24 * time.Hour
+		} else {
+			log.Printf("invalid %s %q, using the default of %s", accountDeletionGraceEnv, value, defaultAccountDeletionGrace)
+		}
+	}
+
+	return &AccountServiceImpl{
+		DB:                   db,
+		AccountRepository:    accountRepository,
+		DataExportRepository: dataExportRepository,
+		UserRepository:       userRepository,
+		RoleRepository:       roleRepository,
+		ProfileRepository:    profileRepository,
+		AIContextRepository:  aiContextRepository,
+		Journal:              journal,
+		AI:                   ai,
+		AccessService:        accessService,
+		Storage:              mediaStorage,
+		RedisClient:          redisClient,
+		audit:                audit,
+		deletionGrace:        deletionGrace,
+	}
+}
+
+// RequestExport membuat arsip di background. Kalau masih ada ekspor yang sedang diproses atau arsip yang belum expired,
+// ekspor itu yang dikembalikan supaya user tidak bisa membuat banyak arsip sekaligus.
+func (s *AccountServiceImpl) RequestExport(ctx context.Context, userID int) (*response.DataExportResponse, error) {
+	// step 1: pakai ekspor yang masih aktif kalau ada
+	export, err := s.DataExportRepository.FindActive(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export != nil {
+		return toDataExportResponse(export), nil
+	}
+
+	// step 2: buat ekspor baru
+	export, err = s.DataExportRepository.Create(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditDataExportRequested,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+		Metadata:   map[string]interface{}{"export_id": export.ExportID},
+	})
+
+	// step 3: proses di background, tetap jalan walaupun request-nya sudah selesai.
+	// Kalau server mati di tengah jalan, job background yang melanjutkannya.
+	go func() {
+		exportCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dataExportTimeout)
+		defer cancel()
+		s.processExport(exportCtx, export.ExportID)
+	}()
+
+	return toDataExportResponse(export), nil
+}
+
+func (s *AccountServiceImpl) GetExports(ctx context.Context, userID int) ([]*response.DataExportResponse, error) {
+	exports, err := s.DataExportRepository.FindByUserID(ctx, s.DB, userID, dataExportListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	exportResponses := make([]*response.DataExportResponse, 0, len(exports))
+	for _, export := range exports {
+		exportResponses = append(exportResponses, toDataExportResponse(export))
+	}
+	return exportResponses, nil
+}
+
+// DownloadExport arsipnya tidak pernah dibagikan lewat URL storage, selalu lewat endpoint yang butuh login ini
+func (s *AccountServiceImpl) DownloadExport(ctx context.Context, userID, exportID int) ([]byte, error) {
+	// step 1: ekspor milik user lain dianggap tidak ada
+	export, err := s.DataExportRepository.FindByID(ctx, s.DB, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil || export.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+
+	// step 2: pastikan arsipnya sudah jadi dan belum expired
+	if export.Status != entity.DataExportReady || export.StorageKey == "" {
+		return nil, ErrExportNotReady
+	}
+	if export.ExpiresAt != nil && !export.ExpiresAt.After(time.Now()) {
+		return nil, ErrExportNotFound
+	}
+
+	return s.Storage.Get(ctx, export.StorageKey)
+}
+
+// RequestDeletion menjadwalkan penghapusan akun setelah masa tenggang. Password diminta ulang
+// supaya token yang bocor saja tidak cukup untuk menghapus akun orang.
+func (s *AccountServiceImpl) RequestDeletion(ctx context.Context, userID int, password string) (*response.AccountDeletionResponse, error) {
+	// step 1: cek password-nya
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Password != password {
+		return nil, ErrInvalidPassword
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: rollback
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// step 4: admin terakhir tidak boleh menghapus akunnya, nanti tidak ada yang bisa mengelola role lagi
+	if err = s.ensureNotLastAdmin(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	// step 5: jadwalkan penghapusannya
+	scheduledAt := time.Now().Add(s.deletionGrace)
+	scheduled, err := s.AccountRepository.ScheduleDeletion(ctx, tx, userID, scheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	if !scheduled {
+		err = ErrDeletionAlreadyScheduled
+		return nil, err
+	}
+
+	// step 6: commit
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditDeletionRequested,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+		Metadata:   map[string]interface{}{"scheduled_at": scheduledAt.UTC().Format(time.RFC3339)},
+	})
+	return &response.AccountDeletionResponse{ScheduledAt: &scheduledAt}, nil
+}
+
+func (s *AccountServiceImpl) CancelDeletion(ctx context.Context, userID int) (*response.AccountDeletionResponse, error) {
+	cancelled, err := s.AccountRepository.CancelDeletion(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrDeletionNotScheduled
+	}
+
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditDeletionCancelled,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+	})
+	return &response.AccountDeletionResponse{}, nil
+}
+
+func (s *AccountServiceImpl) GetDeletion(ctx context.Context, userID int) (*response.AccountDeletionResponse, error) {
+	scheduledAt, err := s.AccountRepository.FindDeletionSchedule(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.AccountDeletionResponse{ScheduledAt: scheduledAt}, nil
+}
+
+// RunJob memproses ekspor yang tertunda, membersihkan arsip yang expired, lalu menghapus akun yang masa tenggangnya sudah lewat
+func (s *AccountServiceImpl) RunJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.runPendingExports(ctx)
+
+		jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		if err := s.cleanupExpiredExports(jobCtx); err != nil {
+			log.Printf("failed to clean up expired data exports: %v", err)
+		}
+		cancel()
+
+		jobCtx, cancel = context.WithTimeout(ctx, 5*time.Minute)
+		if err := s.purgeDueAccounts(jobCtx); err != nil {
+			log.Printf("failed to purge deleted accounts: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *AccountServiceImpl) runPendingExports(ctx context.Context) {
+	exportIDs, err := s.DataExportRepository.FindPendingIDs(ctx, s.DB, time.Now().Add(-dataExportStaleAfter), accountJobBatchSize)
+	if err != nil {
+		log.Printf("failed to find pending data exports: %v", err)
+		return
+	}
+	for _, exportID := range exportIDs {
+		exportCtx, cancel := context.WithTimeout(ctx, dataExportTimeout)
+		s.processExport(exportCtx, exportID)
+		cancel()
+	}
+}
+
+// processExport membuat arsip lalu menyimpannya ke storage. Gagal di tengah jalan ditandai "failed" supaya user bisa minta ulang.
+func (s *AccountServiceImpl) processExport(ctx context.Context, exportID int) {
+	// step 1: ambil alih ekspornya, kalau sudah diambil proses lain berarti tidak perlu dikerjakan di sini
+	claimed, err := s.DataExportRepository.Claim(ctx, s.DB, exportID, time.Now().Add(-dataExportStaleAfter))
+	if err != nil {
+		log.Printf("failed to claim data export %d: %v", exportID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+	export, err := s.DataExportRepository.FindByID(ctx, s.DB, exportID)
+	if err != nil || export == nil {
+		log.Printf("failed to load data export %d: %v", exportID, err)
+		return
+	}
+
+	// step 2: buat arsipnya lalu simpan dengan key acak, jadi file-nya tidak bisa ditebak walaupun storage-nya publik
+	storageKey, size, err := s.storeArchive(ctx, export.UserID)
+	if err != nil {
+		log.Printf("failed to build data export %d: %v", exportID, err)
+		if markErr := s.DataExportRepository.MarkFailed(context.WithoutCancel(ctx), s.DB, exportID, "failed to build the archive, please request a new export"); markErr != nil {
+			log.Printf("failed to mark data export %d as failed: %v", exportID, markErr)
+		}
+		return
+	}
+
+	// step 3: tandai sudah jadi, arsipnya dibuang lagi kalau ekspornya sudah tidak ada
+	ready, err := s.DataExportRepository.MarkReady(ctx, s.DB, exportID, storageKey, size, time.Now().Add(dataExportRetention))
+	if err != nil || !ready {
+		if err != nil {
+			log.Printf("failed to mark data export %d as ready: %v", exportID, err)
+		}
+		deleteMediaObjects(context.WithoutCancel(ctx), s.Storage, storageKey)
+	}
+}
+
+func (s *AccountServiceImpl) storeArchive(ctx context.Context, userID int) (string, int64, error) {
+	archive, err := s.buildArchive(ctx, userID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", 0, err
+	}
+	storageKey := fmt.Sprintf("exports/%d/%s.zip", userID, hex.EncodeToString(suffix))
+	if err := s.Storage.Put(ctx, storageKey, archive, "application/zip"); err != nil {
+		return "", 0, err
+	}
+	return storageKey, int64(len(archive)), nil
+}
+
+// buildArchive mengumpulkan semua data user ke dalam satu file zip: JSON untuk datanya dan file asli untuk media
+func (s *AccountServiceImpl) buildArchive(ctx context.Context, userID int) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	writeJSON := func(name string, value interface{}) error {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	writeObject := func(name, key string) error {
+		data, err := s.Storage.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", key, err)
+		}
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(data)
+		return err
+	}
+
+	// step 1: akun, profil, role, dan pengaturan AI
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.RoleRepository.FindRolesByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.ProfileRepository.FindByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	aiSettings, err := s.AIContextRepository.FindSettings(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	deletionScheduledAt, err := s.AccountRepository.FindDeletionSchedule(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	account := response.AccountExport{
+		UserID:          user.ID,
+		Username:        user.Username,
+		Fullname:        user.Fullname,
+		Email:           user.Email,
+		CreatedAt:       user.CreatedAt,
+		Roles:           nonNilStrings(roles),
+		Profile:         profile,
+		AIContext:       aiSettings,
+		ExportedAt:      time.Now(),
+		DeletionPending: deletionScheduledAt,
+	}
+	if err := writeJSON("account.json", account); err != nil {
+		return nil, err
+	}
+	if user.AvatarKey.Valid {
+		if err := writeObject("media/avatar.jpg", avatarObjectKey(user.AvatarKey.String, media.AvatarLarge)); err != nil {
+			return nil, err
+		}
+	}
+
+	// step 2: postingan beserta file medianya
+	posts, err := s.AccountRepository.FindPostsByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	mediaItems, err := s.AccountRepository.FindMediaByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	mediaByPost := make(map[int][]string)
+	for _, item := range mediaItems {
+		name := fmt.Sprintf("media/posts/%d_%d%s", *item.PostID, item.MediaID, path.Ext(item.StorageKey))
+		if err := writeObject(name, item.StorageKey); err != nil {
+			return nil, err
+		}
+		mediaByPost[*item.PostID] = append(mediaByPost[*item.PostID], name)
+	}
+
+	var moods []response.MoodEntryExport
+	postExports := make([]response.PostExport, 0, len(posts))
+	for _, post := range posts {
+		postExports = append(postExports, response.PostExport{
+			PostID:       post.PostID,
+			Content:      post.Content,
+			Mood:         post.Mood,
+			DeclaredMood: post.DeclaredMood,
+			Visibility:   post.Visibility,
+			IsAnonymous:  post.IsAnonymous,
+			CreatedAt:    post.CreatedAt,
+			EditedAt:     post.EditedAt,
+			Media:        nonNilStrings(mediaByPost[post.PostID]),
+		})
+		mood := post.Mood
+		moods = append(moods, response.MoodEntryExport{Source: "post", SourceID: post.PostID, Mood: &mood, DeclaredMood: post.DeclaredMood, RecordedAt: post.CreatedAt})
+	}
+	if err := writeJSON("posts.json", postExports); err != nil {
+		return nil, err
+	}
+
+	// step 3: komentar
+	comments, err := s.AccountRepository.FindCommentsByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	commentExports := make([]response.CommentExport, 0, len(comments))
+	for _, comment := range comments {
+		commentExports = append(commentExports, response.CommentExport{
+			CommentID:       comment.CommentID,
+			PostID:          comment.PostID,
+			ParentCommentID: comment.ParentCommentID,
+			Content:         comment.Content,
+			Mood:            comment.Mood,
+			CreatedAt:       comment.CreatedAt,
+			EditedAt:        comment.EditedAt,
+		})
+		if comment.Mood != nil {
+			moods = append(moods, response.MoodEntryExport{Source: "comment", SourceID: comment.CommentID, Mood: comment.Mood, RecordedAt: comment.CreatedAt})
+		}
+	}
+	if err := writeJSON("comments.json", commentExports); err != nil {
+		return nil, err
+	}
+
+	// step 4: pesan yang dikirim dan diterima
+	messages, err := s.AccountRepository.FindMessagesByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	messageExports := make([]response.MessageExport, 0, len(messages))
+	for _, message := range messages {
+		messageExport := response.MessageExport{
+			MessageID:     message.ID,
+			Direction:     "sent",
+			OtherUserID:   message.RecipientID,
+			OtherUsername: message.OtherUsername,
+			Content:       message.Content,
+			Status:        string(message.Status),
+			Timestamp:     message.Timestamp,
+		}
+		if message.SenderID != userID {
+			messageExport.Direction = "received"
+			messageExport.OtherUserID = message.SenderID
+		}
+		messageExports = append(messageExports, messageExport)
+	}
+	if err := writeJSON("messages.json", messageExports); err != nil {
+		return nil, err
+	}
+
+	// step 5: teman dan permintaan pertemanan
+	friends, err := s.AccountRepository.FindFriendsByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	friendExports := make([]response.FriendExport, 0, len(friends))
+	for _, friend := range friends {
+		status := "accepted"
+		if !friend.Accepted && friend.Outgoing {
+			status = "request_sent"
+		} else if !friend.Accepted {
+			status = "request_received"
+		}
+		friendExports = append(friendExports, response.FriendExport{
+			UserID:    friend.UserID,
+			Username:  friend.Username,
+			Fullname:  friend.Fullname,
+			Status:    status,
+			CreatedAt: friend.CreatedAt,
+		})
+	}
+	if err := writeJSON("friends.json", friendExports); err != nil {
+		return nil, err
+	}
+
+	// step 6: jurnal (sudah didekripsi) dan riwayat mood dari semua sumber
+	journal, err := s.Journal.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeJSON("journal.json", journal); err != nil {
+		return nil, err
+	}
+	for _, entry := range journal {
+		recordedAt, err := time.Parse("2006-01-02", entry.EntryDate)
+		if err != nil {
+			recordedAt = entry.CreatedAt
+		}
+		moods = append(moods, response.MoodEntryExport{Source: "journal", SourceID: entry.EntryID, Mood: entry.PredictedMood, SelfMood: entry.SelfMood, RecordedAt: recordedAt})
+	}
+	if moods == nil {
+		moods = []response.MoodEntryExport{}
+	}
+	if err := writeJSON("mood_history.json", moods); err != nil {
+		return nil, err
+	}
+
+	// step 7: percakapan dengan AI
+	conversation := response.AIConversationExport{Note: "AI conversations are only kept in memory until the server restarts"}
+	if s.AI != nil {
+		conversation.Transcript = s.AI.History(userID)
+	}
+	if err := writeJSON("ai_conversations.json", conversation); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *AccountServiceImpl) cleanupExpiredExports(ctx context.Context) error {
+	exports, err := s.DataExportRepository.FindExpired(ctx, s.DB, time.Now().Add(-dataExportRetention), accountJobBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.StorageKey != "" {
+			deleteMediaObjects(ctx, s.Storage, export.StorageKey)
+		}
+		if _, err := s.DataExportRepository.Delete(ctx, s.DB, export.ExportID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AccountServiceImpl) purgeDueAccounts(ctx context.Context) error {
+	userIDs, err := s.AccountRepository.FindDueDeletions(ctx, s.DB, accountJobBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := s.purgeAccount(ctx, userID); err != nil {
+			log.Printf("failed to purge account %d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// purgeAccount menghapus data user di database, lalu file di storage dan cache yang masih menyimpan datanya
+func (s *AccountServiceImpl) purgeAccount(ctx context.Context, userID int) (err error) {
+	// step 1: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	// step 2: rollback
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// step 3: cek ulang admin terakhir, bisa saja admin lain ikut menghapus akunnya selama masa tenggang
+	if err = s.ensureNotLastAdmin(ctx, tx, userID); err != nil {
+		if errors.Is(err, ErrLastAdminDeletion) {
+			_ = tx.Rollback()
+			log.Printf("account %d is the last admin, cancelling its scheduled deletion", userID)
+			_, cancelErr := s.AccountRepository.CancelDeletion(ctx, s.DB, userID)
+			return cancelErr
+		}
+		return err
+	}
+
+	// step 4: hapus dan anonimkan datanya
+	result, err := s.AccountRepository.Purge(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		// jadwalnya baru saja dibatalkan
+		return tx.Commit()
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	// step 5: bersihkan file, history AI, dan cache-nya
+	objectKeys := result.ObjectKeys
+	if result.AvatarKey.Valid {
+		objectKeys = append(objectKeys, avatarObjectKeys(result.AvatarKey.String)...)
+	}
+	deleteMediaObjects(ctx, s.Storage, objectKeys...)
+	if s.AI != nil {
+		s.AI.ClearHistory(userID)
+	}
+	s.AccessService.Invalidate(ctx, userID)
+	s.invalidateCaches(ctx, userID, result)
+
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditAccountDeleted,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+		Metadata: map[string]interface{}{
+			"posts":    len(result.PostIDs),
+			"comments": len(result.CommentIDs),
+			"friends":  len(result.FriendIDs),
+		},
+	})
+	return nil
+}
+
+func (s *AccountServiceImpl) ensureNotLastAdmin(ctx context.Context, tx *sql.Tx, userID int) error {
+	roles, err := s.RoleRepository.FindRolesByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return err
+	}
+	for _, roleName := range roles {
+		if roleName != entity.RoleAdmin {
+			continue
+		}
+		role, err := s.RoleRepository.FindByName(ctx, s.DB, entity.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return nil
+		}
+		admins, err := s.RoleRepository.CountUsersWithRole(ctx, tx, role.RoleID)
+		if err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return ErrLastAdminDeletion
+		}
+	}
+	return nil
+}
+
+// invalidateCaches membuang semua cache yang masih berisi data user yang dihapus
+func (s *AccountServiceImpl) invalidateCaches(ctx context.Context, userID int, result *repository.AccountPurgeResult) {
+	// step 1: postingan user, postingan yang pernah dia beri reaksi atau komentar, dan semua list postingan
+	var keys []string
+	for _, ids := range [][]int{result.PostIDs, result.ReactedPostIDs, result.CommentPostIDs} {
+		for _, postID := range ids {
+			keys = append(keys, postCacheKey(postID))
+		}
+	}
+	for _, commentID := range result.CommentIDs {
+		keys = append(keys, commentCacheKey(commentID))
+	}
+	keys = append(keys, fmt.Sprintf("comment:user:%d:v%d", userID, cacheVersion))
+
+	// step 2: feed, daftar teman, dan rekomendasi user itu sendiri beserta mantan temannya
+	for _, id := range append([]int{userID}, result.FriendIDs...) {
+		keys = append(keys, feedKey(id), feedMetaKey(id))
+		_ = s.RedisClient.Incr(ctx, friendListGenerationKey("friend", id)).Err()
+		_ = s.RedisClient.Incr(ctx, friendListGenerationKey("friendrequest", id)).Err()
+		_ = s.RedisClient.Incr(ctx, friendListGenerationKey("friendoutgoing", id)).Err()
+	}
+	for start := 0; start < len(keys); start += 500 {
+		end := min(start+500, len(keys))
+		_ = s.RedisClient.Del(ctx, keys[start:end]...).Err()
+	}
+	_ = s.RedisClient.Incr(ctx, postListGenerationKey).Err()
+	for _, postID := range result.CommentPostIDs {
+		_ = s.RedisClient.Incr(ctx, commentPageGenerationKey(postID)).Err()
+	}
+
+	// step 3: user ini bisa muncul di rekomendasi teman siapa saja, jadi semua cache rekomendasi dibuang
+	s.deleteKeysMatching(ctx, "friendrecommendation:*")
+}
+
+func (s *AccountServiceImpl) deleteKeysMatching(ctx context.Context, pattern string) {
+	iter := s.RedisClient.Scan(ctx, 0, pattern, 500).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 500 {
+			_ = s.RedisClient.Del(ctx, keys...).Err()
+			keys = keys[:0]
+		}
+	}
+	if len(keys) > 0 {
+		_ = s.RedisClient.Del(ctx, keys...).Err()
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("failed to scan redis keys %s: %v", pattern, err)
+	}
+}
+
+func toDataExportResponse(export *entity.DataExport) *response.DataExportResponse {
+	exportResponse := &response.DataExportResponse{
+		ExportID:    export.ExportID,
+		Status:      export.Status,
+		SizeBytes:   export.SizeBytes,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == entity.DataExportReady {
+		exportResponse.DownloadURL = fmt.Sprintf("/api/user/export/%d/download", export.ExportID)
+	}
+	return exportResponse
+}
@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"strings"
+	"time"
+)
+
+var ErrAdminExists = errors.New("an admin already exists, assign more admins through /api/admin")
+
+type AdminService interface {
+	GetUsers(ctx context.Context, cursor string, limit int) (*pagination.Page[*response.AdminUserResponse], error)
+	GetUser(ctx context.Context, userID int) (*response.AdminUserResponse, error)
+	GetRoles(ctx context.Context) ([]*response.RoleResponse, error)
+	Suspend(ctx context.Context, adminID, userID int, req request.AdminSuspendRequest) (*response.AdminUserResponse, error)
+	Unsuspend(ctx context.Context, adminID, userID int) (*response.AdminUserResponse, error)
+	Ban(ctx context.Context, adminID, userID int, req request.AdminBanRequest) (*response.AdminUserResponse, error)
+	Unban(ctx context.Context, adminID, userID int) (*response.AdminUserResponse, error)
+	ResetPassword(ctx context.Context, adminID, userID int) (*response.PasswordResetResponse, error)
+	AssignRole(ctx context.Context, adminID, userID int, roleName string) (*response.AdminUserResponse, error)
+	RevokeRole(ctx context.Context, adminID, userID int, roleName string) (*response.AdminUserResponse, error)
+	ResetMFA(ctx context.Context, adminID, userID int) (*response.AdminUserResponse, error)
+	SetRoleMFA(ctx context.Context, adminID int, roleName string, required bool) (*response.RoleResponse, error)
+	BootstrapAdmin(ctx context.Context, username string) error
+}
+
+type AdminServiceImpl struct {
+	DB                   *sql.DB
+	UserRepository       repository.UserRepository
+	RoleRepository       repository.RoleRepository
+	ModerationRepository repository.ModerationRepository
+	MFARepository        repository.MFARepository
+	AccessService        AccessService
+	NotificationService  NotificationService
+	Audit                AuditLogger
+}
+
+func NewAdminService(db *sql.DB, userRepository repository.UserRepository, roleRepository repository.RoleRepository, moderationRepository repository.ModerationRepository, mfaRepository repository.MFARepository, accessService AccessService, notificationService NotificationService, audit AuditLogger) AdminService {
+	return &AdminServiceImpl{
+		DB:                   db,
+		UserRepository:       userRepository,
+		RoleRepository:       roleRepository,
+		ModerationRepository: moderationRepository,
+		MFARepository:        mfaRepository,
+		AccessService:        accessService,
+		NotificationService:  notificationService,
+		Audit:                audit,
+	}
+}
+
+func (s *AdminServiceImpl) GetUsers(ctx context.Context, cursor string, limit int) (*pagination.Page[*response.AdminUserResponse], error) {
+	// step 1: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 2: ambil user-nya, lalu role dan status akunnya sekaligus untuk satu halaman
+	users, err := s.UserRepository.FindAll(ctx, s.DB, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	userResponses, err := s.toAdminUserResponses(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewPage(userResponses, limit, func(user *response.AdminUserResponse) string {
+		return pagination.EncodeCursor(user.CreatedAt, user.UserID)
+	}), nil
+}
+
+func (s *AdminServiceImpl) GetUser(ctx context.Context, userID int) (*response.AdminUserResponse, error) {
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	userResponses, err := s.toAdminUserResponses(ctx, []*entity.User{user})
+	if err != nil {
+		return nil, err
+	}
+	return userResponses[0], nil
+}
+
+func (s *AdminServiceImpl) GetRoles(ctx context.Context) ([]*response.RoleResponse, error) {
+	roles, err := s.RoleRepository.FindAll(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	roleResponses := []*response.RoleResponse{}
+	for _, role := range roles {
+		roleResponses = append(roleResponses, &response.RoleResponse{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+			RequireMFA:  role.RequireMFA,
+		})
+	}
+	return roleResponses, nil
+}
+
+func (s *AdminServiceImpl) Suspend(ctx context.Context, adminID, userID int, req request.AdminSuspendRequest) (*response.AdminUserResponse, error) {
+	if adminID == userID {
+		return nil, fmt.Errorf("you can't suspend yourself")
+	}
+	reason := strings.TrimSpace(req.Reason)
+
+	err := s.withUserAction(ctx, adminID, userID, entity.ModerationActionSuspend, strings.TrimSpace(fmt.Sprintf("%d days. %s", req.Days, reason)), func(tx *sql.Tx) error {
+		return s.UserRepository.Suspend(ctx, tx, userID, time.Now().AddDate(0, 0, req.Days))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.NotificationService.Notify(ctx, userID, entity.NotificationAccountSuspended,
+		withModeratorNote(fmt.Sprintf("Your account has been suspended for %d days.", req.Days), reason), nil)
+	return s.GetUser(ctx, userID)
+}
+
+func (s *AdminServiceImpl) Unsuspend(ctx context.Context, adminID, userID int) (*response.AdminUserResponse, error) {
+	err := s.withUserAction(ctx, adminID, userID, entity.ModerationActionUnsuspend, "", func(tx *sql.Tx) error {
+		changed, err := s.UserRepository.Unsuspend(ctx, tx, userID)
+		if err == nil && !changed {
+			return fmt.Errorf("user is not suspended")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, userID)
+}
+
+func (s *AdminServiceImpl) Ban(ctx context.Context, adminID, userID int, req request.AdminBanRequest) (*response.AdminUserResponse, error) {
+	if adminID == userID {
+		return nil, fmt.Errorf("you can't ban yourself")
+	}
+	reason := strings.TrimSpace(req.Reason)
+
+	err := s.withUserAction(ctx, adminID, userID, entity.ModerationActionBan, reason, func(tx *sql.Tx) error {
+		changed, err := s.UserRepository.Ban(ctx, tx, userID, reason)
+		if err == nil && !changed {
+			return fmt.Errorf("user is already banned")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, userID)
+}
+
+func (s *AdminServiceImpl) Unban(ctx context.Context, adminID, userID int) (*response.AdminUserResponse, error) {
+	err := s.withUserAction(ctx, adminID, userID, entity.ModerationActionUnban, "", func(tx *sql.Tx) error {
+		changed, err := s.UserRepository.Unban(ctx, tx, userID)
+		if err == nil && !changed {
+			return fmt.Errorf("user is not banned")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, userID)
+}
+
+// ResetPassword mengganti password user dengan password sementara yang cuma ditampilkan sekali ke admin
+func (s *AdminServiceImpl) ResetPassword(ctx context.Context, adminID, userID int) (*response.PasswordResetResponse, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	temporaryPassword := base64.RawURLEncoding.EncodeToString(buf)
+
+	err := s.withUserAction(ctx, adminID, userID, entity.ModerationActionResetPassword, "", func(tx *sql.Tx) error {
+		return s.UserRepository.UpdatePassword(ctx, tx, userID, temporaryPassword)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response.PasswordResetResponse{TemporaryPassword: temporaryPassword}, nil
+}
+
+func (s *AdminServiceImpl) AssignRole(ctx context.Context, adminID, userID int, roleName string) (*response.AdminUserResponse, error) {
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withUserAction(ctx, adminID, userID, entity.ModerationActionAssignRole, role.Name, func(tx *sql.Tx) error {
+		changed, err := s.RoleRepository.Assign(ctx, tx, userID, role.RoleID, &adminID)
+		if err == nil && !changed {
+			return fmt.Errorf("user already has role %s", role.Name)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, userID)
+}
+
+func (s *AdminServiceImpl) RevokeRole(ctx context.Context, adminID, userID int, roleName string) (*response.AdminUserResponse, error) {
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+	// admin tidak bisa mencabut role admin-nya sendiri, supaya tidak ada yang tidak sengaja mengunci diri
+	if role.Name == entity.RoleAdmin && adminID == userID {
+		return nil, fmt.Errorf("you can't revoke your own admin role")
+	}
+
+	err = s.withUserAction(ctx, adminID, userID, entity.ModerationActionRevokeRole, role.Name, func(tx *sql.Tx) error {
+		if role.Name == entity.RoleAdmin {
+			admins, err := s.RoleRepository.CountUsersWithRole(ctx, tx, role.RoleID)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return fmt.Errorf("can't revoke the last admin")
+			}
+		}
+
+		changed, err := s.RoleRepository.Revoke(ctx, tx, userID, role.RoleID)
+		if err == nil && !changed {
+			return fmt.Errorf("user doesn't have role %s", role.Name)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, userID)
+}
+
+// ResetMFA dipakai kalau user kehilangan authenticator app sekaligus recovery code-nya. User bisa enroll ulang setelah login.
+func (s *AdminServiceImpl) ResetMFA(ctx context.Context, adminID, userID int) (*response.AdminUserResponse, error) {
+	err := s.withUserAction(ctx, adminID, userID, entity.ModerationActionResetMFA, "", func(tx *sql.Tx) error {
+		changed, err := s.MFARepository.Delete(ctx, tx, userID)
+		if err == nil && !changed {
+			return fmt.Errorf("user doesn't have two-factor authentication")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, userID)
+}
+
+// SetRoleMFA mewajibkan (atau tidak lagi mewajibkan) 2FA untuk satu role. Pemegang role yang belum mengaktifkan 2FA
+// kehilangan permission role itu paling lambat setelah cache akses-nya expired (accessCacheTTL).
+func (s *AdminServiceImpl) SetRoleMFA(ctx context.Context, adminID int, roleName string, required bool) (*response.RoleResponse, error) {
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	// admin yang memegang role ini harus sudah mengaktifkan 2FA, supaya tidak mengunci dirinya sendiri
+	if required {
+		roles, err := s.RoleRepository.FindRolesByUserID(ctx, s.DB, adminID)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range roles {
+			if name != role.Name {
+				continue
+			}
+			enabled, err := s.MFARepository.IsEnabled(ctx, s.DB, adminID)
+			if err != nil {
+				return nil, err
+			}
+			if !enabled {
+				return nil, fmt.Errorf("enable two-factor authentication on your own account before requiring it for role %s", role.Name)
+			}
+		}
+	}
+
+	changed, err := s.RoleRepository.SetRequireMFA(ctx, s.DB, role.RoleID, required)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		s.Audit.Log(ctx, AuditEntry{
+			ActorID:    &adminID,
+			Action:     entity.AuditRoleMFAChanged,
+			TargetType: entity.AuditTargetRole,
+			TargetID:   &role.RoleID,
+			Metadata:   map[string]interface{}{"role": role.Name, "require_mfa": required},
+		})
+	}
+
+	roles, err := s.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleResponse := range roles {
+		if roleResponse.Name == role.Name {
+			return roleResponse, nil
+		}
+	}
+	return nil, fmt.Errorf("role %s not found", role.Name)
+}
+
+// BootstrapAdmin dipakai CLI create-admin untuk menjadikan user yang sudah terdaftar sebagai admin pertama.
+// Setelah ada admin, admin berikutnya harus ditambahkan lewat /api/admin.
+func (s *AdminServiceImpl) BootstrapAdmin(ctx context.Context, username string) error {
+	// step 1: cari user dan role admin-nya
+	user, err := s.UserRepository.Find(ctx, s.DB, strings.TrimSpace(strings.ToLower(username)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("user %s not found, register the account first", username)
+		}
+		return err
+	}
+	role, err := s.findRole(ctx, entity.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	// step 3: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 4: pastikan memang belum ada admin sama sekali
+	admins, err := s.RoleRepository.CountUsersWithRole(ctx, tx, role.RoleID)
+	if err != nil {
+		return err
+	}
+	if admins > 0 {
+		err = ErrAdminExists
+		return err
+	}
+
+	// step 5: kasih role admin dan catat di log (ModeratorID nil karena dilakukan lewat CLI)
+	if _, err = s.RoleRepository.Assign(ctx, tx, user.ID, role.RoleID, nil); err != nil {
+		return err
+	}
+	err = s.ModerationRepository.LogAction(ctx, tx, &entity.ModerationAction{
+		Action:     entity.ModerationActionAssignRole,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   user.ID,
+		Note:       "bootstrap " + role.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	// step 6: commit transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	s.Audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditAdmin + entity.ModerationActionAssignRole,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &user.ID,
+		Metadata:   map[string]interface{}{"role": role.Name, "source": "cli"},
+	})
+	s.AccessService.Invalidate(ctx, user.ID)
+	return nil
+}
+
+// withUserAction menjalankan perubahan akun user dan mencatatnya ke log moderasi dalam satu transaksi,
+// lalu membuang cache status akses user tersebut supaya perubahannya langsung berlaku
+func (s *AdminServiceImpl) withUserAction(ctx context.Context, adminID, userID int, action, note string, apply func(tx *sql.Tx) error) error {
+	// step 1: pastikan user-nya ada
+	if _, err := s.UserRepository.FindByID(ctx, s.DB, userID); err != nil {
+		return err
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	// step 3: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 4: jalankan perubahan lalu catat di log
+	if err = apply(tx); err != nil {
+		return err
+	}
+	err = s.ModerationRepository.LogAction(ctx, tx, &entity.ModerationAction{
+		ModeratorID: &adminID,
+		Action:      action,
+		TargetType:  entity.AuditTargetUser,
+		TargetID:    userID,
+		Note:        note,
+	})
+	if err != nil {
+		return err
+	}
+
+	// step 5: commit transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	metadata := map[string]interface{}{}
+	if note != "" {
+		metadata["note"] = note
+	}
+	s.Audit.Log(ctx, AuditEntry{
+		ActorID:    &adminID,
+		Action:     entity.AuditAdmin + action,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+		Metadata:   metadata,
+	})
+	s.AccessService.Invalidate(ctx, userID)
+	return nil
+}
+
+func (s *AdminServiceImpl) findRole(ctx context.Context, name string) (*entity.Role, error) {
+	role, err := s.RoleRepository.FindByName(ctx, s.DB, strings.ToLower(strings.TrimSpace(name)))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, fmt.Errorf("role %s not found", name)
+	}
+	return role, nil
+}
+
+func (s *AdminServiceImpl) toAdminUserResponses(ctx context.Context, users []*entity.User) ([]*response.AdminUserResponse, error) {
+	userIDs := make([]int, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	roles, err := s.RoleRepository.FindRolesByUserIDs(ctx, s.DB, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	restrictions, err := s.UserRepository.FindRestrictions(ctx, s.DB, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	userResponses := []*response.AdminUserResponse{}
+	for _, user := range users {
+		userResponse := &response.AdminUserResponse{
+			UserID:    user.ID,
+			Username:  user.Username,
+			Fullname:  user.Fullname,
+			Email:     user.Email,
+			Roles:     roles[user.ID],
+			CreatedAt: user.CreatedAt,
+		}
+		if restriction := restrictions[user.ID]; restriction != nil {
+			userResponse.SuspendedUntil = restriction.SuspendedUntil
+			userResponse.BannedAt = restriction.BannedAt
+			userResponse.BanReason = restriction.BanReason
+		}
+		userResponses = append(userResponses, userResponse)
+	}
+	return userResponses, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	aiContextWindowDays   = 30 // rentang waktu postingan yang dipakai buat ngitung tren mood
+	aiContextMaxPosts     = 50 // batas jumlah postingan yang di-index
+	aiContextSharedPosts  = 3  // jumlah postingan relevan yang disisipkan ke prompt
+	aiContextSnippetRunes = 280
+)
+
+type AIContextService interface {
+	GetSettings(ctx context.Context, userID int) (*response.AIContextSettingsResponse, error)
+	UpdateSettings(ctx context.Context, userID int, req request.UpdateAIContextSettingsRequest) (*response.AIContextSettingsResponse, error)
+	BuildContext(ctx context.Context, userID int, input string) (*response.AIContextResponse, error)
+}
+
+type AIContextServiceImpl struct {
+	DB                  *sql.DB
+	AIContextRepository repository.AIContextRepository
+	PostRepository      repository.PostRepository
+}
+
+func NewAIContextService(db *sql.DB, aiContextRepository repository.AIContextRepository, postRepository repository.PostRepository) AIContextService {
+	return &AIContextServiceImpl{
+		DB:                  db,
+		AIContextRepository: aiContextRepository,
+		PostRepository:      postRepository,
+	}
+}
+
+func (s *AIContextServiceImpl) GetSettings(ctx context.Context, userID int) (*response.AIContextSettingsResponse, error) {
+	setting, err := s.AIContextRepository.FindSettings(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toAIContextSettingsResponse(setting), nil
+}
+
+func (s *AIContextServiceImpl) UpdateSettings(ctx context.Context, userID int, req request.UpdateAIContextSettingsRequest) (*response.AIContextSettingsResponse, error) {
+	// step 1: ambil pengaturan yang sekarang supaya field yang tidak dikirim tetap sama
+	setting, err := s.AIContextRepository.FindSettings(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		setting.Enabled = *req.Enabled
+	}
+	if req.IncludeMoodTrend != nil {
+		setting.IncludeMoodTrend = *req.IncludeMoodTrend
+	}
+	if req.IncludePosts != nil {
+		setting.IncludePosts = *req.IncludePosts
+	}
+
+	// step 2: simpan ke database
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	updatedSetting, err := s.AIContextRepository.UpsertSettings(ctx, tx, setting)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return toAIContextSettingsResponse(updatedSetting), nil
+}
+
+func (s *AIContextServiceImpl) BuildContext(ctx context.Context, userID int, input string) (*response.AIContextResponse, error) {
+	// step 1: cek dulu user-nya udah opt-in atau belum
+	setting, err := s.AIContextRepository.FindSettings(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.AIContextResponse{
+		Settings:    *toAIContextSettingsResponse(setting),
+		SharedPosts: []response.SharedPost{},
+	}
+	if !setting.Enabled || (!setting.IncludeMoodTrend && !setting.IncludePosts) {
+		return result, nil
+	}
+
+	// step 2: ambil postingan terbaru user dalam window yang ditentukan
+	since := time.Now().AddDate(0, 0, -aiContextWindowDays)
+	posts, err := s.PostRepository.FindRecentByUserID(ctx, s.DB, userID, since, aiContextMaxPosts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent posts: %v", err)
+	}
+	if len(posts) == 0 {
+		return result, nil
+	}
+
+	// step 3: ringkas tren mood-nya
+	if setting.IncludeMoodTrend {
+		result.MoodTrend = summarizeMoodTrend(posts)
+	}
+
+	// step 4: cari postingan yang paling relevan dengan pesan user pakai BM25
+	if setting.IncludePosts {
+		documents := make([]string, len(posts))
+		for i, post := range posts {
+			documents[i] = post.Content
+		}
+		for _, match := range utils.NewBM25Index(documents).Search(input, aiContextSharedPosts) {
+			post := posts[match.Index]
+			result.SharedPosts = append(result.SharedPosts, response.SharedPost{
+				PostID:    post.PostID,
+				Content:   truncateRunes(post.Content, aiContextSnippetRunes),
+				Mood:      post.Mood,
+				Score:     match.Score,
+				CreatedAt: post.CreatedAt,
+			})
+		}
+	}
+
+	// step 5: susun teks yang nantinya disisipkan ke prompt
+	result.Prompt = buildContextPrompt(result.MoodTrend, result.SharedPosts)
+	return result, nil
+}
+
+func summarizeMoodTrend(posts []*entity.Post) *response.MoodTrendSummary {
+	counts := make(map[string]int)
+	latest := make(map[string]time.Time)
+	for _, post := range posts {
+		counts[post.Mood]++
+		if post.CreatedAt.After(latest[post.Mood]) {
+			latest[post.Mood] = post.CreatedAt
+		}
+	}
+
+	// mood dominan = paling sering muncul, kalau seri ambil yang paling baru (sama kayak GetFriendRecommendation)
+	moods := make([]string, 0, len(counts))
+	for mood := range counts {
+		moods = append(moods, mood)
+	}
+	sort.Slice(moods, func(i, j int) bool {
+		if counts[moods[i]] != counts[moods[j]] {
+			return counts[moods[i]] > counts[moods[j]]
+		}
+		return latest[moods[i]].After(latest[moods[j]])
+	})
+
+	trend := &response.MoodTrendSummary{
+		DominantMood: moods[0],
+		LatestMood:   posts[0].Mood, // posts sudah urut dari yang terbaru
+		MoodCounts:   counts,
+		PostCount:    len(posts),
+		WindowDays:   aiContextWindowDays,
+	}
+
+	parts := make([]string, 0, len(moods))
+	for _, mood := range moods {
+		parts = append(parts, fmt.Sprintf("%s (%d)", mood, counts[mood]))
+	}
+	trend.Summary = fmt.Sprintf(
+		"Over the last %d days the user wrote %d posts. Mood labels: %s. Most frequent mood: %s. Most recent mood: %s.",
+		aiContextWindowDays, len(posts), strings.Join(parts, ", "), trend.DominantMood, trend.LatestMood,
+	)
+	return trend
+}
+
+func buildContextPrompt(trend *response.MoodTrendSummary, sharedPosts []response.SharedPost) string {
+	if trend == nil && len(sharedPosts) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Background about the user (shared with their consent, do not quote it back verbatim):\n")
+	if trend != nil {
+		sb.WriteString("- " + trend.Summary + "\n")
+	}
+	if len(sharedPosts) > 0 {
+		sb.WriteString("- Recent posts that may be relevant:\n")
+		for _, post := range sharedPosts {
+			sb.WriteString(fmt.Sprintf("  * [%s, %s] %s\n", post.CreatedAt.Format("2006-01-02"), post.Mood, post.Content))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
+
+func toAIContextSettingsResponse(setting *entity.AIContextSetting) *response.AIContextSettingsResponse {
+	return &response.AIContextSettingsResponse{
+		UserID:           setting.UserID,
+		Enabled:          setting.Enabled,
+		IncludeMoodTrend: setting.IncludeMoodTrend,
+		IncludePosts:     setting.IncludePosts,
+		UpdatedAt:        setting.UpdatedAt,
+	}
+}
@@ -6,6 +6,7 @@ import (
     "encoding/json"
     "errors"
     "io"
+    "log"
     "net/http"
     "os"
     "strings"
@@ -17,21 +18,28 @@ type HuggingFaceResponse struct {
     GeneratedText string `json:"generated_text"`
 }
 
+const aiSystemPrompt = "You are a compassionate mental health support assistant. " +
+    "Listen carefully and respond empathetically to the user's messages. " +
+    "Always be polite, supportive, and encouraging.\n\n"
+
 type DialoGPTService struct {
     client  *http.Client
     apiURL  string
     apiKey  string
 
+    contextService AIContextService // opsional, buat nyisipin konteks personal user yang sudah opt-in
+
     mu      sync.Mutex
     history map[int]string // chat history per user ID
 }
 
-func NewDialoGPTService() *DialoGPTService {
+func NewDialoGPTService(contextService AIContextService) *DialoGPTService {
     return &DialoGPTService{
-        client:  &http.Client{Timeout: 60 * time.Second},
-        apiURL:  "https://api-inference.huggingface.co/models/HuggingFaceH4/zephyr-7b-beta",
-        apiKey:  os.Getenv("HUGGINGFACE_API_TOKEN"),
-        history: make(map[int]string),
+        client:         &http.Client{Timeout: 60 * time.Second},
+        apiURL:         "https://api-inference.huggingface.co/models/HuggingFaceH4/zephyr-7b-beta",
+        apiKey:         os.Getenv("HUGGINGFACE_API_TOKEN"),
+        contextService: contextService,
+        history:        make(map[int]string),
     }
 }
 
@@ -45,18 +53,28 @@ func (s *DialoGPTService) Chat(ctx context.Context, userID int, input string) (s
     // Retrieve existing chat history or initialize with system prompt if first time
     chatHistory, ok := s.history[userID]
     if !ok {
-        chatHistory = "You are a compassionate mental health support assistant. " +
-            "Listen carefully and respond empathetically to the user's messages. " +
-            "Always be polite, supportive, and encouraging.\n\n"
+        chatHistory = aiSystemPrompt
     }
 
     // Append user input with prefix
     promptText := chatHistory + "User message: " + input + "\nAssistant:"
     s.mu.Unlock()
 
+    // Sisipkan konteks personal tepat setelah system prompt. Konteks ini dibangun ulang setiap giliran
+    // dan tidak disimpan ke history, jadi kalau user mematikan fitur ini efeknya langsung terasa.
+    requestPrompt := promptText
+    if s.contextService != nil {
+        personalContext, err := s.contextService.BuildContext(ctx, userID, input)
+        if err != nil {
+            log.Printf("AIService: failed to build personal context for user %d: %v", userID, err)
+        } else if personalContext.Prompt != "" {
+            requestPrompt = aiSystemPrompt + personalContext.Prompt + strings.TrimPrefix(promptText, aiSystemPrompt)
+        }
+    }
+
     // Construct payload with parameters including stop tokens to prevent runaway generation
     payload := map[string]interface{}{
-        "inputs": promptText,
+        "inputs": requestPrompt,
         "parameters": map[string]interface{}{
             "max_new_tokens": 500,
             "temperature":    0.7,
@@ -102,8 +120,8 @@ func (s *DialoGPTService) Chat(ctx context.Context, userID int, input string) (s
 
     // Use strings.HasPrefix and TrimPrefix to remove prompt from model output if present
     var assistantReply string
-    if strings.HasPrefix(fullOutput, promptText) {
-        assistantReply = strings.TrimPrefix(fullOutput, promptText)
+    if strings.HasPrefix(fullOutput, requestPrompt) {
+        assistantReply = strings.TrimPrefix(fullOutput, requestPrompt)
     } else {
         assistantReply = fullOutput
     }
@@ -125,3 +143,18 @@ func (s *DialoGPTService) Chat(ctx context.Context, userID int, input string) (s
 
     return assistantReply, nil
 }
+
+// History mengembalikan percakapan user dengan AI tanpa system prompt, kosong kalau belum pernah chat.
+// History cuma disimpan di memori, jadi hilang kalau server restart.
+func (s *DialoGPTService) History(userID int) string {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return strings.TrimPrefix(s.history[userID], aiSystemPrompt)
+}
+
+// ClearHistory dipakai waktu akun user dihapus
+func (s *DialoGPTService) ClearHistory(userID int) {
+    s.mu.Lock()
+    delete(s.history, userID)
+    s.mu.Unlock()
+}
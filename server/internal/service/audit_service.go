@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Event yang lebih lama dari ini dipindah ke audit_events_archive
+	auditRetention = 180 * 24 * time.Hour
+	// Arsip yang lebih lama dari ini dihapus permanen
+	auditArchiveRetention = 2 * 365 * 24 * time.Hour
+	auditArchiveBatchSize = 1000
+	auditVerifyBatchSize  = 500
+)
+
+var ErrInvalidAuditRange = errors.New("from must be before to")
+
+// PrevHash untuk event pertama
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// AuditEntry satu kejadian yang mau dicatat. ActorID kosong berarti diambil dari user yang login di ctx.
+type AuditEntry struct {
+	ActorID    *int
+	Action     string
+	TargetType string
+	TargetID   *int
+	Metadata   map[string]interface{}
+}
+
+// AuditLogger dipakai service lain untuk menulis ke log audit. Gagal menulis cuma di-log,
+// jadi tindakan user-nya sendiri tidak ikut gagal.
+type AuditLogger interface {
+	Log(ctx context.Context, entry AuditEntry)
+}
+
+type AuditService interface {
+	AuditLogger
+	GetEvents(ctx context.Context, query request.AuditEventQuery, cursor string, limit int) (*pagination.Page[*response.AuditEventResponse], error)
+	Verify(ctx context.Context) (*response.AuditVerifyResponse, error)
+	RunRetentionJob(ctx context.Context, interval time.Duration)
+}
+
+type AuditServiceImpl struct {
+	DB              *sql.DB
+	AuditRepository repository.AuditRepository
+}
+
+func NewAuditService(db *sql.DB, auditRepository repository.AuditRepository) AuditService {
+	return &AuditServiceImpl{
+		DB:              db,
+		AuditRepository: auditRepository,
+	}
+}
+
+func (s *AuditServiceImpl) Log(ctx context.Context, entry AuditEntry) {
+	// tetap ditulis walaupun request-nya sudah selesai/dibatalkan
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := s.append(ctx, entry); err != nil {
+		log.Printf("AuditService: failed to record %s: %v", entry.Action, err)
+	}
+}
+
+func (s *AuditServiceImpl) append(ctx context.Context, entry AuditEntry) error {
+	// step 1: lengkapi event-nya
+	metadata := "{}"
+	if len(entry.Metadata) > 0 {
+		raw, err := json.Marshal(entry.Metadata) // key map diurutkan, jadi hasilnya selalu sama
+		if err != nil {
+			return err
+		}
+		metadata = string(raw)
+	}
+	actorID := entry.ActorID
+	if actorID == nil {
+		if userID, ok := ctx.Value("userID").(int); ok && userID > 0 {
+			actorID = &userID
+		}
+	}
+	ipAddress, _ := ctx.Value("clientIP").(string)
+
+	event := &entity.AuditEvent{
+		ActorID:    actorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Metadata:   metadata,
+		IPAddress:  ipAddress,
+		// presisi timestamp postgres cuma sampai mikrodetik, jadi dipotong dulu supaya hash-nya bisa dihitung ulang
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// step 3: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 4: kunci rantainya, ambil hash terakhir lalu sambungkan
+	if err = s.AuditRepository.LockChain(ctx, tx); err != nil {
+		return err
+	}
+	event.PrevHash, err = s.AuditRepository.LastHash(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if event.PrevHash == "" {
+		event.PrevHash = auditGenesisHash
+	}
+	event.Hash = auditEventHash(event)
+
+	if _, err = s.AuditRepository.Insert(ctx, tx, event); err != nil {
+		return err
+	}
+
+	// step 5: commit transaction
+	return tx.Commit()
+}
+
+func (s *AuditServiceImpl) GetEvents(ctx context.Context, query request.AuditEventQuery, cursor string, limit int) (*pagination.Page[*response.AuditEventResponse], error) {
+	// step 1: validasi filter dan cursor
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, ErrInvalidAuditRange
+	}
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 2: ambil event-nya (createdat disimpan dalam UTC)
+	filter := repository.AuditEventFilter{
+		ActorID:    query.ActorID,
+		Action:     query.Action,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+	}
+	if query.From != nil {
+		from := query.From.UTC()
+		filter.From = &from
+	}
+	if query.To != nil {
+		to := query.To.UTC()
+		filter.To = &to
+	}
+	events, err := s.AuditRepository.Find(ctx, s.DB, filter, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: convert ke response
+	eventResponses := []*response.AuditEventResponse{}
+	for _, event := range events {
+		eventResponses = append(eventResponses, &response.AuditEventResponse{
+			EventID:    event.EventID,
+			ActorID:    event.ActorID,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetID:   event.TargetID,
+			Metadata:   json.RawMessage(event.Metadata),
+			IPAddress:  event.IPAddress,
+			Hash:       event.Hash,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return pagination.NewPage(eventResponses, limit, func(event *response.AuditEventResponse) string {
+		return pagination.EncodeCursor(event.CreatedAt, event.EventID)
+	}), nil
+}
+
+// Verify menghitung ulang hash semua event yang belum diarsip dan mengecek sambungannya satu per satu.
+// Event paling lama dipakai sebagai titik awal, PrevHash-nya menunjuk ke event yang sudah diarsip.
+func (s *AuditServiceImpl) Verify(ctx context.Context) (*response.AuditVerifyResponse, error) {
+	result := &response.AuditVerifyResponse{Valid: true}
+	previousHash := ""
+	afterID := 0
+
+	for {
+		events, err := s.AuditRepository.FindChain(ctx, s.DB, afterID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			reason := ""
+			if previousHash != "" && event.PrevHash != previousHash {
+				reason = "prev_hash doesn't match the previous event"
+			} else if auditEventHash(event) != event.Hash {
+				reason = "hash doesn't match the event contents"
+			}
+			if reason != "" {
+				brokenAt := event.EventID
+				result.Valid = false
+				result.BrokenAt = &brokenAt
+				result.Reason = reason
+				return result, nil
+			}
+
+			result.Checked++
+			previousHash = event.Hash
+			afterID = event.EventID
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// RunRetentionJob mengarsip event lama lalu menghapus arsip yang sudah kedaluwarsa setiap interval sampai ctx dibatalkan
+func (s *AuditServiceImpl) RunRetentionJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		jobCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		if err := s.applyRetention(jobCtx); err != nil {
+			log.Printf("failed to apply audit log retention: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *AuditServiceImpl) applyRetention(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	// step 1: arsip per batch supaya transaksinya tidak terlalu besar
+	for {
+		moved, err := s.inTx(ctx, func(tx *sql.Tx) (int64, error) {
+			return s.AuditRepository.Archive(ctx, tx, now.Add(-auditRetention), auditArchiveBatchSize)
+		})
+		if err != nil {
+			return err
+		}
+		if moved < auditArchiveBatchSize {
+			break
+		}
+	}
+
+	// step 2: hapus arsip yang sudah lewat masa simpan
+	purged, err := s.inTx(ctx, func(tx *sql.Tx) (int64, error) {
+		return s.AuditRepository.PurgeArchive(ctx, tx, now.Add(-auditArchiveRetention))
+	})
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("AuditService: purged %d archived audit events", purged)
+	}
+	return nil
+}
+
+func (s *AuditServiceImpl) inTx(ctx context.Context, apply func(tx *sql.Tx) (int64, error)) (int64, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := apply(tx)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return affected, tx.Commit()
+}
+
+// auditEventHash menghitung hash event dari PrevHash dan semua isinya (kecuali EventID dan Hash itu sendiri)
+func auditEventHash(event *entity.AuditEvent) string {
+	actorID, targetID := "", ""
+	if event.ActorID != nil {
+		actorID = strconv.Itoa(*event.ActorID)
+	}
+	if event.TargetID != nil {
+		targetID = strconv.Itoa(*event.TargetID)
+	}
+
+	payload := strings.Join([]string{
+		event.PrevHash,
+		actorID,
+		event.Action,
+		event.TargetType,
+		targetID,
+		event.Metadata,
+		event.IPAddress,
+		event.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z"),
+	}, "\n")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -12,11 +12,13 @@ import (
 )
 
 type Claims struct {
-	User *response.CreateUserResponse `json:"user"`
+	User        *response.CreateUserResponse `json:"user"`
+	Roles       []string                     `json:"roles,omitempty"`
+	Permissions []string                     `json:"permissions,omitempty"` // salinan saat login, middleware tetap mengecek ulang ke database
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(user *response.CreateUserResponse) (*string, error) {
+func GenerateToken(user *response.CreateUserResponse, roles, permissions []string) (*string, error) {
 	// baca dulu si .env-nya
 	err := godotenv.Load()
 	if err != nil {
@@ -30,7 +32,9 @@ func GenerateToken(user *response.CreateUserResponse) (*string, error) {
 	expTime, _ := strconv.Atoi(os.Getenv("JWT_EXPIRATION_TIME"))
 	expirationTime := time.Now().Add(time.Duration(expTime) * time.Minute).Unix()
 	claims := &Claims{
-		User: user,
+		User:        user,
+		Roles:       roles,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Unix(expirationTime, 0)),
 		},
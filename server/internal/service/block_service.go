@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrBlocked dikembalikan setiap kali interaksi ditolak karena salah satu user memblokir yang lain.
+// Pesannya sengaja tidak menyebut siapa yang memblokir siapa.
+var ErrBlocked = errors.New("you can't interact with this user")
+
+// InteractionGuard adalah satu-satunya tempat aturan blokir/mute dicek, dipakai oleh service lain
+// (chat, komentar, reaksi, postingan, pertemanan) supaya aturannya tidak tersebar
+type InteractionGuard interface {
+	// CanInteract return ErrBlocked kalau actor dan target saling terblokir (arah mana pun)
+	CanInteract(ctx context.Context, actorID, targetID int) error
+	// HiddenUserIDs adalah user yang kontennya tidak boleh tampil ke viewer: yang terblokir dua arah ditambah yang di-mute viewer
+	HiddenUserIDs(ctx context.Context, viewerID int) (map[int]bool, error)
+}
+
+type BlockService interface {
+	InteractionGuard
+	Block(ctx context.Context, userID, targetID int) (string, error)
+	Unblock(ctx context.Context, userID, targetID int) (string, error)
+	Mute(ctx context.Context, userID, targetID int) (string, error)
+	Unmute(ctx context.Context, userID, targetID int) (string, error)
+	GetBlocked(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.BlockedUserResponse], error)
+	GetMuted(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.BlockedUserResponse], error)
+}
+
+type BlockServiceImpl struct {
+	DB               *sql.DB
+	BlockRepository  repository.BlockRepository
+	FriendRepository repository.FriendRepository
+	UserRepository   repository.UserRepository
+	RedisClient      *redis.Client
+}
+
+func NewBlockService(db *sql.DB, blockRepository repository.BlockRepository, friendRepository repository.FriendRepository, userRepository repository.UserRepository, redisClient *redis.Client) BlockService {
+	return &BlockServiceImpl{
+		DB:               db,
+		BlockRepository:  blockRepository,
+		FriendRepository: friendRepository,
+		UserRepository:   userRepository,
+		RedisClient:      redisClient,
+	}
+}
+
+func (s *BlockServiceImpl) CanInteract(ctx context.Context, actorID, targetID int) error {
+	if actorID == 0 || targetID == 0 || actorID == targetID {
+		return nil
+	}
+	blocked, err := s.BlockRepository.IsBlocked(ctx, s.DB, actorID, targetID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
+func (s *BlockServiceImpl) HiddenUserIDs(ctx context.Context, viewerID int) (map[int]bool, error) {
+	hidden := map[int]bool{}
+	if viewerID == 0 {
+		return hidden, nil // user yang belum login tidak punya daftar blokir/mute
+	}
+	userIDs, err := s.BlockRepository.FindHiddenUserIDs(ctx, s.DB, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range userIDs {
+		hidden[id] = true
+	}
+	return hidden, nil
+}
+
+func (s *BlockServiceImpl) Block(ctx context.Context, userID, targetID int) (string, error) {
+	// step 1: validasi target
+	if err := s.validateTarget(ctx, userID, targetID); err != nil {
+		return "", err
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+
+	// step 3: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 4: simpan blokirnya dan putuskan pertemanan/friend request yang masih ada di antara keduanya
+	if _, err = s.BlockRepository.Block(ctx, tx, userID, targetID); err != nil {
+		return "", err
+	}
+	if err = s.FriendRepository.BlockBetween(ctx, tx, userID, targetID); err != nil {
+		return "", err
+	}
+
+	// step 5: commit transaction
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+
+	// step 6: buang cache yang mungkin masih menampilkan user satu ke yang lain
+	for _, id := range []int{userID, targetID} {
+		_ = s.RedisClient.Incr(ctx, friendListGenerationKey("friend", id)).Err()
+		_ = s.RedisClient.Incr(ctx, friendListGenerationKey("friendrequest", id)).Err()
+		_ = s.RedisClient.Incr(ctx, friendListGenerationKey("friendoutgoing", id)).Err()
+		_ = s.RedisClient.Del(ctx, friendRecommendationCacheKey(id)).Err()
+	}
+	s.invalidatePostLists(ctx)
+
+	return "User blocked successfully", nil
+}
+
+func (s *BlockServiceImpl) Unblock(ctx context.Context, userID, targetID int) (string, error) {
+	changed, err := s.BlockRepository.Unblock(ctx, s.DB, userID, targetID)
+	if err != nil {
+		return "", err
+	}
+	if !changed {
+		return "", fmt.Errorf("user with id %d is not blocked", targetID)
+	}
+
+	// pertemanan yang diputus waktu blokir (status blocked) tidak dikembalikan, cukup buang cache-nya
+	_ = s.RedisClient.Del(ctx, friendRecommendationCacheKey(userID), friendRecommendationCacheKey(targetID)).Err()
+	s.invalidatePostLists(ctx)
+
+	return "User unblocked successfully", nil
+}
+
+func (s *BlockServiceImpl) Mute(ctx context.Context, userID, targetID int) (string, error) {
+	if err := s.validateTarget(ctx, userID, targetID); err != nil {
+		return "", err
+	}
+	if _, err := s.BlockRepository.Mute(ctx, s.DB, userID, targetID); err != nil {
+		return "", err
+	}
+
+	_ = s.RedisClient.Del(ctx, friendRecommendationCacheKey(userID)).Err()
+	s.invalidatePostLists(ctx)
+
+	return "User muted successfully", nil
+}
+
+func (s *BlockServiceImpl) Unmute(ctx context.Context, userID, targetID int) (string, error) {
+	changed, err := s.BlockRepository.Unmute(ctx, s.DB, userID, targetID)
+	if err != nil {
+		return "", err
+	}
+	if !changed {
+		return "", fmt.Errorf("user with id %d is not muted", targetID)
+	}
+
+	_ = s.RedisClient.Del(ctx, friendRecommendationCacheKey(userID)).Err()
+	s.invalidatePostLists(ctx)
+
+	return "User unmuted successfully", nil
+}
+
+func (s *BlockServiceImpl) GetBlocked(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.BlockedUserResponse], error) {
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	blocks, err := s.BlockRepository.FindBlocked(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	blockResponses := []*response.BlockedUserResponse{}
+	for _, block := range blocks {
+		blockResponses = append(blockResponses, &response.BlockedUserResponse{
+			User: response.UserSummary{
+				UserID:   block.User.ID,
+				Username: block.User.Username,
+				FullName: block.User.Fullname,
+			},
+			CreatedAt: block.CreatedAt,
+		})
+	}
+	return pagination.NewPage(blockResponses, limit, blockedUserCursor), nil
+}
+
+func (s *BlockServiceImpl) GetMuted(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.BlockedUserResponse], error) {
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	mutes, err := s.BlockRepository.FindMuted(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	muteResponses := []*response.BlockedUserResponse{}
+	for _, mute := range mutes {
+		muteResponses = append(muteResponses, &response.BlockedUserResponse{
+			User: response.UserSummary{
+				UserID:   mute.User.ID,
+				Username: mute.User.Username,
+				FullName: mute.User.Fullname,
+			},
+			CreatedAt: mute.CreatedAt,
+		})
+	}
+	return pagination.NewPage(muteResponses, limit, blockedUserCursor), nil
+}
+
+func (s *BlockServiceImpl) validateTarget(ctx context.Context, userID, targetID int) error {
+	if userID == targetID {
+		return fmt.Errorf("you can't block or mute yourself")
+	}
+	if _, err := s.UserRepository.FindByID(ctx, s.DB, targetID); err != nil {
+		return fmt.Errorf("user with id %d not found", targetID)
+	}
+	return nil
+}
+
+// list postingan disaring per viewer di query, jadi cache list yang lama harus dibuang
+func (s *BlockServiceImpl) invalidatePostLists(ctx context.Context) {
+	_ = s.RedisClient.Incr(ctx, postListGenerationKey).Err()
+}
+
+func blockedUserCursor(item *response.BlockedUserResponse) string {
+	return pagination.EncodeCursor(item.CreatedAt, item.User.UserID)
+}
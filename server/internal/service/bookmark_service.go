@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"strings"
+)
+
+// NOTES: bookmark bersifat privat, jadi semua method selalu menerima userID pemiliknya dan tidak di-cache ke Redis
+
+type BookmarkService interface {
+	CreateCollection(ctx context.Context, userID int, req request.BookmarkCollectionRequest) (*response.BookmarkCollectionResponse, error)
+	GetCollections(ctx context.Context, userID int) ([]*response.BookmarkCollectionResponse, error)
+	RenameCollection(ctx context.Context, userID, collectionID int, req request.BookmarkCollectionRequest) (*response.BookmarkCollectionResponse, error)
+	DeleteCollection(ctx context.Context, userID, collectionID int) (string, error)
+	AddPost(ctx context.Context, userID, collectionID, postID int) (*response.BookmarkCollectionResponse, error)
+	RemovePost(ctx context.Context, userID, collectionID, postID int) (*response.BookmarkCollectionResponse, error)
+	GetPosts(ctx context.Context, userID, collectionID int, cursor string, limit int) (*pagination.Page[*response.BookmarkResponse], error)
+}
+
+type BookmarkServiceImpl struct {
+	DB                 *sql.DB
+	BookmarkRepository repository.BookmarkRepository
+	PostService        PostService
+}
+
+func NewBookmarkService(db *sql.DB, bookmarkRepository repository.BookmarkRepository, postService PostService) BookmarkService {
+	return &BookmarkServiceImpl{
+		DB:                 db,
+		BookmarkRepository: bookmarkRepository,
+		PostService:        postService,
+	}
+}
+
+const maxBookmarkCollections = 50
+
+func (s *BookmarkServiceImpl) CreateCollection(ctx context.Context, userID int, req request.BookmarkCollectionRequest) (*response.BookmarkCollectionResponse, error) {
+	// step 1: rapikan nama koleksinya
+	name, err := normalizeCollectionName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: batasi jumlah koleksi per user
+	count, err := s.BookmarkRepository.CountCollections(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxBookmarkCollections {
+		return nil, fmt.Errorf("you can have at most %d bookmark collections", maxBookmarkCollections)
+	}
+
+	// step 3: simpan, nil berarti namanya sudah dipakai
+	collection, err := s.BookmarkRepository.CreateCollection(ctx, s.DB, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("bookmark collection %q already exists", name)
+	}
+	return toBookmarkCollectionResponse(collection), nil
+}
+
+func (s *BookmarkServiceImpl) GetCollections(ctx context.Context, userID int) ([]*response.BookmarkCollectionResponse, error) {
+	collections, err := s.BookmarkRepository.FindCollectionsByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionResponses := []*response.BookmarkCollectionResponse{}
+	for _, collection := range collections {
+		collectionResponses = append(collectionResponses, toBookmarkCollectionResponse(collection))
+	}
+	return collectionResponses, nil
+}
+
+func (s *BookmarkServiceImpl) RenameCollection(ctx context.Context, userID, collectionID int, req request.BookmarkCollectionRequest) (*response.BookmarkCollectionResponse, error) {
+	name, err := normalizeCollectionName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findOwnedCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	collection, err := s.BookmarkRepository.RenameCollection(ctx, s.DB, collectionID, name)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("bookmark collection %q already exists", name)
+	}
+	return toBookmarkCollectionResponse(collection), nil
+}
+
+func (s *BookmarkServiceImpl) DeleteCollection(ctx context.Context, userID, collectionID int) (string, error) {
+	if _, err := s.findOwnedCollection(ctx, userID, collectionID); err != nil {
+		return "", err
+	}
+
+	deleted, err := s.BookmarkRepository.DeleteCollection(ctx, s.DB, collectionID)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return "", fmt.Errorf("bookmark collection with ID %d not found", collectionID)
+	}
+	return fmt.Sprintf("Bookmark collection with ID %d deleted successfully", collectionID), nil
+}
+
+// AddPost dan RemovePost sifatnya idempotent, sama seperti reaksi dan follow tag
+func (s *BookmarkServiceImpl) AddPost(ctx context.Context, userID, collectionID, postID int) (*response.BookmarkCollectionResponse, error) {
+	// step 1: pastikan koleksinya milik user ini
+	if _, err := s.findOwnedCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	// step 2: cuma postingan yang boleh dilihat user yang bisa disimpan (Find sudah ngecek visibility)
+	if _, err := s.PostService.Find(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	// step 3: simpan bookmark-nya lalu kembalikan koleksi dengan jumlah terbaru
+	if _, err := s.BookmarkRepository.Add(ctx, s.DB, collectionID, postID); err != nil {
+		return nil, err
+	}
+	return s.reloadCollection(ctx, collectionID)
+}
+
+func (s *BookmarkServiceImpl) RemovePost(ctx context.Context, userID, collectionID, postID int) (*response.BookmarkCollectionResponse, error) {
+	if _, err := s.findOwnedCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+	if _, err := s.BookmarkRepository.Remove(ctx, s.DB, collectionID, postID); err != nil {
+		return nil, err
+	}
+	return s.reloadCollection(ctx, collectionID)
+}
+
+func (s *BookmarkServiceImpl) GetPosts(ctx context.Context, userID, collectionID int, cursor string, limit int) (*pagination.Page[*response.BookmarkResponse], error) {
+	// step 1: pastikan koleksinya milik user ini
+	if _, err := s.findOwnedCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	// step 2: ambil bookmark-nya (satu lebih banyak buat ngecek masih ada halaman berikutnya)
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterPostID := after.After()
+	bookmarks, err := s.BookmarkRepository.FindByCollectionID(ctx, s.DB, collectionID, afterCreatedAt, afterPostID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page[*response.BookmarkResponse]{Items: []*response.BookmarkResponse{}}
+	if len(bookmarks) > limit {
+		page.HasMore = true
+		bookmarks = bookmarks[:limit]
+	}
+	if len(bookmarks) == 0 {
+		return page, nil
+	}
+	// cursor-nya dari bookmark terakhir, bukan dari postingan, supaya tetap maju walaupun ada postingan yang disaring
+	if page.HasMore {
+		last := bookmarks[len(bookmarks)-1]
+		page.NextCursor = pagination.EncodeCursor(last.CreatedAt, last.PostID)
+	}
+
+	// step 3: ambil postingannya dengan aturan visibility yang sama seperti di feed.
+	// Postingan yang sudah tidak boleh dilihat (misalnya sudah tidak berteman) tidak ikut dikirim.
+	postIDs := make([]int, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		postIDs = append(postIDs, bookmark.PostID)
+	}
+	posts, err := s.PostService.FindByIDs(ctx, postIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	postMap := make(map[int]*response.CreatePostResponse)
+	for _, post := range posts {
+		postMap[post.PostID] = post
+	}
+
+	for _, bookmark := range bookmarks {
+		post, ok := postMap[bookmark.PostID]
+		if !ok {
+			continue
+		}
+		page.Items = append(page.Items, &response.BookmarkResponse{
+			Post:         post,
+			BookmarkedAt: bookmark.CreatedAt,
+		})
+	}
+	return page, nil
+}
+
+// findOwnedCollection menganggap koleksi milik orang lain tidak ada, biar keberadaannya tidak bocor
+func (s *BookmarkServiceImpl) findOwnedCollection(ctx context.Context, userID, collectionID int) (*entity.BookmarkCollection, error) {
+	collection, err := s.BookmarkRepository.FindCollection(ctx, s.DB, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil || collection.UserID != userID {
+		return nil, fmt.Errorf("bookmark collection with ID %d not found", collectionID)
+	}
+	return collection, nil
+}
+
+func (s *BookmarkServiceImpl) reloadCollection(ctx context.Context, collectionID int) (*response.BookmarkCollectionResponse, error) {
+	collection, err := s.BookmarkRepository.FindCollection(ctx, s.DB, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("bookmark collection with ID %d not found", collectionID)
+	}
+	return toBookmarkCollectionResponse(collection), nil
+}
+
+func normalizeCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("collection name is required")
+	}
+	if len([]rune(name)) > 100 {
+		return "", fmt.Errorf("collection name must be at most 100 characters")
+	}
+	return name, nil
+}
+
+func toBookmarkCollectionResponse(collection *entity.BookmarkCollection) *response.BookmarkCollectionResponse {
+	return &response.BookmarkCollectionResponse{
+		CollectionID: collection.CollectionID,
+		Name:         collection.Name,
+		PostCount:    collection.PostCount,
+		CreatedAt:    collection.CreatedAt,
+		UpdatedAt:    collection.UpdatedAt,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
 	"mood-bridge-v2/server/internal/entity"
 	"mood-bridge-v2/server/internal/model/response"
 	"mood-bridge-v2/server/internal/repository"
@@ -15,6 +16,7 @@ import (
 )
 
 type ChatService interface {
+	Authorize(ctx context.Context, userID int) error
 	HandleNewConnection(ctx context.Context, userID int, conn *websocket.Conn) error
 	HandleIncomingMessage(ctx context.Context, senderID int, recipientID int, content string) error
 	FetchConversationHistory(ctx context.Context, senderID, recipientID, limit, offset int) ([]*response.ChatMessage, error)
@@ -24,22 +26,48 @@ type ChatService interface {
 type ChatServiceImpl struct {
 	messageRepo repository.ChatRepository
 	hub Hub
+	guard InteractionGuard
+	audit AuditLogger
+	verifier EmailVerifier
+	limiter ratelimit.Store
+	policy ratelimit.Policy
 }
 
-func NewChatService(msgRepo repository.ChatRepository, hub Hub) ChatService {
+func NewChatService(msgRepo repository.ChatRepository, hub Hub, guard InteractionGuard, audit AuditLogger, verifier EmailVerifier, limiter ratelimit.Store, policy ratelimit.Policy) ChatService {
 	return &ChatServiceImpl{
 		messageRepo: msgRepo,
 		hub: hub,
+		guard: guard,
+		audit: audit,
+		verifier: verifier,
+		limiter: limiter,
+		policy: policy,
 	}
 }
 
+// Authorize dipanggil sebelum koneksi di-upgrade ke WebSocket, chat hanya untuk akun yang email-nya sudah diverifikasi
+func (s *ChatServiceImpl) Authorize(ctx context.Context, userID int) error {
+	verified, err := s.verifier.IsVerified(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
 func (s *ChatServiceImpl) HandleNewConnection(ctx context.Context, userID int, conn *websocket.Conn) error {
 	// step 1: buat client baru
-	client := NewClient(userID, s.hub, conn, s)
+	client := NewClient(userID, s.hub, conn, s, s.limiter, s.policy)
 
 	// step 2: hubungkan client ke hub
 	s.hub.RegisterClient(client)
 	log.Printf("ChatService: User %d connected. Client registered with Hub.", userID)
+	s.audit.Log(ctx, AuditEntry{
+		ActorID: &userID,
+		Action:  entity.AuditChatConnected,
+	})
 
 	// step 3: dapatkan pesan yang belum dibaca
 	unreadMessages, err := s.messageRepo.GetUnreadMessagesForUser(ctx, userID, time.Now().Add(-7*24*time.Hour))
@@ -96,6 +124,18 @@ func (s *ChatServiceImpl) HandleIncomingMessage(ctx context.Context, senderID in
 	if senderID == recipientID {
 		return errors.New("sender and recipient cannot be the same")
 	}
+	// pesan ke/dari user yang saling blokir ditolak sebelum disimpan
+	if err := s.guard.CanInteract(ctx, senderID, recipientID); err != nil {
+		if errors.Is(err, ErrBlocked) {
+			s.audit.Log(ctx, AuditEntry{
+				ActorID:    &senderID,
+				Action:     entity.AuditChatMessageBlocked,
+				TargetType: entity.AuditTargetUser,
+				TargetID:   &recipientID,
+			})
+		}
+		return err
+	}
 
 	// step 1: buat pesan baru dalam bentuk entity.Message
 	msg := entity.NewMessage(senderID, recipientID, content)
@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"mood-bridge-v2/server/internal/entity"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
 	"mood-bridge-v2/server/internal/repository"
 	"mood-bridge-v2/server/internal/utils"
 	"time"
@@ -17,44 +20,67 @@ import (
 
 type CommentService interface {
 	Create(ctx context.Context, req request.CreateCommentRequest) (*response.CreateCommentResponse, error)
-	GetAllByPostID(ctx context.Context, postID int) ([]*response.CreateCommentResponse, error)
-	Delete(ctx context.Context, commentID int) (string, error)
-	GetByID(ctx context.Context, commentID int) (*response.CreateCommentResponse, error)
+	GetByPostID(ctx context.Context, postID, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreateCommentResponse], error)
+	GetReplies(ctx context.Context, commentID, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreateCommentResponse], error)
+	Update(ctx context.Context, commentID, userID int, req request.UpdateCommentRequest) (*response.CreateCommentResponse, error)
+	Delete(ctx context.Context, commentID, userID int) (string, error)
+	GetByID(ctx context.Context, commentID, viewerID int) (*response.CreateCommentResponse, error)
+	GetRevisions(ctx context.Context, commentID, userID int) ([]*response.CommentRevisionResponse, error)
+	GetNeedsReview(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.CreateCommentResponse], error)
 }
 
+var (
+	// ErrCommentNotFound dipakai handler buat membedakan komentar yang tidak ada (404) dari error lain
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrCommentForbidden: komentar cuma boleh dihapus pemiliknya (atau moderator)
+	ErrCommentForbidden = errors.New("you can only delete your own comments")
+	// ErrCommentReviewForbidden: antrian komentar needsreview cuma buat moderator
+	ErrCommentReviewForbidden = errors.New("only moderators can view comments that need review")
+)
+
 type CommentServiceImpl struct {
 	commentRepository repository.CommentRepository
 	userRepository repository.UserRepository
-	postRepository repository.PostRepository
+	postService PostService
+	moodService MoodPredictionService
+	guard InteractionGuard
+	audit AuditLogger
+	access AccessService
 	DB                *sql.DB
 	RedisClient *redis.Client
 }
 
-func NewCommentService(commentRepository repository.CommentRepository, userRepository repository.UserRepository, postRepository repository.PostRepository, db *sql.DB, redisClient *redis.Client) CommentService {
+func NewCommentService(commentRepository repository.CommentRepository, userRepository repository.UserRepository, postService PostService, moodService MoodPredictionService, guard InteractionGuard, audit AuditLogger, accessService AccessService, db *sql.DB, redisClient *redis.Client) CommentService {
 	return &CommentServiceImpl{
 		commentRepository: commentRepository,
 		userRepository:    userRepository,
-		postRepository:    postRepository,
+		postService:       postService,
+		moodService:       moodService,
+		guard:             guard,
+		audit:             audit,
+		access:            accessService,
 		DB:                db,
 		RedisClient: redisClient,
 	}
 }
 
-func (s *CommentServiceImpl) Create(ctx context.Context, req request.CreateCommentRequest) (*response.CreateCommentResponse, error) {
-	// Step 1: Start a transaction
-	tx, err := s.DB.Begin()
-	if err != nil {
-		return nil, err
-	}
+// Ini yang ditampilkan sebagai pengganti komentar yang sudah dihapus
+const deletedCommentContent = "[deleted]"
 
-	// Step 2: Siap-siap buat rollback
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
+// Ini yang ditampilkan sebagai pengganti komentar yang disembunyikan moderator
+const hiddenCommentContent = "[hidden by moderator]"
 
-	// step 3: Validate kalau user exist
+// Klasifikasi mood komentar jalan di background, jadi dikasih batas waktu sendiri (lepas dari request-nya)
+const commentClassificationTimeout = 30 * time.Second
+
+var deletedUserSummary = response.UserSummary{
+	UserID:   0,
+	Username: "[deleted]",
+	FullName: "[deleted]",
+}
+
+func (s *CommentServiceImpl) Create(ctx context.Context, req request.CreateCommentRequest) (*response.CreateCommentResponse, error) {
+	// step 1: Validate kalau user exist (semua validasi dilakukan sebelum transaction dibuka, biar ga ada transaction yang bocor)
 	user, err := s.userRepository.FindByID(ctx, s.DB, req.UserID)
 	if err != nil {
 		if err == sql.ErrNoRows || user == nil{
@@ -63,24 +89,61 @@ func (s *CommentServiceImpl) Create(ctx context.Context, req request.CreateComme
 		return nil, err
 	}
 
-	// step 4: Validate kalau post exist
-	post, err := s.postRepository.Find(ctx, s.DB, req.PostID)
-	if err != nil {
-		if err == sql.ErrNoRows || post == nil {
-			return nil, fmt.Errorf("post with ID %d not found", req.PostID)
-		}
+	// step 2: Validate kalau post exist dan boleh dilihat user-nya (post khusus teman / hanya saya,
+	// atau milik user yang saling blokir dianggap tidak ada)
+	if _, err = s.postService.Find(ctx, req.PostID, req.UserID); err != nil {
 		return nil, err
 	}
 
-	// step  5: validate struktur input komentar-nya
-	if err := utils.ValidateCommentInput(req.Content); err != nil {
+	// step 3: validate struktur input komentar-nya
+	if err = utils.ValidateCommentInput(req.Content); err != nil {
 		return nil, err
 	}
 
-	// step 6: Insert ke dalam database
+	// step 4: kalau ini balasan, pastikan komentar induknya valid (post-nya sama, belum dihapus, dan belum terlalu dalam)
+	depth := 0
+	if req.ParentCommentID != nil {
+		var parent *entity.Comment
+		parent, err = s.commentRepository.GetByID(ctx, s.DB, *req.ParentCommentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.PostID != req.PostID {
+			err = fmt.Errorf("parent comment with ID %d not found on post %d", *req.ParentCommentID, req.PostID)
+			return nil, err
+		}
+		if parent.IsDeleted {
+			err = fmt.Errorf("cannot reply to a deleted comment")
+			return nil, err
+		}
+		if err = utils.ValidateCommentDepth(parent.Depth); err != nil {
+			return nil, err
+		}
+		if err = s.guard.CanInteract(ctx, req.UserID, parent.UserID); err != nil {
+			return nil, err
+		}
+		depth = parent.Depth + 1
+	}
+
+	// step 5: Start a transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 6: Siap-siap buat rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 7: Insert ke dalam database
 	comment := entity.Comment{
 		PostID:   req.PostID,
 		UserID:   req.UserID,
+		ParentCommentID: req.ParentCommentID,
+		Depth:    depth,
 		Content:  req.Content,
 		CreatedAt: time.Now(),
 	}
@@ -89,216 +152,367 @@ func (s *CommentServiceImpl) Create(ctx context.Context, req request.CreateComme
 		return nil, err
 	}
 
-	// step 7: Commit transaction
-	if err := tx.Commit(); err != nil {
-		return nil, err
-	}
-
-	// step 8: Cari comment yang baru saja di-insert
-	commentResult, err := s.GetByID(ctx, createdComment.CommentID)
-	if err != nil {
+	// step 8: Commit transaction
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	// step 9: Convert ke dalam response
-	commentResp := &response.CreateCommentResponse{
-		CommentID: commentResult.CommentID,
-		PostID:   commentResult.PostID,
-		UserID:   commentResult.UserID,
-		User: response.UserSummary{
-			UserID:    user.ID,
-			Username:  user.Username,
-			FullName: user.Fullname,
-		},
-		Content:   commentResult.Content,
-		CreatedAt: commentResult.CreatedAt,
-	}
+	commentResp := toCommentResponse(createdComment, user)
 
 	// step 10: Invalidate cache pada comment dan post (post tetap harus di-invalidate karena ada perubahan pada komentar)
-	postCacheKey := fmt.Sprintf("comment:post:%d:v%d", commentResult.PostID, cacheVersion)
-	userCacheKey := fmt.Sprintf("comment:user:%d:v%d", commentResult.UserID, cacheVersion)
-	postDetailCacheKey := fmt.Sprintf("post:%d:v%d", commentResult.PostID, cacheVersion)
-	if err := s.RedisClient.Del(ctx, postCacheKey).Err(); err != nil {
-    	fmt.Printf("Failed to delete post comment cache: %v\n", err)
-	}
+	userCacheKey := fmt.Sprintf("comment:user:%d:v%d", createdComment.UserID, cacheVersion)
+	postDetailCacheKey := postCacheKey(createdComment.PostID)
 	if err := s.RedisClient.Del(ctx, userCacheKey).Err(); err != nil {
     	fmt.Printf("Failed to delete user comment cache: %v\n", err)
 	}
 	if err := s.RedisClient.Del(ctx, postDetailCacheKey).Err(); err != nil {
     	fmt.Printf("Failed to delete post detail cache: %v\n", err)
 	}
+	if createdComment.ParentCommentID != nil {
+		// jumlah balasan di komentar induknya berubah
+		_ = s.RedisClient.Del(ctx, commentCacheKey(*createdComment.ParentCommentID)).Err()
+	}
+	s.invalidateCommentPages(ctx, createdComment.PostID)
+
+	// step 11: klasifikasi mood komentar di background supaya user ga perlu nunggu model-nya
+	go s.classifyComment(createdComment.CommentID, createdComment.PostID, createdComment.Content)
 
-	// step 11: Kembalikan commentResp-nya
+	// step 12: Kembalikan commentResp-nya
 	return commentResp, nil
 }
 
-func (s *CommentServiceImpl) GetAllByPostID(ctx context.Context, postID int) ([]*response.CreateCommentResponse, error) {
-	// step 1: Ambil cacheKey-nya, ini contoh cara penyimpanannya di redis (cacheKey -> comment:postID:v1)
-	cacheKey := fmt.Sprintf("comment:post:%d:v%d", postID, cacheVersion)
+func (s *CommentServiceImpl) Update(ctx context.Context, commentID, userID int, req request.UpdateCommentRequest) (*response.CreateCommentResponse, error) {
+	// step 1: validate struktur input komentar-nya
+	if err := utils.ValidateCommentInput(req.Content); err != nil {
+		return nil, err
+	}
 
-	// step 2: Ambil data-nya berdasarkan cacheKey-nya
-	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
-	if err == nil { // Kalau gaada error
-		var commentsResp []*response.CreateCommentResponse
-		// step 3: Decode JSON-nya dari redis ke dalam struct
-		if err := json.Unmarshal([]byte(cached), &commentsResp); err == nil {
-			return commentsResp, nil
+	// step 2: cari comment-nya dan pastikan yang ngedit adalah pemiliknya
+	comment, err := s.commentRepository.GetByID(ctx, s.DB, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.IsDeleted {
+		return nil, fmt.Errorf("comment with ID %d not found", commentID)
+	}
+	if comment.UserID != userID {
+		return nil, fmt.Errorf("you can only edit your own comments")
+	}
+	if comment.Content == req.Content {
+		// isinya sama persis, ga perlu bikin revisi baru
+		return s.loadComment(ctx, commentID)
+	}
+
+	user, err := s.userRepository.FindByID(ctx, s.DB, comment.UserID)
+	if err != nil || user == nil {
+		if err == sql.ErrNoRows || user == nil {
+			return nil, fmt.Errorf("user with ID %d not found", comment.UserID)
 		}
+		return nil, err
 	}
 
-	// step 4: cari postingan-nya dalam database (pastiin postingannya ada)
-	post, err := s.postRepository.Find(ctx, s.DB, postID)
+	// step 3: simpan revisi lama + update isi komentarnya dalam satu transaction
+	tx, err := s.DB.Begin()
 	if err != nil {
-		if err == sql.ErrNoRows || post == nil {
-			return nil, fmt.Errorf("post with ID %d not found", postID)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
 		}
+	}()
+
+	updatedComment, err := s.commentRepository.Update(ctx, tx, commentID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if updatedComment == nil {
+		err = fmt.Errorf("comment with ID %d not found", commentID)
 		return nil, err
 	}
 
-	// step 5: cari semua komentar-nya
-	comments, err := s.commentRepository.GetAllByPostID(ctx, s.DB, postID)
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// step 4: invalidate cache komentar ini dan semua halaman komentar di post-nya
+	_ = s.RedisClient.Del(ctx, commentCacheKey(commentID)).Err()
+	s.invalidateCommentPages(ctx, updatedComment.PostID)
+
+	// step 5: isi baru harus diklasifikasi ulang
+	go s.classifyComment(updatedComment.CommentID, updatedComment.PostID, updatedComment.Content)
+
+	return toCommentResponse(updatedComment, user), nil
+}
+
+func (s *CommentServiceImpl) GetRevisions(ctx context.Context, commentID, userID int) ([]*response.CommentRevisionResponse, error) {
+	// riwayat edit cuma bisa dilihat sama pemilik komentarnya
+	comment, err := s.commentRepository.GetByID(ctx, s.DB, commentID)
 	if err != nil {
-		if err == sql.ErrNoRows || comments == nil {
-			return nil, fmt.Errorf("comments for post with ID %d not found", postID)
+		return nil, err
+	}
+	if comment == nil || comment.IsDeleted {
+		return nil, fmt.Errorf("comment with ID %d not found", commentID)
+	}
+	if comment.UserID != userID {
+		return nil, fmt.Errorf("you can only view the history of your own comments")
+	}
+
+	revisions, err := s.commentRepository.GetRevisions(ctx, s.DB, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionResps := []*response.CommentRevisionResponse{}
+	for _, revision := range revisions {
+		revisionResp := &response.CommentRevisionResponse{
+			RevisionID: revision.RevisionID,
+			CommentID:  revision.CommentID,
+			Content:    revision.Content,
+			EditedAt:   revision.EditedAt,
 		}
+		if revision.Mood != nil {
+			revisionResp.Mood = *revision.Mood
+		}
+		revisionResps = append(revisionResps, revisionResp)
+	}
+	return revisionResps, nil
+}
+
+// classifyComment dijalankan sebagai goroutine setelah komentar dibuat / diedit.
+// Komentar dengan mood distress ditandai needsreview supaya bisa muncul di antrian moderator.
+func (s *CommentServiceImpl) classifyComment(commentID, postID int, content string) {
+	ctx, cancel := context.WithTimeout(context.Background(), commentClassificationTimeout)
+	defer cancel()
+
+	moodResp, err := s.moodService.PredictMood(ctx, request.MoodPredictionRequest{Input: content})
+	if err != nil {
+		log.Printf("Failed to classify comment %d: %v", commentID, err)
+		return
+	}
+
+	updated, err := s.commentRepository.UpdateMood(ctx, s.DB, commentID, content, moodResp.Prediction, utils.IsDistressMood(moodResp.Prediction))
+	if err != nil {
+		log.Printf("Failed to store mood for comment %d: %v", commentID, err)
+		return
+	}
+	if !updated {
+		return // komentarnya sudah diedit / dihapus duluan
+	}
+
+	_ = s.RedisClient.Del(ctx, commentCacheKey(commentID)).Err()
+	s.invalidateCommentPages(ctx, postID)
+}
+
+func (s *CommentServiceImpl) GetByPostID(ctx context.Context, postID, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreateCommentResponse], error) {
+	// step 0: pastiin postingannya ada dan boleh dilihat viewer-nya (dicek sebelum cache, karena cache-nya dipakai semua viewer)
+	if _, err := s.postService.Find(ctx, postID, viewerID); err != nil {
 		return nil, err
 	}
-	
-	// step 6: Lakukan batch query untuk ambil user-nya
-	userIDMap := map[int]bool{}
-	for _, comment := range comments {
-		userIDMap[comment.UserID] = true
+
+	// step 1: Ambil cacheKey-nya, semua halaman komentar satu post berbagi "generation" yang sama
+	// contoh: comment:post:12:g3:cursor::limit:20:v1
+	cacheKey := fmt.Sprintf("comment:post:%d:g%d:cursor:%s:limit:%d:v%d", postID, s.commentPageGeneration(ctx, postID), cursor, limit, cacheVersion)
+
+	// step 2: Ambil data-nya berdasarkan cacheKey-nya
+	if page, ok := s.getCachedPage(ctx, cacheKey); ok {
+		return s.hideComments(ctx, page, viewerID)
 	}
 
-	var userIDs []int
-	for id := range userIDMap {
-		userIDs = append(userIDs, id)
+	// step 3: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
 	}
+	afterCreatedAt, afterID := after.After()
 
-	users, err := s.userRepository.FindByIDs(ctx, s.DB, userIDs)
+	// step 4: cari komentar top-level-nya (ambil satu lebih banyak buat ngecek masih ada halaman berikutnya atau engga)
+	comments, err := s.commentRepository.GetTopLevelByPostID(ctx, s.DB, postID, afterCreatedAt, afterID, limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching users: %v", err)
+		return nil, err
 	}
-	if len(users) == 0 {
-		return nil, fmt.Errorf("no users found for the given comment user IDs")
+
+	// step 5: susun halaman-nya lalu simpan ke cache (cache-nya dipakai semua viewer, jadi penyaringan blokir/mute setelahnya)
+	page, err := s.buildCommentPage(ctx, comments, limit)
+	if err != nil {
+		return nil, err
 	}
+	s.setCachedPage(ctx, cacheKey, page)
 
-	userMap := map[int]*entity.User{}
-	for _, user := range users {
-		userMap[user.ID] = user
+	return s.hideComments(ctx, page, viewerID)
+}
+
+func (s *CommentServiceImpl) GetReplies(ctx context.Context, commentID, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreateCommentResponse], error) {
+	// step 1: pastikan komentar induknya ada (sekalian buat tahu post-nya, karena generation cache-nya per post)
+	// GetByID sudah ngecek post-nya boleh dilihat viewer atau engga
+	parent, err := s.GetByID(ctx, commentID, viewerID)
+	if err != nil {
+		return nil, err
 	}
 
-	// step 7: Convert ke dalam response
-	var commentResps []*response.CreateCommentResponse
-	for _, comment := range comments {
-		user, ok := userMap[comment.UserID]
-		if !ok {
-			fmt.Printf("user with ID %d not found for comment ID %d\n", comment.UserID, comment.CommentID)
-			continue
-		}
+	cacheKey := fmt.Sprintf("comment:replies:%d:g%d:cursor:%s:limit:%d:v%d", commentID, s.commentPageGeneration(ctx, parent.PostID), cursor, limit, cacheVersion)
+	if page, ok := s.getCachedPage(ctx, cacheKey); ok {
+		return s.hideComments(ctx, page, viewerID)
+	}
 
-		commentResp := &response.CreateCommentResponse{
-			CommentID: comment.CommentID,
-			PostID:   comment.PostID,
-			UserID:   comment.UserID,
-			User: response.UserSummary{
-				UserID:    user.ID,
-				Username:  user.Username,
-				FullName: user.Fullname,
-			},
-			Content:   comment.Content,
-			CreatedAt: comment.CreatedAt,
-		}
-		commentResps = append(commentResps, commentResp)
+	// step 2: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
 	}
+	afterCreatedAt, afterID := after.After()
 
-	// step 6: validasi kalau gaada komentar
-	if len(commentResps) == 0 {
-		return []*response.CreateCommentResponse{}, nil
+	// step 3: ambil balasan-nya
+	replies, err := s.commentRepository.GetReplies(ctx, s.DB, commentID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
 	}
 
-	// step 7: Ubah ke dalam JSON untuk disimpan ke dalam cache
-	jsonData, err := json.Marshal(commentResps)
-	if err == nil {
-		// step 8: Kalau gaada error, simpan ke dalam cache
-		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
+	// step 4: susun halaman-nya lalu simpan ke cache
+	page, err := s.buildCommentPage(ctx, replies, limit)
+	if err != nil {
+		return nil, err
 	}
+	s.setCachedPage(ctx, cacheKey, page)
 
-	// step 9: Kembalikan commentResps-nya
-	return commentResps, nil
+	return s.hideComments(ctx, page, viewerID)
 }
 
-func (s *CommentServiceImpl) Delete(ctx context.Context, commentID int) (string, error) {
-	// step 1: start a transaction
-	tx, err := s.DB.Begin()
+// GetNeedsReview menampilkan komentar yang ditandai classifier (mood distress) ke moderator, pakai cursor yang sama dengan halaman komentar
+func (s *CommentServiceImpl) GetNeedsReview(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.CreateCommentResponse], error) {
+	// step 1: pastikan yang minta adalah moderator
+	isModerator, err := s.access.HasPermission(ctx, userID, entity.PermissionModerateReports)
+	if err != nil {
+		return nil, err
+	}
+	if !isModerator {
+		return nil, ErrCommentReviewForbidden
+	}
+
+	// step 2: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 3: ambil komentarnya (tidak di-cache, antriannya harus selalu terbaru)
+	comments, err := s.commentRepository.GetNeedsReview(ctx, s.DB, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildCommentPage(ctx, comments, limit)
+}
+
+func (s *CommentServiceImpl) Delete(ctx context.Context, commentID, userID int) (string, error) {
+	// step 1: cari comment-nya dulu sebelum transaction dibuka
+	comment, err := s.commentRepository.GetByID(ctx, s.DB, commentID)
 	if err != nil {
 		return "", err
 	}
+	if comment == nil || comment.IsDeleted {
+		return "", nil // Comment not found
+	}
 
-	// step 2: siapkan rollback
-	defer func() {
+	// step 1.1: hapusnya ikut menghapus semua balasan di bawahnya, jadi cuma pemilik komentar atau moderator yang boleh
+	if comment.UserID != userID {
+		isModerator, err := s.access.HasPermission(ctx, userID, entity.PermissionModerateReports)
 		if err != nil {
-			tx.Rollback()
+			return "", err
 		}
-	}()
+		if !isModerator {
+			return "", ErrCommentForbidden
+		}
+	}
 
-	// step 3: cari comment-nya
-	comment, err := s.commentRepository.GetByID(ctx, s.DB, commentID)
+	// step 2: start a transaction
+	tx, err := s.DB.Begin()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", fmt.Errorf("comment with ID %d not found", commentID)
-		}
 		return "", err
 	}
 
-	// step 4: delete comment-nya
-	message, err := s.commentRepository.Delete(ctx, tx, commentID)
+	// step 3: siapkan rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 4: soft delete comment-nya beserta semua balasan di bawahnya
+	deletedIDs, err := s.commentRepository.SoftDeleteThread(ctx, tx, commentID)
 	if err != nil {
 		return "", err
 	}
 
 	// step 5: commit transaction
-	if err := tx.Commit(); err != nil {
+	if err = tx.Commit(); err != nil {
 		return "", err
 	}
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditCommentDeleted,
+		TargetType: entity.AuditTargetComment,
+		TargetID:   &commentID,
+		Metadata:   map[string]interface{}{"owner_id": comment.UserID, "post_id": comment.PostID, "deleted_ids": deletedIDs},
+	})
 
-	// step 6: invalidate cache pada comment list post dan user, serta cache post
-	postCacheKey := fmt.Sprintf("comment:post:%d:v%d", comment.PostID, cacheVersion)
+	// step 6: invalidate cache semua komentar yang kena, halaman komentar post-nya, cache user, serta cache post
+	for _, id := range deletedIDs {
+		_ = s.RedisClient.Del(ctx, commentCacheKey(id)).Err()
+	}
 	userCacheKey := fmt.Sprintf("comment:user:%d:v%d", comment.UserID, cacheVersion)
-	postDetailCacheKey := fmt.Sprintf("post:%d:v%d", comment.PostID, cacheVersion)
+	postDetailCacheKey := postCacheKey(comment.PostID)
 
-	_ = s.RedisClient.Del(ctx, postCacheKey).Err()
 	_ = s.RedisClient.Del(ctx, userCacheKey).Err()
 	_ = s.RedisClient.Del(ctx, postDetailCacheKey).Err()
+	s.invalidateCommentPages(ctx, comment.PostID)
 
 	// step 7: kembalikan message-nya
-	return message, nil
+	return fmt.Sprintf("Comment with ID %d deleted successfully", commentID), nil
+}
+
+func (s *CommentServiceImpl) GetByID(ctx context.Context, commentID, viewerID int) (*response.CreateCommentResponse, error) {
+	// Step 1: Ambil komentar-nya (dari cache kalau ada)
+	commentResp, err := s.loadComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Pastikan post-nya boleh dilihat viewer (cache-nya dipakai semua viewer, jadi selalu dicek setelahnya)
+	if _, err := s.postService.Find(ctx, commentResp.PostID, viewerID); err != nil {
+		return nil, err
+	}
+
+	// step 3: Kembalikan commentResp-nya
+	return commentResp, nil
 }
 
-func (s *CommentServiceImpl) GetByID(ctx context.Context, commentID int) (*response.CreateCommentResponse, error) {
+func (s *CommentServiceImpl) loadComment(ctx context.Context, commentID int) (*response.CreateCommentResponse, error) {
 	// Step 1: Ambil cacheKey-nya, ini contoh cara penyimpanannya di redis (cacheKey -> comment:commentID:v1)
-	cacheKey := fmt.Sprintf("comment:%d:v%d", commentID, cacheVersion)
-	
+	cacheKey := commentCacheKey(commentID)
+
 	// Step 2: Ambil data-nya berdasarkan cacheKey-nya
 	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
 	if err == nil { // Kalau gaada error
 		var commentResp response.CreateCommentResponse
 		// Step 3: Decode JSON-nya dari redis ke dalam struct
-		if err := json.Unmarshal([]byte(cached), &commentResp); err != nil {
+		if err := json.Unmarshal([]byte(cached), &commentResp); err == nil {
 			return &commentResp, nil
 		}
 	}
 
 	// Step 4: Cari komentar-nya dalam database
 	comment, err := s.commentRepository.GetByID(ctx, s.DB, commentID)
-	if err != nil {
+	if err != nil || comment == nil {
 		if err == sql.ErrNoRows || comment == nil {
-			return nil, fmt.Errorf("comment with ID %d not found", commentID)
+			return nil, fmt.Errorf("%w: ID %d", ErrCommentNotFound, commentID)
 		}
 		return nil, err
 	}
 
-	// Step 5: Load User dan Post-nya
+	// Step 5: Load User-nya
 	user, err := s.userRepository.FindByID(ctx, s.DB, comment.UserID)
 	if err != nil {
 		if err == sql.ErrNoRows || user == nil {
@@ -306,15 +520,127 @@ func (s *CommentServiceImpl) GetByID(ctx context.Context, commentID int) (*respo
 		}
 		return nil, err
 	}
-	post, err := s.postRepository.Find(ctx, s.DB, comment.PostID)
+	// Step 6: Convert ke response
+	commentResp := toCommentResponse(comment, user)
+
+	// Step 7: Ubah ke dalam JSON untuk disimpan ke dalam cache
+	jsonData, err := json.Marshal(commentResp)
+	if err == nil {
+		// Step 8: Kalau gaada error, simpan ke dalam cache
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
+	}
+
+	return commentResp, nil
+}
+
+// buildCommentPage ngambil user semua komentar sekaligus (batch query) lalu motong hasilnya sesuai limit
+func (s *CommentServiceImpl) buildCommentPage(ctx context.Context, comments []*entity.Comment, limit int) (*pagination.Page[*response.CreateCommentResponse], error) {
+	commentResponses := []*response.CreateCommentResponse{}
+	if len(comments) == 0 {
+		return pagination.NewPage(commentResponses, limit, nil), nil
+	}
+
+	// Lakukan batch query untuk ambil user-nya
+	userIDMap := map[int]bool{}
+	for _, comment := range comments {
+		userIDMap[comment.UserID] = true
+	}
+
+	var userIDs []int
+	for id := range userIDMap {
+		userIDs = append(userIDs, id)
+	}
+
+	users, err := s.userRepository.FindByIDs(ctx, s.DB, userIDs)
 	if err != nil {
-		if err == sql.ErrNoRows || post == nil {
-			return nil, fmt.Errorf("post with ID %d not found", comment.PostID)
+		return nil, fmt.Errorf("error fetching users: %v", err)
+	}
+
+	userMap := map[int]*entity.User{}
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	// Convert ke dalam response
+	for _, comment := range comments {
+		user, ok := userMap[comment.UserID]
+		if !ok {
+			fmt.Printf("user with ID %d not found for comment ID %d\n", comment.UserID, comment.CommentID)
+			continue
 		}
+		commentResponses = append(commentResponses, toCommentResponse(comment, user))
+	}
+
+	// baris tambahan (limit+1) dibuang di sini, cursor-nya dari komentar terakhir yang dikirim
+	return pagination.NewPage(commentResponses, limit, func(comment *response.CreateCommentResponse) string {
+		return pagination.EncodeCursor(comment.CreatedAt, comment.CommentID)
+	}), nil
+}
+
+// hideComments membuang komentar dari user yang terblokir/di-mute viewer.
+// Cursor halamannya tidak diubah, jadi halaman berikutnya tetap sama untuk semua viewer.
+func (s *CommentServiceImpl) hideComments(ctx context.Context, page *pagination.Page[*response.CreateCommentResponse], viewerID int) (*pagination.Page[*response.CreateCommentResponse], error) {
+	hidden, err := s.guard.HiddenUserIDs(ctx, viewerID)
+	if err != nil {
 		return nil, err
 	}
+	if len(hidden) == 0 {
+		return page, nil
+	}
 
-	// Step 6: Convert ke response
+	visible := []*response.CreateCommentResponse{}
+	for _, comment := range page.Items {
+		if !hidden[comment.UserID] {
+			visible = append(visible, comment)
+		}
+	}
+	filtered := *page
+	filtered.Items = visible
+	return &filtered, nil
+}
+
+func (s *CommentServiceImpl) getCachedPage(ctx context.Context, cacheKey string) (*pagination.Page[*response.CreateCommentResponse], bool) {
+	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
+	if err != nil {
+		return nil, false
+	}
+	var page pagination.Page[*response.CreateCommentResponse]
+	if err := json.Unmarshal([]byte(cached), &page); err != nil {
+		return nil, false
+	}
+	return &page, true
+}
+
+func (s *CommentServiceImpl) setCachedPage(ctx context.Context, cacheKey string, page *pagination.Page[*response.CreateCommentResponse]) {
+	jsonData, err := json.Marshal(page)
+	if err == nil {
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
+	}
+}
+
+// Semua halaman komentar (top-level dan balasan) dalam satu post pakai generation yang sama,
+// jadi setiap ada komentar baru / dihapus cukup naikkan generation-nya
+func (s *CommentServiceImpl) commentPageGeneration(ctx context.Context, postID int) int64 {
+	generation, err := s.RedisClient.Get(ctx, commentPageGenerationKey(postID)).Int64()
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+func (s *CommentServiceImpl) invalidateCommentPages(ctx context.Context, postID int) {
+	_ = s.RedisClient.Incr(ctx, commentPageGenerationKey(postID)).Err()
+}
+
+func commentPageGenerationKey(postID int) string {
+	return fmt.Sprintf("comment:post:%d:gen", postID)
+}
+
+func commentCacheKey(commentID int) string {
+	return fmt.Sprintf("comment:%d:v%d", commentID, cacheVersion)
+}
+
+func toCommentResponse(comment *entity.Comment, user *entity.User) *response.CreateCommentResponse {
 	commentResp := &response.CreateCommentResponse{
 		CommentID: comment.CommentID,
 		PostID:   comment.PostID,
@@ -324,17 +650,28 @@ func (s *CommentServiceImpl) GetByID(ctx context.Context, commentID int) (*respo
 			Username:  user.Username,
 			FullName: user.Fullname,
 		},
+		ParentCommentID: comment.ParentCommentID,
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		IsDeleted:  comment.IsDeleted,
 		Content:   comment.Content,
+		IsEdited:  comment.EditedAt != nil,
+		EditedAt:  comment.EditedAt,
 		CreatedAt: comment.CreatedAt,
 	}
-
-	// Step 7: Ubah ke dalam JSON untuk disimpan ke dalam cache
-	jsonData, err := json.Marshal(commentResp)
-	if err == nil {
-		// Step 8: Kalau gaada error, simpan ke dalam cache
-		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
+	if comment.Mood != nil {
+		commentResp.Mood = *comment.Mood
 	}
 
-	// step 9: Kembalikan commentResp-nya
-	return commentResp, nil
-}
\ No newline at end of file
+	// komentar yang sudah dihapus tetap muncul sebagai placeholder supaya balasan di bawahnya ga kehilangan konteks
+	if comment.IsDeleted {
+		commentResp.UserID = 0
+		commentResp.User = deletedUserSummary
+		commentResp.Content = deletedCommentContent
+	} else if comment.HiddenAt != nil {
+		commentResp.IsHidden = true
+		commentResp.Content = hiddenCommentContent
+		commentResp.Mood = ""
+	}
+	return commentResp
+}
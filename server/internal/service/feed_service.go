@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type FeedService interface {
+	GetFeed(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error)
+}
+
+type FeedServiceImpl struct {
+	DB             *sql.DB
+	PostRepository repository.PostRepository
+	PostService    PostService
+	RedisClient    *redis.Client
+}
+
+func NewFeedService(db *sql.DB, postRepository repository.PostRepository, postService PostService, redisClient *redis.Client) FeedService {
+	return &FeedServiceImpl{
+		DB:             db,
+		PostRepository: postRepository,
+		PostService:    postService,
+		RedisClient:    redisClient,
+	}
+}
+
+const (
+	feedVersion = 1
+	feedTTL     = 30 * time.Minute
+
+	// kandidat feed diambil dari postingan 14 hari terakhir, maksimal 500 postingan
+	feedWindow        = 14 * 24 * time.Hour
+	maxFeedCandidates = 500
+
+	// postingan orang lain (bukan teman/tag) baru dianggap "suportif" kalau reaksinya minimal segini
+	minSupportiveReactions = 3
+
+	// fan-out on write cuma dilakukan kalau temannya sedikit, sisanya nunggu feed-nya dibangun ulang
+	maxFanOutFriends = 200
+)
+
+type feedSource int
+
+const (
+	feedSourceFriend feedSource = iota // termasuk postingan sendiri
+	feedSourceFollowedTag
+	feedSourceSupportive
+)
+
+func feedKey(userID int) string {
+	return fmt.Sprintf("feed:%d:v%d", userID, feedVersion)
+}
+
+// meta key sekaligus penanda feed-nya sudah dibangun, isinya "1" kalau user sedang at-risk
+func feedMetaKey(userID int) string {
+	return fmt.Sprintf("feed:%d:meta:v%d", userID, feedVersion)
+}
+
+// feedScore menghitung skor postingan untuk satu viewer. Skor recency-nya pakai waktu posting (bukan umur postingan),
+// jadi skor tidak berubah seiring waktu dan postingan baru selalu masuk di atas cursor yang sedang dipakai.
+// Kira-kira tiap 12.5 jam setara dengan 10x lipat engagement. Return false kalau postingan tidak boleh masuk feed viewer ini.
+func feedScore(post *entity.Post, source feedSource, reactionCount, commentCount int, viewerAtRisk bool) (float64, bool) {
+	score := float64(post.CreatedAt.Unix()) / 45000
+	score += math.Log10(float64(1 + reactionCount + 2*commentCount))
+
+	switch source {
+	case feedSourceFriend:
+		score += 1.0
+	case feedSourceFollowedTag:
+		score += 0.5
+	case feedSourceSupportive:
+		if viewerAtRisk {
+			score += 0.5
+		}
+	}
+
+	// jangan banjiri user yang sedang at-risk dengan konten negatif:
+	// postingan distress dari orang yang bukan teman tidak dimasukkan, dari teman tetap masuk tapi diturunkan
+	if viewerAtRisk && utils.IsDistressMood(post.Mood) {
+		if source != feedSourceFriend {
+			return 0, false
+		}
+		score -= 1.0
+	}
+	return score, true
+}
+
+func (s *FeedServiceImpl) GetFeed(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error) {
+	// step 1: decode cursor-nya (skor + postid dari item terakhir halaman sebelumnya)
+	after, err := pagination.DecodeScoreCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: pastikan feed user ini sudah ada di Redis, kalau belum (atau sudah expired) bangun ulang
+	built, err := s.RedisClient.Exists(ctx, feedMetaKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if built == 0 {
+		if err := s.rebuildFeed(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// step 3: ambil satu halaman dari sorted set, mulai dari skor cursor ke bawah
+	entries, hasMore, err := s.readFeedPage(ctx, userID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: load postingannya (tetap dicek visibility-nya, postingan yang sudah dihapus otomatis hilang)
+	postIDs := make([]int, len(entries))
+	for i, entry := range entries {
+		postIDs[i] = entry.ID
+	}
+	posts, err := s.PostService.FindByIDs(ctx, postIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// cursor-nya dari entry terakhir di sorted set (bukan dari posts), supaya tetap jalan walaupun postingan terakhir sudah dihapus
+	page := &pagination.Page[*response.CreatePostResponse]{
+		Items:   posts,
+		HasMore: hasMore,
+	}
+	if hasMore && len(entries) > 0 {
+		last := entries[len(entries)-1]
+		page.NextCursor = pagination.EncodeScoreCursor(last.Score, last.ID)
+	}
+	return page, nil
+}
+
+func (s *FeedServiceImpl) readFeedPage(ctx context.Context, userID int, after *pagination.ScoreCursor, limit int) ([]*pagination.ScoreCursor, bool, error) {
+	// skor yang sama persis jarang terjadi, tapi tetap diambil sedikit lebih banyak supaya urutan (skor, postid) aman
+	rangeBy := &redis.ZRangeBy{Min: "-inf", Max: "+inf", Count: int64(limit + 20)}
+	if after != nil {
+		rangeBy.Max = strconv.FormatFloat(after.Score, 'f', -1, 64)
+	}
+	members, err := s.RedisClient.ZRevRangeByScoreWithScores(ctx, feedKey(userID), rangeBy).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entries []*pagination.ScoreCursor
+	for _, member := range members {
+		postID, err := strconv.Atoi(fmt.Sprint(member.Member))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, &pagination.ScoreCursor{Score: member.Score, ID: postID})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].ID > entries[j].ID
+	})
+
+	var page []*pagination.ScoreCursor
+	for _, entry := range entries {
+		if after != nil && entry.Score == after.Score && entry.ID >= after.ID {
+			continue
+		}
+		page = append(page, entry)
+	}
+
+	hasMore := len(page) > limit
+	if hasMore {
+		page = page[:limit]
+	}
+	return page, hasMore, nil
+}
+
+// rebuildFeed ngitung ulang seluruh feed user dari database lalu menyimpannya sebagai sorted set (postid -> skor)
+func (s *FeedServiceImpl) rebuildFeed(ctx context.Context, userID int) error {
+	atRisk, err := s.isAtRisk(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	candidates, err := s.PostRepository.FindFeedCandidates(ctx, s.DB, userID, time.Now().Add(-feedWindow), minSupportiveReactions, maxFeedCandidates)
+	if err != nil {
+		return err
+	}
+
+	members := []redis.Z{}
+	for _, candidate := range candidates {
+		source := feedSourceSupportive
+		if candidate.FromFriend {
+			source = feedSourceFriend
+		} else if candidate.FromFollowedTag {
+			source = feedSourceFollowedTag
+		}
+		score, ok := feedScore(candidate.Post, source, candidate.ReactionCount, candidate.CommentCount, atRisk)
+		if !ok {
+			continue
+		}
+		members = append(members, redis.Z{Score: score, Member: candidate.Post.PostID})
+	}
+
+	meta := "0"
+	if atRisk {
+		meta = "1"
+	}
+
+	_, err = s.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, feedKey(userID))
+		if len(members) > 0 {
+			pipe.ZAdd(ctx, feedKey(userID), members...)
+			pipe.Expire(ctx, feedKey(userID), feedTTL)
+		}
+		pipe.Set(ctx, feedMetaKey(userID), meta, feedTTL)
+		return nil
+	})
+	return err
+}
+
+// isAtRisk: user dianggap sedang at-risk kalau minimal 2 dan setidaknya separuh postingannya seminggu terakhir bermood distress
+func (s *FeedServiceImpl) isAtRisk(ctx context.Context, userID int) (bool, error) {
+	posts, err := s.PostRepository.FindRecentByUserID(ctx, s.DB, userID, time.Now().AddDate(0, 0, -7), 20)
+	if err != nil {
+		return false, err
+	}
+	distress := 0
+	for _, post := range posts {
+		if utils.IsDistressMood(post.Mood) {
+			distress++
+		}
+	}
+	return distress >= 2 && distress*2 >= len(posts), nil
+}
+
+// addToFeed dipakai waktu fan-out on write. Feed yang belum dibangun dilewati saja, nanti ikut terhitung waktu dibangun.
+func addToFeed(ctx context.Context, redisClient *redis.Client, userID int, post *entity.Post, source feedSource) error {
+	meta, err := redisClient.Get(ctx, feedMetaKey(userID)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	score, ok := feedScore(post, source, 0, 0, meta == "1")
+	if !ok {
+		return nil
+	}
+	if err := redisClient.ZAdd(ctx, feedKey(userID), redis.Z{Score: score, Member: post.PostID}).Err(); err != nil {
+		return err
+	}
+	return redisClient.Expire(ctx, feedKey(userID), feedTTL).Err()
+}
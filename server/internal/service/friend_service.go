@@ -4,12 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"mood-bridge-v2/server/internal/entity"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
 	"mood-bridge-v2/server/internal/repository"
 	"mood-bridge-v2/server/internal/utils"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -18,48 +23,86 @@ import (
 type FriendService interface {
 	AddFriend(ctx context.Context, req request.FriendRequest) (*response.FriendResponse, error)
 	AcceptRequest(ctx context.Context, req request.FriendRequest) (*response.FriendResponse, error)
-	GetFriends(ctx context.Context, userID int) ([]*response.FriendResponse, error)
+	DeclineRequest(ctx context.Context, userID int, friendID int) (*response.FriendResponse, error)
+	CancelRequest(ctx context.Context, userID int, friendID int) (*response.FriendResponse, error)
+	GetFriends(ctx context.Context, userID, viewerID int, cursor string, limit int) (*pagination.Page[*response.FriendResponse], error)
 	Delete(ctx context.Context, friendID int) (string, error)
-	GetFriendRequests(ctx context.Context, userID int) ([]*response.FriendResponse, error)
+	GetFriendRequests(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.FriendResponse], error)
+	GetOutgoingRequests(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.FriendResponse], error)
 	GetFriendRecommendation(ctx context.Context, userID int) ([]*response.FriendRecommendationResponse, error)
+	GetMutualFriends(ctx context.Context, viewerID, userID int, cursor string, limit int) (*pagination.Page[*response.MutualFriendResponse], error)
+	GetFriendSuggestions(ctx context.Context, userID int) ([]*response.FriendSuggestionResponse, error)
 }
 
+var (
+	ErrFriendRequestExists   = errors.New("friend request already exists")
+	ErrFriendRequestIncoming = errors.New("this user already sent you a friend request, accept it instead")
+	ErrAlreadyFriends        = errors.New("you are already friends with this user")
+	ErrFriendRequestNotFound = errors.New("friend request not found")
+	ErrFriendRequestCooldown = errors.New("your friend request was declined recently, please try again later")
+	ErrFriendListHidden      = errors.New("this user's friend list is not visible to you")
+	ErrMutualFriendsWithSelf = errors.New("cannot get mutual friends with yourself")
+)
+
+const (
+	friendRequestCooldownEnv = "FRIEND_REQUEST_COOLDOWN_DAYS"
+	// Setelah permintaannya ditolak, pengirim baru bisa mengirim lagi ke user yang sama setelah waktu ini
+	defaultFriendRequestCooldown = 7 * 24 * time.Hour
+	// Jumlah kartu "people you may know" yang dikirim
+	friendSuggestionLimit = 20
+)
+
+// Jenis event WebSocket yang dikirim ke user yang sedang online, payload-nya FriendResponse
+const (
+	friendRequestEvent          = "friend_request"
+	friendRequestAcceptedEvent  = "friend_request_accepted"
+	friendRequestCancelledEvent = "friend_request_cancelled"
+)
+
 type FriendServiceImpl struct {
 	friendRepository repository.FriendRepository
 	userRepository repository.UserRepository
+	profileRepository repository.ProfileRepository
+	guard InteractionGuard
+	audit AuditLogger
+	notifications NotificationService
+	realtime RealtimePusher
+	cooldown time.Duration
 	DB *sql.DB
 	RedisClient *redis.Client
 }
 
-func NewFriendService(friendRepository repository.FriendRepository, userRepository repository.UserRepository, db *sql.DB, redisClient *redis.Client) FriendService {
+func NewFriendService(friendRepository repository.FriendRepository, userRepository repository.UserRepository, profileRepository repository.ProfileRepository, guard InteractionGuard, audit AuditLogger, notifications NotificationService, realtime RealtimePusher, db *sql.DB, redisClient *redis.Client) FriendService {
+	cooldown := defaultFriendRequestCooldown
+	if value := os.Getenv(friendRequestCooldownEnv); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			cooldown = time.Duration(days) * 24 * time.Hour
+		} else {
+			log.Printf("invalid %s %q, using the default of %s", friendRequestCooldownEnv, value, defaultFriendRequestCooldown)
+		}
+	}
+
 	return &FriendServiceImpl{
 		friendRepository: friendRepository,
 		userRepository: userRepository,
+		profileRepository: profileRepository,
+		guard: guard,
+		audit: audit,
+		notifications: notifications,
+		realtime: realtime,
+		cooldown: cooldown,
 		DB: db,
 		RedisClient: redisClient,
 	}
 }
 
 func (s *FriendServiceImpl) AddFriend(ctx context.Context, req request.FriendRequest) (*response.FriendResponse, error) {
-	// step 1: start transaction
-	tx, err := s.DB.Begin()
-	if err != nil {
-		return nil, err
-	}
-
-	// step 2: rollback transaction
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// step 3: validate request
+	// step 1: validate request
 	if err := utils.ValidateFriendInput(req.UserID, req.FriendUserID, 0); err != nil {
 		return nil, err
 	}
 
-	// step 4: find user
+	// step 2: find user
 	user, err := s.userRepository.FindByID(ctx, s.DB, req.UserID)
 	if err != nil {
 		if err == sql.ErrNoRows || user == nil {
@@ -68,71 +111,131 @@ func (s *FriendServiceImpl) AddFriend(ctx context.Context, req request.FriendReq
 		return nil, err
 	}
 
-	// step 5: check apakah user dan friend sudah berteman
-	exists, err := s.friendRepository.IsFriendExist(ctx, s.DB, req.UserID, req.FriendUserID)
+	// step 3: tidak bisa add friend ke/dari user yang saling blokir
+	if err := s.guard.CanInteract(ctx, req.UserID, req.FriendUserID); err != nil {
+		return nil, err
+	}
+
+	// step 4: cek hubungan yang sudah ada di antara keduanya (apa pun arahnya)
+	existing, err := s.friendRepository.FindBetween(ctx, s.DB, req.UserID, req.FriendUserID)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, fmt.Errorf("friend request already exists")
+	if err := s.checkCanRequest(existing, req.UserID); err != nil {
+		return nil, err
+	}
+
+	// step 5: start transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
 	}
 
-	// step 6: ubah ke entity friend
-	friend := &entity.Friend{
+	// step 6: rollback transaction
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 7: jalankan repository (baris lama yang declined/cancelled/blocked dipakai ulang)
+	newFriend, err := s.friendRepository.AddFriend(ctx, tx, &entity.Friend{
 		UserID:       req.UserID,
 		FriendUserID: req.FriendUserID,
-		FriendStatus: false, // false = pending
+		Status:       entity.FriendPending,
 		CreatedAt:    time.Now(),
-	}
-
-	// step 6: jalankan repository
-	newFriend, err := s.friendRepository.AddFriend(ctx, tx, friend)
+	})
 	if err != nil {
 		return nil, err
 	}
 	if newFriend == nil {
-		return nil, fmt.Errorf("failed to add friend")
+		// keduluan request lain di antara step 4 dan step 7
+		err = ErrFriendRequestExists
+		return nil, err
 	}
 
-	// step 7: commit transaction
-	if err := tx.Commit(); err != nil {
+	// step 8: commit transaction
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
-	
-	// step 8: return response
-	resp := &response.FriendResponse{
-		FriendID:    newFriend.FriendID,
-		UserID: newFriend.UserID,
-		FriendUserID: newFriend.FriendUserID,
-		FriendStatus: newFriend.FriendStatus,
-		CreatedAt: newFriend.CreatedAt,
-		User: response.UserSummary{
-			UserID: newFriend.User.ID,
-			Username: newFriend.User.Username,
-			FullName: newFriend.User.Fullname,
-		},
-	}
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditFriendRequested,
+		TargetType: entity.AuditTargetFriendship,
+		TargetID:   &newFriend.FriendID,
+		Metadata:   map[string]interface{}{"user_id": req.UserID, "friend_user_id": req.FriendUserID},
+	})
 
 	// step 9: cache logic
 	// 9.1: hapus cache lama dari daftar friend request punya si target karena ada request add friend yang baru
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("friendrequest:%d:v%d", req.FriendUserID, cacheVersion)).Err()
+	s.invalidateFriendList(ctx, "friendrequest", req.FriendUserID)
+	s.invalidateFriendList(ctx, "friendoutgoing", req.UserID)
 	// opsional: hapus cache daftar teman dari user dan target
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("friend:%d:v%d", req.UserID, cacheVersion)).Err()
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("friend:%d:v%d", req.FriendUserID, cacheVersion)).Err()
+	s.invalidateFriendList(ctx, "friend", req.UserID)
+	s.invalidateFriendList(ctx, "friend", req.FriendUserID)
 	// NOTES: kita gaush simpan request ini ke cache karena akan menimpa semua friend request yang ada di cache
 
-	// step 10: return response
-	return resp, nil
+	// step 10: kabari penerimanya, notifikasi disimpan dan kalau dia online langsung dikirim lewat WebSocket
+	s.notifications.Notify(ctx, req.FriendUserID, entity.NotificationFriendRequest,
+		fmt.Sprintf("%s sent you a friend request.", user.Username), &newFriend.FriendID)
+	s.realtime.PushToUser(req.FriendUserID, friendRequestEvent, newFriendResponse(newFriend, user))
+
+	// step 11: return response (user-nya si target)
+	return newFriendResponse(newFriend, newFriend.User), nil
+}
+
+// checkCanRequest menentukan apakah requesterID boleh mengirim permintaan baru ke pasangan yang sudah punya baris existing
+func (s *FriendServiceImpl) checkCanRequest(existing *entity.Friend, requesterID int) error {
+	if existing == nil {
+		return nil
+	}
+	switch existing.Status {
+	case entity.FriendAccepted:
+		return ErrAlreadyFriends
+	case entity.FriendPending:
+		if existing.UserID == requesterID {
+			return ErrFriendRequestExists
+		}
+		return ErrFriendRequestIncoming
+	case entity.FriendDeclined:
+		// cooldown cuma berlaku untuk pengirim yang ditolak, yang menolak boleh langsung mengirim balik
+		if existing.UserID == requesterID && existing.RespondedAt != nil && time.Since(*existing.RespondedAt) < s.cooldown {
+			return ErrFriendRequestCooldown
+		}
+	}
+	return nil
 }
 
 func (s *FriendServiceImpl) AcceptRequest(ctx context.Context, req request.FriendRequest) (*response.FriendResponse, error) {
-	// step 1: start transaction
+	// step 1: validate request
+	if err := utils.ValidateFriendInput(req.UserID, req.FriendUserID, 0); err != nil {
+		return nil, err
+	}
+
+	// step 2: find user (siapa tau usernya gaada tapi malah friend request kan serem)
+	user, err := s.userRepository.FindByID(ctx, s.DB, req.FriendUserID)
+	if err != nil {
+		if err == sql.ErrNoRows || user == nil {
+			return nil, fmt.Errorf("user with id %d not found", req.FriendUserID)
+		}
+		return nil, err
+	}
+
+	// step 3: check apakah user sudah berteman
+	exists, err := s.friendRepository.IsFriendAlreadyAccepted(ctx, s.DB, req.UserID, req.FriendUserID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyFriends
+	}
+
+	// step 4: start transaction
 	tx, err := s.DB.Begin()
 	if err != nil {
 		return nil, err
 	}
 
-	// step 2: rollback transaction
+	// step 5: rollback transaction
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
@@ -142,124 +245,202 @@ func (s *FriendServiceImpl) AcceptRequest(ctx context.Context, req request.Frien
 		}
 	}()
 
-	// step 3: validate request
-	if err := utils.ValidateFriendInput(req.UserID, req.FriendUserID, 0); err != nil {
+	// step 6: jalankan repository-nya
+	acceptFriend, err := s.friendRepository.AcceptRequest(ctx, tx, &entity.Friend{
+		UserID:       req.UserID,
+		FriendUserID: req.FriendUserID,
+		Status:       entity.FriendAccepted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if acceptFriend == nil {
+		err = ErrFriendRequestNotFound
 		return nil, err
 	}
 
-	// step 4: find user (siapa tau usernya gaada tapi malah friend request kan serem)
-	user, err := s.userRepository.FindByID(ctx, s.DB, req.FriendUserID)
-	if err != nil {
-		if err == sql.ErrNoRows || user == nil {
-			return nil, fmt.Errorf("user with id %d not found", req.UserID)
-		}
+	// step 7: commit transaction
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditFriendAccepted,
+		TargetType: entity.AuditTargetFriendship,
+		TargetID:   &acceptFriend.FriendID,
+		Metadata:   map[string]interface{}{"user_id": req.UserID, "friend_user_id": req.FriendUserID},
+	})
 
-	// step 5: check apakah user sudah berteman
-	exists, err := s.friendRepository.IsFriendAlreadyAccepted(ctx, s.DB, req.UserID, req.FriendUserID)
+	// step 8: cache invalidation (ketika kita accept sebuah request, maka perubahan dalam database ada pada friend list dan friend request)
+	s.invalidateFriendList(ctx, "friend", req.UserID)
+	s.invalidateFriendList(ctx, "friend", req.FriendUserID)
+
+	// Hapus juga cache friend request punya si penerima dan daftar request keluar punya si pengirim
+	s.invalidateFriendList(ctx, "friendrequest", req.UserID)
+	s.invalidateFriendList(ctx, "friendoutgoing", req.FriendUserID)
+	// rekomendasi keduanya masih berisi satu sama lain dan jumlah mutual friend yang lama
+	_ = s.RedisClient.Del(ctx, friendRecommendationCacheKey(req.UserID), friendRecommendationCacheKey(req.FriendUserID)).Err()
+
+	// step 9: kabari pengirimnya kalau permintaannya diterima
+	if accepter, err := s.userRepository.FindByID(ctx, s.DB, req.UserID); err == nil && accepter != nil {
+		s.notifications.Notify(ctx, req.FriendUserID, entity.NotificationFriendAccepted,
+			fmt.Sprintf("%s accepted your friend request.", accepter.Username), &acceptFriend.FriendID)
+		s.realtime.PushToUser(req.FriendUserID, friendRequestAcceptedEvent, newFriendResponse(acceptFriend, accepter))
+	}
+
+	// step 10: return response
+	return newFriendResponse(acceptFriend, user), nil
+}
+
+// DeclineRequest dipanggil penerima permintaan. Pengirim tidak diberi notifikasi, tapi tidak bisa mengirim lagi selama cooldown.
+func (s *FriendServiceImpl) DeclineRequest(ctx context.Context, userID int, friendID int) (*response.FriendResponse, error) {
+	// step 1: tandai permintaannya declined
+	declined, err := s.respondToRequest(ctx, func(tx *sql.Tx) (*entity.Friend, error) {
+		return s.friendRepository.Decline(ctx, tx, friendID, userID)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, fmt.Errorf("friend request already accepted")
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditFriendDeclined,
+		TargetType: entity.AuditTargetFriendship,
+		TargetID:   &declined.FriendID,
+		Metadata:   map[string]interface{}{"user_id": declined.UserID, "friend_user_id": declined.FriendUserID},
+	})
+
+	// step 2: buang cache daftar request kedua sisi
+	s.invalidateFriendList(ctx, "friendrequest", userID)
+	s.invalidateFriendList(ctx, "friendoutgoing", declined.UserID)
+
+	// step 3: return response (user-nya si pengirim), permintaannya sudah terlanjur ditolak jadi gagal ambil user tidak dianggap error
+	requester, err := s.userRepository.FindByID(ctx, s.DB, declined.UserID)
+	if err != nil {
+		log.Printf("failed to load requester of declined friend request %d: %v", declined.FriendID, err)
+		requester = nil
 	}
-	
-	// step 6: jalankan repository-nya
-	acceptFriend, err := s.friendRepository.AcceptRequest(ctx, tx, &entity.Friend{
-		UserID:       req.UserID,
-		FriendUserID: req.FriendUserID,
-		FriendStatus: true,
-		CreatedAt:    time.Now(), // update created at buat nunjukin kapan dia di-accept
+	return newFriendResponse(declined, requester), nil
+}
+
+// CancelRequest dipanggil pengirim untuk menarik permintaan yang belum dijawab
+func (s *FriendServiceImpl) CancelRequest(ctx context.Context, userID int, friendID int) (*response.FriendResponse, error) {
+	// step 1: tandai permintaannya cancelled
+	cancelled, err := s.respondToRequest(ctx, func(tx *sql.Tx) (*entity.Friend, error) {
+		return s.friendRepository.Cancel(ctx, tx, friendID, userID)
 	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditFriendCancelled,
+		TargetType: entity.AuditTargetFriendship,
+		TargetID:   &cancelled.FriendID,
+		Metadata:   map[string]interface{}{"user_id": cancelled.UserID, "friend_user_id": cancelled.FriendUserID},
+	})
+
+	// step 2: buang cache daftar request kedua sisi
+	s.invalidateFriendList(ctx, "friendoutgoing", userID)
+	s.invalidateFriendList(ctx, "friendrequest", cancelled.FriendUserID)
+
+	// step 3: kalau penerimanya online, hapus permintaannya dari layar dia
+	requester, err := s.userRepository.FindByID(ctx, s.DB, userID)
+	if err == nil && requester != nil {
+		s.realtime.PushToUser(cancelled.FriendUserID, friendRequestCancelledEvent, newFriendResponse(cancelled, requester))
+	}
 
+	// step 4: return response (user-nya si penerima)
+	recipient, err := s.userRepository.FindByID(ctx, s.DB, cancelled.FriendUserID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("friend request not found")
+		log.Printf("failed to load recipient of cancelled friend request %d: %v", cancelled.FriendID, err)
+		recipient = nil
+	}
+	return newFriendResponse(cancelled, recipient), nil
+}
+
+// respondToRequest menjalankan decline/cancel di dalam transaction, ErrFriendRequestNotFound kalau tidak ada yang berubah
+func (s *FriendServiceImpl) respondToRequest(ctx context.Context, apply func(tx *sql.Tx) (*entity.Friend, error)) (*entity.Friend, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
 		}
+	}()
+
+	friend, err := apply(tx)
+	if err != nil {
+		return nil, err
+	}
+	if friend == nil {
+		err = ErrFriendRequestNotFound
 		return nil, err
 	}
 
-	// step 7: commit transaction
-	if err := tx.Commit(); err != nil {
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
+	return friend, nil
+}
 
-	// step 8: return response
+func newFriendResponse(friend *entity.Friend, user *entity.User) *response.FriendResponse {
 	resp := &response.FriendResponse{
-		FriendID:    acceptFriend.FriendID,
-		UserID: acceptFriend.UserID,
-		FriendUserID: acceptFriend.FriendUserID,
-		FriendStatus: acceptFriend.FriendStatus,
-		CreatedAt: acceptFriend.CreatedAt,
-		User: response.UserSummary{
-			UserID: user.ID,
+		FriendID:     friend.FriendID,
+		UserID:       friend.UserID,
+		FriendUserID: friend.FriendUserID,
+		FriendStatus: friend.Status == entity.FriendAccepted,
+		Status:       friend.Status,
+		CreatedAt:    friend.CreatedAt,
+	}
+	if user != nil {
+		resp.User = response.UserSummary{
+			UserID:   user.ID,
 			Username: user.Username,
 			FullName: user.Fullname,
-		},
+		}
 	}
+	return resp
+}
 
-	// step 9: cache invalidation (ketika kita accept sebuah request, maka perubahan dalam database ada pada friend list dan friend request)
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("friend:%d:v%d", req.UserID, cacheVersion)).Err()
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("friend:%d:v%d", req.FriendUserID, cacheVersion)).Err()
-
-	// Hapus juga cache friend request punya si pengirim (karena kalau dia accept, berarti friend request-nya udah gaada)
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("friendrequest:%d:v%d", req.UserID, cacheVersion)).Err()
+// GetFriends daftar teman userID sesuai friends visibility di profilnya (viewerID 0 = belum login)
+func (s *FriendServiceImpl) GetFriends(ctx context.Context, userID, viewerID int, cursor string, limit int) (*pagination.Page[*response.FriendResponse], error) {
+	// step 0: cek apakah viewer boleh melihat daftar teman user ini (cache-nya per pemilik, jadi dicek sebelum ambil cache)
+	if err := s.checkFriendListVisible(ctx, userID, viewerID); err != nil {
+		return nil, err
+	}
 
-	// step 10: return response
-	return resp, nil
-}
+	// step 1: cache key (semua halaman berbagi generation yang sama, lihat invalidateFriendList)
+	friendCacheKey := fmt.Sprintf("friend:%d:g%d:cursor:%s:limit:%d:v%d", userID, s.friendListGeneration(ctx, "friend", userID), cursor, limit, cacheVersion)
 
-func (s *FriendServiceImpl) GetFriends(ctx context.Context, userID int) ([]*response.FriendResponse, error) {
-	// step 1: cache key
-	friendCacheKey := fmt.Sprintf("friend:%d:v%d", userID, cacheVersion)
-	
 	// step 2: get cache based on cache key
-	cachedFriends, err := s.RedisClient.Get(ctx, friendCacheKey).Result()
-	if err == nil {
-		var friends []*response.FriendResponse
-		if err := json.Unmarshal([]byte(cachedFriends), &friends); err == nil {
-			return friends, nil
-		}
+	if page, ok := s.getCachedFriendPage(ctx, friendCacheKey); ok {
+		return page, nil
 	}
 
-	// step 3: get friends from repository
-	friends, err := s.friendRepository.GetFriends(ctx, s.DB, userID)
+	// step 3: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
 	if err != nil {
 		return nil, err
 	}
+	afterCreatedAt, afterID := after.After()
 
-	// step 4: convert to response
-	var friendResponses []*response.FriendResponse
-	for _, friend := range *friends {
-		friendResponses = append(friendResponses, &response.FriendResponse{
-			FriendID:    friend.FriendID,
-			UserID:      friend.UserID,
-			FriendUserID: friend.FriendUserID,
-			FriendStatus: friend.FriendStatus,
-			CreatedAt:    friend.CreatedAt,
-			User: response.UserSummary{
-				UserID:      friend.User.ID,
-				Username:    friend.User.Username,
-				FullName:    friend.User.Fullname,
-			},
-		})
+	// step 4: get friends from repository (ambil satu lebih banyak buat ngecek masih ada halaman berikutnya)
+	friends, err := s.friendRepository.GetFriends(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
 	}
 
-	// step 5: validasi kalau gaada yang ketemu (kosong misalnya)
-	if len(friendResponses) == 0 {
-		return []*response.FriendResponse{}, nil
+	// step 5: convert to response
+	friendResponses := []*response.FriendResponse{}
+	for _, friend := range *friends {
+		friendResponses = append(friendResponses, newFriendResponse(&friend, friend.User))
 	}
+	page := newFriendPage(friendResponses, limit)
 
-	// step 6: ubah ke dalam json untuk disimpan ke cache
-	jsonData, err := json.Marshal(friendResponses)
-	if err == nil {
-		// step 7: kalau gaada error ketika perubahan ke json ini, kita simpan ke cache
-		_ = s.RedisClient.Set(ctx, friendCacheKey, jsonData, 10*time.Minute).Err()
-	}
+	// step 6: simpan ke cache
+	s.setCachedFriendPage(ctx, friendCacheKey, page)
 
-	// step 8: return response
-	return friendResponses, nil
+	// step 7: return response
+	return page, nil
 }
 
 func (s *FriendServiceImpl) Delete(ctx context.Context, friendID int) (string, error) {
@@ -290,73 +471,258 @@ func (s *FriendServiceImpl) Delete(ctx context.Context, friendID int) (string, e
 	if err != nil {
 		return "", err
 	}
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditFriendRemoved,
+		TargetType: entity.AuditTargetFriendship,
+		TargetID:   &friendID,
+	})
 
 	// step 5: delete cache (invalidate cache)
-	friendCacheKey := fmt.Sprintf("friend:%d:v%d", friendID, cacheVersion)
-	_ = s.RedisClient.Del(ctx, friendCacheKey).Err()
+	s.invalidateFriendList(ctx, "friend", friendID)
 
 	// step 6: return response
 	return message, nil
 }
 
-func (s *FriendServiceImpl) GetFriendRequests(ctx context.Context, userID int) ([]*response.FriendResponse, error) {
+func (s *FriendServiceImpl) GetFriendRequests(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.FriendResponse], error) {
 	// step 1: set cache key
-	friendCacheKey := fmt.Sprintf("friendrequest:%d:v%d", userID, cacheVersion)
+	friendCacheKey := fmt.Sprintf("friendrequest:%d:g%d:cursor:%s:limit:%d:v%d", userID, s.friendListGeneration(ctx, "friendrequest", userID), cursor, limit, cacheVersion)
 
 	// step 2: get cache based on cache key
-	cachedFriendRequests, err := s.RedisClient.Get(ctx, friendCacheKey).Result()
-	if err == nil {
-		var friendRequests []*response.FriendResponse
-		if err := json.Unmarshal([]byte(cachedFriendRequests), &friendRequests); err == nil {
-			return friendRequests, nil
+	if page, ok := s.getCachedFriendPage(ctx, friendCacheKey); ok {
+		return page, nil
+	}
+
+	// step 3: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 4: get friend requests from repository
+	friendRequests, err := s.friendRepository.GetFriendRequests(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 5: convert to response
+	friendRequestResponses := []*response.FriendResponse{}
+	for _, friendRequest := range *friendRequests {
+		friendRequest.User.ID = friendRequest.UserID // query-nya tidak mengambil userid dari tabel users
+		friendRequestResponses = append(friendRequestResponses, newFriendResponse(&friendRequest, friendRequest.User))
+	}
+	page := newFriendPage(friendRequestResponses, limit)
+
+	// step 6: simpan ke cache
+	s.setCachedFriendPage(ctx, friendCacheKey, page)
+
+	return page, nil
+}
+
+// GetOutgoingRequests daftar permintaan yang dikirim user dan belum dijawab, user di tiap item-nya si penerima
+func (s *FriendServiceImpl) GetOutgoingRequests(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.FriendResponse], error) {
+	// step 1: set cache key
+	friendCacheKey := fmt.Sprintf("friendoutgoing:%d:g%d:cursor:%s:limit:%d:v%d", userID, s.friendListGeneration(ctx, "friendoutgoing", userID), cursor, limit, cacheVersion)
+
+	// step 2: get cache based on cache key
+	if page, ok := s.getCachedFriendPage(ctx, friendCacheKey); ok {
+		return page, nil
+	}
+
+	// step 3: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 4: get outgoing requests from repository
+	outgoingRequests, err := s.friendRepository.GetOutgoingRequests(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 5: convert to response
+	outgoingResponses := []*response.FriendResponse{}
+	for _, outgoingRequest := range *outgoingRequests {
+		outgoingResponses = append(outgoingResponses, newFriendResponse(&outgoingRequest, outgoingRequest.User))
+	}
+	page := newFriendPage(outgoingResponses, limit)
+
+	// step 6: simpan ke cache
+	s.setCachedFriendPage(ctx, friendCacheKey, page)
+
+	return page, nil
+}
+
+// GetMutualFriends teman yang dimiliki viewer dan userID sekaligus
+func (s *FriendServiceImpl) GetMutualFriends(ctx context.Context, viewerID, userID int, cursor string, limit int) (*pagination.Page[*response.MutualFriendResponse], error) {
+	// step 1: validasi request
+	if viewerID == userID {
+		return nil, ErrMutualFriendsWithSelf
+	}
+
+	// step 2: cek blokir dan friends visibility user yang dilihat
+	if err := s.checkFriendListVisible(ctx, userID, viewerID); err != nil {
+		return nil, err
+	}
+
+	// step 3: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 4: ambil dari repository (satu lebih banyak buat ngecek masih ada halaman berikutnya)
+	users, err := s.friendRepository.GetMutualFriends(ctx, s.DB, viewerID, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 5: convert to response
+	mutualFriends := []*response.MutualFriendResponse{}
+	for _, user := range users {
+		mutualFriends = append(mutualFriends, &response.MutualFriendResponse{
+			UserID:    user.ID,
+			Username:  user.Username,
+			Fullname:  user.Fullname,
+			CreatedAt: user.CreatedAt,
+		})
+	}
+	return pagination.NewPage(mutualFriends, limit, func(friend *response.MutualFriendResponse) string {
+		return pagination.EncodeCursor(friend.CreatedAt, friend.UserID)
+	}), nil
+}
+
+// GetFriendSuggestions "people you may know" dari koneksi derajat dua
+func (s *FriendServiceImpl) GetFriendSuggestions(ctx context.Context, userID int) ([]*response.FriendSuggestionResponse, error) {
+	// step 1: cache key, ikut generation daftar teman user jadi langsung basi kalau pertemanannya berubah
+	cacheKey := fmt.Sprintf("friendsuggestion:%d:g%d:v%d", userID, s.friendListGeneration(ctx, "friend", userID), cacheVersion)
+
+	// step 2: get cache based on cache key
+	if cached, err := s.RedisClient.Get(ctx, cacheKey).Result(); err == nil {
+		var suggestions []*response.FriendSuggestionResponse
+		if err := json.Unmarshal([]byte(cached), &suggestions); err == nil {
+			return suggestions, nil
 		}
 	}
 
-	// step 3: get friend requests from repository
-	friendRequests, err := s.friendRepository.GetFriendRequests(ctx, s.DB, userID)
+	// step 3: ambil dari repository
+	suggestions, err := s.friendRepository.GetFriendSuggestions(ctx, s.DB, userID, friendSuggestionLimit)
 	if err != nil {
 		return nil, err
 	}
 
 	// step 4: convert to response
-	var friendRequestResponses []*response.FriendResponse
-	for _, friendRequest := range *friendRequests {
-		friendRequestResponses = append(friendRequestResponses, &response.FriendResponse{
-			FriendID:    friendRequest.FriendID,
-			UserID:      friendRequest.UserID,
-			FriendUserID: friendRequest.FriendUserID,
-			FriendStatus: friendRequest.FriendStatus,
-			CreatedAt:    friendRequest.CreatedAt,
-			User: response.UserSummary{
-				UserID:      friendRequest.UserID,
-				Username:    friendRequest.User.Username,
-				FullName:    friendRequest.User.Fullname,
-			},
+	suggestionResponses := []*response.FriendSuggestionResponse{}
+	for _, suggestion := range suggestions {
+		suggestionResponses = append(suggestionResponses, &response.FriendSuggestionResponse{
+			UserID:        suggestion.User.ID,
+			Username:      suggestion.User.Username,
+			Fullname:      suggestion.User.Fullname,
+			MutualFriends: suggestion.MutualFriends,
 		})
 	}
 
-	// step 5: validasi kalau gaada yang ketemu (kosong misalnya)
-	if len(friendRequestResponses) == 0 {
-		return []*response.FriendResponse{}, nil
+	// step 5: simpan ke cache
+	if jsonData, err := json.Marshal(suggestionResponses); err == nil {
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
 	}
 
-	// step 6: ubah ke dalam json untuk disimpan ke cache
-	jsonData, err := json.Marshal(friendRequestResponses)
+	return suggestionResponses, nil
+}
+
+// checkFriendListVisible: pemilik selalu boleh, selain itu tidak boleh saling blokir dan harus sesuai friends visibility
+// di profil pemilik (public = siapa saja, friends = cuma teman, private = cuma pemilik)
+func (s *FriendServiceImpl) checkFriendListVisible(ctx context.Context, ownerID, viewerID int) error {
+	if viewerID != 0 && viewerID == ownerID {
+		return nil
+	}
+	if viewerID != 0 {
+		if err := s.guard.CanInteract(ctx, viewerID, ownerID); err != nil {
+			return err
+		}
+	}
+
+	profile, err := s.profileRepository.FindByUserID(ctx, s.DB, ownerID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		profile = entity.DefaultUserProfile(ownerID)
+	}
+
+	switch profile.FriendsVisibility {
+	case entity.VisibilityPublic:
+		return nil
+	case entity.VisibilityFriends:
+		if viewerID == 0 {
+			return ErrFriendListHidden
+		}
+		isFriend, err := s.friendRepository.IsFriendAlreadyAccepted(ctx, s.DB, viewerID, ownerID)
+		if err != nil {
+			return err
+		}
+		if !isFriend {
+			return ErrFriendListHidden
+		}
+		return nil
+	default:
+		return ErrFriendListHidden
+	}
+}
+
+func newFriendPage(friendResponses []*response.FriendResponse, limit int) *pagination.Page[*response.FriendResponse] {
+	return pagination.NewPage(friendResponses, limit, func(friend *response.FriendResponse) string {
+		return pagination.EncodeCursor(friend.CreatedAt, friend.FriendID)
+	})
+}
+
+func (s *FriendServiceImpl) getCachedFriendPage(ctx context.Context, cacheKey string) (*pagination.Page[*response.FriendResponse], bool) {
+	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
+	if err != nil {
+		return nil, false
+	}
+	var page pagination.Page[*response.FriendResponse]
+	if err := json.Unmarshal([]byte(cached), &page); err != nil {
+		return nil, false
+	}
+	return &page, true
+}
+
+func (s *FriendServiceImpl) setCachedFriendPage(ctx context.Context, cacheKey string, page *pagination.Page[*response.FriendResponse]) {
+	jsonData, err := json.Marshal(page)
 	if err == nil {
-		// step 7: kalau gaada error ketika perubahan ke json ini, kita simpan ke cache
-		_ = s.RedisClient.Set(ctx, friendCacheKey, jsonData, 10*time.Minute).Err()
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
 	}
+}
 
-	// step 8: return response
-	return friendRequestResponses, nil
+// Cache daftar teman / friend request per user sekarang per halaman (cursor), jadi daripada hapus satu-satu
+// cukup naikkan generation-nya. kind = "friend", "friendrequest" (masuk), atau "friendoutgoing" (keluar).
+func (s *FriendServiceImpl) friendListGeneration(ctx context.Context, kind string, userID int) int64 {
+	generation, err := s.RedisClient.Get(ctx, friendListGenerationKey(kind, userID)).Int64()
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+func (s *FriendServiceImpl) invalidateFriendList(ctx context.Context, kind string, userID int) {
+	_ = s.RedisClient.Incr(ctx, friendListGenerationKey(kind, userID)).Err()
+}
+
+func friendListGenerationKey(kind string, userID int) string {
+	return fmt.Sprintf("%s:%d:gen", kind, userID)
 }
 
 func (s *FriendServiceImpl) GetFriendRecommendation(ctx context.Context, userID int) ([]*response.FriendRecommendationResponse, error) {
 	// step 1: set cache key
-	friendRecommendationCacheKey := fmt.Sprintf("friendrecommendation:%d:v%d", userID, cacheVersion)
+	cacheKey := friendRecommendationCacheKey(userID)
 
 	// step 2: get cache based on cache key
-	cachedFriendCommendations, err := s.RedisClient.Get(ctx, friendRecommendationCacheKey).Result()
+	cachedFriendCommendations, err := s.RedisClient.Get(ctx, cacheKey).Result()
 	if err == nil {
 		var friendRecommendations []*response.FriendRecommendationResponse
 		if err := json.Unmarshal([]byte(cachedFriendCommendations), &friendRecommendations); err == nil {
@@ -374,11 +740,14 @@ func (s *FriendServiceImpl) GetFriendRecommendation(ctx context.Context, userID
 	var friendRecommendationResponses []*response.FriendRecommendationResponse
 	for _, friendRecommendation := range *friendRecommendations {
 		friendRecommendationResponses = append(friendRecommendationResponses, &response.FriendRecommendationResponse{
-			UserID:      friendRecommendation.User.ID,
-			Username:    friendRecommendation.User.Username,
-			Fullname:    friendRecommendation.User.Fullname,
-			Email:       friendRecommendation.User.Email,
-			OverallMood: friendRecommendation.OverallMood,
+			UserID:          friendRecommendation.User.ID,
+			Username:        friendRecommendation.User.Username,
+			Fullname:        friendRecommendation.User.Fullname,
+			Email:           friendRecommendation.User.Email,
+			OverallMood:     friendRecommendation.OverallMood,
+			SharedInterests: nonNilStrings(friendRecommendation.SharedInterests),
+			SupportOffered:  nonNilStrings(friendRecommendation.SupportOffered),
+			SupportSought:   nonNilStrings(friendRecommendation.SupportSought),
 		})
 	}
 
@@ -387,12 +756,30 @@ func (s *FriendServiceImpl) GetFriendRecommendation(ctx context.Context, userID
 		return []*response.FriendRecommendationResponse{}, nil
 	}
 
+	// step 5.1: jumlah mutual friend di tiap kartu rekomendasi
+	candidateIDs := make([]int, 0, len(friendRecommendationResponses))
+	for _, recommendation := range friendRecommendationResponses {
+		candidateIDs = append(candidateIDs, recommendation.UserID)
+	}
+	mutualCounts, err := s.friendRepository.CountMutualFriends(ctx, s.DB, userID, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, recommendation := range friendRecommendationResponses {
+		recommendation.MutualFriends = mutualCounts[recommendation.UserID]
+	}
+
 	// step 6: ubah ke dalam json untuk disimpan ke cache
 	jsonData, err := json.Marshal(friendRecommendationResponses)
 	if err == nil {
-		_ = s.RedisClient.Set(ctx, friendRecommendationCacheKey, jsonData, 10*time.Minute).Err()
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonData, 10*time.Minute).Err()
 	}
 
 	// step 7: return response
 	return friendRecommendationResponses, nil
-}
\ No newline at end of file
+}
+
+// friendRecommendationCacheKey juga dipakai ProfileService buat membuang cache waktu profil diubah
+func friendRecommendationCacheKey(userID int) string {
+	return fmt.Sprintf("friendrecommendation:%d:v%d", userID, cacheVersion)
+}
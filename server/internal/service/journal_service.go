@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+	"strings"
+	"time"
+)
+
+// NOTES: isi jurnal sengaja tidak pernah disimpan ke Redis karena di sana datanya tidak terenkripsi
+
+const journalMasterKeyEnv = "JOURNAL_MASTER_KEY"
+
+var checkInPrompts = []response.CheckInPrompt{
+	{PromptID: 1, TextEN: "How are you feeling right now, honestly?", TextID: "Jujur, apa yang kamu rasakan sekarang?"},
+	{PromptID: 2, TextEN: "What is one small thing that went well today?", TextID: "Apa satu hal kecil yang berjalan baik hari ini?"},
+	{PromptID: 3, TextEN: "What has been weighing on your mind lately?", TextID: "Apa yang akhir-akhir ini paling membebani pikiranmu?"},
+	{PromptID: 4, TextEN: "Who or what made you feel supported recently?", TextID: "Siapa atau apa yang membuatmu merasa didukung belakangan ini?"},
+	{PromptID: 5, TextEN: "How did you take care of yourself today?", TextID: "Bagaimana kamu merawat dirimu sendiri hari ini?"},
+	{PromptID: 6, TextEN: "What would you like to let go of before tomorrow?", TextID: "Apa yang ingin kamu lepaskan sebelum hari esok?"},
+	{PromptID: 7, TextEN: "Describe your energy level today and what affected it.", TextID: "Ceritakan tingkat energimu hari ini dan apa yang memengaruhinya."},
+}
+
+type JournalService interface {
+	Create(ctx context.Context, userID int, req request.CreateJournalEntryRequest) (*response.JournalEntryResponse, error)
+	FindByID(ctx context.Context, userID, entryID int) (*response.JournalEntryResponse, error)
+	FindAll(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.JournalEntryResponse], error)
+	Update(ctx context.Context, userID, entryID int, req request.CreateJournalEntryRequest) (*response.JournalEntryResponse, error)
+	Delete(ctx context.Context, userID, entryID int) (string, error)
+	GetStreak(ctx context.Context, userID int) (*response.JournalStreakResponse, error)
+	GetDailyCheckIn(ctx context.Context, userID int) (*response.DailyCheckInResponse, error)
+	Export(ctx context.Context, userID int) ([]*response.JournalEntryResponse, error)
+}
+
+type JournalServiceImpl struct {
+	DB                *sql.DB
+	JournalRepository repository.JournalRepository
+	MoodService       MoodPredictionService
+}
+
+func NewJournalService(db *sql.DB, journalRepository repository.JournalRepository, moodService MoodPredictionService) JournalService {
+	return &JournalServiceImpl{
+		DB:                db,
+		JournalRepository: journalRepository,
+		MoodService:       moodService,
+	}
+}
+
+func (s *JournalServiceImpl) Create(ctx context.Context, userID int, req request.CreateJournalEntryRequest) (*response.JournalEntryResponse, error) {
+	// step 1: validasi input
+	tags := normalizeEmotionTags(req.EmotionTags)
+	if err := utils.ValidateJournalInput(req.Content, req.SelfMood, tags); err != nil {
+		return nil, err
+	}
+
+	// step 2: ambil (atau buat) kunci enkripsi milik user
+	key, err := s.userKey(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: enkripsi isi jurnal dan prediksi mood-nya (prediksi disimpan berdampingan dengan mood yang dipilih user)
+	entry, err := s.sealEntry(ctx, key, userID, req.Content, req.SelfMood, tags)
+	if err != nil {
+		return nil, err
+	}
+	entry.EntryDate = today()
+
+	// step 4: simpan ke database
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	createdEntry, err := s.JournalRepository.Create(ctx, tx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return openEntry(key, createdEntry)
+}
+
+func (s *JournalServiceImpl) FindByID(ctx context.Context, userID, entryID int) (*response.JournalEntryResponse, error) {
+	entry, err := s.findOwnedEntry(ctx, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.userKey(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	return openEntry(key, entry)
+}
+
+func (s *JournalServiceImpl) FindAll(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.JournalEntryResponse], error) {
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterEntryDate, afterID := after.After()
+
+	// ambil satu lebih banyak buat ngecek masih ada halaman berikutnya
+	entries, err := s.JournalRepository.FindByUserID(ctx, s.DB, userID, afterEntryDate, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return pagination.NewPage([]*response.JournalEntryResponse{}, limit, nil), nil
+	}
+
+	key, err := s.userKey(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	entryResponses := make([]*response.JournalEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		entryResponse, err := openEntry(key, entry)
+		if err != nil {
+			return nil, err
+		}
+		entryResponses = append(entryResponses, entryResponse)
+	}
+
+	// cursor-nya (entrydate, entryid) dari entry terakhir yang dikirim
+	return pagination.NewPage(entryResponses, limit, func(entry *response.JournalEntryResponse) string {
+		entryDate, _ := time.Parse("2006-01-02", entry.EntryDate)
+		return pagination.EncodeCursor(entryDate, entry.EntryID)
+	}), nil
+}
+
+func (s *JournalServiceImpl) Update(ctx context.Context, userID, entryID int, req request.CreateJournalEntryRequest) (*response.JournalEntryResponse, error) {
+	// step 1: pastikan entry-nya ada dan milik user ini
+	if _, err := s.findOwnedEntry(ctx, userID, entryID); err != nil {
+		return nil, err
+	}
+
+	// step 2: validasi input
+	tags := normalizeEmotionTags(req.EmotionTags)
+	if err := utils.ValidateJournalInput(req.Content, req.SelfMood, tags); err != nil {
+		return nil, err
+	}
+
+	// step 3: enkripsi ulang isi jurnal dan prediksi ulang mood-nya
+	key, err := s.userKey(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.sealEntry(ctx, key, userID, req.Content, req.SelfMood, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: update ke database
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	updatedEntry, err := s.JournalRepository.Update(ctx, tx, entryID, entry)
+	if err != nil {
+		return nil, err
+	}
+	if updatedEntry == nil {
+		err = fmt.Errorf("journal entry with ID %d not found", entryID)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return openEntry(key, updatedEntry)
+}
+
+func (s *JournalServiceImpl) Delete(ctx context.Context, userID, entryID int) (string, error) {
+	if _, err := s.findOwnedEntry(ctx, userID, entryID); err != nil {
+		return "", err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	message, err := s.JournalRepository.Delete(ctx, tx, entryID)
+	if err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return message, nil
+}
+
+func (s *JournalServiceImpl) GetStreak(ctx context.Context, userID int) (*response.JournalStreakResponse, error) {
+	// step 1: ambil semua tanggal unik user menulis jurnal (sudah urut dari yang terbaru)
+	dates, err := s.JournalRepository.GetEntryDates(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	streak := &response.JournalStreakResponse{TotalDays: len(dates)}
+	if len(dates) == 0 {
+		return streak, nil
+	}
+
+	lastEntryDate := dates[0].Format("2006-01-02")
+	streak.LastEntryDate = &lastEntryDate
+
+	// step 2: hitung streak terpanjang
+	longest, run := 1, 1
+	for i := 1; i < len(dates); i++ {
+		if dayDiff(dates[i-1], dates[i]) == 1 {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	streak.LongestStreak = longest
+
+	// step 3: streak sekarang masih dihitung kalau entry terakhir hari ini atau kemarin (biar streak gak putus sebelum hari berakhir)
+	gap := dayDiff(today(), dates[0])
+	streak.CheckedInToday = gap == 0
+	if gap <= 1 {
+		current := 1
+		for i := 1; i < len(dates) && dayDiff(dates[i-1], dates[i]) == 1; i++ {
+			current++
+		}
+		streak.CurrentStreak = current
+	}
+
+	return streak, nil
+}
+
+func (s *JournalServiceImpl) GetDailyCheckIn(ctx context.Context, userID int) (*response.DailyCheckInResponse, error) {
+	streak, err := s.GetStreak(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// prompt dirotasi per hari, jadi semua user dapat prompt yang sama di hari yang sama
+	now := today()
+	prompt := checkInPrompts[int(now.Unix()/86400)%len(checkInPrompts)]
+
+	return &response.DailyCheckInResponse{
+		Date:   now.Format("2006-01-02"),
+		Prompt: prompt,
+		Streak: *streak,
+	}, nil
+}
+
+func (s *JournalServiceImpl) Export(ctx context.Context, userID int) ([]*response.JournalEntryResponse, error) {
+	// ambil semua entry per batch supaya query-nya tidak terlalu berat
+	const batchSize = 200
+	var entries []*response.JournalEntryResponse
+	cursor := ""
+	for {
+		batch, err := s.FindAll(ctx, userID, cursor, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, batch.Items...)
+		if !batch.HasMore {
+			break
+		}
+		cursor = batch.NextCursor
+	}
+	if entries == nil {
+		entries = []*response.JournalEntryResponse{}
+	}
+	return entries, nil
+}
+
+func (s *JournalServiceImpl) findOwnedEntry(ctx context.Context, userID, entryID int) (*entity.JournalEntry, error) {
+	entry, err := s.JournalRepository.FindByID(ctx, s.DB, entryID)
+	if err != nil {
+		return nil, err
+	}
+	// jurnal orang lain dianggap tidak ada, biar keberadaannya tidak bocor
+	if entry == nil || entry.UserID != userID {
+		return nil, fmt.Errorf("journal entry with ID %d not found", entryID)
+	}
+	return entry, nil
+}
+
+// userKey mengambil kunci data milik user lalu membuka wrap-nya pakai master key
+func (s *JournalServiceImpl) userKey(ctx context.Context, userID int, create bool) ([]byte, error) {
+	masterKey, err := utils.LoadKeyFromEnv(journalMasterKeyEnv)
+	if err != nil {
+		return nil, fmt.Errorf("journal encryption is not configured: %v", err)
+	}
+
+	journalKey, err := s.JournalRepository.FindKey(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if journalKey == nil {
+		if !create {
+			return nil, fmt.Errorf("journal key for user %d not found", userID)
+		}
+		dataKey, err := utils.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		wrappedKey, err := utils.Encrypt(masterKey, dataKey)
+		if err != nil {
+			return nil, err
+		}
+		journalKey, err = s.JournalRepository.CreateKey(ctx, s.DB, &entity.JournalKey{UserID: userID, WrappedKey: wrappedKey})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dataKey, err := utils.Decrypt(masterKey, journalKey.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap journal key: %v", err)
+	}
+	return dataKey, nil
+}
+
+func (s *JournalServiceImpl) sealEntry(ctx context.Context, key []byte, userID int, content string, selfMood *int, tags []string) (*entity.JournalEntry, error) {
+	encryptedContent, err := utils.Encrypt(key, []byte(content))
+	if err != nil {
+		return nil, err
+	}
+
+	tagBytes, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+	encryptedTags, err := utils.Encrypt(key, tagBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// prediksi classifier cuma buat perbandingan, jadi kalau gagal jurnal-nya tetap disimpan
+	var predictedMood *string
+	moodResp, err := s.MoodService.PredictMood(ctx, request.MoodPredictionRequest{Input: content})
+	if err != nil {
+		log.Printf("JournalService: failed to predict mood for user %d: %v", userID, err)
+	} else {
+		predictedMood = &moodResp.Prediction
+	}
+
+	return &entity.JournalEntry{
+		UserID:        userID,
+		Content:       encryptedContent,
+		EmotionTags:   encryptedTags,
+		SelfMood:      selfMood,
+		PredictedMood: predictedMood,
+	}, nil
+}
+
+func openEntry(key []byte, entry *entity.JournalEntry) (*response.JournalEntryResponse, error) {
+	content, err := utils.Decrypt(key, entry.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt journal entry %d: %v", entry.EntryID, err)
+	}
+
+	tags := []string{}
+	if len(entry.EmotionTags) > 0 {
+		tagBytes, err := utils.Decrypt(key, entry.EmotionTags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt journal entry %d: %v", entry.EntryID, err)
+		}
+		if err := json.Unmarshal(tagBytes, &tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return &response.JournalEntryResponse{
+		EntryID:       entry.EntryID,
+		UserID:        entry.UserID,
+		Content:       string(content),
+		SelfMood:      entry.SelfMood,
+		EmotionTags:   tags,
+		PredictedMood: entry.PredictedMood,
+		EntryDate:     entry.EntryDate.Format("2006-01-02"),
+		CreatedAt:     entry.CreatedAt,
+		UpdatedAt:     entry.UpdatedAt,
+	}, nil
+}
+
+func normalizeEmotionTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// today mengembalikan tanggal hari ini (waktu server) dalam bentuk tengah malam UTC, sama seperti kolom DATE dari Postgres
+func today() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func dayDiff(later, earlier time.Time) int {
+	y1, m1, d1 := later.Date()
+	y2, m2, d2 := earlier.Date()
+	a := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	b := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(a.Sub(b).Hours() / 24)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mood-bridge-v2/server/infrastructure/storage"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/media"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/repository"
+	"time"
+)
+
+type MediaService interface {
+	Upload(ctx context.Context, userID int, data []byte) (*response.MediaResponse, error)
+	CleanupPending(ctx context.Context) error
+	RunCleanupJob(ctx context.Context, interval time.Duration)
+}
+
+type MediaServiceImpl struct {
+	DB              *sql.DB
+	MediaRepository repository.MediaRepository
+	Storage         storage.Storage
+}
+
+func NewMediaService(db *sql.DB, mediaRepository repository.MediaRepository, mediaStorage storage.Storage) MediaService {
+	return &MediaServiceImpl{
+		DB:              db,
+		MediaRepository: mediaRepository,
+		Storage:         mediaStorage,
+	}
+}
+
+const (
+	// gambar yang di-upload tapi tidak dipasang ke postingan dalam waktu ini dianggap ditinggal
+	pendingMediaTTL        = 24 * time.Hour
+	pendingMediaBatchLimit = 100
+)
+
+func (s *MediaServiceImpl) Upload(ctx context.Context, userID int, data []byte) (*response.MediaResponse, error) {
+	// step 1: validasi dan olah gambarnya (EXIF dibuang, dikecilkan, thumbnail + blurhash)
+	processed, err := media.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: simpan file asli dan thumbnail-nya dengan nama acak (tidak bisa ditebak dari id postingan)
+	name, err := randomMediaName()
+	if err != nil {
+		return nil, err
+	}
+	storageKey := fmt.Sprintf("posts/%d/%s.%s", userID, name, processed.Extension)
+	thumbnailKey := fmt.Sprintf("posts/%d/%s_thumb.jpg", userID, name)
+
+	if err := s.Storage.Put(ctx, storageKey, processed.Data, processed.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.Storage.Put(ctx, thumbnailKey, processed.Thumbnail, "image/jpeg"); err != nil {
+		s.deleteObjects(ctx, storageKey)
+		return nil, err
+	}
+
+	// step 3: catat di database, postid masih kosong sampai dipakai waktu bikin postingan
+	created, err := s.MediaRepository.Create(ctx, s.DB, &entity.PostMedia{
+		UserID:       userID,
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  processed.ContentType,
+		Width:        processed.Width,
+		Height:       processed.Height,
+		Blurhash:     processed.Blurhash,
+		SizeBytes:    len(processed.Data),
+	})
+	if err != nil {
+		s.deleteObjects(ctx, storageKey, thumbnailKey)
+		return nil, err
+	}
+	return toMediaResponse(s.Storage, created), nil
+}
+
+// CleanupPending menghapus gambar yang di-upload tapi tidak pernah dipasang ke postingan
+func (s *MediaServiceImpl) CleanupPending(ctx context.Context) error {
+	pending, err := s.MediaRepository.FindPending(ctx, s.DB, time.Now().Add(-pendingMediaTTL), pendingMediaBatchLimit)
+	if err != nil {
+		return err
+	}
+	for _, item := range pending {
+		deleted, err := s.MediaRepository.DeletePending(ctx, s.DB, item.MediaID)
+		if err != nil {
+			return err
+		}
+		// file-nya cuma dihapus kalau barisnya benar-benar terhapus (bukan keburu dipakai postingan)
+		if deleted {
+			s.deleteObjects(ctx, item.StorageKey, item.ThumbnailKey)
+		}
+	}
+	return nil
+}
+
+// RunCleanupJob membersihkan gambar yang ditinggal setiap interval sampai ctx dibatalkan
+func (s *MediaServiceImpl) RunCleanupJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		if err := s.CleanupPending(jobCtx); err != nil {
+			log.Printf("failed to clean up pending media: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *MediaServiceImpl) deleteObjects(ctx context.Context, keys ...string) {
+	deleteMediaObjects(ctx, s.Storage, keys...)
+}
+
+// deleteMediaObjects dipakai juga oleh PostService waktu postingan dihapus. Gagal hapus cuma di-log,
+// barisnya di database sudah terhapus jadi file-nya tidak akan pernah tersaji lagi lewat API.
+func deleteMediaObjects(ctx context.Context, store storage.Storage, keys ...string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete media object %s: %v", key, err)
+		}
+	}
+}
+
+func toMediaResponse(store storage.Storage, item *entity.PostMedia) *response.MediaResponse {
+	return &response.MediaResponse{
+		MediaID:      item.MediaID,
+		URL:          store.URL(item.StorageKey),
+		ThumbnailURL: store.URL(item.ThumbnailKey),
+		ContentType:  item.ContentType,
+		Width:        item.Width,
+		Height:       item.Height,
+		Blurhash:     item.Blurhash,
+	}
+}
+
+func randomMediaName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
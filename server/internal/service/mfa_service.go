@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/media"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = errors.New("start two-factor enrollment first")
+	ErrMFARequiredByRole = errors.New("two-factor authentication is required by one of your roles")
+	ErrInvalidMFACode    = errors.New("invalid two-factor code")
+	ErrInvalidMFAToken   = errors.New("invalid or expired two-factor login token")
+)
+
+const (
+	mfaSecretKeyEnv = "MFA_SECRET_KEY"
+	// Kode dari periode sebelum dan sesudahnya juga diterima, untuk jam HP yang meleset sedikit
+	mfaSkew = 1
+	// Token "mfa pending" dari Login harus ditukar dalam waktu ini, dengan maksimal mfaChallengeAttempts kode yang salah
+	mfaChallengeTTL      = 5 * time.Minute
+	mfaChallengeAttempts = 5
+	mfaRecoveryCodeCount = 10
+	mfaQRCodeScale       = 6
+)
+
+// Huruf/angka yang gampang tertukar (0/o, 1/l/i) tidak dipakai di recovery code
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// MFAChallenger dipakai UserService.Login untuk langkah kedua login
+type MFAChallenger interface {
+	IsEnabled(ctx context.Context, userID int) (bool, error)
+	StartChallenge(ctx context.Context, userID int) (*response.MFAChallengeResponse, error)
+	CompleteChallenge(ctx context.Context, token, code string) (int, error)
+}
+
+type MFAService interface {
+	MFAChallenger
+	GetStatus(ctx context.Context, userID int) (*response.MFAStatusResponse, error)
+	Enroll(ctx context.Context, userID int) (*response.MFAEnrollmentResponse, error)
+	ConfirmEnrollment(ctx context.Context, userID int, code string) (*response.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID int, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*response.MFARecoveryCodesResponse, error)
+}
+
+type MFAServiceImpl struct {
+	DB             *sql.DB
+	MFARepository  repository.MFARepository
+	UserRepository repository.UserRepository
+	RoleRepository repository.RoleRepository
+	AccessService  AccessService
+	RedisClient    *redis.Client
+	Issuer         string // nama yang tampil di authenticator app
+	audit          AuditLogger
+}
+
+func NewMFAService(db *sql.DB, mfaRepository repository.MFARepository, userRepository repository.UserRepository, roleRepository repository.RoleRepository, accessService AccessService, redisClient *redis.Client, audit AuditLogger) MFAService {
+	issuer := strings.TrimSpace(os.Getenv("MFA_ISSUER"))
+	if issuer == "" {
+		issuer = "Mood Bridge"
+	}
+	return &MFAServiceImpl{
+		DB:             db,
+		MFARepository:  mfaRepository,
+		UserRepository: userRepository,
+		RoleRepository: roleRepository,
+		AccessService:  accessService,
+		RedisClient:    redisClient,
+		Issuer:         issuer,
+		audit:          audit,
+	}
+}
+
+func (s *MFAServiceImpl) IsEnabled(ctx context.Context, userID int) (bool, error) {
+	return s.MFARepository.IsEnabled(ctx, s.DB, userID)
+}
+
+func (s *MFAServiceImpl) GetStatus(ctx context.Context, userID int) (*response.MFAStatusResponse, error) {
+	enabled, err := s.MFARepository.IsEnabled(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.RoleRepository.RequiresMFA(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &response.MFAStatusResponse{Enabled: enabled, Required: required}
+	if enabled {
+		status.RecoveryCodesRemaining, err = s.MFARepository.CountRecoveryCodes(ctx, s.DB, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll membuat secret baru. 2FA belum aktif sampai user mengirim kode pertamanya lewat ConfirmEnrollment,
+// jadi enroll ulang sebelum konfirmasi cukup mengganti secret yang lama.
+func (s *MFAServiceImpl) Enroll(ctx context.Context, userID int) (*response.MFAEnrollmentResponse, error) {
+	// step 1: ambil user-nya untuk label di authenticator app
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: buat secret lalu simpan dalam bentuk terenkripsi
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	key, err := utils.LoadKeyFromEnv(mfaSecretKeyEnv)
+	if err != nil {
+		return nil, fmt.Errorf("two-factor authentication is not configured: %v", err)
+	}
+	encrypted, err := utils.Encrypt(key, []byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.MFARepository.SavePending(ctx, s.DB, userID, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	// step 3: buat URI dan QR code-nya
+	uri := utils.TOTPURI(s.Issuer, user.Username, secret)
+	qrCode, err := media.EncodeQRCodePNG(uri, mfaQRCodeScale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+func (s *MFAServiceImpl) ConfirmEnrollment(ctx context.Context, userID int, code string) (*response.MFARecoveryCodesResponse, error) {
+	// step 1: cek enrollment-nya dan kode pertamanya
+	mfa, err := s.MFARepository.Find(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnrolled
+	}
+	if mfa.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	step, ok, err := s.validateCode(mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	// step 2: buat recovery code
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: rollback
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// step 5: aktifkan 2FA sekaligus simpan recovery code-nya
+	enabled, err := s.MFARepository.Enable(ctx, tx, userID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		err = ErrMFAAlreadyEnabled
+		return nil, err
+	}
+	if err = s.MFARepository.ReplaceRecoveryCodes(ctx, tx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	// step 6: commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// permission dari role yang mewajibkan 2FA sekarang berlaku
+	s.AccessService.Invalidate(ctx, userID)
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditMFAEnabled,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+	})
+	return &response.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable butuh kode yang valid (atau recovery code), supaya sesi yang dicuri tidak bisa mematikan 2FA begitu saja
+func (s *MFAServiceImpl) Disable(ctx context.Context, userID int, code string) error {
+	// step 1: user dengan role yang mewajibkan 2FA tidak bisa mematikannya sendiri
+	required, err := s.RoleRepository.RequiresMFA(ctx, s.DB, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredByRole
+	}
+
+	// step 2: cek kodenya
+	if err := s.verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	// step 3: hapus secret dan recovery code-nya
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := s.MFARepository.Delete(ctx, tx, userID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.AccessService.Invalidate(ctx, userID)
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditMFADisabled,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+	})
+	return nil
+}
+
+// RegenerateRecoveryCodes mengganti semua recovery code, yang lama langsung tidak berlaku
+func (s *MFAServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*response.MFARecoveryCodesResponse, error) {
+	// step 1: cek kodenya
+	if err := s.verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	// step 2: buat dan simpan recovery code baru
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.MFARepository.ReplaceRecoveryCodes(ctx, tx, userID, hashes); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditMFARecoveryCodesReset,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+	})
+	return &response.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("mfa:challenge:%s", tokenHash)
+}
+
+func mfaChallengeAttemptsKey(tokenHash string) string {
+	return fmt.Sprintf("mfa:challenge:%s:attempts", tokenHash)
+}
+
+// StartChallenge membuat token "mfa pending" sekali pakai. Token-nya bukan JWT dan tidak bisa dipakai untuk endpoint lain,
+// yang disimpan di Redis cuma hash-nya.
+func (s *MFAServiceImpl) StartChallenge(ctx context.Context, userID int) (*response.MFAChallengeResponse, error) {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := s.RedisClient.Set(ctx, mfaChallengeKey(hashMFAToken(token)), userID, mfaChallengeTTL).Err(); err != nil {
+		return nil, err
+	}
+	return &response.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int(mfaChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteChallenge menukar token "mfa pending" dan kode 2FA dengan userID. Setelah mfaChallengeAttempts kode yang salah,
+// token-nya dihapus dan user harus login ulang dengan password. Kalau kodenya salah userID tetap dikembalikan bersama
+// ErrInvalidMFACode, supaya pemanggil bisa menghitungnya sebagai login yang gagal.
+func (s *MFAServiceImpl) CompleteChallenge(ctx context.Context, token, code string) (int, error) {
+	// step 1: cari user dari token-nya
+	tokenHash := hashMFAToken(strings.TrimSpace(token))
+	key := mfaChallengeKey(tokenHash)
+	value, err := s.RedisClient.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, ErrInvalidMFAToken
+		}
+		return 0, err
+	}
+	userID, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, ErrInvalidMFAToken
+	}
+
+	// step 2: cek kodenya, kode yang salah menghabiskan jatah percobaan token ini
+	verifyErr := s.verify(ctx, userID, code)
+	if errors.Is(verifyErr, ErrInvalidMFACode) {
+		attemptsKey := mfaChallengeAttemptsKey(tokenHash)
+		attempts, err := s.RedisClient.Incr(ctx, attemptsKey).Result()
+		if err != nil {
+			return 0, err
+		}
+		s.RedisClient.Expire(ctx, attemptsKey, mfaChallengeTTL)
+		if attempts >= mfaChallengeAttempts {
+			s.RedisClient.Del(ctx, key, attemptsKey)
+		}
+		return userID, verifyErr
+	}
+	if verifyErr != nil {
+		return 0, verifyErr
+	}
+
+	// step 3: token-nya cuma bisa dipakai sekali. Del mengembalikan 0 kalau request lain sudah lebih dulu memakainya.
+	deleted, err := s.RedisClient.Del(ctx, key, mfaChallengeAttemptsKey(tokenHash)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if deleted == 0 {
+		return 0, ErrInvalidMFAToken
+	}
+	return userID, nil
+}
+
+// verify menerima kode TOTP atau recovery code milik user yang 2FA-nya sudah aktif
+func (s *MFAServiceImpl) verify(ctx context.Context, userID int, code string) error {
+	mfa, err := s.MFARepository.Find(ctx, s.DB, userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || mfa.EnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	// step 1: kode dari authenticator app, periode yang sudah dipakai ditolak
+	if step, ok, err := s.validateCode(mfa, code); err != nil {
+		return err
+	} else if ok {
+		used, err := s.MFARepository.UseStep(ctx, s.DB, userID, step)
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+		return s.invalidCode(ctx, userID, "code already used")
+	}
+
+	// step 2: recovery code
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return s.invalidCode(ctx, userID, "invalid code")
+	}
+	used, err := s.MFARepository.UseRecoveryCode(ctx, s.DB, userID, hashMFAToken(normalized))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return s.invalidCode(ctx, userID, "invalid code")
+	}
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditMFARecoveryCodeUsed,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+	})
+	return nil
+}
+
+func (s *MFAServiceImpl) invalidCode(ctx context.Context, userID int, reason string) error {
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditMFAFailed,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &userID,
+		Metadata:   map[string]interface{}{"reason": reason},
+	})
+	return ErrInvalidMFACode
+}
+
+// validateCode mendekripsi secret user lalu mengecek kode TOTP-nya
+func (s *MFAServiceImpl) validateCode(mfa *entity.UserMFA, code string) (int64, bool, error) {
+	key, err := utils.LoadKeyFromEnv(mfaSecretKeyEnv)
+	if err != nil {
+		return 0, false, fmt.Errorf("two-factor authentication is not configured: %v", err)
+	}
+	secret, err := utils.Decrypt(key, mfa.Secret)
+	if err != nil {
+		log.Printf("Error decrypting MFA secret for user %d: %v", mfa.UserID, err)
+		return 0, false, err
+	}
+
+	step, ok := utils.ValidateTOTP(string(secret), strings.ReplaceAll(code, " ", ""), time.Now(), mfaSkew)
+	return step, ok, nil
+}
+
+// generateRecoveryCodes membuat recovery code berformat "xxxx-xxxx" beserta hash-nya
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	hashes := make([]string, 0, mfaRecoveryCodeCount)
+	// byte di atas kelipatan panjang alphabet dibuang, supaya semua karakter punya peluang yang sama
+	limit := byte(256 / len(recoveryCodeAlphabet) * len(recoveryCodeAlphabet))
+	buf := make([]byte, 1)
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		var code strings.Builder
+		for code.Len() < 9 {
+			if code.Len() == 4 {
+				code.WriteByte('-')
+				continue
+			}
+			if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+				return nil, nil, err
+			}
+			if buf[0] >= limit {
+				continue
+			}
+			code.WriteByte(recoveryCodeAlphabet[int(buf[0])%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, code.String())
+		hashes = append(hashes, hashMFAToken(normalizeRecoveryCode(code.String())))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode supaya kode yang diketik dengan huruf besar, spasi, atau tanpa strip tetap cocok
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// hashMFAToken dipakai untuk token "mfa pending" di Redis dan recovery code di database
+func hashMFAToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,637 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrNotModerator    = errors.New("only moderators can access this resource")
+	ErrReportClaimed   = errors.New("report is claimed by another moderator")
+	ErrReportResolved  = errors.New("report has already been resolved")
+	ErrAlreadyReported = errors.New("you have already reported this")
+)
+
+// Postingan/komentar otomatis disembunyikan sementara kalau jumlah laporan yang belum selesai mencapai angka ini,
+// sampai moderator memutuskan (dismiss memunculkannya lagi)
+const reportAutoHideThreshold = 5
+
+const defaultSuspendDays = 7
+
+// Tindakan yang boleh dipakai untuk setiap jenis target
+var moderationActionsByTarget = map[string]map[string]bool{
+	entity.ReportTargetPost:    {entity.ModerationActionHide: true, entity.ModerationActionDelete: true, entity.ModerationActionWarn: true, entity.ModerationActionSuspend: true, entity.ModerationActionDismiss: true},
+	entity.ReportTargetComment: {entity.ModerationActionHide: true, entity.ModerationActionDelete: true, entity.ModerationActionWarn: true, entity.ModerationActionSuspend: true, entity.ModerationActionDismiss: true},
+	entity.ReportTargetMessage: {entity.ModerationActionDelete: true, entity.ModerationActionWarn: true, entity.ModerationActionSuspend: true, entity.ModerationActionDismiss: true},
+	entity.ReportTargetUser:    {entity.ModerationActionWarn: true, entity.ModerationActionSuspend: true, entity.ModerationActionDismiss: true},
+}
+
+type ModerationService interface {
+	Report(ctx context.Context, reporterID int, req request.CreateReportRequest) (*response.ReportResponse, error)
+	GetQueue(ctx context.Context, moderatorID int, status, cursor string, limit int) (*pagination.Page[*response.ReportResponse], error)
+	GetReport(ctx context.Context, moderatorID, reportID int) (*response.ReportResponse, error)
+	Claim(ctx context.Context, moderatorID, reportID int) (*response.ReportResponse, error)
+	Resolve(ctx context.Context, moderatorID, reportID int, req request.ResolveReportRequest) (*response.ReportResponse, error)
+	GetActions(ctx context.Context, moderatorID int, cursor string, limit int) (*pagination.Page[*response.ModerationActionResponse], error)
+}
+
+type ModerationServiceImpl struct {
+	DB                   *sql.DB
+	ModerationRepository repository.ModerationRepository
+	UserRepository       repository.UserRepository
+	RoleRepository       repository.RoleRepository
+	PostRepository       repository.PostRepository
+	CommentRepository    repository.CommentRepository
+	ChatRepository       repository.ChatRepository
+	PostService          PostService
+	CommentService       CommentService
+	NotificationService  NotificationService
+	AccessService        AccessService
+	Audit                AuditLogger
+	RedisClient          *redis.Client
+}
+
+func NewModerationService(db *sql.DB, moderationRepository repository.ModerationRepository, userRepository repository.UserRepository, roleRepository repository.RoleRepository, postRepository repository.PostRepository, commentRepository repository.CommentRepository, chatRepository repository.ChatRepository, postService PostService, commentService CommentService, notificationService NotificationService, accessService AccessService, audit AuditLogger, redisClient *redis.Client) ModerationService {
+	return &ModerationServiceImpl{
+		DB:                   db,
+		ModerationRepository: moderationRepository,
+		UserRepository:       userRepository,
+		RoleRepository:       roleRepository,
+		PostRepository:       postRepository,
+		CommentRepository:    commentRepository,
+		ChatRepository:       chatRepository,
+		PostService:          postService,
+		CommentService:       commentService,
+		NotificationService:  notificationService,
+		AccessService:        accessService,
+		Audit:                audit,
+		RedisClient:          redisClient,
+	}
+}
+
+func (s *ModerationServiceImpl) Report(ctx context.Context, reporterID int, req request.CreateReportRequest) (*response.ReportResponse, error) {
+	// step 1: validasi alasan
+	details := strings.TrimSpace(req.Details)
+	if req.Reason == entity.ReportReasonOther && details == "" {
+		return nil, fmt.Errorf("details are required when reason is other")
+	}
+
+	// step 2: pastikan target-nya ada dan memang bisa dilihat pelapor, sekalian cari pemiliknya
+	ownerID, err := s.findTargetOwner(ctx, reporterID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == reporterID {
+		return nil, fmt.Errorf("you can't report your own %s", req.TargetType)
+	}
+
+	// step 3: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 5: simpan laporannya, satu user cuma bisa melaporkan target yang sama sekali
+	report, err := s.ModerationRepository.CreateReport(ctx, tx, &entity.Report{
+		ReporterID:    reporterID,
+		TargetType:    req.TargetType,
+		TargetID:      req.TargetID,
+		TargetOwnerID: &ownerID,
+		Reason:        req.Reason,
+		Details:       details,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		err = ErrAlreadyReported
+		return nil, err
+	}
+
+	// step 6: sembunyikan sementara kalau laporannya sudah terlalu banyak
+	autoHidden := false
+	if req.TargetType == entity.ReportTargetPost || req.TargetType == entity.ReportTargetComment {
+		var openReports int
+		openReports, err = s.ModerationRepository.CountOpenReports(ctx, tx, req.TargetType, req.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if openReports >= reportAutoHideThreshold {
+			autoHidden, err = s.ModerationRepository.SetHidden(ctx, tx, req.TargetType, req.TargetID, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if autoHidden {
+			err = s.ModerationRepository.LogAction(ctx, tx, &entity.ModerationAction{
+				ReportID:   &report.ReportID,
+				Action:     entity.ModerationActionAutoHide,
+				TargetType: req.TargetType,
+				TargetID:   req.TargetID,
+				Note:       fmt.Sprintf("%d open reports", openReports),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// step 7: commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if autoHidden {
+		s.auditAction(ctx, entity.ModerationActionAutoHide, report.ReportID, req.TargetType, req.TargetID, "")
+		s.invalidateTarget(ctx, req.TargetType, req.TargetID)
+	}
+	return toReporterReportResponse(report), nil
+}
+
+func (s *ModerationServiceImpl) GetQueue(ctx context.Context, moderatorID int, status, cursor string, limit int) (*pagination.Page[*response.ReportResponse], error) {
+	if err := s.requireModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = entity.ReportStatusOpen
+	}
+	if status != entity.ReportStatusOpen && status != entity.ReportStatusClaimed && status != entity.ReportStatusResolved {
+		return nil, fmt.Errorf("invalid status %q", status)
+	}
+
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	reports, err := s.ModerationRepository.FindQueue(ctx, s.DB, status, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	reportResponses := []*response.ReportResponse{}
+	for _, report := range reports {
+		reportResponses = append(reportResponses, toModeratorReportResponse(report))
+	}
+	return pagination.NewPage(reportResponses, limit, func(report *response.ReportResponse) string {
+		return pagination.EncodeCursor(report.CreatedAt, report.ReportID)
+	}), nil
+}
+
+func (s *ModerationServiceImpl) GetReport(ctx context.Context, moderatorID, reportID int) (*response.ReportResponse, error) {
+	if err := s.requireModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	report, err := s.ModerationRepository.FindReportByID(ctx, s.DB, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("report with id %d not found", reportID)
+	}
+
+	reportResponse := toModeratorReportResponse(report)
+	reportResponse.Target, err = s.targetPreview(ctx, report.TargetType, report.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	return reportResponse, nil
+}
+
+// Claim menandai laporan sedang ditangani, supaya dua moderator tidak mengerjakan laporan yang sama
+func (s *ModerationServiceImpl) Claim(ctx context.Context, moderatorID, reportID int) (*response.ReportResponse, error) {
+	if err := s.requireModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	// step 1: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 3: kunci laporannya lalu cek statusnya
+	report, err := s.ModerationRepository.FindReportForUpdate(ctx, tx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkClaimable(report, reportID, moderatorID); err != nil {
+		return nil, err
+	}
+
+	// step 4: claim dan catat di log
+	if err = s.ModerationRepository.ClaimReport(ctx, tx, reportID, moderatorID); err != nil {
+		return nil, err
+	}
+	err = s.ModerationRepository.LogAction(ctx, tx, &entity.ModerationAction{
+		ModeratorID: &moderatorID,
+		ReportID:    &reportID,
+		Action:      entity.ModerationActionClaim,
+		TargetType:  report.TargetType,
+		TargetID:    report.TargetID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// step 5: commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.auditAction(ctx, entity.ModerationActionClaim, reportID, report.TargetType, report.TargetID, "")
+
+	return s.GetReport(ctx, moderatorID, reportID)
+}
+
+func (s *ModerationServiceImpl) Resolve(ctx context.Context, moderatorID, reportID int, req request.ResolveReportRequest) (*response.ReportResponse, error) {
+	if err := s.requireModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	// step 1: cek laporan dan tindakannya sebelum melakukan apa pun
+	report, err := s.ModerationRepository.FindReportByID(ctx, s.DB, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClaimable(report, reportID, moderatorID); err != nil {
+		return nil, err
+	}
+	if !moderationActionsByTarget[report.TargetType][req.Action] {
+		return nil, fmt.Errorf("action %s is not available for %s reports", req.Action, report.TargetType)
+	}
+	needsOwner := req.Action == entity.ModerationActionWarn || req.Action == entity.ModerationActionSuspend
+	if needsOwner && report.TargetOwnerID == nil {
+		return nil, fmt.Errorf("the owner of this %s no longer exists", report.TargetType)
+	}
+	note := strings.TrimSpace(req.Note)
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 4: cek ulang dengan laporan yang sudah dikunci, siapa tahu sudah ditangani moderator lain.
+	// Harus sebelum tindakan apa pun yang tidak bisa dibatalkan (hapus konten), dan kunci-nya ditahan sampai commit
+	report, err = s.ModerationRepository.FindReportForUpdate(ctx, tx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkClaimable(report, reportID, moderatorID); err != nil {
+		return nil, err
+	}
+
+	// step 5: hapus kontennya (lewat service masing-masing supaya cache dan file media ikut dibersihkan)
+	// selagi laporannya masih dikunci, jadi kalau penghapusan gagal laporannya belum ditandai selesai
+	if req.Action == entity.ModerationActionDelete {
+		if err = s.deleteTarget(ctx, moderatorID, report.TargetType, report.TargetID); err != nil {
+			return nil, err
+		}
+	}
+
+	// step 6: jalankan tindakannya
+	suspendDays := req.SuspendDays
+	if suspendDays == 0 {
+		suspendDays = defaultSuspendDays
+	}
+	switch req.Action {
+	case entity.ModerationActionHide:
+		_, err = s.ModerationRepository.SetHidden(ctx, tx, report.TargetType, report.TargetID, true)
+	case entity.ModerationActionDismiss:
+		// konten yang sempat disembunyikan otomatis dimunculkan lagi
+		if report.TargetType == entity.ReportTargetPost || report.TargetType == entity.ReportTargetComment {
+			_, err = s.ModerationRepository.SetHidden(ctx, tx, report.TargetType, report.TargetID, false)
+		}
+	case entity.ModerationActionSuspend:
+		err = s.UserRepository.Suspend(ctx, tx, *report.TargetOwnerID, time.Now().AddDate(0, 0, suspendDays))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// step 7: tandai semua laporan untuk target yang sama sebagai selesai, lalu catat di log
+	resolved, err := s.ModerationRepository.ResolveReports(ctx, tx, report.TargetType, report.TargetID, moderatorID, req.Action, note)
+	if err != nil {
+		return nil, err
+	}
+	logNote := note
+	if req.Action == entity.ModerationActionSuspend {
+		logNote = strings.TrimSpace(fmt.Sprintf("%d days. %s", suspendDays, note))
+	}
+	err = s.ModerationRepository.LogAction(ctx, tx, &entity.ModerationAction{
+		ModeratorID: &moderatorID,
+		ReportID:    &reportID,
+		Action:      req.Action,
+		TargetType:  report.TargetType,
+		TargetID:    report.TargetID,
+		Note:        logNote,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// step 8: commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.auditAction(ctx, req.Action, reportID, report.TargetType, report.TargetID, logNote)
+
+	// step 9: buang cache konten yang berubah lalu kirim notifikasi
+	if req.Action == entity.ModerationActionHide || req.Action == entity.ModerationActionDismiss {
+		s.invalidateTarget(ctx, report.TargetType, report.TargetID)
+	}
+	if req.Action == entity.ModerationActionSuspend {
+		s.AccessService.Invalidate(ctx, *report.TargetOwnerID)
+	}
+	for _, resolvedReport := range resolved {
+		s.NotificationService.Notify(ctx, resolvedReport.ReporterID, entity.NotificationReportResolved,
+			reportResolvedMessage(resolvedReport.TargetType, req.Action), &resolvedReport.ReportID)
+	}
+	switch req.Action {
+	case entity.ModerationActionWarn:
+		s.NotificationService.Notify(ctx, *report.TargetOwnerID, entity.NotificationModerationWarning,
+			withModeratorNote(fmt.Sprintf("A moderator reviewed your %s and issued a warning.", report.TargetType), note), nil)
+	case entity.ModerationActionSuspend:
+		s.NotificationService.Notify(ctx, *report.TargetOwnerID, entity.NotificationAccountSuspended,
+			withModeratorNote(fmt.Sprintf("Your account has been suspended for %d days.", suspendDays), note), nil)
+	}
+
+	return s.GetReport(ctx, moderatorID, reportID)
+}
+
+func (s *ModerationServiceImpl) GetActions(ctx context.Context, moderatorID int, cursor string, limit int) (*pagination.Page[*response.ModerationActionResponse], error) {
+	if err := s.requireModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	actions, err := s.ModerationRepository.FindActions(ctx, s.DB, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	actionResponses := []*response.ModerationActionResponse{}
+	for _, action := range actions {
+		actionResponses = append(actionResponses, &response.ModerationActionResponse{
+			ActionID:    action.ActionID,
+			ModeratorID: action.ModeratorID,
+			ReportID:    action.ReportID,
+			Action:      action.Action,
+			TargetType:  action.TargetType,
+			TargetID:    action.TargetID,
+			Note:        action.Note,
+			CreatedAt:   action.CreatedAt,
+		})
+	}
+	return pagination.NewPage(actionResponses, limit, func(action *response.ModerationActionResponse) string {
+		return pagination.EncodeCursor(action.CreatedAt, action.ActionID)
+	}), nil
+}
+
+// auditAction mencatat tindakan moderasi ke log audit, di samping log moderasi yang dipakai antrian moderator
+func (s *ModerationServiceImpl) auditAction(ctx context.Context, action string, reportID int, targetType string, targetID int, note string) {
+	metadata := map[string]interface{}{"report_id": reportID}
+	if note != "" {
+		metadata["note"] = note
+	}
+	s.Audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditModeration + action,
+		TargetType: targetType,
+		TargetID:   &targetID,
+		Metadata:   metadata,
+	})
+}
+
+func (s *ModerationServiceImpl) requireModerator(ctx context.Context, userID int) error {
+	isModerator, err := s.RoleRepository.HasPermission(ctx, s.DB, userID, entity.PermissionModerateReports)
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		return ErrNotModerator
+	}
+	return nil
+}
+
+// findTargetOwner mengikuti aturan visibility yang sama dengan yang dilihat pelapor,
+// jadi user tidak bisa melaporkan (dan menebak keberadaan) konten yang tidak bisa dia lihat
+func (s *ModerationServiceImpl) findTargetOwner(ctx context.Context, reporterID int, targetType string, targetID int) (int, error) {
+	notFound := fmt.Errorf("%s with id %d not found", targetType, targetID)
+
+	switch targetType {
+	case entity.ReportTargetPost:
+		if _, err := s.PostService.Find(ctx, targetID, reporterID); err != nil {
+			return 0, notFound
+		}
+		// PostService menyamarkan penulis postingan anonim, pemilik aslinya diambil dari repository
+		post, err := s.PostRepository.Find(ctx, s.DB, targetID)
+		if err != nil || post == nil {
+			return 0, notFound
+		}
+		return post.UserID, nil
+	case entity.ReportTargetComment:
+		comment, err := s.CommentRepository.GetByID(ctx, s.DB, targetID)
+		if err != nil {
+			return 0, err
+		}
+		if comment == nil || comment.IsDeleted {
+			return 0, notFound
+		}
+		if _, err := s.PostService.Find(ctx, comment.PostID, reporterID); err != nil {
+			return 0, notFound
+		}
+		return comment.UserID, nil
+	case entity.ReportTargetMessage:
+		// pesan cuma bisa dilaporkan oleh penerimanya
+		message, err := s.ChatRepository.FindMessageByID(ctx, targetID)
+		if err != nil {
+			return 0, err
+		}
+		if message == nil || message.RecipientID != reporterID {
+			return 0, notFound
+		}
+		return message.SenderID, nil
+	case entity.ReportTargetUser:
+		if _, err := s.UserRepository.FindByID(ctx, s.DB, targetID); err != nil {
+			return 0, notFound
+		}
+		return targetID, nil
+	}
+	return 0, fmt.Errorf("invalid target type %q", targetType)
+}
+
+func (s *ModerationServiceImpl) targetPreview(ctx context.Context, targetType string, targetID int) (*response.ReportTargetResponse, error) {
+	switch targetType {
+	case entity.ReportTargetPost:
+		post, err := s.PostRepository.Find(ctx, s.DB, targetID)
+		if err == sql.ErrNoRows || (err == nil && post == nil) {
+			return &response.ReportTargetResponse{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &response.ReportTargetResponse{Exists: true, Content: post.Content, IsHidden: post.HiddenAt != nil}, nil
+	case entity.ReportTargetComment:
+		comment, err := s.CommentRepository.GetByID(ctx, s.DB, targetID)
+		if err != nil {
+			return nil, err
+		}
+		if comment == nil || comment.IsDeleted {
+			return &response.ReportTargetResponse{}, nil
+		}
+		return &response.ReportTargetResponse{Exists: true, Content: comment.Content, IsHidden: comment.HiddenAt != nil}, nil
+	case entity.ReportTargetMessage:
+		message, err := s.ChatRepository.FindMessageByID(ctx, targetID)
+		if err != nil {
+			return nil, err
+		}
+		if message == nil {
+			return &response.ReportTargetResponse{}, nil
+		}
+		return &response.ReportTargetResponse{Exists: true, Content: message.Content}, nil
+	default:
+		user, err := s.UserRepository.FindByID(ctx, s.DB, targetID)
+		if err != nil || user == nil {
+			return &response.ReportTargetResponse{}, nil
+		}
+		return &response.ReportTargetResponse{Exists: true, Content: fmt.Sprintf("%s (@%s)", user.Fullname, user.Username)}, nil
+	}
+}
+
+// deleteTarget tidak error kalau kontennya memang sudah tidak ada (misalnya sudah dihapus pemiliknya)
+func (s *ModerationServiceImpl) deleteTarget(ctx context.Context, moderatorID int, targetType string, targetID int) error {
+	switch targetType {
+	case entity.ReportTargetPost:
+		post, err := s.PostRepository.Find(ctx, s.DB, targetID)
+		if err == sql.ErrNoRows || (err == nil && post == nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = s.PostService.Delete(ctx, targetID, moderatorID)
+		return err
+	case entity.ReportTargetComment:
+		_, err := s.CommentService.Delete(ctx, targetID, moderatorID)
+		return err
+	case entity.ReportTargetMessage:
+		return s.ChatRepository.DeleteMessage(ctx, targetID)
+	}
+	return fmt.Errorf("%s can't be deleted", targetType)
+}
+
+// invalidateTarget membuang cache postingan/komentar yang baru disembunyikan atau dimunculkan lagi
+func (s *ModerationServiceImpl) invalidateTarget(ctx context.Context, targetType string, targetID int) {
+	switch targetType {
+	case entity.ReportTargetPost:
+		_ = s.RedisClient.Del(ctx, postCacheKey(targetID)).Err()
+		_ = s.RedisClient.Incr(ctx, postListGenerationKey).Err()
+	case entity.ReportTargetComment:
+		_ = s.RedisClient.Del(ctx, commentCacheKey(targetID)).Err()
+		comment, err := s.CommentRepository.GetByID(ctx, s.DB, targetID)
+		if err != nil || comment == nil {
+			log.Printf("ModerationService: failed to invalidate comment pages for comment %d: %v", targetID, err)
+			return
+		}
+		_ = s.RedisClient.Incr(ctx, commentPageGenerationKey(comment.PostID)).Err()
+	}
+}
+
+func checkClaimable(report *entity.Report, reportID, moderatorID int) error {
+	if report == nil {
+		return fmt.Errorf("report with id %d not found", reportID)
+	}
+	if report.Status == entity.ReportStatusResolved {
+		return ErrReportResolved
+	}
+	if report.Status == entity.ReportStatusClaimed && report.ClaimedBy != nil && *report.ClaimedBy != moderatorID {
+		return ErrReportClaimed
+	}
+	return nil
+}
+
+func reportResolvedMessage(targetType, action string) string {
+	if action == entity.ModerationActionDismiss {
+		return fmt.Sprintf("Thanks for your report. A moderator reviewed the %s and found it doesn't break our community guidelines.", targetType)
+	}
+	return fmt.Sprintf("Thanks for your report. A moderator reviewed the %s and took action.", targetType)
+}
+
+func withModeratorNote(message, note string) string {
+	if note == "" {
+		return message
+	}
+	return message + " Moderator note: " + note
+}
+
+// toReporterReportResponse tidak menyertakan pemilik target dan detail moderator
+func toReporterReportResponse(report *entity.Report) *response.ReportResponse {
+	reportResponse := &response.ReportResponse{
+		ReportID:   report.ReportID,
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		CreatedAt:  report.CreatedAt,
+		ResolvedAt: report.ResolvedAt,
+	}
+	if report.Resolution != nil {
+		reportResponse.Resolution = *report.Resolution
+	}
+	return reportResponse
+}
+
+func toModeratorReportResponse(report *entity.Report) *response.ReportResponse {
+	reportResponse := toReporterReportResponse(report)
+	reportResponse.ReporterID = report.ReporterID
+	reportResponse.TargetOwnerID = report.TargetOwnerID
+	reportResponse.ClaimedBy = report.ClaimedBy
+	reportResponse.ClaimedAt = report.ClaimedAt
+	reportResponse.ResolvedBy = report.ResolvedBy
+	reportResponse.OpenReports = report.OpenReports
+	if report.ResolutionNote != nil {
+		reportResponse.ResolutionNote = *report.ResolutionNote
+	}
+	return reportResponse
+}
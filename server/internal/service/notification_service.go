@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+)
+
+type NotificationService interface {
+	Notify(ctx context.Context, userID int, notificationType, message string, referenceID *int)
+	GetNotifications(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.NotificationResponse], error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID, notificationID int) error
+	MarkAllRead(ctx context.Context, userID int) error
+}
+
+type NotificationServiceImpl struct {
+	DB                     *sql.DB
+	NotificationRepository repository.NotificationRepository
+}
+
+func NewNotificationService(db *sql.DB, notificationRepository repository.NotificationRepository) NotificationService {
+	return &NotificationServiceImpl{
+		DB:                     db,
+		NotificationRepository: notificationRepository,
+	}
+}
+
+// Notify dipanggil setelah aksi utamanya berhasil, jadi kalau gagal cukup dicatat di log tanpa menggagalkan aksinya
+func (s *NotificationServiceImpl) Notify(ctx context.Context, userID int, notificationType, message string, referenceID *int) {
+	notification := &entity.Notification{
+		UserID:      userID,
+		Type:        notificationType,
+		Message:     message,
+		ReferenceID: referenceID,
+	}
+	if _, err := s.NotificationRepository.Create(ctx, s.DB, notification); err != nil {
+		log.Printf("NotificationService: failed to notify user %d (%s): %v", userID, notificationType, err)
+	}
+}
+
+func (s *NotificationServiceImpl) GetNotifications(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.NotificationResponse], error) {
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	notifications, err := s.NotificationRepository.FindByUserID(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationResponses := []*response.NotificationResponse{}
+	for _, notification := range notifications {
+		notificationResponses = append(notificationResponses, &response.NotificationResponse{
+			NotificationID: notification.NotificationID,
+			Type:           notification.Type,
+			Message:        notification.Message,
+			ReferenceID:    notification.ReferenceID,
+			IsRead:         notification.ReadAt != nil,
+			CreatedAt:      notification.CreatedAt,
+		})
+	}
+	return pagination.NewPage(notificationResponses, limit, func(notification *response.NotificationResponse) string {
+		return pagination.EncodeCursor(notification.CreatedAt, notification.NotificationID)
+	}), nil
+}
+
+func (s *NotificationServiceImpl) CountUnread(ctx context.Context, userID int) (int, error) {
+	return s.NotificationRepository.CountUnread(ctx, s.DB, userID)
+}
+
+func (s *NotificationServiceImpl) MarkRead(ctx context.Context, userID, notificationID int) error {
+	found, err := s.NotificationRepository.MarkRead(ctx, s.DB, userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("notification with id %d not found", notificationID)
+	}
+	return nil
+}
+
+func (s *NotificationServiceImpl) MarkAllRead(ctx context.Context, userID int) error {
+	return s.NotificationRepository.MarkAllRead(ctx, s.DB, userID)
+}
@@ -2,14 +2,23 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"mood-bridge-v2/server/infrastructure/storage"
 	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/media"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
 	"mood-bridge-v2/server/internal/repository"
 	"mood-bridge-v2/server/internal/utils"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -17,34 +26,79 @@ import (
 
 type PostService interface {
 	Create(ctx context.Context, req request.CreatePostRequest) (*response.CreatePostResponse, error)
-	Find(ctx context.Context, postID int) (*response.CreatePostResponse, error)
-	FindAll(ctx context.Context, limit, offset int) ([]*response.CreatePostResponse, error)
-	FindByUserID(ctx context.Context, userID int) ([]*response.CreatePostResponse, error)
-	Update(ctx context.Context, postID int, req request.CreatePostRequest) (*response.CreatePostResponse, error)
-	Delete(ctx context.Context, postID int) (string, error)
-	// GetPostBySearch(ctx context.Context, query string) ([]*response.CreatePostResponse, error)
-	GetFriendPosts(ctx context.Context, userID, limit, offset int) ([]*response.CreatePostResponse, error)
+	Find(ctx context.Context, postID, viewerID int) (*response.CreatePostResponse, error)
+	FindAll(ctx context.Context, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error)
+	FindByUserID(ctx context.Context, userID, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error)
+	Update(ctx context.Context, postID, userID int, req request.CreatePostRequest) (*response.CreatePostResponse, error)
+	GetRevisions(ctx context.Context, postID, viewerID int) ([]*response.PostRevisionResponse, error)
+	Delete(ctx context.Context, postID, userID int) (string, error)
+	Search(ctx context.Context, viewerID int, req request.SearchPostRequest) (*pagination.Page[*response.PostSearchResult], error)
+	GetFriendPosts(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error)
+	FindByTag(ctx context.Context, tag string, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error)
+	FindByIDs(ctx context.Context, postIDs []int, viewerID int) ([]*response.CreatePostResponse, error)
 }
 
+// ErrPostNotFound juga dipakai untuk postingan yang ada tapi tidak boleh dilihat viewer-nya
+var ErrPostNotFound = errors.New("post not found")
+
+// ErrPostHistoryForbidden: riwayat edit cuma boleh dilihat pemilik postingan dan moderator
+var ErrPostHistoryForbidden = errors.New("you can only view the history of your own posts")
+
+// ErrPostForbidden: post (beserta gambar dan bookmark-nya) cuma boleh dihapus pemiliknya dan moderator
+var ErrPostForbidden = errors.New("you can only delete your own posts")
+
 type PostServiceImpl struct {
 	DB *sql.DB
 	PostRepository repository.PostRepository
 	UserRepository repository.UserRepository
+	FriendRepository repository.FriendRepository
+	ReactionRepository repository.ReactionRepository
+	TagRepository repository.TagRepository
+	BookmarkRepository repository.BookmarkRepository
+	MediaRepository repository.MediaRepository
 	MoodService MoodPredictionService
+	Guard InteractionGuard
+	Audit AuditLogger
+	Access AccessService
+	Storage storage.Storage
 	RedisClient *redis.Client
 }
 
-func NewPostService(db *sql.DB, postRepository repository.PostRepository, userRepository repository.UserRepository, moodService MoodPredictionService, redisClient *redis.Client) PostService {
+func NewPostService(db *sql.DB, postRepository repository.PostRepository, userRepository repository.UserRepository, friendRepository repository.FriendRepository, reactionRepository repository.ReactionRepository, tagRepository repository.TagRepository, bookmarkRepository repository.BookmarkRepository, mediaRepository repository.MediaRepository, moodService MoodPredictionService, guard InteractionGuard, audit AuditLogger, accessService AccessService, mediaStorage storage.Storage, redisClient *redis.Client) PostService {
 	return &PostServiceImpl {
 		DB: db,
 		PostRepository: postRepository,
 		UserRepository: userRepository,
+		FriendRepository: friendRepository,
+		ReactionRepository: reactionRepository,
+		TagRepository: tagRepository,
+		BookmarkRepository: bookmarkRepository,
+		MediaRepository: mediaRepository,
 		MoodService: moodService,
+		Guard: guard,
+		Audit: audit,
+		Access: accessService,
+		Storage: mediaStorage,
 		RedisClient: redisClient,
 	}
 }
 
-const cacheVersion = 1
+const cacheVersion = 3 // naikkan kalau bentuk CreatePostResponse berubah (v2: ada media, v3: is_hidden)
+
+func postCacheKey(postID int) string {
+	return fmt.Sprintf("post:%d:v%d", postID, cacheVersion)
+}
+
+// Semua cache list postingan (post:all dan friend_posts) menyertakan "generation" ini di key-nya.
+// Karena key list sekarang beda-beda per viewer, daripada nyari dan hapus satu-satu cukup naikkan generation-nya.
+const postListGenerationKey = "post:list:gen"
+
+// Ini yang ditampilkan ke user lain kalau postingannya anonim
+var anonymousUserSummary = response.UserSummary{
+	UserID:   0,
+	Username: "anonymous",
+	FullName: "Anonymous",
+}
 
 func (s *PostServiceImpl) Create(ctx context.Context, req request.CreatePostRequest) (*response.CreatePostResponse, error) {
 	tx, err := s.DB.Begin()
@@ -80,12 +134,28 @@ func (s *PostServiceImpl) Create(ctx context.Context, req request.CreatePostRequ
 		UserID: req.UserID,
 		Content: req.Content,
 		Mood: moodResp.Prediction,
+		Visibility: entity.VisibilityPublic,
+		IsAnonymous: req.IsAnonymous != nil && *req.IsAnonymous,
 		CreatedAt: time.Now(),
 	}
+	if req.Visibility != "" {
+		post.Visibility = req.Visibility
+	}
+	if req.DeclaredMood != nil && *req.DeclaredMood != "" {
+		post.DeclaredMood = req.DeclaredMood
+	}
 
 	if err := utils.ValidatePostInput(post.Content, post.Mood); err != nil {
 		return nil, err
 	}
+	if err := utils.ValidatePostVisibility(post.Visibility); err != nil {
+		return nil, err
+	}
+	if post.DeclaredMood != nil {
+		if err := utils.ValidateDeclaredMood(*post.DeclaredMood); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create Post (store ke dalam database)
 	createdPost, err := s.PostRepository.Create(ctx, tx, &post)
@@ -93,25 +163,65 @@ func (s *PostServiceImpl) Create(ctx context.Context, req request.CreatePostRequ
 		return nil, err
 	}
 
+	// Simpan hashtag-nya (masih dalam transaction yang sama)
+	err = s.TagRepository.ReplacePostTags(ctx, tx, createdPost.PostID, utils.ExtractHashtags(createdPost.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	// Pasang gambar yang sudah di-upload sebelumnya (lewat /post/media)
+	if len(req.MediaIDs) > media.MaxImagesPerPost {
+		err = fmt.Errorf("a post can have at most %d images", media.MaxImagesPerPost)
+		return nil, err
+	}
+	err = s.MediaRepository.AttachToPost(ctx, tx, createdPost.PostID, req.UserID, req.MediaIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := s.Find(ctx, createdPost.PostID)
+	result, err := s.Find(ctx, createdPost.PostID, req.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Invalidate the "post:all" cache after post creation
-	_ = s.RedisClient.Del(ctx, fmt.Sprintf("post:all:v%d", cacheVersion)).Err()
+	// Invalidate semua cache list postingan setelah post dibuat
+	s.invalidatePostLists(ctx)
+
+	// Masukkan langsung ke feed teman-temannya yang sudah dibangun (fan-out on write)
+	s.fanOutPost(ctx, createdPost)
 
 	return result, nil
 }
 
-func (s *PostServiceImpl) Find(ctx context.Context, postID int) (*response.CreatePostResponse, error) {
+func (s *PostServiceImpl) Find(ctx context.Context, postID, viewerID int) (*response.CreatePostResponse, error) {
+	// Step 0: Ambil post-nya (dari cache kalau ada). Cache ini menyimpan versi lengkap (author asli),
+	// jadi cek visibility dan penyamaran author anonim selalu dilakukan setelahnya.
+	postResponse, err := s.loadPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Pastikan viewer boleh melihat post ini (kalau tidak boleh, anggap saja post-nya tidak ada)
+	visible, err := s.canViewPost(ctx, postResponse, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, fmt.Errorf("%w: ID %d", ErrPostNotFound, postID)
+	}
+
+	// Return response
+	return presentPost(postResponse, viewerID), nil
+}
+
+func (s *PostServiceImpl) loadPost(ctx context.Context, postID int) (*response.CreatePostResponse, error) {
 	// Step 0: Check if post yang mau kita cari ada di cache
-	cacheKey := fmt.Sprintf("post:%d:v%d", postID, cacheVersion)
+	cacheKey := postCacheKey(postID)
 	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
 	if err == nil {
 		var postResp response.CreatePostResponse
@@ -122,9 +232,9 @@ func (s *PostServiceImpl) Find(ctx context.Context, postID int) (*response.Creat
 
 	// Start step 1 kalau step 0 gagal: cari post-nya di database
 	post, err := s.PostRepository.Find(ctx, s.DB, postID)
-	if err != nil {
+	if err != nil || post == nil {
 		if err == sql.ErrNoRows || post == nil {
-			return nil, fmt.Errorf("post with ID %d not found", postID)
+			return nil, fmt.Errorf("%w: ID %d", ErrPostNotFound, postID)
 		}
 		return nil, err
 	}
@@ -139,17 +249,17 @@ func (s *PostServiceImpl) Find(ctx context.Context, postID int) (*response.Creat
 	}
 
 	// Convert to response
-	postResponse := response.CreatePostResponse{
-		PostID:    post.PostID,
-		UserID:    post.UserID,
-		User: response.UserSummary{
-			UserID: user.ID,
-			Username: user.Username,
-			FullName: user.Fullname,
-		},
-		Content:   post.Content,
-		Mood:      post.Mood,
-		CreatedAt:  post.CreatedAt,
+	postResponse := toPostResponse(post, user)
+
+	// Step 2.5: Load jumlah reaksi dan tag-nya (ikut di-cache, jadi setiap reaksi berubah cache ini dihapus)
+	if err := s.attachReactionCounts(ctx, []*response.CreatePostResponse{postResponse}); err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, []*response.CreatePostResponse{postResponse}); err != nil {
+		return nil, err
+	}
+	if err := s.attachMedia(ctx, []*response.CreatePostResponse{postResponse}); err != nil {
+		return nil, err
 	}
 
 	// Cache the response
@@ -158,156 +268,101 @@ func (s *PostServiceImpl) Find(ctx context.Context, postID int) (*response.Creat
 		_ = s.RedisClient.Set(ctx, cacheKey, jsonVal, 10*time.Minute).Err()
 	}
 
-	// Return response
-	return &postResponse, nil
+	return postResponse, nil
 }
 
-func (s *PostServiceImpl) FindAll(ctx context.Context, limit, offset int) ([]*response.CreatePostResponse, error) {
+func (s *PostServiceImpl) FindAll(ctx context.Context, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error) {
 	// step 0: Check cache-nya dulu (apakah data yang diretrieve ada perubahan atau engga)
-	cacheKey := fmt.Sprintf("post:all:v%d:limit:%d:offset:%d", cacheVersion, limit, offset)
-	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var postResp []*response.CreatePostResponse
-		if err := json.Unmarshal([]byte(cached), &postResp); err == nil {
-			return postResp, nil
-		}
+	// hasilnya beda-beda tergantung siapa yang lihat, jadi viewerID wajib masuk ke cache key
+	cacheKey := fmt.Sprintf("post:all:v%d:g%d:viewer:%d:cursor:%s:limit:%d", cacheVersion, s.postListGeneration(ctx), viewerID, cursor, limit)
+	if page, ok := s.getCachedPostPage(ctx, cacheKey); ok {
+		return page, nil
 	}
 
-	// step 1: find all posts (fetch dari database jika tidak ada di cache)
-	posts, err := s.PostRepository.FindAll(ctx, s.DB, limit, offset)
+	// step 1: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
 	if err != nil {
-		if err == sql.ErrNoRows || posts == nil {
-			return nil, fmt.Errorf("no posts found")
-		}
 		return nil, err
 	}
+	afterCreatedAt, afterID := after.After()
 
-	// step 2: kumpulkan semua user id buat di proses sebagai batch queries (menghindari N+1 query)
-	userIDMap := make(map[int]bool)
-	for _, post := range posts {
-		userIDMap[post.UserID] = true
-	}
-
-	// step 3: ambil semua id user yang sudah kita kumpulkan (ambil yang True aja)
-	var userIDs []int
-	for id := range userIDMap {
-		userIDs = append(userIDs, id)
-	}
-
-	// step 4: query ke database untuk ambil semua user yang ada di userIDMap secara sekaligus
-	users, err := s.UserRepository.FindByIDs(ctx, s.DB, userIDs)
+	// step 2: find posts yang boleh dilihat viewer (ambil satu lebih banyak buat ngecek masih ada halaman berikutnya)
+	posts, err := s.PostRepository.FindAll(ctx, s.DB, viewerID, afterCreatedAt, afterID, limit+1)
 	if err != nil {
-		if err == sql.ErrNoRows || users == nil {
-			return nil, fmt.Errorf("no users found")
-		}
 		return nil, err
 	}
 
-	// step 5: buat map untuk memudahkan pengambilan user berdasarkan ID
-	userMap := make(map[int]*entity.User)
-	for _, user := range users {
-		userMap[user.ID] = user
-	}
-
-	var postResponses []*response.CreatePostResponse
-	for _, post := range posts {
-		user, ok := userMap[post.UserID]
-		if !ok {
-			fmt.Printf("user with ID %d not found for post ID %d\n", post.UserID, post.PostID)
-			continue // Skip this post if user not found
-		}
-
-		postResponse := &response.CreatePostResponse{
-			PostID:    post.PostID,
-			UserID:    post.UserID,
-			User: response.UserSummary{
-				UserID: user.ID,
-				Username: user.Username,
-				FullName: user.Fullname,
-			},
-			Content:   post.Content,
-			Mood:      post.Mood,
-			CreatedAt:  post.CreatedAt,
-		}
-		postResponses = append(postResponses, postResponse)
-	}
-
-	// Check if any posts were found
-	if len(postResponses) == 0 {
-		return []*response.CreatePostResponse{}, nil
+	// step 3 - 5: ambil data user-nya sekaligus lalu susun response
+	postResponses, err := s.buildPostResponses(ctx, posts, viewerID)
+	if err != nil {
+		return nil, err
 	}
+	page := newPostPage(postResponses, limit)
 
 	// Simpan response ke dalam cache
-	jsonVal, err := json.Marshal(postResponses) // Convert data ke JSON
-	if err == nil {
-		_ = s.RedisClient.Set(ctx, cacheKey, jsonVal, 10*time.Minute).Err() // Simpan ke Redis
-	}
+	s.setCachedPostPage(ctx, cacheKey, page)
 
 	// Return response
-	return postResponses, nil
+	return page, nil
 }
 
-func (s *PostServiceImpl) FindByUserID(ctx context.Context, userID int) ([]*response.CreatePostResponse, error) {
+func (s *PostServiceImpl) FindByUserID(ctx context.Context, userID, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error) {
 	// step 1: validate if user exists
 	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
 	if err != nil || user == nil {
-		if err == sql.ErrNoRows {
+		if err == sql.ErrNoRows || user == nil {
 			return nil, fmt.Errorf("user with ID %d not found", userID)
 		}
 		return nil, err
 	}
 
-	// step 2: find posts by userID
-	posts, err := s.PostRepository.FindByUserID(ctx, s.DB, userID)
+	// step 2: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 3: find posts by userID (repository sudah nyaring visibility dan postingan anonim)
+	posts, err := s.PostRepository.FindByUserID(ctx, s.DB, userID, viewerID, afterCreatedAt, afterID, limit+1)
 	if err != nil {
-		if err == sql.ErrNoRows || posts == nil {
-			return nil, fmt.Errorf("no posts found for user with ID %d", userID)
-		}
 		return nil, err
 	}
 
 	var postResponses []*response.CreatePostResponse
 	for _, post := range posts {
-		user, err := s.UserRepository.FindByID(ctx, s.DB, post.UserID)
-		if err != nil {
-			if err == sql.ErrNoRows || user == nil {
-				return nil, fmt.Errorf("user with ID %d not found", post.UserID)
-			}
-			return nil, err
-		}
-		postResponse := &response.CreatePostResponse{
-			PostID:    post.PostID,
-			UserID:    post.UserID,
-			User: response.UserSummary{
-				UserID: user.ID,
-				Username: user.Username,
-				FullName: user.Fullname,
-			},
-			Content:   post.Content,
-			Mood:      post.Mood,
-			CreatedAt:  post.CreatedAt,
-		}
-		postResponses = append(postResponses, postResponse)
+		postResponses = append(postResponses, presentPost(toPostResponse(post, user), viewerID))
 	}
-
-	// Check if any posts were found
-	if len(postResponses) == 0 {
-		return []*response.CreatePostResponse{}, nil
+	if err := s.attachReactionCounts(ctx, postResponses); err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, postResponses); err != nil {
+		return nil, err
 	}
 
-	return postResponses, nil
+	return newPostPage(postResponses, limit), nil
 }
 
-func (s *PostServiceImpl) Update(ctx context.Context, postID int, req request.CreatePostRequest) (*response.CreatePostResponse, error) {
+func (s *PostServiceImpl) Update(ctx context.Context, postID, userID int, req request.CreatePostRequest) (*response.CreatePostResponse, error) {
 	// Validate if post exists
 	post, err := s.PostRepository.Find(ctx, s.DB, postID)
-	if err != nil {
+	if err != nil || post == nil {
 		if err == sql.ErrNoRows || post == nil {
 			return nil, fmt.Errorf("post with ID %d not found", postID)
 		}
 		return nil, err
 	}
 
+	// Cuma pemilik postingan yang boleh ngedit
+	if post.UserID != userID {
+		return nil, fmt.Errorf("you can only edit your own posts")
+	}
+
+	// Gambar cuma bisa dipasang waktu bikin postingan
+	if len(req.MediaIDs) > 0 {
+		return nil, fmt.Errorf("images can only be attached when creating a post")
+	}
+
 	// Validate if user exists
 	user, err := s.UserRepository.FindByID(ctx, s.DB, post.UserID)
 	if err != nil {
@@ -317,7 +372,7 @@ func (s *PostServiceImpl) Update(ctx context.Context, postID int, req request.Cr
 		return nil, err
 	}
 
-	// Ambil data mood dari MoodPredictionService
+	// Ambil data mood dari MoodPredictionService (selalu diprediksi ulang dari isi yang baru, mood dari client tidak dipakai)
 	moodPrediction := request.MoodPredictionRequest{
 		Input: req.Content,
 	}
@@ -332,8 +387,31 @@ func (s *PostServiceImpl) Update(ctx context.Context, postID int, req request.Cr
 	}
 
 	// Kalau udah aman, baru kita update post-nya
+	// visibility dan anonim cuma diganti kalau dikirim di request, kalau engga pakai yang lama
+	previousVisibility := post.Visibility
 	post.Content = req.Content
 	post.Mood = moodResp.Prediction
+	if req.Visibility != "" {
+		post.Visibility = req.Visibility
+	}
+	if req.IsAnonymous != nil {
+		post.IsAnonymous = *req.IsAnonymous
+	}
+	if req.DeclaredMood != nil {
+		// string kosong berarti user menghapus mood pilihannya
+		post.DeclaredMood = nil
+		if *req.DeclaredMood != "" {
+			post.DeclaredMood = req.DeclaredMood
+		}
+	}
+	if err := utils.ValidatePostVisibility(post.Visibility); err != nil {
+		return nil, err
+	}
+	if post.DeclaredMood != nil {
+		if err := utils.ValidateDeclaredMood(*post.DeclaredMood); err != nil {
+			return nil, err
+		}
+	}
 
 	// Start transaction
 	tx, err := s.DB.Begin()
@@ -353,6 +431,24 @@ func (s *PostServiceImpl) Update(ctx context.Context, postID int, req request.Cr
 	if err != nil {
 		return nil, err
 	}
+	if updatedPost == nil {
+		err = fmt.Errorf("post with ID %d not found", postID)
+		return nil, err
+	}
+
+	// Hashtag diekstrak ulang dari isi yang baru
+	err = s.TagRepository.ReplacePostTags(ctx, tx, postID, utils.ExtractHashtags(updatedPost.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	// Kalau visibility-nya berubah, bookmark milik user yang sudah tidak boleh lihat postingan ini ikut dibuang
+	if updatedPost.Visibility != previousVisibility {
+		_, err = s.BookmarkRepository.DeleteInaccessible(ctx, tx, postID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Commit transaction
 	err = tx.Commit()
@@ -361,11 +457,11 @@ func (s *PostServiceImpl) Update(ctx context.Context, postID int, req request.Cr
 	}
 
 	// Invalidate the cache for the updated post
-	s.RedisClient.Del(ctx, fmt.Sprintf("post:%d:v%d", postID, cacheVersion))
-	s.RedisClient.Del(ctx, fmt.Sprintf("post:all:v%d", cacheVersion))
+	s.RedisClient.Del(ctx, postCacheKey(postID))
+	s.invalidatePostLists(ctx)
 
-	// Cari post yang udah diupdate untuk direturn sebagai response
-	postResponse, err := s.Find(ctx, updatedPost.PostID)
+	// Cari post yang udah diupdate untuk direturn sebagai response (dilihat dari sisi pemiliknya)
+	postResponse, err := s.Find(ctx, updatedPost.PostID, post.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -373,7 +469,77 @@ func (s *PostServiceImpl) Update(ctx context.Context, postID int, req request.Cr
 	return postResponse, nil
 }
 
-func (s *PostServiceImpl) Delete(ctx context.Context, postID int) (string, error) {
+func (s *PostServiceImpl) GetRevisions(ctx context.Context, postID, viewerID int) ([]*response.PostRevisionResponse, error) {
+	// step 1: pastikan post-nya ada
+	post, err := s.PostRepository.Find(ctx, s.DB, postID)
+	if err != nil || post == nil {
+		if err == sql.ErrNoRows || post == nil {
+			return nil, fmt.Errorf("%w: ID %d", ErrPostNotFound, postID)
+		}
+		return nil, err
+	}
+
+	// step 2: riwayat edit cuma boleh dilihat pemilik postingan dan moderator (buat nindaklanjutin komentar / laporan)
+	if post.UserID != viewerID {
+		isModerator, err := s.Access.HasPermission(ctx, viewerID, entity.PermissionModerateReports)
+		if err != nil {
+			return nil, err
+		}
+		if !isModerator {
+			return nil, ErrPostHistoryForbidden
+		}
+	}
+
+	// step 3: ambil semua revisinya (yang terbaru duluan)
+	revisions, err := s.PostRepository.GetRevisions(ctx, s.DB, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionResponses := []*response.PostRevisionResponse{}
+	for _, revision := range revisions {
+		revisionResponse := &response.PostRevisionResponse{
+			RevisionID: revision.RevisionID,
+			PostID:     revision.PostID,
+			Content:    revision.Content,
+			Mood:       revision.Mood,
+			CreatedAt:  revision.CreatedAt,
+		}
+		if revision.DeclaredMood != nil {
+			revisionResponse.DeclaredMood = *revision.DeclaredMood
+		}
+		revisionResponses = append(revisionResponses, revisionResponse)
+	}
+	return revisionResponses, nil
+}
+
+func (s *PostServiceImpl) Delete(ctx context.Context, postID, userID int) (string, error) {
+	// Check if post exists (sebelum transaction dibuka, biar early return ga ninggalin transaction)
+	post, err := s.PostRepository.Find(ctx, s.DB, postID)
+	if err != nil || post == nil {
+		if err == sql.ErrNoRows || post == nil {
+			return "", fmt.Errorf("%w: ID %d", ErrPostNotFound, postID)
+		}
+		return "", err
+	}
+
+	// Yang boleh hapus cuma pemilik post dan moderator (buat nindaklanjutin laporan)
+	if post.UserID != userID {
+		isModerator, err := s.Access.HasPermission(ctx, userID, entity.PermissionModerateReports)
+		if err != nil {
+			return "", err
+		}
+		if !isModerator {
+			return "", ErrPostForbidden
+		}
+	}
+
+	// Catat dulu gambar-gambarnya, barisnya ikut terhapus (ON DELETE CASCADE) tapi file-nya di storage harus dihapus sendiri
+	postMedia, err := s.MediaRepository.GetByPostIDs(ctx, s.DB, []int{postID})
+	if err != nil {
+		return "", err
+	}
+
 	// Start transaction
 	tx, err := s.DB.Begin()
 	if err != nil {
@@ -387,15 +553,6 @@ func (s *PostServiceImpl) Delete(ctx context.Context, postID int) (string, error
 		}
 	}()
 
-	// Check if post exists
-	_, err = s.PostRepository.Find(ctx, s.DB, postID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", fmt.Errorf("post with ID %d not found", postID)
-		}
-		return "", err
-	}
-
 	// Delete from DB
 	message, err := s.PostRepository.Delete(ctx, tx, postID)
 	if err != nil {
@@ -408,95 +565,437 @@ func (s *PostServiceImpl) Delete(ctx context.Context, postID int) (string, error
 		return "", err
 	}
 
+	s.Audit.Log(ctx, AuditEntry{
+		ActorID:    &userID,
+		Action:     entity.AuditPostDeleted,
+		TargetType: entity.AuditTargetPost,
+		TargetID:   &postID,
+		Metadata:   map[string]interface{}{"owner_id": post.UserID},
+	})
+
 	// Invalidate the cache for the deleted post
-	s.RedisClient.Del(ctx, fmt.Sprintf("post:%d:v%d", postID, cacheVersion))
-	s.RedisClient.Del(ctx, fmt.Sprintf("post:all:v%d", cacheVersion))
+	s.RedisClient.Del(ctx, postCacheKey(postID))
+	s.invalidatePostLists(ctx)
+
+	// Baru hapus file gambarnya setelah commit, supaya kalau transaksi gagal gambarnya masih utuh
+	for _, item := range postMedia[postID] {
+		deleteMediaObjects(ctx, s.Storage, item.StorageKey, item.ThumbnailKey)
+	}
 
 	return message, nil
 }
 
-// func (s *PostServiceImpl) GetPostBySearch(ctx context.Context, query string) ([]*response.CreatePostResponse, error) {
-// 	// nanti diisinya besok.
-// }
+func (s *PostServiceImpl) Search(ctx context.Context, viewerID int, req request.SearchPostRequest) (*pagination.Page[*response.PostSearchResult], error) {
+	// step 0: rapikan parameter-nya dulu supaya query yang sama selalu dapat cache key yang sama
+	req.Query = strings.TrimSpace(req.Query)
+	req.Author = strings.TrimSpace(req.Author)
+	if req.Limit <= 0 {
+		req.Limit = pagination.DefaultLimit
+	}
 
-func (s *PostServiceImpl) GetFriendPosts(ctx context.Context, userID, limit, offset int) ([]*response.CreatePostResponse, error) {
-	cacheKey := fmt.Sprintf("friend_posts:%d:limit=%d:offset=%d:v%d", userID, limit, offset, cacheVersion)
+	// step 1: cek cache, key-nya pakai hash dari semua parameter (hasilnya juga tergantung siapa yang lihat)
+	queryHash := sha256.Sum256([]byte(strings.Join([]string{req.Query, req.Mood, req.Author, req.From, req.To, req.Cursor, strconv.Itoa(req.Limit)}, "\x00")))
+	cacheKey := fmt.Sprintf("post:search:v%d:g%d:viewer:%d:%s", cacheVersion, s.postListGeneration(ctx), viewerID, hex.EncodeToString(queryHash[:]))
 	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
 	if err == nil {
-		var postResp []*response.CreatePostResponse
-		if err := json.Unmarshal([]byte(cached), &postResp); err == nil {
-			return postResp, nil
+		var page pagination.Page[*response.PostSearchResult]
+		if err := json.Unmarshal([]byte(cached), &page); err == nil {
+			return &page, nil
 		}
 	}
 
-	// Step 1: Get friend posts from repository
-	friendPosts, err := s.PostRepository.GetFriendPosts(ctx, s.DB, userID, limit, offset)
+	// step 2: parse cursor dan rentang tanggal (tanggal "to" ikut dihitung, jadi batasnya besoknya)
+	params := repository.PostSearchParams{
+		ViewerID: viewerID,
+		Query:    req.Query,
+		Mood:     req.Mood,
+		Author:   req.Author,
+		Limit:    req.Limit + 1, // ambil satu lebih banyak buat ngecek masih ada halaman berikutnya
+	}
+	after, err := pagination.DecodeScoreCursor(req.Cursor)
 	if err != nil {
-		if err == sql.ErrNoRows || friendPosts == nil {
-			return nil, fmt.Errorf("no friend posts found for user with ID %d", userID)
+		return nil, err
+	}
+	if after != nil {
+		params.AfterRank, params.AfterPostID = after.Score, after.ID
+	}
+	if req.From != "" {
+		from, err := time.Parse("2006-01-02", req.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date, must be YYYY-MM-DD")
 		}
+		params.From = &from
+	}
+	if req.To != "" {
+		to, err := time.Parse("2006-01-02", req.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date, must be YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+		params.To = &to
+	}
+
+	// step 3: jalankan search-nya
+	hits, err := s.PostRepository.Search(ctx, s.DB, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: load author-nya sekaligus (author anonim tetap disamarkan sesuai viewer)
+	posts := make([]*entity.Post, len(hits))
+	for i, hit := range hits {
+		posts[i] = hit.Post
+	}
+	postResponses, err := s.buildPostResponses(ctx, posts, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	postResponseMap := make(map[int]*response.CreatePostResponse)
+	for _, postResponse := range postResponses {
+		postResponseMap[postResponse.PostID] = postResponse
+	}
+
+	results := []*response.PostSearchResult{}
+	for _, hit := range hits {
+		postResponse, ok := postResponseMap[hit.Post.PostID]
+		if !ok {
+			continue
+		}
+		results = append(results, &response.PostSearchResult{
+			Post:    postResponse,
+			Snippet: hit.Snippet,
+			Rank:    hit.Rank,
+		})
+	}
+	page := pagination.NewPage(results, req.Limit, func(result *response.PostSearchResult) string {
+		return pagination.EncodeScoreCursor(result.Rank, result.Post.PostID)
+	})
+
+	// step 5: simpan ke cache
+	jsonVal, err := json.Marshal(page)
+	if err == nil {
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonVal, 10*time.Minute).Err()
+	}
+
+	return page, nil
+}
+
+func (s *PostServiceImpl) GetFriendPosts(ctx context.Context, userID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error) {
+	cacheKey := fmt.Sprintf("friend_posts:%d:cursor:%s:limit:%d:v%d:g%d", userID, cursor, limit, cacheVersion, s.postListGeneration(ctx))
+	if page, ok := s.getCachedPostPage(ctx, cacheKey); ok {
+		return page, nil
+	}
+
+	// Step 1: Validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
 		return nil, err
 	}
+	afterCreatedAt, afterID := after.After()
+
+	// Step 2: Get friend posts from repository
+	friendPosts, err := s.PostRepository.GetFriendPosts(ctx, s.DB, userID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3 - 5: Load all authors in one go and build the responses
+	postResponses, err := s.buildPostResponses(ctx, friendPosts, userID)
+	if err != nil {
+		return nil, err
+	}
+	page := newPostPage(postResponses, limit)
+
+	// Step 6: Cache the response
+	s.setCachedPostPage(ctx, cacheKey, page)
+
+	return page, nil
+}
+
+func (s *PostServiceImpl) FindByTag(ctx context.Context, tag string, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error) {
+	cacheKey := fmt.Sprintf("tag:%s:posts:v%d:g%d:viewer:%d:cursor:%s:limit:%d", tag, cacheVersion, s.postListGeneration(ctx), viewerID, cursor, limit)
+	if page, ok := s.getCachedPostPage(ctx, cacheKey); ok {
+		return page, nil
+	}
+
+	// step 1: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterID := after.After()
+
+	// step 2: ambil postingan dengan tag ini yang boleh dilihat viewer
+	posts, err := s.PostRepository.FindByTag(ctx, s.DB, tag, viewerID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: susun response-nya (author anonim disamarkan)
+	postResponses, err := s.buildPostResponses(ctx, posts, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	page := newPostPage(postResponses, limit)
+
+	// step 4: simpan ke cache
+	s.setCachedPostPage(ctx, cacheKey, page)
+
+	return page, nil
+}
+
+// FindByIDs urutan hasilnya mengikuti urutan postIDs, postingan yang tidak ada / tidak boleh dilihat dilewati
+func (s *PostServiceImpl) FindByIDs(ctx context.Context, postIDs []int, viewerID int) ([]*response.CreatePostResponse, error) {
+	posts, err := s.PostRepository.FindVisibleByIDs(ctx, s.DB, viewerID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	postMap := make(map[int]*entity.Post)
+	for _, post := range posts {
+		postMap[post.PostID] = post
+	}
+	orderedPosts := make([]*entity.Post, 0, len(posts))
+	for _, postID := range postIDs {
+		if post, ok := postMap[postID]; ok {
+			orderedPosts = append(orderedPosts, post)
+		}
+	}
+
+	postResponses, err := s.buildPostResponses(ctx, orderedPosts, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(postResponses) == 0 {
+		return []*response.CreatePostResponse{}, nil
+	}
+	return postResponses, nil
+}
+
+// fanOutPost masukin postingan baru ke feed author-nya dan (kalau temannya tidak terlalu banyak) ke feed semua temannya.
+// Postingan private/anonim cuma masuk ke feed author-nya sendiri, sama seperti aturan di GetFriendPosts.
+// Follower tag tidak di-fan-out, postingannya baru masuk waktu feed mereka dibangun ulang.
+func (s *PostServiceImpl) fanOutPost(ctx context.Context, post *entity.Post) {
+	recipients := []int{post.UserID}
+	if post.Visibility != entity.VisibilityPrivate && !post.IsAnonymous {
+		friendIDs, err := s.FriendRepository.GetFriendIDs(ctx, s.DB, post.UserID)
+		if err != nil {
+			log.Printf("failed to load friends for feed fan-out of post %d: %v", post.PostID, err)
+		} else if len(friendIDs) <= maxFanOutFriends {
+			recipients = append(recipients, friendIDs...)
+		}
+	}
+
+	for _, userID := range recipients {
+		if err := addToFeed(ctx, s.RedisClient, userID, post, feedSourceFriend); err != nil {
+			log.Printf("failed to fan out post %d to feed of user %d: %v", post.PostID, userID, err)
+		}
+	}
+}
+
+// newPostPage motong hasil query limit+1 jadi satu halaman, cursor-nya dari (createdat, postid) postingan terakhir
+func newPostPage(postResponses []*response.CreatePostResponse, limit int) *pagination.Page[*response.CreatePostResponse] {
+	return pagination.NewPage(postResponses, limit, func(post *response.CreatePostResponse) string {
+		return pagination.EncodeCursor(post.CreatedAt, post.PostID)
+	})
+}
+
+func (s *PostServiceImpl) getCachedPostPage(ctx context.Context, cacheKey string) (*pagination.Page[*response.CreatePostResponse], bool) {
+	cached, err := s.RedisClient.Get(ctx, cacheKey).Result()
+	if err != nil {
+		return nil, false
+	}
+	var page pagination.Page[*response.CreatePostResponse]
+	if err := json.Unmarshal([]byte(cached), &page); err != nil {
+		return nil, false
+	}
+	return &page, true
+}
+
+func (s *PostServiceImpl) setCachedPostPage(ctx context.Context, cacheKey string, page *pagination.Page[*response.CreatePostResponse]) {
+	jsonVal, err := json.Marshal(page)
+	if err == nil {
+		_ = s.RedisClient.Set(ctx, cacheKey, jsonVal, 10*time.Minute).Err()
+	}
+}
 
-	// step 2: Collect all user IDs from friend posts
+// buildPostResponses ngambil semua author sekaligus (menghindari N+1 query) lalu nyusun response sesuai viewer
+func (s *PostServiceImpl) buildPostResponses(ctx context.Context, posts []*entity.Post, viewerID int) ([]*response.CreatePostResponse, error) {
+	if len(posts) == 0 {
+		return []*response.CreatePostResponse{}, nil
+	}
+
+	// kumpulkan semua user id buat di proses sebagai batch queries
 	userIDMap := make(map[int]bool)
-	for _, post := range friendPosts {
+	for _, post := range posts {
 		userIDMap[post.UserID] = true
 	}
 
-	// step 3: Get all user IDs as a slice
 	var userIDs []int
 	for id := range userIDMap {
 		userIDs = append(userIDs, id)
 	}
 
-	// step 4: Query the database to get all users in one go
+	// query ke database untuk ambil semua user yang ada di userIDMap secara sekaligus
 	users, err := s.UserRepository.FindByIDs(ctx, s.DB, userIDs)
 	if err != nil {
 		if err == sql.ErrNoRows || users == nil {
-			return nil, fmt.Errorf("no users found for friend posts")
+			return nil, fmt.Errorf("no users found")
 		}
 		return nil, err
 	}
 
-	// step 5: Create a map for easy user lookup by ID
+	// buat map untuk memudahkan pengambilan user berdasarkan ID
 	userMap := make(map[int]*entity.User)
 	for _, user := range users {
 		userMap[user.ID] = user
 	}
 
 	var postResponses []*response.CreatePostResponse
-	for _, post := range friendPosts {
+	for _, post := range posts {
 		user, ok := userMap[post.UserID]
 		if !ok {
 			fmt.Printf("user with ID %d not found for post ID %d\n", post.UserID, post.PostID)
 			continue // Skip this post if user not found
 		}
+		postResponses = append(postResponses, presentPost(toPostResponse(post, user), viewerID))
+	}
+
+	// ambil jumlah reaksi dan tag semua post sekaligus juga
+	if err := s.attachReactionCounts(ctx, postResponses); err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, postResponses); err != nil {
+		return nil, err
+	}
+	if err := s.attachMedia(ctx, postResponses); err != nil {
+		return nil, err
+	}
+	return postResponses, nil
+}
+
+func (s *PostServiceImpl) attachReactionCounts(ctx context.Context, postResponses []*response.CreatePostResponse) error {
+	postIDs := make([]int, 0, len(postResponses))
+	for _, postResponse := range postResponses {
+		postIDs = append(postIDs, postResponse.PostID)
+	}
+	counts, err := s.ReactionRepository.GetCountsByPostIDs(ctx, s.DB, postIDs)
+	if err != nil {
+		return err
+	}
+	for _, postResponse := range postResponses {
+		postResponse.ReactionCounts = counts[postResponse.PostID]
+	}
+	return nil
+}
 
-		postResponse := &response.CreatePostResponse{
-			PostID:    post.PostID,
-			UserID:    post.UserID,
-			User: response.UserSummary{
-				UserID: user.ID,
-				Username: user.Username,
-				FullName: user.Fullname,
-			},
-			Content:   post.Content,
-			Mood:      post.Mood,
-			CreatedAt:  post.CreatedAt,
+func (s *PostServiceImpl) attachTags(ctx context.Context, postResponses []*response.CreatePostResponse) error {
+	postIDs := make([]int, 0, len(postResponses))
+	for _, postResponse := range postResponses {
+		postIDs = append(postIDs, postResponse.PostID)
+	}
+	tags, err := s.TagRepository.GetTagsByPostIDs(ctx, s.DB, postIDs)
+	if err != nil {
+		return err
+	}
+	for _, postResponse := range postResponses {
+		postResponse.Tags = tags[postResponse.PostID]
+		if postResponse.Tags == nil {
+			postResponse.Tags = []string{}
 		}
-		postResponses = append(postResponses, postResponse)
 	}
+	return nil
+}
 
-	if len(postResponses) == 0 {
-		return []*response.CreatePostResponse{}, nil
+func (s *PostServiceImpl) attachMedia(ctx context.Context, postResponses []*response.CreatePostResponse) error {
+	postIDs := make([]int, 0, len(postResponses))
+	for _, postResponse := range postResponses {
+		postIDs = append(postIDs, postResponse.PostID)
 	}
+	postMedia, err := s.MediaRepository.GetByPostIDs(ctx, s.DB, postIDs)
+	if err != nil {
+		return err
+	}
+	for _, postResponse := range postResponses {
+		postResponse.Media = []*response.MediaResponse{}
+		for _, item := range postMedia[postResponse.PostID] {
+			postResponse.Media = append(postResponse.Media, toMediaResponse(s.Storage, item))
+		}
+	}
+	return nil
+}
 
-	// Step 6: Cache the response
-	jsonVal, err := json.Marshal(postResponses)
-	if err == nil {
-		_ = s.RedisClient.Set(ctx, cacheKey, jsonVal, 10*time.Minute).Err()
+// canViewPost ngecek apakah viewer boleh lihat post ini berdasarkan visibility-nya (viewerID 0 = belum login)
+// dan blokir antara viewer dan penulisnya (kecuali postingan anonim, biar penulisnya tidak bisa ditebak).
+// Postingan yang disembunyikan moderator cuma bisa dilihat penulisnya.
+func (s *PostServiceImpl) canViewPost(ctx context.Context, post *response.CreatePostResponse, viewerID int) (bool, error) {
+	if viewerID != 0 && post.UserID == viewerID {
+		return true, nil
 	}
+	if post.IsHidden {
+		return false, nil
+	}
+	if !post.IsAnonymous {
+		if err := s.Guard.CanInteract(ctx, viewerID, post.UserID); err != nil {
+			if err == ErrBlocked {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	if post.Visibility == entity.VisibilityPublic {
+		return true, nil
+	}
+	if post.Visibility != entity.VisibilityFriends || viewerID == 0 {
+		return false, nil
+	}
+	return s.FriendRepository.IsFriendAlreadyAccepted(ctx, s.DB, viewerID, post.UserID)
+}
 
-	return postResponses, nil
-}
\ No newline at end of file
+func (s *PostServiceImpl) postListGeneration(ctx context.Context) int64 {
+	generation, err := s.RedisClient.Get(ctx, postListGenerationKey).Int64()
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+func (s *PostServiceImpl) invalidatePostLists(ctx context.Context) {
+	_ = s.RedisClient.Incr(ctx, postListGenerationKey).Err()
+}
+
+func toPostResponse(post *entity.Post, user *entity.User) *response.CreatePostResponse {
+	postResponse := &response.CreatePostResponse{
+		PostID:    post.PostID,
+		UserID:    post.UserID,
+		User: response.UserSummary{
+			UserID: user.ID,
+			Username: user.Username,
+			FullName: user.Fullname,
+		},
+		Content:   post.Content,
+		Mood:      post.Mood,
+		Visibility: post.Visibility,
+		IsAnonymous: post.IsAnonymous,
+		IsEdited:   post.EditedAt != nil,
+		EditedAt:   post.EditedAt,
+		IsHidden:   post.HiddenAt != nil,
+		CreatedAt:  post.CreatedAt,
+	}
+	if post.DeclaredMood != nil {
+		postResponse.DeclaredMood = *post.DeclaredMood
+	}
+	return postResponse
+}
+
+// presentPost nyembunyiin identitas author kalau postingannya anonim dan yang lihat bukan author-nya sendiri.
+// Author aslinya tetap tersimpan di database, jadi masih bisa ditelusuri sama moderator.
+func presentPost(post *response.CreatePostResponse, viewerID int) *response.CreatePostResponse {
+	if !post.IsAnonymous || (viewerID != 0 && post.UserID == viewerID) {
+		return post
+	}
+	masked := *post
+	masked.UserID = 0
+	masked.User = anonymousUserSummary
+	return &masked
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/request"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type ProfileService interface {
+	Find(ctx context.Context, userID, viewerID int) (*response.ProfileResponse, error)
+	Update(ctx context.Context, userID int, request request.UpdateProfileRequest) (*response.ProfileResponse, error)
+}
+
+type ProfileServiceImpl struct {
+	DB                *sql.DB
+	ProfileRepository repository.ProfileRepository
+	UserRepository    repository.UserRepository
+	FriendRepository  repository.FriendRepository
+	RedisClient       *redis.Client
+}
+
+func NewProfileService(db *sql.DB, profileRepository repository.ProfileRepository, userRepository repository.UserRepository, friendRepository repository.FriendRepository, redisClient *redis.Client) ProfileService {
+	return &ProfileServiceImpl{
+		DB:                db,
+		ProfileRepository: profileRepository,
+		UserRepository:    userRepository,
+		FriendRepository:  friendRepository,
+		RedisClient:       redisClient,
+	}
+}
+
+// Find mengembalikan profil user sesuai apa yang boleh dilihat viewer-nya (viewerID 0 = belum login)
+func (s *ProfileServiceImpl) Find(ctx context.Context, userID, viewerID int) (*response.ProfileResponse, error) {
+	// step 1: pastikan user-nya ada
+	if _, err := s.UserRepository.FindByID(ctx, s.DB, userID); err != nil {
+		return nil, err
+	}
+
+	// step 2: ambil profilnya, user yang belum pernah mengisi profil pakai default
+	profile, err := s.ProfileRepository.FindByUserID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = entity.DefaultUserProfile(userID)
+	}
+
+	// step 3: cek hubungan viewer dengan pemilik profil
+	isOwner := viewerID != 0 && viewerID == userID
+	isFriend := false
+	if !isOwner && viewerID != 0 {
+		isFriend, err = s.FriendRepository.IsFriendAlreadyAccepted(ctx, s.DB, viewerID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	profileResponse := toProfileResponse(profile, isOwner, isFriend)
+
+	// step 4: jumlah mutual friend, 0 juga kalau daftar teman pemilik profil tidak boleh dilihat viewer
+	if !isOwner && viewerID != 0 {
+		mutualCounts, err := s.FriendRepository.CountMutualFriends(ctx, s.DB, viewerID, []int{userID})
+		if err != nil {
+			return nil, err
+		}
+		mutualFriends := mutualCounts[userID]
+		profileResponse.MutualFriends = &mutualFriends
+	}
+
+	return profileResponse, nil
+}
+
+// Update mengubah sebagian field profil, field yang tidak dikirim tetap seperti sebelumnya
+func (s *ProfileServiceImpl) Update(ctx context.Context, userID int, request request.UpdateProfileRequest) (*response.ProfileResponse, error) {
+	// step 1: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 3: ambil profil yang sekarang (dikunci sampai commit)
+	profile, err := s.ProfileRepository.FindForUpdate(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 4: terapkan perubahan lalu validasi hasil akhirnya
+	err = applyProfileUpdate(profile, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 5: simpan dan commit
+	updated, err := s.ProfileRepository.Update(ctx, tx, profile)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	// profil ikut menentukan rekomendasi teman, jadi cache rekomendasi user ini dibuang
+	_ = s.RedisClient.Del(ctx, friendRecommendationCacheKey(userID)).Err()
+
+	return toProfileResponse(updated, true, false), nil
+}
+
+func applyProfileUpdate(profile *entity.UserProfile, request request.UpdateProfileRequest) error {
+	var err error
+	if request.Bio != nil {
+		profile.Bio = strings.TrimSpace(*request.Bio)
+	}
+	if request.Pronouns != nil {
+		profile.Pronouns = strings.TrimSpace(*request.Pronouns)
+	}
+	if request.Location != nil {
+		profile.Location = strings.Join(strings.Fields(*request.Location), " ")
+	}
+	if err := utils.ValidateProfileText(profile.Bio, profile.Pronouns, profile.Location); err != nil {
+		return err
+	}
+
+	if request.Interests != nil {
+		if profile.Interests, err = utils.NormalizeInterests(*request.Interests); err != nil {
+			return err
+		}
+	}
+	if request.SupportSeeking != nil {
+		if profile.SupportSeeking, err = utils.NormalizeSupportTypes(*request.SupportSeeking); err != nil {
+			return err
+		}
+	}
+	if request.SupportOffering != nil {
+		if profile.SupportOffering, err = utils.NormalizeSupportTypes(*request.SupportOffering); err != nil {
+			return err
+		}
+	}
+	if request.Language != nil {
+		if profile.Language, err = utils.NormalizeLanguage(*request.Language); err != nil {
+			return err
+		}
+	}
+
+	if visibility := request.Visibility; visibility != nil {
+		fields := []struct {
+			value  *string
+			target *string
+		}{
+			{visibility.Bio, &profile.BioVisibility},
+			{visibility.Pronouns, &profile.PronounsVisibility},
+			{visibility.Location, &profile.LocationVisibility},
+			{visibility.Interests, &profile.InterestsVisibility},
+			{visibility.SupportSeeking, &profile.SupportSeekingVisibility},
+			{visibility.SupportOffering, &profile.SupportOfferingVisibility},
+			{visibility.Language, &profile.LanguageVisibility},
+			{visibility.Friends, &profile.FriendsVisibility},
+		}
+		for _, field := range fields {
+			if field.value == nil {
+				continue
+			}
+			if err := utils.ValidatePostVisibility(*field.value); err != nil {
+				return err
+			}
+			*field.target = *field.value
+		}
+	}
+	return nil
+}
+
+// toProfileResponse cuma mengisi field yang boleh dilihat: pemilik lihat semua, teman lihat public + friends,
+// selain itu cuma public
+func toProfileResponse(profile *entity.UserProfile, isOwner, isFriend bool) *response.ProfileResponse {
+	visible := func(visibility string) bool {
+		return isOwner || visibility == entity.VisibilityPublic || (isFriend && visibility == entity.VisibilityFriends)
+	}
+	list := func(values []string) *[]string {
+		values = nonNilStrings(values)
+		return &values
+	}
+
+	profileResponse := &response.ProfileResponse{UserID: profile.UserID}
+	if visible(profile.BioVisibility) {
+		profileResponse.Bio = &profile.Bio
+	}
+	if visible(profile.PronounsVisibility) {
+		profileResponse.Pronouns = &profile.Pronouns
+	}
+	if visible(profile.LocationVisibility) {
+		profileResponse.Location = &profile.Location
+	}
+	if visible(profile.InterestsVisibility) {
+		profileResponse.Interests = list(profile.Interests)
+	}
+	if visible(profile.SupportSeekingVisibility) {
+		profileResponse.SupportSeeking = list(profile.SupportSeeking)
+	}
+	if visible(profile.SupportOfferingVisibility) {
+		profileResponse.SupportOffering = list(profile.SupportOffering)
+	}
+	if visible(profile.LanguageVisibility) {
+		profileResponse.Language = &profile.Language
+	}
+
+	if isOwner {
+		profileResponse.Visibility = &response.ProfileVisibilityResponse{
+			Bio:             profile.BioVisibility,
+			Pronouns:        profile.PronounsVisibility,
+			Location:        profile.LocationVisibility,
+			Interests:       profile.InterestsVisibility,
+			SupportSeeking:  profile.SupportSeekingVisibility,
+			SupportOffering: profile.SupportOfferingVisibility,
+			Language:        profile.LanguageVisibility,
+			Friends:         profile.FriendsVisibility,
+		}
+		if !profile.UpdatedAt.IsZero() {
+			profileResponse.UpdatedAt = &profile.UpdatedAt
+		}
+	}
+	return profileResponse
+}
+
+// nonNilStrings supaya array kosong dari database tetap dikirim sebagai [] bukan null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
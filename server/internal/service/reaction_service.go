@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type ReactionService interface {
+	React(ctx context.Context, postID, userID int, reactionType string) (*response.PostReactionResponse, error)
+	Unreact(ctx context.Context, postID, userID int, reactionType string) (*response.PostReactionResponse, error)
+	GetReactions(ctx context.Context, postID, userID int) (*response.PostReactionResponse, error)
+	GetReactors(ctx context.Context, postID, viewerID int, reactionType, cursor string, limit int) (*pagination.Page[*response.ReactorResponse], error)
+}
+
+type ReactionServiceImpl struct {
+	DB                 *sql.DB
+	ReactionRepository repository.ReactionRepository
+	UserRepository     repository.UserRepository
+	PostService        PostService
+	RedisClient        *redis.Client
+}
+
+func NewReactionService(db *sql.DB, reactionRepository repository.ReactionRepository, userRepository repository.UserRepository, postService PostService, redisClient *redis.Client) ReactionService {
+	return &ReactionServiceImpl{
+		DB:                 db,
+		ReactionRepository: reactionRepository,
+		UserRepository:     userRepository,
+		PostService:        postService,
+		RedisClient:        redisClient,
+	}
+}
+
+// React dan Unreact sifatnya idempotent: dipanggil berkali-kali hasilnya tetap sama
+func (s *ReactionServiceImpl) React(ctx context.Context, postID, userID int, reactionType string) (*response.PostReactionResponse, error) {
+	return s.toggle(ctx, postID, userID, reactionType, true)
+}
+
+func (s *ReactionServiceImpl) Unreact(ctx context.Context, postID, userID int, reactionType string) (*response.PostReactionResponse, error) {
+	return s.toggle(ctx, postID, userID, reactionType, false)
+}
+
+func (s *ReactionServiceImpl) toggle(ctx context.Context, postID, userID int, reactionType string, on bool) (*response.PostReactionResponse, error) {
+	// step 1: validasi jenis reaksinya
+	if err := utils.ValidateReactionType(reactionType); err != nil {
+		return nil, err
+	}
+
+	// step 2: pastikan post-nya ada dan boleh dilihat sama user ini (ga bisa kasih reaksi ke post yang ga kelihatan)
+	if _, err := s.PostService.Find(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	// step 3: simpan perubahan reaksi + counter-nya dalam satu transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var changed bool
+	if on {
+		changed, err = s.ReactionRepository.Add(ctx, tx, &entity.PostReaction{PostID: postID, UserID: userID, ReactionType: reactionType})
+	} else {
+		changed, err = s.ReactionRepository.Remove(ctx, tx, postID, userID, reactionType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// step 4: kalau jumlah reaksinya berubah, cache post dan list postingan harus di-invalidate
+	if changed {
+		s.RedisClient.Del(ctx, postCacheKey(postID))
+		_ = s.RedisClient.Incr(ctx, postListGenerationKey).Err()
+	}
+
+	return s.reactionSummary(ctx, postID, userID)
+}
+
+func (s *ReactionServiceImpl) GetReactions(ctx context.Context, postID, userID int) (*response.PostReactionResponse, error) {
+	if _, err := s.PostService.Find(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+	return s.reactionSummary(ctx, postID, userID)
+}
+
+func (s *ReactionServiceImpl) GetReactors(ctx context.Context, postID, viewerID int, reactionType, cursor string, limit int) (*pagination.Page[*response.ReactorResponse], error) {
+	// step 1: filter jenis reaksi opsional, tapi kalau diisi harus valid
+	if reactionType != "" {
+		if err := utils.ValidateReactionType(reactionType); err != nil {
+			return nil, err
+		}
+	}
+
+	// step 2: daftar reaktor ikut aturan visibility post-nya
+	if _, err := s.PostService.Find(ctx, postID, viewerID); err != nil {
+		return nil, err
+	}
+
+	// step 3: ambil reaksinya lalu load user-nya sekaligus (menghindari N+1 query)
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	afterCreatedAt, afterUserID := after.After()
+	reactions, err := s.ReactionRepository.FindReactors(ctx, s.DB, postID, viewerID, reactionType, afterCreatedAt, afterUserID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDMap := make(map[int]bool)
+	for _, reaction := range reactions {
+		userIDMap[reaction.UserID] = true
+	}
+	var userIDs []int
+	for id := range userIDMap {
+		userIDs = append(userIDs, id)
+	}
+	users, err := s.UserRepository.FindByIDs(ctx, s.DB, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	userMap := make(map[int]*entity.User)
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	// step 4: susun response
+	reactors := []*response.ReactorResponse{}
+	for _, reaction := range reactions {
+		user, ok := userMap[reaction.UserID]
+		if !ok {
+			continue
+		}
+		reactors = append(reactors, &response.ReactorResponse{
+			User: response.UserSummary{
+				UserID:   user.ID,
+				Username: user.Username,
+				FullName: user.Fullname,
+			},
+			ReactionType: reaction.ReactionType,
+			CreatedAt:    reaction.CreatedAt,
+		})
+	}
+	return pagination.NewPage(reactors, limit, func(reactor *response.ReactorResponse) string {
+		return pagination.EncodeCursor(reactor.CreatedAt, reactor.User.UserID)
+	}), nil
+}
+
+func (s *ReactionServiceImpl) reactionSummary(ctx context.Context, postID, userID int) (*response.PostReactionResponse, error) {
+	counts, err := s.ReactionRepository.GetCounts(ctx, s.DB, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	myReactions := []string{}
+	if userID != 0 {
+		myReactions, err = s.ReactionRepository.GetUserReactions(ctx, s.DB, postID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &response.PostReactionResponse{
+		PostID:         postID,
+		ReactionCounts: counts,
+		MyReactions:    myReactions,
+	}, nil
+}
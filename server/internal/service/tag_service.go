@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
+	"mood-bridge-v2/server/internal/repository"
+	"mood-bridge-v2/server/internal/utils"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type TagService interface {
+	Find(ctx context.Context, name string, viewerID int) (*response.TagResponse, error)
+	GetPosts(ctx context.Context, name string, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error)
+	Follow(ctx context.Context, name string, userID int) (*response.TagResponse, error)
+	Unfollow(ctx context.Context, name string, userID int) (*response.TagResponse, error)
+	GetFollowedTags(ctx context.Context, userID int) ([]string, error)
+	GetTrending(ctx context.Context, limit int) ([]*response.TrendingTagResponse, error)
+	RefreshTrending(ctx context.Context) error
+	RunTrendingJob(ctx context.Context, interval time.Duration)
+}
+
+type TagServiceImpl struct {
+	DB            *sql.DB
+	TagRepository repository.TagRepository
+	PostService   PostService
+	RedisClient   *redis.Client
+}
+
+func NewTagService(db *sql.DB, tagRepository repository.TagRepository, postService PostService, redisClient *redis.Client) TagService {
+	return &TagServiceImpl{
+		DB:            db,
+		TagRepository: tagRepository,
+		PostService:   postService,
+		RedisClient:   redisClient,
+	}
+}
+
+const (
+	// trending dihitung dari postingan public 24 jam terakhir
+	trendingWindow  = 24 * time.Hour
+	maxTrendingTags = 50
+)
+
+func (s *TagServiceImpl) Find(ctx context.Context, name string, viewerID int) (*response.TagResponse, error) {
+	// step 1: rapikan nama tag-nya (#Anxiety -> anxiety)
+	tag, err := utils.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: hitung postingan public-nya dan ambil resource yang dikurasi
+	postCount, err := s.TagRepository.CountPublicPosts(ctx, s.DB, tag)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.TagRepository.GetResources(ctx, s.DB, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	tagResponse := &response.TagResponse{
+		Name:      tag,
+		PostCount: postCount,
+		Resources: []*response.TagResourceResponse{},
+	}
+	for _, resource := range resources {
+		tagResponse.Resources = append(tagResponse.Resources, &response.TagResourceResponse{
+			Title:       resource.Title,
+			URL:         resource.URL,
+			Description: resource.Description,
+		})
+	}
+
+	// step 3: kalau sudah login, kasih tahu juga apakah tag ini sudah di-follow
+	if viewerID != 0 {
+		tagResponse.IsFollowing, err = s.TagRepository.IsFollowing(ctx, s.DB, viewerID, tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tagResponse, nil
+}
+
+func (s *TagServiceImpl) GetPosts(ctx context.Context, name string, viewerID int, cursor string, limit int) (*pagination.Page[*response.CreatePostResponse], error) {
+	tag, err := utils.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.PostService.FindByTag(ctx, tag, viewerID, cursor, limit)
+}
+
+// Follow dan Unfollow sifatnya idempotent, sama seperti reaksi
+func (s *TagServiceImpl) Follow(ctx context.Context, name string, userID int) (*response.TagResponse, error) {
+	return s.toggleFollow(ctx, name, userID, true)
+}
+
+func (s *TagServiceImpl) Unfollow(ctx context.Context, name string, userID int) (*response.TagResponse, error) {
+	return s.toggleFollow(ctx, name, userID, false)
+}
+
+func (s *TagServiceImpl) toggleFollow(ctx context.Context, name string, userID int, on bool) (*response.TagResponse, error) {
+	tag, err := utils.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed bool
+	if on {
+		changed, err = s.TagRepository.Follow(ctx, s.DB, userID, tag)
+	} else {
+		changed, err = s.TagRepository.Unfollow(ctx, s.DB, userID, tag)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// tag yang di-follow ikut menentukan isi feed, jadi cache list postingan harus di-invalidate
+	if changed {
+		_ = s.RedisClient.Incr(ctx, postListGenerationKey).Err()
+	}
+
+	return s.Find(ctx, tag, userID)
+}
+
+func (s *TagServiceImpl) GetFollowedTags(ctx context.Context, userID int) ([]string, error) {
+	return s.TagRepository.GetFollowedTags(ctx, s.DB, userID)
+}
+
+func (s *TagServiceImpl) GetTrending(ctx context.Context, limit int) ([]*response.TrendingTagResponse, error) {
+	trending, err := s.TagRepository.GetTrending(ctx, s.DB, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	trendingResponses := []*response.TrendingTagResponse{}
+	for _, tag := range trending {
+		trendingResponses = append(trendingResponses, &response.TrendingTagResponse{
+			Name:       tag.Tag,
+			PostCount:  tag.PostCount,
+			ComputedAt: tag.ComputedAt,
+		})
+	}
+	return trendingResponses, nil
+}
+
+func (s *TagServiceImpl) RefreshTrending(ctx context.Context) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = s.TagRepository.RefreshTrending(ctx, tx, time.Now().Add(-trendingWindow), maxTrendingTags)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RunTrendingJob ngitung ulang trending tag setiap interval sampai ctx dibatalkan (dijalankan sebagai goroutine dari main)
+func (s *TagServiceImpl) RunTrendingJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		jobCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := s.RefreshTrending(jobCtx); err != nil {
+			log.Printf("failed to refresh trending tags: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
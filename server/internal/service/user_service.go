@@ -3,11 +3,16 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"mood-bridge-v2/server/infrastructure/storage"
 	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/media"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/model/response"
+	"mood-bridge-v2/server/internal/pagination"
 	"mood-bridge-v2/server/internal/repository"
 	"mood-bridge-v2/server/internal/utils"
 	"strings"
@@ -15,25 +20,62 @@ import (
 	"time"
 )
 
+var ErrAccountLocked = errors.New("account temporarily locked")
+
+// AccountLockedError dikembalikan Login selama akun terkunci karena terlalu banyak percobaan password yang salah
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account temporarily locked, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
 type UserService interface {
 	Create(ctx context.Context, request request.CreateUserRequest) (*response.CreateUserResponse, error)
 	Find(ctx context.Context, username string) (*response.CreateUserResponse, error)
 	FindByEmail(ctx context.Context, email string) (*response.CreateUserResponse, error)
 	FindByID(ctx context.Context, id int) (*response.CreateUserResponse, error)
-	FindAll(ctx context.Context) ([]*response.CreateUserResponse, error)
-	Login(ctx context.Context, request request.ValidateUserRequest) (*string, error)
-	Update(ctx context.Context, id int, request request.UpdateUserRequest) (*response.CreateUserResponse, error)
+	FindAll(ctx context.Context, cursor string, limit int) (*pagination.Page[*response.CreateUserResponse], error)
+	Login(ctx context.Context, request request.ValidateUserRequest) (*response.LoginResponse, error)
+	LoginMFA(ctx context.Context, request request.LoginMFARequest) (*string, error)
+	Update(ctx context.Context, actorID, id int, request request.UpdateUserRequest) (*response.CreateUserResponse, error)
+	UploadAvatar(ctx context.Context, id int, data []byte) (*response.CreateUserResponse, error)
+	DeleteAvatar(ctx context.Context, id int) (*response.CreateUserResponse, error)
 }
 
+var (
+	// ErrUserUpdateForbidden: data akun (email, password) cuma boleh diubah pemiliknya
+	ErrUserUpdateForbidden = errors.New("you can only update your own account")
+	// ErrCurrentPasswordMismatch: ganti password harus menyertakan password yang sekarang
+	ErrCurrentPasswordMismatch = errors.New("current password is incorrect")
+)
+
 type UserServiceImpl struct {
 	DB             *sql.DB
 	UserRepository repository.UserRepository
+	RoleRepository repository.RoleRepository
+	Storage        storage.Storage
+	audit          AuditLogger
+	lockout        *ratelimit.Lockout
+	verifier       EmailVerifier
+	mfa            MFAChallenger
 }
 
-func NewUserService(db *sql.DB, userRepository repository.UserRepository) UserService {
+func NewUserService(db *sql.DB, userRepository repository.UserRepository, roleRepository repository.RoleRepository, mediaStorage storage.Storage, audit AuditLogger, lockout *ratelimit.Lockout, verifier EmailVerifier, mfa MFAChallenger) UserService {
 	return &UserServiceImpl{
 		DB:             db,
 		UserRepository: userRepository,
+		RoleRepository: roleRepository,
+		Storage:        mediaStorage,
+		audit:          audit,
+		lockout:        lockout,
+		verifier:       verifier,
+		mfa:            mfa,
 	}
 }
 
@@ -53,12 +95,11 @@ func (s *UserServiceImpl) Create(ctx context.Context, request request.CreateUser
 
 	// step 3: convert request ke model User
 	user := entity.User{
-		Username:   strings.TrimSpace(strings.ToLower(request.Username)),
-		Fullname:   request.Fullname,
-		Email:      strings.TrimSpace(strings.ToLower(request.Email)),
-		Password:   request.Password,
-		ProfileUrl: sql.NullString{String: "https://upload.wikimedia.org/wikipedia/commons/a/ac/Default_pfp.jpg", Valid: true}, // Default URL for new users
-		CreatedAt:  time.Now(),
+		Username:  strings.TrimSpace(strings.ToLower(request.Username)),
+		Fullname:  request.Fullname,
+		Email:     strings.TrimSpace(strings.ToLower(request.Email)),
+		Password:  request.Password,
+		CreatedAt: time.Now(),
 	}
 
 	// Appendix: validate request, existingUsername, and existingEmail concurrently
@@ -125,6 +166,16 @@ func (s *UserServiceImpl) Create(ctx context.Context, request request.CreateUser
 	if err != nil {
 		return nil, err
 	}
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &createdUser.ID,
+		Action:     entity.AuditUserRegistered,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &createdUser.ID,
+	})
+	// email verifikasi gagal dikirim tidak menggagalkan registrasi, user bisa minta kirim ulang
+	if err := s.verifier.SendVerification(ctx, createdUser.ID); err != nil {
+		log.Printf("Error sending verification email to user %d: %v", createdUser.ID, err)
+	}
 
 	// step 6: Find the created user
 	result, err := s.Find(ctx, createdUser.Username)
@@ -145,16 +196,10 @@ func (s *UserServiceImpl) Find(ctx context.Context, username string) (*response.
 	}
 
 	// step 2: convert result ke response
-	searchedUser := response.CreateUserResponse{
-		UserID:    user.ID,
-		Username:  user.Username,
-		Fullname:  user.Fullname,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}
+	searchedUser := s.toUserResponse(user)
 
 	// step 3: return response
-	return &searchedUser, nil
+	return searchedUser, nil
 }
 
 func (s *UserServiceImpl) FindByEmail(ctx context.Context, email string) (*response.CreateUserResponse, error) {
@@ -165,16 +210,10 @@ func (s *UserServiceImpl) FindByEmail(ctx context.Context, email string) (*respo
 	}
 
 	// step 2: convert result ke response
-	searchedUser := response.CreateUserResponse{
-		UserID:    user.ID,
-		Username:  user.Username,
-		Fullname:  user.Fullname,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}
+	searchedUser := s.toUserResponse(user)
 
 	// step 3: return response
-	return &searchedUser, nil
+	return searchedUser, nil
 }
 
 func (s *UserServiceImpl) FindByID(ctx context.Context, id int) (*response.CreateUserResponse, error) {
@@ -183,65 +222,151 @@ func (s *UserServiceImpl) FindByID(ctx context.Context, id int) (*response.Creat
 		return nil, err
 	}
 
-	searchedUser := response.CreateUserResponse{
-		UserID:    user.ID,
-		Username:  user.Username,
-		Fullname:  user.Fullname,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}
+	searchedUser := s.toUserResponse(user)
 
-	return &searchedUser, nil
+	return searchedUser, nil
 }
 
-func (s *UserServiceImpl) FindAll(ctx context.Context) ([]*response.CreateUserResponse, error) {
-	// step 1: call repository to find all users
-	users, err := s.UserRepository.FindAll(ctx, s.DB)
+func (s *UserServiceImpl) FindAll(ctx context.Context, cursor string, limit int) (*pagination.Page[*response.CreateUserResponse], error) {
+	// step 1: validasi cursor-nya
+	after, err := pagination.DecodeCursor(cursor)
 	if err != nil {
 		return nil, err
 	}
+	afterCreatedAt, afterID := after.After()
 
-	// step 2: convert result ke response
-	var userResponses []*response.CreateUserResponse
+	// step 2: call repository to find users (ambil satu lebih banyak buat ngecek masih ada halaman berikutnya)
+	users, err := s.UserRepository.FindAll(ctx, s.DB, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: convert result ke response
+	userResponses := []*response.CreateUserResponse{}
 	for _, user := range users {
-		userResponse := &response.CreateUserResponse{
-			UserID:    user.ID,
-			Username:  user.Username,
-			Fullname:  user.Fullname,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
-		}
-		userResponses = append(userResponses, userResponse)
+		userResponses = append(userResponses, s.toUserResponse(user))
 	}
 
-	// step 3: return response
-	return userResponses, nil
+	// step 4: return response
+	return pagination.NewPage(userResponses, limit, func(user *response.CreateUserResponse) string {
+		return pagination.EncodeCursor(user.CreatedAt, user.UserID)
+	}), nil
 }
 
-func (s *UserServiceImpl) Login(ctx context.Context, request request.ValidateUserRequest) (*string, error) {
+// Login mengembalikan JWT, atau token "mfa pending" kalau user mengaktifkan 2FA. Token itu ditukar dengan JWT lewat LoginMFA.
+func (s *UserServiceImpl) Login(ctx context.Context, request request.ValidateUserRequest) (*response.LoginResponse, error) {
 	// step 1: validate request
 	err := utils.ValidateUserLoginInput(request.Username, request.Password)
 	if err != nil {
 		return nil, err
 	}
 
+	// Appendix: akun yang terkunci karena terlalu banyak percobaan gagal langsung ditolak, bahkan kalau password-nya benar
+	lockoutID := strings.TrimSpace(strings.ToLower(request.Username))
+	if lockedFor, err := s.lockout.LockedFor(ctx, lockoutID); err != nil {
+		log.Printf("Error checking login lockout for %s: %v", lockoutID, err)
+	} else if lockedFor > 0 {
+		return nil, &AccountLockedError{RetryAfter: lockedFor}
+	}
+
 	// step 2: call repository to find user by Username
 	user, err := s.UserRepository.Find(ctx, s.DB, request.Username)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.loginFailed(ctx, lockoutID, nil, "unknown username")
+		}
 		return nil, err
 	}
 
 	// step 3: check if user is found
 	if user == nil {
+		s.loginFailed(ctx, lockoutID, nil, "unknown username")
 		return nil, fmt.Errorf("user %s not found", request.Username)
 	}
 
 	// step 4: validate password
 	if user.Password != request.Password {
+		s.loginFailed(ctx, lockoutID, &user.ID, "invalid password")
 		return nil, fmt.Errorf("invalid password")
 	}
 
-	// step 5: get user response
+	// step 5: user yang sedang di-suspend atau di-ban tidak bisa login
+	restriction, err := s.UserRepository.FindRestriction(ctx, s.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := restrictionError(restriction); err != nil {
+		s.audit.Log(ctx, AuditEntry{
+			ActorID:    &user.ID,
+			Action:     entity.AuditLoginRejected,
+			TargetType: entity.AuditTargetUser,
+			TargetID:   &user.ID,
+			Metadata:   map[string]interface{}{"reason": err.Error()},
+		})
+		return nil, err
+	}
+
+	// step 6: user yang mengaktifkan 2FA masih harus memasukkan kodenya
+	enabled, err := s.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		challenge, err := s.mfa.StartChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &response.LoginResponse{MFAChallenge: challenge}, nil
+	}
+
+	// step 7: generate token
+	token, err := s.issueToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &response.LoginResponse{Token: token}, nil
+}
+
+// LoginMFA langkah kedua login untuk user yang mengaktifkan 2FA. Kode yang salah juga dihitung ke lockout login.
+func (s *UserServiceImpl) LoginMFA(ctx context.Context, request request.LoginMFARequest) (*string, error) {
+	// step 1: tukar token "mfa pending" dan kodenya dengan userID
+	userID, err := s.mfa.CompleteChallenge(ctx, request.MFAToken, request.Code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		if user, findErr := s.UserRepository.FindByID(ctx, s.DB, userID); findErr == nil && user != nil {
+			s.loginFailed(ctx, user.Username, &user.ID, "invalid two-factor code")
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: cek ulang lockout dan status akun, bisa saja berubah selama token-nya menunggu
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if lockedFor, err := s.lockout.LockedFor(ctx, user.Username); err != nil {
+		log.Printf("Error checking login lockout for %s: %v", user.Username, err)
+	} else if lockedFor > 0 {
+		return nil, &AccountLockedError{RetryAfter: lockedFor}
+	}
+	restriction, err := s.UserRepository.FindRestriction(ctx, s.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := restrictionError(restriction); err != nil {
+		return nil, err
+	}
+
+	// step 3: generate token
+	return s.issueToken(ctx, user)
+}
+
+// issueToken membuat JWT berisi role dan permission user, lalu mencatat login-nya.
+// Lockout baru di-reset di sini, jadi password yang benar saja tidak menghapus hitungan kode 2FA yang salah.
+func (s *UserServiceImpl) issueToken(ctx context.Context, user *entity.User) (*string, error) {
+	// step 1: get user response
 	userResponse := &response.CreateUserResponse{
 		UserID:    user.ID,
 		Username:  user.Username,
@@ -250,45 +375,102 @@ func (s *UserServiceImpl) Login(ctx context.Context, request request.ValidateUse
 		CreatedAt: user.CreatedAt,
 	}
 
-	// step 6: generate token
-	token, err := GenerateToken(userResponse)
+	// step 2: ambil role dan permission untuk dimasukkan ke token
+	roles, err := s.RoleRepository.FindRolesByUserID(ctx, s.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.RoleRepository.FindPermissionsByUserID(ctx, s.DB, user.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	// step 3: generate token
+	token, err := GenerateToken(userResponse, roles, permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lockout.Reset(ctx, user.Username); err != nil {
+		log.Printf("Error resetting login lockout for %s: %v", user.Username, err)
+	}
+
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &user.ID,
+		Action:     entity.AuditLoginSucceeded,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
 	return token, nil
 }
 
-func (s *UserServiceImpl) Update(ctx context.Context, id int, request request.UpdateUserRequest) (*response.CreateUserResponse, error) {
-	// step 1: begin transaction
-	tx, err := s.DB.Begin()
+// loginFailed mencatat username yang dicoba ke log audit dan ke lockout. Username yang tidak terdaftar juga dihitung,
+// supaya respons-nya tidak membocorkan username mana yang ada.
+func (s *UserServiceImpl) loginFailed(ctx context.Context, username string, userID *int, reason string) {
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditLoginFailed,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]interface{}{"username": username, "reason": reason},
+	})
+
+	lockedFor, err := s.lockout.Fail(ctx, username)
 	if err != nil {
-		return nil, err
+		log.Printf("Error recording failed login for %s: %v", username, err)
+		return
 	}
+	if lockedFor > 0 {
+		s.audit.Log(ctx, AuditEntry{
+			Action:     entity.AuditLoginLocked,
+			TargetType: entity.AuditTargetUser,
+			TargetID:   userID,
+			Metadata:   map[string]interface{}{"username": username, "locked_seconds": int(lockedFor.Seconds())},
+		})
+	}
+}
 
-	// step 2: rollback
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
+func (s *UserServiceImpl) Update(ctx context.Context, actorID, id int, request request.UpdateUserRequest) (*response.CreateUserResponse, error) {
+	// step 1: user cuma boleh mengubah akunnya sendiri
+	if actorID != id {
+		return nil, ErrUserUpdateForbidden
+	}
 
-	// step 3: convert request ke model User
+	// step 2: convert request ke model User
 	user := entity.User{
-		Username:   strings.TrimSpace(strings.ToLower(request.Username)),
-		Fullname:   request.Fullname,
-		Email:      strings.TrimSpace(strings.ToLower(request.Email)),
-		Password:   request.Password,
-		ProfileUrl: sql.NullString{String: request.Profile, Valid: true},
-		CreatedAt:  time.Now(),
+		Username:  strings.TrimSpace(strings.ToLower(request.Username)),
+		Fullname:  request.Fullname,
+		Email:     strings.TrimSpace(strings.ToLower(request.Email)),
+		Password:  request.Password,
+		CreatedAt: time.Now(),
 	}
 
-	// step 4: validate request
+	// step 3: validate request
 	if err := utils.ValidateUserInput(&user); err != nil {
 		return nil, err
 	}
 
-	// step 5: call repository to update user
+	// step 4: ganti password harus menyertakan password yang sekarang (data lama juga dipakai untuk log audit)
+	existingUser, err := s.UserRepository.FindByID(ctx, s.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Password != existingUser.Password && request.CurrentPassword != existingUser.Password {
+		return nil, ErrCurrentPasswordMismatch
+	}
+
+	// step 5: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// step 5.1: rollback
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// step 5.2: call repository to update user
 	updatedUser, err := s.UserRepository.Update(ctx, tx, id, &user)
 	if err != nil {
 		return nil, err
@@ -299,6 +481,20 @@ func (s *UserServiceImpl) Update(ctx context.Context, id int, request request.Up
 	if err != nil {
 		return nil, err
 	}
+	if existingUser.Password != updatedUser.Password {
+		s.audit.Log(ctx, AuditEntry{
+			ActorID:    &actorID,
+			Action:     entity.AuditPasswordChanged,
+			TargetType: entity.AuditTargetUser,
+			TargetID:   &updatedUser.ID,
+		})
+	}
+	// email baru harus diverifikasi ulang
+	if existingUser.Email != updatedUser.Email {
+		if err := s.verifier.SendVerification(ctx, updatedUser.ID); err != nil {
+			log.Printf("Error sending verification email to user %d: %v", updatedUser.ID, err)
+		}
+	}
 
 	// step 7: Find the updated user
 	result, err := s.FindByID(ctx, updatedUser.ID)
@@ -309,3 +505,85 @@ func (s *UserServiceImpl) Update(ctx context.Context, id int, request request.Up
 	// step 8: return response
 	return result, nil
 }
+
+func (s *UserServiceImpl) UploadAvatar(ctx context.Context, id int, data []byte) (*response.CreateUserResponse, error) {
+	// step 1: validasi dan olah gambarnya (EXIF dibuang, dipotong persegi, dibuat beberapa ukuran)
+	sizes, err := media.ProcessAvatar(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: simpan setiap ukuran dengan prefix acak, jadi URL-nya berubah setiap kali avatar diganti
+	// dan file-nya aman di-cache selamanya
+	name, err := randomMediaName()
+	if err != nil {
+		return nil, err
+	}
+	avatarKey := fmt.Sprintf("avatars/%d/%s", id, name)
+	var stored []string
+	for _, size := range media.AvatarSizes {
+		key := avatarObjectKey(avatarKey, size)
+		if err := s.Storage.Put(ctx, key, sizes[size], "image/jpeg"); err != nil {
+			deleteMediaObjects(ctx, s.Storage, stored...)
+			return nil, err
+		}
+		stored = append(stored, key)
+	}
+
+	// step 3: pasang ke user, kalau gagal file yang baru di-upload dibuang lagi
+	previous, err := s.UserRepository.UpdateAvatar(ctx, s.DB, id, sql.NullString{String: avatarKey, Valid: true})
+	if err != nil {
+		deleteMediaObjects(ctx, s.Storage, stored...)
+		return nil, err
+	}
+
+	// step 4: hapus file avatar lama
+	if previous.Valid {
+		deleteMediaObjects(ctx, s.Storage, avatarObjectKeys(previous.String)...)
+	}
+
+	return s.FindByID(ctx, id)
+}
+
+// DeleteAvatar mengembalikan user ke avatar default
+func (s *UserServiceImpl) DeleteAvatar(ctx context.Context, id int) (*response.CreateUserResponse, error) {
+	previous, err := s.UserRepository.UpdateAvatar(ctx, s.DB, id, sql.NullString{})
+	if err != nil {
+		return nil, err
+	}
+	if previous.Valid {
+		deleteMediaObjects(ctx, s.Storage, avatarObjectKeys(previous.String)...)
+	}
+	return s.FindByID(ctx, id)
+}
+
+func (s *UserServiceImpl) toUserResponse(user *entity.User) *response.CreateUserResponse {
+	userResponse := &response.CreateUserResponse{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Fullname:  user.Fullname,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+	if user.AvatarKey.Valid {
+		userResponse.Avatar = &response.AvatarResponse{
+			Small:  s.Storage.URL(avatarObjectKey(user.AvatarKey.String, media.AvatarSmall)),
+			Medium: s.Storage.URL(avatarObjectKey(user.AvatarKey.String, media.AvatarMedium)),
+			Large:  s.Storage.URL(avatarObjectKey(user.AvatarKey.String, media.AvatarLarge)),
+		}
+	}
+	return userResponse
+}
+
+// avatarObjectKey adalah key file untuk satu ukuran avatar, misalnya avatars/12/ab34..._256.jpg
+func avatarObjectKey(avatarKey string, size int) string {
+	return fmt.Sprintf("%s_%d.jpg", avatarKey, size)
+}
+
+func avatarObjectKeys(avatarKey string) []string {
+	keys := make([]string, 0, len(media.AvatarSizes))
+	for _, size := range media.AvatarSizes {
+		keys = append(keys, avatarObjectKey(avatarKey, size))
+	}
+	return keys
+}
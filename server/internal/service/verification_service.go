@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mood-bridge-v2/server/infrastructure/mail"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/repository"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEmailNotVerified     = errors.New("email is not verified")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrInvalidEmailToken    = errors.New("invalid or expired token")
+)
+
+const (
+	verifyEmailTokenTTL   = 24 * time.Hour
+	passwordResetTokenTTL = time.Hour
+	// Token yang sudah expired selama ini dihapus oleh job cleanup
+	emailTokenRetention = 7 * 24 * time.Hour
+	// Pengiriman email jalan di background, jadi timeout-nya tidak ikut timeout request
+	mailSendTimeout = 30 * time.Second
+)
+
+// EmailVerifier dipakai UserService (kirim email verifikasi waktu register/ganti email) dan ChatService (chat khusus akun terverifikasi)
+type EmailVerifier interface {
+	SendVerification(ctx context.Context, userID int) error
+	IsVerified(ctx context.Context, userID int) (bool, error)
+}
+
+type VerificationService interface {
+	EmailVerifier
+	ConfirmEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	RunCleanupJob(ctx context.Context, interval time.Duration)
+}
+
+type VerificationServiceImpl struct {
+	DB              *sql.DB
+	UserRepository  repository.UserRepository
+	TokenRepository repository.EmailTokenRepository
+	Mailer          mail.Mailer
+	AppURL          string // URL client, link di email mengarah ke halaman client yang lalu memanggil endpoint confirm
+	audit           AuditLogger
+	lockout         *ratelimit.Lockout
+}
+
+func NewVerificationService(db *sql.DB, userRepository repository.UserRepository, tokenRepository repository.EmailTokenRepository, mailer mail.Mailer, audit AuditLogger, lockout *ratelimit.Lockout) VerificationService {
+	appURL := strings.TrimRight(os.Getenv("APP_URL"), "/")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+	return &VerificationServiceImpl{
+		DB:              db,
+		UserRepository:  userRepository,
+		TokenRepository: tokenRepository,
+		Mailer:          mailer,
+		AppURL:          appURL,
+		audit:           audit,
+		lockout:         lockout,
+	}
+}
+
+// emailTemplateData isi template di infrastructure/mail/templates
+type emailTemplateData struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
+
+func (s *VerificationServiceImpl) SendVerification(ctx context.Context, userID int) error {
+	// step 1: cek user-nya dan pastikan email-nya belum diverifikasi
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return err
+	}
+	verified, err := s.UserRepository.IsEmailVerified(ctx, s.DB, userID)
+	if err != nil {
+		return err
+	}
+	if verified {
+		return ErrEmailAlreadyVerified
+	}
+
+	// step 2: buat token baru (token lama otomatis tidak berlaku) lalu kirim email-nya
+	token, err := s.issueToken(ctx, user, entity.EmailTokenVerifyEmail, verifyEmailTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.deliver(ctx, mail.TemplateVerifyEmail, user, "/verify-email", token, verifyEmailTokenTTL)
+}
+
+func (s *VerificationServiceImpl) IsVerified(ctx context.Context, userID int) (bool, error) {
+	return s.UserRepository.IsEmailVerified(ctx, s.DB, userID)
+}
+
+func (s *VerificationServiceImpl) ConfirmEmail(ctx context.Context, token string) error {
+	// step 1: cek tanda tangan token dulu, token palsu tidak perlu sampai ke database
+	tokenHash, ok := verifyEmailToken(token, entity.EmailTokenVerifyEmail)
+	if !ok {
+		return ErrInvalidEmailToken
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// step 3: pakai token-nya, lalu tandai email terverifikasi (gagal kalau user sudah ganti email setelah token dikirim)
+	emailToken, err := s.TokenRepository.Consume(ctx, tx, tokenHash, entity.EmailTokenVerifyEmail)
+	if err != nil {
+		return err
+	}
+	if emailToken == nil {
+		err = ErrInvalidEmailToken
+		return err
+	}
+	verified, err := s.UserRepository.MarkEmailVerified(ctx, tx, emailToken.UserID, emailToken.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		err = ErrInvalidEmailToken
+		return err
+	}
+
+	// step 4: commit transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &emailToken.UserID,
+		Action:     entity.AuditEmailVerified,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &emailToken.UserID,
+		Metadata:   map[string]interface{}{"email": emailToken.Email},
+	})
+	return nil
+}
+
+// RequestPasswordReset selalu berhasil untuk email yang tidak terdaftar, supaya endpoint ini tidak bisa dipakai
+// untuk mengecek email mana yang punya akun
+func (s *VerificationServiceImpl) RequestPasswordReset(ctx context.Context, email string) error {
+	// step 1: cari user dari email-nya
+	userID, err := s.UserRepository.FindIDByEmail(ctx, s.DB, strings.TrimSpace(strings.ToLower(email)))
+	if err != nil {
+		return err
+	}
+	if userID == 0 {
+		return nil
+	}
+	user, err := s.UserRepository.FindByID(ctx, s.DB, userID)
+	if err != nil {
+		return err
+	}
+
+	// step 2: buat token dan kirim email-nya
+	token, err := s.issueToken(ctx, user, entity.EmailTokenPasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	if err := s.deliver(ctx, mail.TemplatePasswordReset, user, "/reset-password", token, passwordResetTokenTTL); err != nil {
+		return err
+	}
+
+	s.audit.Log(ctx, AuditEntry{
+		Action:     entity.AuditPasswordResetRequested,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+	return nil
+}
+
+func (s *VerificationServiceImpl) ResetPassword(ctx context.Context, token, password string) error {
+	// step 1: validasi password baru dan tanda tangan token
+	if len(password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters long")
+	}
+	tokenHash, ok := verifyEmailToken(token, entity.EmailTokenPasswordReset)
+	if !ok {
+		return ErrInvalidEmailToken
+	}
+
+	// step 2: begin transaction
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	// step 3: pakai token-nya dan ganti password
+	emailToken, err := s.TokenRepository.Consume(ctx, tx, tokenHash, entity.EmailTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	if emailToken == nil {
+		err = ErrInvalidEmailToken
+		return err
+	}
+	if err = s.UserRepository.UpdatePassword(ctx, tx, emailToken.UserID, password); err != nil {
+		return err
+	}
+
+	// step 4: token reset lain yang masih berlaku ikut dicabut. Karena user terbukti bisa membuka email-nya,
+	// email-nya sekalian dianggap terverifikasi.
+	if err = s.TokenRepository.RevokeByUserID(ctx, tx, emailToken.UserID, entity.EmailTokenPasswordReset); err != nil {
+		return err
+	}
+	verified, err := s.UserRepository.MarkEmailVerified(ctx, tx, emailToken.UserID, emailToken.Email)
+	if err != nil {
+		return err
+	}
+	if verified {
+		if err = s.TokenRepository.RevokeByUserID(ctx, tx, emailToken.UserID, entity.EmailTokenVerifyEmail); err != nil {
+			return err
+		}
+	}
+
+	// step 5: commit transaction
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	// step 6: lockout login dibuka lagi, user sudah membuktikan pemilik akunnya
+	if user, err := s.UserRepository.FindByID(ctx, s.DB, emailToken.UserID); err == nil {
+		if err := s.lockout.Reset(ctx, user.Username); err != nil {
+			log.Printf("Error resetting login lockout for %s: %v", user.Username, err)
+		}
+	}
+	s.audit.Log(ctx, AuditEntry{
+		ActorID:    &emailToken.UserID,
+		Action:     entity.AuditPasswordReset,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   &emailToken.UserID,
+	})
+	return nil
+}
+
+func (s *VerificationServiceImpl) RunCleanupJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		if _, err := s.TokenRepository.DeleteExpired(jobCtx, s.DB, time.Now().Add(-emailTokenRetention)); err != nil {
+			log.Printf("failed to delete expired email tokens: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// issueToken membuat token baru dan mencabut token lama dengan kegunaan yang sama
+func (s *VerificationServiceImpl) issueToken(ctx context.Context, user *entity.User, purpose string, ttl time.Duration) (string, error) {
+	token, tokenHash, err := newEmailToken(purpose)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = s.TokenRepository.RevokeByUserID(ctx, tx, user.ID, purpose); err != nil {
+		return "", err
+	}
+	err = s.TokenRepository.Create(ctx, tx, &entity.EmailToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     user.Email,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// deliver menyusun email dari template lalu mengirimnya di background, supaya SMTP yang lambat tidak menahan request
+func (s *VerificationServiceImpl) deliver(ctx context.Context, template string, user *entity.User, path, token string, ttl time.Duration) error {
+	message, err := mail.Render(template, user.Email, emailTemplateData{
+		Name:      user.Fullname,
+		Link:      s.AppURL + path + "?token=" + url.QueryEscape(token),
+		ExpiresIn: formatTokenTTL(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mailSendTimeout)
+		defer cancel()
+		if err := s.Mailer.Send(sendCtx, message); err != nil {
+			log.Printf("failed to send %s email to user %d: %v", template, user.ID, err)
+		}
+	}()
+	return nil
+}
+
+func formatTokenTTL(ttl time.Duration) string {
+	if ttl >= time.Hour {
+		hours := int(ttl.Hours())
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
+
+var (
+	emailTokenKey     []byte
+	emailTokenKeyOnce sync.Once
+)
+
+// Token email ditandatangani pakai HMAC (kuncinya dari EMAIL_TOKEN_SECRET_KEY, kalau tidak ada pakai JWT_SECRET_KEY),
+// jadi token palsu langsung ditolak tanpa query ke database. Tanda tangannya juga mengikat kegunaan token,
+// token verifikasi email tidak bisa dipakai untuk reset password.
+func emailTokenSigningKey() []byte {
+	emailTokenKeyOnce.Do(func() {
+		for _, name := range []string{"EMAIL_TOKEN_SECRET_KEY", "JWT_SECRET_KEY"} {
+			if secret := os.Getenv(name); secret != "" {
+				emailTokenKey = []byte(secret)
+				return
+			}
+		}
+		log.Println("WARNING: EMAIL_TOKEN_SECRET_KEY is not set, using a random key for email tokens")
+		emailTokenKey = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, emailTokenKey); err != nil {
+			log.Fatalf("failed to generate email token signing key: %v", err)
+		}
+	})
+	return emailTokenKey
+}
+
+func signEmailToken(purpose, secret string) string {
+	mac := hmac.New(sha256.New, emailTokenSigningKey())
+	mac.Write([]byte(purpose + "." + secret))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashEmailToken yang disimpan di database, jadi bocornya tabel email_tokens tidak membocorkan token yang masih berlaku
+func hashEmailToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newEmailToken mengembalikan token untuk dikirim ke user dan hash-nya untuk disimpan
+func newEmailToken(purpose string) (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+	return secret + "." + signEmailToken(purpose, secret), hashEmailToken(secret), nil
+}
+
+// verifyEmailToken mengecek tanda tangan token dan mengembalikan hash-nya untuk dicari di database
+func verifyEmailToken(token, purpose string) (string, bool) {
+	secret, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || secret == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(signature), []byte(signEmailToken(purpose, secret))) {
+		return "", false
+	}
+	return hashEmailToken(secret), true
+}
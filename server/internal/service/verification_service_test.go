@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"mood-bridge-v2/server/infrastructure/mail"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
+	"mood-bridge-v2/server/internal/entity"
+	"mood-bridge-v2/server/internal/repository"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// txOnlyDriver cuma mendukung Begin/Commit/Rollback. Semua query di VerificationService lewat repository palsu,
+// jadi transaksinya tidak perlu menyentuh database sungguhan.
+type txOnlyDriver struct{}
+
+type txOnlyConn struct{}
+
+func (txOnlyDriver) Open(name string) (driver.Conn, error) { return txOnlyConn{}, nil }
+
+func (txOnlyConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("txOnlyConn does not run queries")
+}
+func (txOnlyConn) Close() error              { return nil }
+func (txOnlyConn) Begin() (driver.Tx, error) { return txOnlyConn{}, nil }
+func (txOnlyConn) Commit() error             { return nil }
+func (txOnlyConn) Rollback() error           { return nil }
+
+func init() {
+	sql.Register("verification-test", txOnlyDriver{})
+}
+
+// fakeTokenRepository mengikuti kontrak EmailTokenRepositoryImpl: Consume hanya mengembalikan token
+// yang belum dipakai dan belum expired, lalu langsung menandainya terpakai
+type fakeTokenRepository struct {
+	mu     sync.Mutex
+	now    time.Time
+	tokens []*entity.EmailToken
+}
+
+func (r *fakeTokenRepository) Create(ctx context.Context, tx *sql.Tx, token *entity.EmailToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *token
+	r.tokens = append(r.tokens, &stored)
+	return nil
+}
+
+func (r *fakeTokenRepository) RevokeByUserID(ctx context.Context, tx *sql.Tx, userID int, purpose string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			usedAt := r.now
+			token.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenRepository) Consume(ctx context.Context, tx *sql.Tx, tokenHash, purpose string) (*entity.EmailToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash && token.Purpose == purpose && token.UsedAt == nil && token.ExpiresAt.After(r.now) {
+			usedAt := r.now
+			token.UsedAt = &usedAt
+			consumed := *token
+			return &consumed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTokenRepository) DeleteExpired(ctx context.Context, db *sql.DB, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// Advance memajukan waktu yang dipakai Consume untuk mengecek expired
+func (r *fakeTokenRepository) Advance(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = r.now.Add(d)
+}
+
+// fakeUserRepository cuma mengimplementasikan method yang dipakai VerificationService
+type fakeUserRepository struct {
+	repository.UserRepository
+	user     entity.User
+	verified bool
+	password string
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, db *sql.DB, id int) (*entity.User, error) {
+	if id != r.user.ID {
+		return nil, sql.ErrNoRows
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *fakeUserRepository) FindIDByEmail(ctx context.Context, db *sql.DB, email string) (int, error) {
+	if email != r.user.Email {
+		return 0, nil
+	}
+	return r.user.ID, nil
+}
+
+func (r *fakeUserRepository) IsEmailVerified(ctx context.Context, db *sql.DB, id int) (bool, error) {
+	return r.verified, nil
+}
+
+func (r *fakeUserRepository) MarkEmailVerified(ctx context.Context, tx *sql.Tx, id int, email string) (bool, error) {
+	if id != r.user.ID || email != r.user.Email {
+		return false, nil
+	}
+	r.verified = true
+	return true, nil
+}
+
+func (r *fakeUserRepository) UpdatePassword(ctx context.Context, tx *sql.Tx, id int, password string) error {
+	r.password = password
+	return nil
+}
+
+type nopAuditLogger struct{}
+
+func (nopAuditLogger) Log(ctx context.Context, entry AuditEntry) {}
+
+var tokenLinkPattern = regexp.MustCompile(`token=([^\s"&<]+)`)
+
+type verificationFixture struct {
+	service *VerificationServiceImpl
+	mailer  *mail.CaptureMailer
+	tokens  *fakeTokenRepository
+	users   *fakeUserRepository
+}
+
+func newVerificationFixture(t *testing.T) *verificationFixture {
+	t.Helper()
+	db, err := sql.Open("verification-test", "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mailer := mail.NewCaptureMailer()
+	tokens := &fakeTokenRepository{now: time.Now()}
+	users := &fakeUserRepository{user: entity.User{ID: 7, Username: "alice", Fullname: "Alice", Email: "alice@example.com"}}
+	lockout := ratelimit.NewLockout(ratelimit.NewMemoryStore(), "login", ratelimit.DefaultLoginLockout)
+
+	service := NewVerificationService(db, users, tokens, mailer, nopAuditLogger{}, lockout).(*VerificationServiceImpl)
+	return &verificationFixture{service: service, mailer: mailer, tokens: tokens, users: users}
+}
+
+// waitForToken menunggu email ke-n untuk user (email dikirim di background) lalu mengambil token dari link-nya
+func (f *verificationFixture) waitForToken(t *testing.T, n int) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(f.mailer.Messages()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for email %d, got %d", n, len(f.mailer.Messages()))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	message, ok := f.mailer.Last(f.users.user.Email)
+	if !ok {
+		t.Fatalf("no email sent to %s", f.users.user.Email)
+	}
+	match := tokenLinkPattern.FindStringSubmatch(message.Text)
+	if match == nil {
+		t.Fatalf("no token link in email: %q", message.Text)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("QueryUnescape() error = %v", err)
+	}
+	return token
+}
+
+func TestEmailTokenFromCaptureMailer(t *testing.T) {
+	const newPassword = "correct horse battery"
+
+	issueVerify := func(t *testing.T, f *verificationFixture) string {
+		if err := f.service.SendVerification(context.Background(), f.users.user.ID); err != nil {
+			t.Fatalf("SendVerification() error = %v", err)
+		}
+		return f.waitForToken(t, len(f.mailer.Messages())+1)
+	}
+	issueReset := func(t *testing.T, f *verificationFixture) string {
+		if err := f.service.RequestPasswordReset(context.Background(), f.users.user.Email); err != nil {
+			t.Fatalf("RequestPasswordReset() error = %v", err)
+		}
+		return f.waitForToken(t, len(f.mailer.Messages())+1)
+	}
+	confirm := func(f *verificationFixture, token string) error {
+		return f.service.ConfirmEmail(context.Background(), token)
+	}
+	reset := func(f *verificationFixture, token string) error {
+		return f.service.ResetPassword(context.Background(), token, newPassword)
+	}
+
+	tests := []struct {
+		name string
+		// issue mengirim email dan mengembalikan token dari email-nya
+		issue func(t *testing.T, f *verificationFixture) string
+		// before dijalankan sebelum token dipakai, misalnya memajukan waktu
+		before  func(t *testing.T, f *verificationFixture, token string) string
+		use     func(f *verificationFixture, token string) error
+		wantErr error
+	}{
+		{
+			name:  "verification token confirms email",
+			issue: issueVerify,
+			use:   confirm,
+		},
+		{
+			name:  "verification token is single-use",
+			issue: issueVerify,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				if err := confirm(f, token); err != nil {
+					t.Fatalf("first ConfirmEmail() error = %v", err)
+				}
+				return token
+			},
+			use:     confirm,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:  "verification token expires",
+			issue: issueVerify,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				f.tokens.Advance(verifyEmailTokenTTL + time.Second)
+				return token
+			},
+			use:     confirm,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:  "verification token still valid just before expiry",
+			issue: issueVerify,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				f.tokens.Advance(verifyEmailTokenTTL - time.Minute)
+				return token
+			},
+			use: confirm,
+		},
+		{
+			name:  "resending verification revokes the older token",
+			issue: issueVerify,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				issueVerify(t, f)
+				return token
+			},
+			use:     confirm,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:  "reset token changes password",
+			issue: issueReset,
+			use:   reset,
+		},
+		{
+			name:  "reset token is single-use",
+			issue: issueReset,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				if err := reset(f, token); err != nil {
+					t.Fatalf("first ResetPassword() error = %v", err)
+				}
+				return token
+			},
+			use:     reset,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:  "reset token expires",
+			issue: issueReset,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				f.tokens.Advance(passwordResetTokenTTL + time.Second)
+				return token
+			},
+			use:     reset,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:    "reset token cannot verify email",
+			issue:   issueReset,
+			use:     confirm,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:    "verification token cannot reset password",
+			issue:   issueVerify,
+			use:     reset,
+			wantErr: ErrInvalidEmailToken,
+		},
+		{
+			name:  "tampered token is rejected",
+			issue: issueVerify,
+			before: func(t *testing.T, f *verificationFixture, token string) string {
+				return "x" + token
+			},
+			use:     confirm,
+			wantErr: ErrInvalidEmailToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newVerificationFixture(t)
+			token := tt.issue(t, f)
+			if tt.before != nil {
+				token = tt.before(t, f, token)
+			}
+
+			err := tt.use(f, token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResetPasswordAppliesNewPassword(t *testing.T) {
+	f := newVerificationFixture(t)
+	if err := f.service.RequestPasswordReset(context.Background(), f.users.user.Email); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	token := f.waitForToken(t, 1)
+
+	if err := f.service.ResetPassword(context.Background(), token, "correct horse battery"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if f.users.password != "correct horse battery" {
+		t.Fatalf("password = %q, want it updated", f.users.password)
+	}
+	// membuka email reset membuktikan pemilik email-nya
+	if !f.users.verified {
+		t.Fatalf("email not marked verified after password reset")
+	}
+}
+
+func TestRequestPasswordResetUnknownEmailSendsNothing(t *testing.T) {
+	f := newVerificationFixture(t)
+	if err := f.service.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := len(f.mailer.Messages()); got != 0 {
+		t.Fatalf("sent %d emails, want 0", got)
+	}
+}
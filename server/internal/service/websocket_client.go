@@ -14,7 +14,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"math"
+	"mood-bridge-v2/server/infrastructure/ratelimit"
 	"mood-bridge-v2/server/internal/model/request"
 	"mood-bridge-v2/server/internal/model/response"
 	"time"
@@ -37,15 +40,19 @@ type Client struct {
 	Conn *websocket.Conn // Koneksi WebSocket untuk client yang aktif
 	Send chan []byte // Channel untuk mengirim pesan ke client
 	ChatService ChatService // Service yang menangani logika chat, seperti mengirim pesan, mengambil riwayat chat, dll.
+	Limiter ratelimit.Store // Membatasi jumlah pesan per user, dihitung per user bukan per koneksi
+	Policy ratelimit.Policy
 }
 
-func NewClient(userID int, hub Hub, conn *websocket.Conn, chatService ChatService) *Client {
+func NewClient(userID int, hub Hub, conn *websocket.Conn, chatService ChatService, limiter ratelimit.Store, policy ratelimit.Policy) *Client {
 	return &Client{
 		UserID: userID,
 		Hub: hub,
 		Conn: conn,
 		Send: make(chan []byte, 256),
 		ChatService: chatService,
+		Limiter: limiter,
+		Policy: policy,
 	}
 }
 
@@ -79,6 +86,12 @@ func (c *Client) ReadPump() {
 
 		// step 5: jika pesan yang diterima adalah teks, proses pesan tersebut
 		if messageType == websocket.TextMessage {
+			// pesan yang melebihi kuota dibuang dan client diberi tahu kapan boleh kirim lagi
+			if retryAfter, limited := c.rateLimited(); limited {
+				c.sendError("rate_limited", fmt.Sprintf("Too many messages, try again in %d seconds", int(math.Ceil(retryAfter.Seconds()))))
+				continue
+			}
+
 			var msgPayload request.PrivateMessagePayload
 			if err := json.Unmarshal(messageBytes, &msgPayload); err != nil {
 				log.Printf("Error unmarshalling message from client %d: %v. Message: %s", c.UserID, err, string(messageBytes))
@@ -117,6 +130,31 @@ func (c *Client) ReadPump() {
 	}
 }
 
+// rateLimited mencatat satu pesan ke kuota user. Kalau limiter-nya error pesan tetap diproses.
+func (c *Client) rateLimited() (time.Duration, bool) {
+	if c.Limiter == nil {
+		return 0, false
+	}
+	result, err := c.Limiter.Allow(context.Background(), fmt.Sprintf("user:%d", c.UserID), c.Policy)
+	if err != nil {
+		log.Printf("Error checking chat rate limit for client %d: %v", c.UserID, err)
+		return 0, false
+	}
+	return result.RetryAfter, !result.Allowed
+}
+
+func (c *Client) sendError(code, message string) {
+	errorBytes, _ := json.Marshal(response.WebSocketMessage{
+		Type: "error",
+		Payload: response.ErrorMessage{Code: code, Message: message},
+	})
+	select {
+	case c.Send <- errorBytes:
+	default:
+		log.Printf("Error sending error message to client %d: send channel is full", c.UserID)
+	}
+}
+
 func (c *Client) WritePump() {
 	// step 1: define timer untuk mengirim ping ke client secara berkala
 	ticker := time.NewTicker(pingPeriod)
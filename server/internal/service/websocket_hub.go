@@ -10,6 +10,7 @@ package service
 		4. RoutePrivateMessage(message *entity.Message):
 			- meneruskan pesan pribadi dari satu client ke client lain yang dituju.
 			- misal: client A kirim pesan ke client B -> hub akan menerima pesan tersebut dan mengirimkannya ke client B.
+		5. PushToUser(userID, messageType, payload): mengirim event selain chat (misalnya friend request) ke user yang sedang online.
 */
 
 import (
@@ -27,6 +28,12 @@ type Hub interface {
 	RegisterClient(client *Client)
 	UnregisterClient(client *Client)
 	RoutePrivateMessage(message *entity.Message)
+	RealtimePusher
+}
+
+// RealtimePusher dipakai service lain yang cuma perlu mengirim event ke user yang online, tanpa urusan koneksi WebSocket
+type RealtimePusher interface {
+	PushToUser(userID int, messageType string, payload interface{}) bool
 }
 
 type HubImpl struct { // berfungsi untuk menyimpan daftar client yang aktif (yang terhubung via WebSocket) dan menyediakan cara untuk mengatur koneksi tersebut.
@@ -134,4 +141,36 @@ func (h *HubImpl) RoutePrivateMessage(message *entity.Message) {
 		// step 6: jika client penerima tidak ada (offline), simpan pesan ke database dan akan diambil saat client reconnect
 		log.Printf("Recipient client %d is offline. Message ID: %d stored in DB", message.RecipientID, message.ID)
 	}
-}
\ No newline at end of file
+}
+
+// PushToUser return false kalau user-nya sedang offline atau antriannya penuh, event-nya tidak disimpan
+// (pemanggil yang butuh riwayat sebaiknya juga membuat notifikasi lewat NotificationService)
+func (h *HubImpl) PushToUser(userID int, messageType string, payload interface{}) bool {
+	// step 1: ubah event ke bentuk json
+	payloadBytes, err := json.Marshal(response.WebSocketMessage{
+		Type:    messageType,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Printf("Error marshalling %s event for user %d: %v", messageType, userID, err)
+		return false
+	}
+
+	// step 2: kunci map clients selama mengirim, supaya Send tidak ditutup UnregisterClient di tengah jalan
+	h.clientsMutex.RLock()
+	defer h.clientsMutex.RUnlock()
+
+	client, ok := h.clients[userID]
+	if !ok {
+		return false
+	}
+
+	// step 3: kirim tanpa menunggu, client yang lambat tidak boleh menahan request HTTP
+	select {
+	case client.Send <- payloadBytes:
+		return true
+	default:
+		log.Printf("Hub: Send channel full for user %d, dropping %s event", userID, messageType)
+		return false
+	}
+}
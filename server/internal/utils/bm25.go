@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Parameter standar BM25 (k1 ngatur saturasi term frequency, b ngatur normalisasi panjang dokumen)
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Stopword campuran bahasa Inggris dan Indonesia karena user kita nulis pakai dua bahasa itu
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "had": true, "her": true, "was": true, "one": true,
+	"our": true, "out": true, "has": true, "have": true, "this": true, "that": true, "with": true,
+	"from": true, "they": true, "will": true, "just": true, "been": true, "into": true, "what": true,
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "ini": true, "itu": true,
+	"aku": true, "saya": true, "kamu": true, "dia": true, "ada": true, "untuk": true, "dengan": true,
+	"juga": true, "tidak": true, "gak": true, "nggak": true, "udah": true, "sudah": true, "lagi": true,
+	"akan": true, "karena": true, "tapi": true, "atau": true, "jadi": true, "kalau": true, "banget": true,
+}
+
+// Tokenize memecah teks jadi token huruf kecil tanpa tanda baca dan stopword
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || stopwords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+type BM25Result struct {
+	Index int
+	Score float64
+}
+
+// BM25Index adalah index in-process yang ringan, cocok buat korpus kecil kayak postingan milik satu user
+type BM25Index struct {
+	docs         []map[string]int
+	docLengths   []int
+	docFreq      map[string]int
+	avgDocLength float64
+}
+
+func NewBM25Index(documents []string) *BM25Index {
+	index := &BM25Index{
+		docs:       make([]map[string]int, len(documents)),
+		docLengths: make([]int, len(documents)),
+		docFreq:    make(map[string]int),
+	}
+
+	totalLength := 0
+	for i, document := range documents {
+		tokens := Tokenize(document)
+		termFreq := make(map[string]int)
+		for _, token := range tokens {
+			termFreq[token]++
+		}
+		for term := range termFreq {
+			index.docFreq[term]++
+		}
+		index.docs[i] = termFreq
+		index.docLengths[i] = len(tokens)
+		totalLength += len(tokens)
+	}
+
+	if len(documents) > 0 {
+		index.avgDocLength = float64(totalLength) / float64(len(documents))
+	}
+	return index
+}
+
+// Search mengembalikan maksimal topK dokumen dengan skor > 0, diurutkan dari yang paling relevan
+func (idx *BM25Index) Search(query string, topK int) []BM25Result {
+	queryTerms := Tokenize(query)
+	if len(queryTerms) == 0 || len(idx.docs) == 0 || topK <= 0 {
+		return []BM25Result{}
+	}
+
+	n := float64(len(idx.docs))
+	var results []BM25Result
+	for i, termFreq := range idx.docs {
+		score := 0.0
+		for _, term := range queryTerms {
+			tf := float64(termFreq[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(idx.docLengths[i])/idx.avgDocLength
+			score += idf * (tf * (bm25K1 + 1)) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			results = append(results, BM25Result{Index: i, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results
+}
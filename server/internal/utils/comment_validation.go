@@ -7,4 +7,14 @@ func ValidateCommentInput(Content string) error {
 		return fmt.Errorf("comment content must be between 1 and 500 characters")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Balasan dibatasi kedalamannya supaya thread-nya tetap enak dibaca (0 = top-level)
+const MaxCommentDepth = 3
+
+func ValidateCommentDepth(parentDepth int) error {
+	if parentDepth+1 > MaxCommentDepth {
+		return fmt.Errorf("replies can only be nested %d levels deep", MaxCommentDepth)
+	}
+	return nil
+}
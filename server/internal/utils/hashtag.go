@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	MaxTagLength   = 50
+	MaxTagsPerPost = 10
+)
+
+// # harus di awal teks atau setelah karakter yang bukan huruf/angka, jadi "abc#def" atau "&#39;" tidak dianggap hashtag
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&])#([\p{L}\p{N}_]+)`)
+
+var tagNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
+// ExtractHashtags ngambil semua hashtag unik dari isi postingan (lowercase, urut sesuai kemunculan).
+// Hashtag yang kepanjangan dilewati dan maksimal cuma MaxTagsPerPost yang disimpan.
+func ExtractHashtags(content string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, match := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		tag := strings.ToLower(match[1])
+		if len([]rune(tag)) > MaxTagLength || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == MaxTagsPerPost {
+			break
+		}
+	}
+	return tags
+}
+
+// NormalizeTagName dipakai buat nama tag dari URL, boleh diawali # atau huruf besar
+func NormalizeTagName(name string) (string, error) {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if tag == "" || len([]rune(tag)) > MaxTagLength || !tagNamePattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid tag name: %s", name)
+	}
+	return tag, nil
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Daftar emosi yang bisa dipilih user waktu nulis jurnal
+var EmotionTags = map[string]bool{
+	"happy": true, "calm": true, "grateful": true, "hopeful": true, "proud": true, "excited": true,
+	"sad": true, "anxious": true, "angry": true, "lonely": true, "tired": true, "overwhelmed": true,
+	"stressed": true, "numb": true, "confused": true, "guilty": true,
+}
+
+func ValidateJournalInput(content string, selfMood *int, tags []string) error {
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("content is required")
+	}
+	if len(content) > 5000 {
+		return fmt.Errorf("content must be at most 5000 characters")
+	}
+	if selfMood != nil && (*selfMood < 1 || *selfMood > 5) {
+		return fmt.Errorf("self_mood must be between 1 and 5")
+	}
+	if len(tags) > 10 {
+		return fmt.Errorf("at most 10 emotion tags are allowed")
+	}
+	for _, tag := range tags {
+		if !EmotionTags[strings.ToLower(tag)] {
+			return fmt.Errorf("unknown emotion tag: %s", tag)
+		}
+	}
+
+	// Kalau semua aman, kita return nil
+	return nil
+}
@@ -0,0 +1,15 @@
+package utils
+
+// Label dari model klasifikasi yang dianggap "distress" (sama dengan daftar negativeMoods di rekomendasi teman)
+var DistressMoods = map[string]bool{
+	"Depression":           true,
+	"Anxiety":              true,
+	"Suicidal":             true,
+	"Personality disorder": true,
+	"Bipolar":              true,
+	"Stress":               true,
+}
+
+func IsDistressMood(mood string) bool {
+	return DistressMoods[mood]
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func ValidatePostInput(content string, mood string) error {
 	if content == "" || mood == "" {
@@ -12,4 +15,21 @@ func ValidatePostInput(content string, mood string) error {
 
 	// Kalau semua aman, kita return nil
 	return nil
-}
\ No newline at end of file
+}
+
+func ValidatePostVisibility(visibility string) error {
+	switch visibility {
+	case "public", "friends", "private":
+		return nil
+	default:
+		return fmt.Errorf("visibility must be one of public, friends, or private")
+	}
+}
+
+// ValidateDeclaredMood memastikan mood yang dipilih user ada di daftar emosi yang sama dengan jurnal
+func ValidateDeclaredMood(mood string) error {
+	if !EmotionTags[strings.ToLower(mood)] {
+		return fmt.Errorf("unknown declared mood: %s", mood)
+	}
+	return nil
+}
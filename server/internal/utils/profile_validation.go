@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"mood-bridge-v2/server/internal/entity"
+	"regexp"
+	"strings"
+)
+
+const (
+	MaxBioLength      = 300
+	MaxPronounsLength = 30
+	MaxLocationLength = 100
+	MaxInterests      = 10
+	MaxInterestLength = 30
+)
+
+var SupportTypes = map[string]bool{
+	entity.SupportListening:        true,
+	entity.SupportAdvice:           true,
+	entity.SupportSharedExperience: true,
+	entity.SupportEncouragement:    true,
+	entity.SupportResources:        true,
+	entity.SupportCheckIns:         true,
+}
+
+// lokasi cuma boleh huruf dan tanda baca sederhana, angka ditolak supaya tidak ada alamat jalan atau kode pos
+var coarseLocationPattern = regexp.MustCompile(`^[\p{L}\s.,'-]*$`)
+
+// kode bahasa pendek ala BCP 47, misalnya "id", "en" atau "en-us"
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2})?$`)
+
+func ValidateProfileText(bio, pronouns, location string) error {
+	if len([]rune(bio)) > MaxBioLength {
+		return fmt.Errorf("bio must be at most %d characters", MaxBioLength)
+	}
+	if len([]rune(pronouns)) > MaxPronounsLength {
+		return fmt.Errorf("pronouns must be at most %d characters", MaxPronounsLength)
+	}
+	if len([]rune(location)) > MaxLocationLength {
+		return fmt.Errorf("location must be at most %d characters", MaxLocationLength)
+	}
+	if !coarseLocationPattern.MatchString(location) {
+		return fmt.Errorf("location should only be a city, region or country")
+	}
+	return nil
+}
+
+// NormalizeInterests merapikan daftar minat (lowercase, spasi dirapikan, duplikat dibuang, urutan tetap)
+func NormalizeInterests(interests []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, interest := range interests {
+		interest = strings.ToLower(strings.Join(strings.Fields(interest), " "))
+		if interest == "" || seen[interest] {
+			continue
+		}
+		if len([]rune(interest)) > MaxInterestLength {
+			return nil, fmt.Errorf("each interest must be at most %d characters", MaxInterestLength)
+		}
+		seen[interest] = true
+		normalized = append(normalized, interest)
+	}
+	if len(normalized) > MaxInterests {
+		return nil, fmt.Errorf("at most %d interests are allowed", MaxInterests)
+	}
+	return normalized, nil
+}
+
+// NormalizeSupportTypes memvalidasi jenis dukungan yang dicari/ditawarkan dan membuang duplikatnya
+func NormalizeSupportTypes(types []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, supportType := range types {
+		supportType = strings.ToLower(strings.TrimSpace(supportType))
+		if !SupportTypes[supportType] {
+			return nil, fmt.Errorf("unknown support type: %s", supportType)
+		}
+		if seen[supportType] {
+			continue
+		}
+		seen[supportType] = true
+		normalized = append(normalized, supportType)
+	}
+	return normalized, nil
+}
+
+// NormalizeLanguage mengembalikan kode bahasa dalam lowercase, string kosong berarti tidak diisi
+func NormalizeLanguage(language string) (string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language != "" && !languagePattern.MatchString(language) {
+		return "", fmt.Errorf("language must be a language code such as \"id\" or \"en\"")
+	}
+	return language, nil
+}
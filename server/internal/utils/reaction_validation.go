@@ -0,0 +1,19 @@
+package utils
+
+import "fmt"
+
+// Daftar reaksi yang bisa dikasih ke postingan (sengaja dibatasi supaya isinya tetap suportif)
+var ReactionTypes = map[string]bool{
+	"hug":              true,
+	"relate":           true,
+	"sending_strength": true,
+	"proud_of_you":     true,
+	"here_for_you":     true,
+}
+
+func ValidateReactionType(reactionType string) error {
+	if !ReactionTypes[reactionType] {
+		return fmt.Errorf("unknown reaction type: %s", reactionType)
+	}
+	return nil
+}